@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/carlmjohnson/requests"
+	"github.com/vertex-center/vertex/config"
 	"github.com/vertex-center/vertex/core/port"
 	types2 "github.com/vertex-center/vertex/core/types"
 	"github.com/vertex-center/vertex/pkg/log"
@@ -17,12 +18,45 @@ type BaselinesApiAdapter struct {
 func NewBaselinesApiAdapter() port.BaselinesAdapter {
 	return &BaselinesApiAdapter{
 		config: func(rb *requests.Builder) {
-			rb.BaseURL("https://bl.vx.quentinguidee.dev/")
+			rb.BaseURL(config.Current.BaselinesURL)
 		},
 	}
 }
 
+// GetLatest returns the latest baseline available on the channel. The more
+// stable channels are also considered, so a beta user gets the stable
+// release once it is newer than the last prerelease. They are skipped if
+// they can't be fetched.
 func (a *BaselinesApiAdapter) GetLatest(ctx context.Context, channel types2.SettingsUpdatesChannel) (types2.Baseline, error) {
+	latest, err := a.get(ctx, channel)
+	if err != nil {
+		return latest, err
+	}
+
+	for _, c := range types2.SettingsUpdatesChannels {
+		if c == channel {
+			break
+		}
+
+		baseline, err := a.get(ctx, c)
+		if err != nil {
+			log.Warn("failed to fetch a more stable baseline",
+				vlog.String("channel", string(c)),
+				vlog.String("reason", err.Error()),
+			)
+			continue
+		}
+
+		if baseline.Date > latest.Date {
+			log.Info("a more stable baseline is newer", vlog.String("channel", string(c)))
+			latest = baseline
+		}
+	}
+
+	return latest, nil
+}
+
+func (a *BaselinesApiAdapter) get(ctx context.Context, channel types2.SettingsUpdatesChannel) (types2.Baseline, error) {
 	var baseline types2.Baseline
 	builder := requests.New(a.config).
 		Pathf("%s.json", channel).
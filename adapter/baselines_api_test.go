@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/h2non/gock"
 	"github.com/stretchr/testify/suite"
+	types2 "github.com/vertex-center/vertex/core/types"
 	"log"
 	"net/http"
 	"testing"
@@ -50,6 +51,13 @@ func (suite *BaselinesApiAdapterTestSuite) TestGetLatest() {
 
 func (suite *BaselinesApiAdapterTestSuite) TestGetLatestBeta() {
 	gock.Off()
+	gock.New(baseURL).
+		Get("stable.json").
+		Reply(http.StatusOK).
+		JSON(map[string]interface{}{
+			"date":    "2023-10-13",
+			"version": "v0.12.0",
+		})
 	gock.New(baseURL).
 		Get("beta.json").
 		Reply(http.StatusOK).
@@ -70,3 +78,57 @@ func (suite *BaselinesApiAdapterTestSuite) TestGetLatestBeta() {
 	suite.Equal("v0.13.3-beta", baseline.VertexClient)
 	suite.Equal("071bcdc8162664fb9b6c489c00277f0cce15ad87", baseline.VertexServices)
 }
+
+func (suite *BaselinesApiAdapterTestSuite) TestGetLatestBetaStableUnreachable() {
+	gock.Off()
+	gock.New(baseURL).
+		Get("stable.json").
+		Reply(http.StatusInternalServerError)
+	gock.New(baseURL).
+		Get("beta.json").
+		Reply(http.StatusOK).
+		JSON(map[string]interface{}{
+			"date":    "2023-10-15",
+			"version": "v0.13.0-beta",
+		})
+
+	baseline, err := suite.adapter.GetLatest(context.Background(), "beta")
+	suite.NoError(err)
+	suite.Equal("v0.13.0-beta", baseline.Version)
+
+	// The channel of the user is still required.
+	gock.Off()
+	gock.New(baseURL).
+		Get("beta.json").
+		Reply(http.StatusInternalServerError)
+
+	_, err = suite.adapter.GetLatest(context.Background(), "beta")
+	suite.ErrorIs(err, types2.ErrFailedToFetchBaseline)
+}
+
+func (suite *BaselinesApiAdapterTestSuite) TestGetLatestBetaOlderThanStable() {
+	gock.Off()
+	gock.New(baseURL).
+		Get("stable.json").
+		Reply(http.StatusOK).
+		JSON(map[string]interface{}{
+			"date":          "2023-10-20",
+			"version":       "v0.13.0",
+			"vertex":        "v0.13.6",
+			"vertex_client": "v0.13.4",
+		})
+	gock.New(baseURL).
+		Get("beta.json").
+		Reply(http.StatusOK).
+		JSON(map[string]interface{}{
+			"date":          "2023-10-15",
+			"version":       "v0.13.0-beta",
+			"vertex":        "v0.13.5-beta",
+			"vertex_client": "v0.13.3-beta",
+		})
+
+	baseline, err := suite.adapter.GetLatest(context.Background(), "beta")
+	suite.NoError(err)
+	suite.Equal("v0.13.0", baseline.Version)
+	suite.Equal("v0.13.6", baseline.Vertex)
+}
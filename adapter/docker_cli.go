@@ -2,15 +2,25 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	"github.com/vertex-center/vertex/core/types"
 	"io"
 
 	dockertypes "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vlog"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	ErrDockerUnavailable = errors.New("the docker client is not available")
 )
 
 type DockerCliAdapter struct {
@@ -32,8 +42,27 @@ func NewDockerCliAdapter() DockerCliAdapter {
 	}
 }
 
-func (a DockerCliAdapter) ListContainers() ([]types.Container, error) {
-	res, err := a.cli.ContainerList(context.Background(), dockertypes.ContainerListOptions{All: true})
+func (a DockerCliAdapter) Ping() error {
+	if a.cli == nil {
+		return ErrDockerUnavailable
+	}
+	_, err := a.cli.Ping(context.Background())
+	return err
+}
+
+func (a DockerCliAdapter) ListContainers(options types.ListContainersOptions) ([]types.Container, error) {
+	args := filters.NewArgs()
+	for key, value := range options.Labels {
+		args.Add("label", key+"="+value)
+	}
+	if options.Name != "" {
+		args.Add("name", options.Name)
+	}
+
+	res, err := a.cli.ContainerList(context.Background(), dockertypes.ContainerListOptions{
+		All:     true,
+		Filters: args,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -58,6 +87,7 @@ func (a DockerCliAdapter) CreateContainer(options types.CreateContainerOptions)
 		AttachStdout: true,
 		AttachStderr: true,
 		Cmd:          options.Cmd,
+		Labels:       options.Labels,
 	}
 
 	hostConfig := container.HostConfig{
@@ -65,9 +95,39 @@ func (a DockerCliAdapter) CreateContainer(options types.CreateContainerOptions)
 		PortBindings: options.PortBindings,
 		CapAdd:       options.CapAdd,
 		Sysctls:      options.Sysctls,
+		ExtraHosts:   options.ExtraHosts,
+		ShmSize:      options.ShmSize,
+	}
+
+	if options.Init {
+		hostConfig.Init = &options.Init
 	}
 
-	res, err := a.cli.ContainerCreate(context.Background(), &config, &hostConfig, nil, nil, options.ContainerName)
+	for _, v := range options.Volumes {
+		hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+			Type:   mount.TypeVolume,
+			Source: v.Name,
+			Target: v.Target,
+		})
+	}
+
+	var networkingConfig *network.NetworkingConfig
+	if options.Network != "" {
+		err := a.ensureNetwork(options.Network)
+		if err != nil {
+			return types.CreateContainerResponse{}, err
+		}
+		hostConfig.NetworkMode = container.NetworkMode(options.Network)
+		networkingConfig = &network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				options.Network: {
+					Aliases: options.NetworkAliases,
+				},
+			},
+		}
+	}
+
+	res, err := a.cli.ContainerCreate(context.Background(), &config, &hostConfig, networkingConfig, nil, options.ContainerName)
 	if err != nil {
 		return types.CreateContainerResponse{}, err
 	}
@@ -78,6 +138,26 @@ func (a DockerCliAdapter) CreateContainer(options types.CreateContainerOptions)
 	}, nil
 }
 
+// ensureNetwork creates the bridge network if it doesn't exist.
+func (a DockerCliAdapter) ensureNetwork(name string) error {
+	_, err := a.cli.NetworkInspect(context.Background(), name, dockertypes.NetworkInspectOptions{})
+	if err == nil {
+		return nil
+	} else if !client.IsErrNotFound(err) {
+		return err
+	}
+
+	log.Info("creating docker network", vlog.String("network", name))
+	_, err = a.cli.NetworkCreate(context.Background(), name, dockertypes.NetworkCreate{
+		CheckDuplicate: true,
+		Driver:         "bridge",
+		Labels: map[string]string{
+			"vertex.managed": "true",
+		},
+	})
+	return err
+}
+
 func (a DockerCliAdapter) StartContainer(id string) error {
 	return a.cli.ContainerStart(context.Background(), id, dockertypes.ContainerStartOptions{})
 }
@@ -86,37 +166,74 @@ func (a DockerCliAdapter) StopContainer(id string) error {
 	return a.cli.ContainerStop(context.Background(), id, container.StopOptions{})
 }
 
+func (a DockerCliAdapter) PauseContainer(id string) error {
+	return a.cli.ContainerPause(context.Background(), id)
+}
+
+func (a DockerCliAdapter) UnpauseContainer(id string) error {
+	return a.cli.ContainerUnpause(context.Background(), id)
+}
+
 func (a DockerCliAdapter) InfoContainer(id string) (types.InfoContainerResponse, error) {
 	info, err := a.cli.ContainerInspect(context.Background(), id)
 	if err != nil {
 		return types.InfoContainerResponse{}, err
 	}
-	return types.InfoContainerResponse{
+	res := types.InfoContainerResponse{
 		ID:       info.ID,
 		Name:     info.Name,
 		Platform: info.Platform,
 		Image:    info.Image,
-	}, nil
+		Mounts:   types.NewMounts(info.Mounts),
+		Ports:    map[string][]string{},
+	}
+	if info.State != nil {
+		res.State = info.State.Status
+	}
+	if info.NetworkSettings != nil {
+		for name := range info.NetworkSettings.Networks {
+			res.Networks = append(res.Networks, name)
+		}
+		slices.Sort(res.Networks)
+		for port, bindings := range info.NetworkSettings.Ports {
+			for _, b := range bindings {
+				// Docker binds the same port on IPv4 and IPv6.
+				if !slices.Contains(res.Ports[string(port)], b.HostPort) {
+					res.Ports[string(port)] = append(res.Ports[string(port)], b.HostPort)
+				}
+			}
+		}
+	}
+	return res, nil
 }
 
-func (a DockerCliAdapter) LogsStdoutContainer(id string) (io.ReadCloser, error) {
+func (a DockerCliAdapter) LogsStdoutContainer(id string, options types.LogsContainerOptions) (io.ReadCloser, error) {
 	return a.cli.ContainerLogs(context.Background(), id, dockertypes.ContainerLogsOptions{
 		ShowStdout: true,
-		Timestamps: false,
+		Timestamps: options.Timestamps,
 		Follow:     true,
-		Tail:       "0",
+		Tail:       logsTail(options.Tail),
 	})
 }
 
-func (a DockerCliAdapter) LogsStderrContainer(id string) (io.ReadCloser, error) {
+func (a DockerCliAdapter) LogsStderrContainer(id string, options types.LogsContainerOptions) (io.ReadCloser, error) {
 	return a.cli.ContainerLogs(context.Background(), id, dockertypes.ContainerLogsOptions{
 		ShowStderr: true,
-		Timestamps: false,
+		Timestamps: options.Timestamps,
 		Follow:     true,
-		Tail:       "0",
+		Tail:       logsTail(options.Tail),
 	})
 }
 
+// logsTail returns the tail of the logs to print before following them,
+// none by default.
+func logsTail(tail string) string {
+	if tail == "" {
+		return "0"
+	}
+	return tail
+}
+
 func (a DockerCliAdapter) WaitContainer(id string, cond types.WaitContainerCondition) error {
 	statusCh, errCh := a.cli.ContainerWait(context.Background(), id, container.WaitCondition(cond))
 
@@ -131,10 +248,15 @@ func (a DockerCliAdapter) WaitContainer(id string, cond types.WaitContainerCondi
 	return nil
 }
 
+// CopyToContainer extracts the tar archive in the dir of the container.
+func (a DockerCliAdapter) CopyToContainer(id string, dir string, archive io.Reader) error {
+	return a.cli.CopyToContainer(context.Background(), id, dir, archive, dockertypes.CopyToContainerOptions{})
+}
+
 func (a DockerCliAdapter) InfoImage(id string) (types.InfoImageResponse, error) {
 	info, _, err := a.cli.ImageInspectWithRaw(context.Background(), id)
 	if err != nil {
-		return types.InfoImageResponse{}, nil
+		return types.InfoImageResponse{}, err
 	}
 	return types.InfoImageResponse{
 		ID:           info.ID,
@@ -142,14 +264,26 @@ func (a DockerCliAdapter) InfoImage(id string) (types.InfoImageResponse, error)
 		OS:           info.Os,
 		Size:         info.Size,
 		Tags:         info.RepoTags,
+		Digests:      info.RepoDigests,
 	}, nil
 }
 
-func (a DockerCliAdapter) PullImage(options types.PullImageOptions) (io.ReadCloser, error) {
-	return a.cli.ImagePull(context.Background(), options.Image, dockertypes.ImagePullOptions{})
+func (a DockerCliAdapter) PullImage(ctx context.Context, options types.PullImageOptions) (io.ReadCloser, error) {
+	pullOptions := dockertypes.ImagePullOptions{}
+	if options.Auth != nil {
+		auth, err := registry.EncodeAuthConfig(registry.AuthConfig{
+			Username: options.Auth.Username,
+			Password: options.Auth.Password,
+		})
+		if err != nil {
+			return nil, err
+		}
+		pullOptions.RegistryAuth = auth
+	}
+	return a.cli.ImagePull(ctx, options.Image, pullOptions)
 }
 
-func (a DockerCliAdapter) BuildImage(options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error) {
+func (a DockerCliAdapter) BuildImage(ctx context.Context, options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error) {
 	buildOptions := dockertypes.ImageBuildOptions{
 		Dockerfile: options.Dockerfile,
 		Tags:       []string{options.Name},
@@ -163,5 +297,5 @@ func (a DockerCliAdapter) BuildImage(options types.BuildImageOptions) (dockertyp
 		return dockertypes.ImageBuildResponse{}, err
 	}
 
-	return a.cli.ImageBuild(context.Background(), reader, buildOptions)
+	return a.cli.ImageBuild(ctx, reader, buildOptions)
 }
@@ -0,0 +1,27 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/carlmjohnson/requests"
+	"github.com/vertex-center/vertex/config"
+	"github.com/vertex-center/vertex/core/port"
+)
+
+type DockerKernelApiAdapter struct {
+	config requests.Config
+}
+
+func NewDockerKernelApiAdapter() port.DockerKernelAdapter {
+	return &DockerKernelApiAdapter{
+		config: func(rb *requests.Builder) {
+			rb.BaseURL(config.Current.KernelURL())
+		},
+	}
+}
+
+func (a *DockerKernelApiAdapter) Ping() error {
+	return requests.New(a.config).
+		Path("/api/docker/ping").
+		Fetch(context.Background())
+}
@@ -0,0 +1,44 @@
+package adapter
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/h2non/gock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/config"
+)
+
+type DockerKernelApiAdapterTestSuite struct {
+	suite.Suite
+
+	adapter DockerKernelApiAdapter
+}
+
+func TestDockerKernelApiAdapterTestSuite(t *testing.T) {
+	suite.Run(t, new(DockerKernelApiAdapterTestSuite))
+}
+
+func (suite *DockerKernelApiAdapterTestSuite) SetupTest() {
+	suite.adapter = *NewDockerKernelApiAdapter().(*DockerKernelApiAdapter)
+}
+
+func (suite *DockerKernelApiAdapterTestSuite) TestPing() {
+	gock.Off()
+	gock.New(config.Current.KernelURL()).
+		Get("/api/docker/ping").
+		Reply(http.StatusOK)
+
+	err := suite.adapter.Ping()
+	suite.NoError(err)
+}
+
+func (suite *DockerKernelApiAdapterTestSuite) TestPingUnavailable() {
+	gock.Off()
+	gock.New(config.Current.KernelURL()).
+		Get("/api/docker/ping").
+		Reply(http.StatusInternalServerError)
+
+	err := suite.adapter.Ping()
+	suite.Error(err)
+}
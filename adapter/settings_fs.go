@@ -9,6 +9,7 @@ import (
 	"io/fs"
 	"os"
 	"path"
+	"sync"
 
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vertex/pkg/storage"
@@ -23,7 +24,11 @@ var (
 
 type SettingsFSAdapter struct {
 	settingsDir string
-	settings    types.Settings
+
+	// settings are read and written by concurrent requests. The setters
+	// hold the lock while writing the file, so the writes don't interleave.
+	settings      types.Settings
+	settingsMutex sync.RWMutex
 }
 
 type SettingsFSAdapterParams struct {
@@ -59,11 +64,42 @@ func NewSettingsFSAdapter(params *SettingsFSAdapterParams) port.SettingsAdapter
 	return adapter
 }
 
+// GetSettings returns a copy of the settings. The setters replace the
+// sections instead of modifying them, so the copy can be read without lock.
 func (a *SettingsFSAdapter) GetSettings() types.Settings {
-	return a.settings
+	a.settingsMutex.RLock()
+	defer a.settingsMutex.RUnlock()
+
+	settings := a.settings
+	if a.settings.Custom != nil {
+		settings.Custom = types.SettingsCustom{}
+		for namespace, values := range a.settings.Custom {
+			settings.Custom[namespace] = map[string]json.RawMessage{}
+			for key, value := range values {
+				settings.Custom[namespace][key] = value
+			}
+		}
+	}
+	return settings
+}
+
+func (a *SettingsFSAdapter) SetSettings(settings types.Settings) error {
+	a.settingsMutex.Lock()
+	defer a.settingsMutex.Unlock()
+
+	previous := a.settings
+	a.settings = settings
+	err := a.write()
+	if err != nil {
+		a.settings = previous
+	}
+	return err
 }
 
 func (a *SettingsFSAdapter) GetNotificationsWebhook() *string {
+	a.settingsMutex.RLock()
+	defer a.settingsMutex.RUnlock()
+
 	if a.settings.Notifications == nil {
 		return nil
 	}
@@ -71,14 +107,22 @@ func (a *SettingsFSAdapter) GetNotificationsWebhook() *string {
 }
 
 func (a *SettingsFSAdapter) SetNotificationsWebhook(webhook string) error {
-	if a.settings.Notifications == nil {
-		a.settings.Notifications = &types.SettingsNotifications{}
+	a.settingsMutex.Lock()
+	defer a.settingsMutex.Unlock()
+
+	var notifications types.SettingsNotifications
+	if a.settings.Notifications != nil {
+		notifications = *a.settings.Notifications
 	}
-	a.settings.Notifications.Webhook = &webhook
+	notifications.Webhook = &webhook
+	a.settings.Notifications = &notifications
 	return a.write()
 }
 
 func (a *SettingsFSAdapter) GetChannel() *types.SettingsUpdatesChannel {
+	a.settingsMutex.RLock()
+	defer a.settingsMutex.RUnlock()
+
 	if a.settings.Updates == nil {
 		return nil
 	}
@@ -86,15 +130,71 @@ func (a *SettingsFSAdapter) GetChannel() *types.SettingsUpdatesChannel {
 }
 
 func (a *SettingsFSAdapter) SetChannel(channel types.SettingsUpdatesChannel) error {
-	if a.settings.Updates == nil {
-		a.settings.Updates = &types.SettingsUpdates{}
+	a.settingsMutex.Lock()
+	defer a.settingsMutex.Unlock()
+
+	var updates types.SettingsUpdates
+	if a.settings.Updates != nil {
+		updates = *a.settings.Updates
 	}
-	a.settings.Updates.Channel = &channel
+	updates.Channel = &channel
+	a.settings.Updates = &updates
 	return a.write()
 }
 
+func (a *SettingsFSAdapter) GetCustom(namespace string, key string) (json.RawMessage, error) {
+	a.settingsMutex.RLock()
+	defer a.settingsMutex.RUnlock()
+
+	value, ok := a.settings.Custom[namespace][key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", types.ErrCustomSettingNotFound, namespace, key)
+	}
+	return value, nil
+}
+
+func (a *SettingsFSAdapter) SetCustom(namespace string, key string, value json.RawMessage) error {
+	a.settingsMutex.Lock()
+	defer a.settingsMutex.Unlock()
+
+	if value == nil {
+		delete(a.settings.Custom[namespace], key)
+		if len(a.settings.Custom[namespace]) == 0 {
+			delete(a.settings.Custom, namespace)
+		}
+		return a.write()
+	}
+
+	if a.settings.Custom == nil {
+		a.settings.Custom = types.SettingsCustom{}
+	}
+	if a.settings.Custom[namespace] == nil {
+		a.settings.Custom[namespace] = map[string]json.RawMessage{}
+	}
+	a.settings.Custom[namespace][key] = value
+	return a.write()
+}
+
+// read reads the settings. If settings.json is corrupted, the previous version
+// kept by write is read instead, so the settings are not lost.
 func (a *SettingsFSAdapter) read() error {
 	p := path.Join(a.settingsDir, "settings.json")
+	err := a.readFile(p)
+	if !errors.Is(err, errSettingsFailedToDecode) {
+		return err
+	}
+
+	backupErr := a.readFile(p + storage.BackupSuffix)
+	if backupErr != nil {
+		return err
+	}
+	log.Warn("settings.json is corrupted, the backup was restored",
+		vlog.String("reason", err.Error()),
+	)
+	return nil
+}
+
+func (a *SettingsFSAdapter) readFile(p string) error {
 	file, err := os.ReadFile(p)
 
 	if errors.Is(err, fs.ErrNotExist) {
@@ -103,13 +203,18 @@ func (a *SettingsFSAdapter) read() error {
 		return fmt.Errorf("%w: %w", errSettingsFailedToRead, err)
 	}
 
-	err = json.Unmarshal(file, &a.settings)
+	var settings types.Settings
+	err = json.Unmarshal(file, &settings)
 	if err != nil {
 		return fmt.Errorf("%w: %w", errSettingsFailedToDecode, err)
 	}
+	a.settingsMutex.Lock()
+	a.settings = settings
+	a.settingsMutex.Unlock()
 	return nil
 }
 
+// write writes the settings. The caller must hold the lock.
 func (a *SettingsFSAdapter) write() error {
 	p := path.Join(a.settingsDir, "settings.json")
 
@@ -118,5 +223,5 @@ func (a *SettingsFSAdapter) write() error {
 		return err
 	}
 
-	return os.WriteFile(p, bytes, os.ModePerm)
+	return storage.WriteFileAtomic(p, bytes, os.ModePerm)
 }
@@ -2,11 +2,14 @@ package adapter
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/core/types"
 )
 
 type SettingsFSAdapterTestSuite struct {
@@ -51,3 +54,82 @@ func (suite *SettingsFSAdapterTestSuite) TestReadCorruptedSettings() {
 	err = suite.adapter.read()
 	suite.ErrorIs(err, errSettingsFailedToDecode)
 }
+
+func (suite *SettingsFSAdapterTestSuite) TestCustom() {
+	_, err := suite.adapter.GetCustom("vx-containers", "idle_timeout")
+	suite.ErrorIs(err, types.ErrCustomSettingNotFound)
+
+	err = suite.adapter.SetCustom("vx-containers", "idle_timeout", json.RawMessage("10"))
+	suite.Require().NoError(err)
+
+	// The custom settings are persisted with the other settings.
+	err = suite.adapter.read()
+	suite.Require().NoError(err)
+
+	value, err := suite.adapter.GetCustom("vx-containers", "idle_timeout")
+	suite.Require().NoError(err)
+	suite.JSONEq("10", string(value))
+
+	err = suite.adapter.SetCustom("vx-containers", "idle_timeout", nil)
+	suite.Require().NoError(err)
+	suite.NotContains(suite.adapter.settings.Custom, "vx-containers")
+}
+
+func (suite *SettingsFSAdapterTestSuite) TestReadCorruptedSettingsBackup() {
+	err := suite.adapter.SetChannel(types.SettingsUpdatesChannelBeta)
+	suite.Require().NoError(err)
+	err = suite.adapter.SetNotificationsWebhook("https://example.com/webhook")
+	suite.Require().NoError(err)
+
+	p := path.Join(suite.adapter.settingsDir, "settings.json")
+	err = os.WriteFile(p, []byte("{\"updates\":"), 0644)
+	suite.Require().NoError(err)
+
+	suite.adapter.settings = types.Settings{}
+	err = suite.adapter.read()
+	suite.Require().NoError(err)
+	suite.Equal(types.SettingsUpdatesChannelBeta, *suite.adapter.GetChannel())
+}
+
+func (suite *SettingsFSAdapterTestSuite) TestConcurrentWrites() {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(3)
+		go func(i int) {
+			defer wg.Done()
+			suite.NoError(suite.adapter.SetCustom("test", fmt.Sprintf("key_%d", i), json.RawMessage("true")))
+		}(i)
+		go func() {
+			defer wg.Done()
+			suite.NoError(suite.adapter.SetNotificationsWebhook("https://example.com/webhook"))
+		}()
+		go func() {
+			defer wg.Done()
+			_ = suite.adapter.GetSettings()
+		}()
+	}
+	wg.Wait()
+
+	// No update is lost, in memory nor on disk.
+	err := suite.adapter.read()
+	suite.Require().NoError(err)
+	suite.Len(suite.adapter.GetSettings().Custom["test"], 20)
+	suite.Equal("https://example.com/webhook", *suite.adapter.GetNotificationsWebhook())
+}
+
+func (suite *SettingsFSAdapterTestSuite) TestSetSettings() {
+	err := suite.adapter.SetNotificationsWebhook("https://example.com/webhook")
+	suite.Require().NoError(err)
+
+	channel := types.SettingsUpdatesChannelBeta
+	err = suite.adapter.SetSettings(types.Settings{
+		Updates: &types.SettingsUpdates{Channel: &channel},
+	})
+	suite.Require().NoError(err)
+
+	// The imported settings replace all the previous ones.
+	err = suite.adapter.read()
+	suite.Require().NoError(err)
+	suite.Nil(suite.adapter.GetNotificationsWebhook())
+	suite.Equal(types.SettingsUpdatesChannelBeta, *suite.adapter.GetChannel())
+}
@@ -89,7 +89,7 @@ func (a *ContainerFSAdapter) GetAll() ([]uuid.UUID, error) {
 		isContainer := entry.IsDir() || info.Mode()&os.ModeSymlink != 0
 
 		if isContainer {
-			log.Info("found container",
+			log.Debug("found container",
 				vlog.String("uuid", entry.Name()),
 			)
 
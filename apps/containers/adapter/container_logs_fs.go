@@ -7,6 +7,7 @@ import (
 	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
 	"os"
 	"path"
+	"regexp"
 	"sync"
 	"time"
 
@@ -17,7 +18,12 @@ import (
 	"github.com/vertex-center/vlog"
 )
 
-const bufferSize = 50
+const (
+	bufferSize = 50
+
+	// buildBufferSize is larger, as a build prints many lines at once.
+	buildBufferSize = 500
+)
 
 var (
 	ErrLoggerNotFound = errors.New("container logger not found")
@@ -26,10 +32,24 @@ var (
 type ContainerLogger struct {
 	uuid uuid.UUID
 
+	// mutex guards the file, the buffer and the level regex, which are
+	// accessed by the log goroutines, the requests and the daily cron.
+	mutex sync.Mutex
+
 	file        *os.File
 	buffer      []containerstypes.LogLine
 	currentLine int
 	scheduler   *gocron.Scheduler
+	levelRegex  *regexp.Regexp
+
+	// buildFile and buildBuffer keep the build lines apart from the
+	// runtime ones. The build file is opened at the first build line.
+	buildFile   *os.File
+	buildBuffer []containerstypes.LogLine
+
+	// lastTime is the time of the last line printed by the container
+	// itself, to detect the containers running but silent.
+	lastTime time.Time
 
 	dir string
 }
@@ -89,24 +109,33 @@ func (a *ContainerLogsFSAdapter) Register(uuid uuid.UUID) error {
 }
 
 func (a *ContainerLogsFSAdapter) Unregister(uuid uuid.UUID) error {
-	l, err := a.getLogger(uuid)
-	if err != nil {
-		return err
+	// The logger is removed first, so concurrent calls can't close it twice.
+	a.loggersMutex.Lock()
+	l, ok := a.loggers[uuid]
+	delete(a.loggers, uuid)
+	a.loggersMutex.Unlock()
+
+	if !ok {
+		return ErrLoggerNotFound
 	}
 
-	err = l.stopCron()
+	err := l.stopCron()
 	if err != nil {
 		return err
 	}
 
-	err = l.Close()
+	return l.Close()
+}
+
+func (a *ContainerLogsFSAdapter) SetLevelRegex(uuid uuid.UUID, levelRegex *regexp.Regexp) error {
+	l, err := a.getLogger(uuid)
 	if err != nil {
 		return err
 	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
 
-	a.loggersMutex.Lock()
-	defer a.loggersMutex.Unlock()
-	delete(a.loggers, uuid)
+	l.levelRegex = levelRegex
 	return nil
 }
 
@@ -116,23 +145,67 @@ func (a *ContainerLogsFSAdapter) Push(uuid uuid.UUID, line containerstypes.LogLi
 		log.Error(err)
 		return
 	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if line.Level == "" && line.Kind != containerstypes.LogKindDownloads {
+		line.Level = containerstypes.DetectLogLevel(line.Message.String(), l.levelRegex)
+	}
+	if line.Kind == containerstypes.LogKindBuild {
+		l.pushBuild(line)
+		return
+	}
+	if line.Kind == containerstypes.LogKindOut || line.Kind == containerstypes.LogKindErr {
+		l.lastTime = time.Now()
+	}
 	l.currentLine += 1
 	l.buffer = append(l.buffer, line)
 	if len(l.buffer) > bufferSize {
 		l.buffer = l.buffer[1:]
 	}
 
+	if l.file == nil {
+		return
+	}
+
 	_, err = fmt.Fprintf(l.file, "%s\n", line.Message.String())
 	if err != nil {
 		log.Error(err)
 	}
 }
 
+// pushBuild adds a line to the build buffer and file. The mutex must be held.
+func (l *ContainerLogger) pushBuild(line containerstypes.LogLine) {
+	l.buildBuffer = append(l.buildBuffer, line)
+	if len(l.buildBuffer) > buildBufferSize {
+		l.buildBuffer = l.buildBuffer[1:]
+	}
+
+	if l.buildFile == nil {
+		file, err := l.openFile("build")
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		l.buildFile = file
+	}
+
+	_, err := fmt.Fprintf(l.buildFile, "%s\n", line.Message.String())
+	if err != nil {
+		log.Error(err)
+	}
+}
+
 func (a *ContainerLogsFSAdapter) Pop(uuid uuid.UUID) (containerstypes.LogLine, error) {
 	l, err := a.getLogger(uuid)
 	if err != nil {
 		return containerstypes.LogLine{}, err
 	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
 	if len(l.buffer) == 0 {
 		return containerstypes.LogLine{}, containerstypes.ErrBufferEmpty
 	}
@@ -146,7 +219,42 @@ func (a *ContainerLogsFSAdapter) LoadBuffer(uuid uuid.UUID) ([]containerstypes.L
 	if err != nil {
 		return nil, err
 	}
-	return l.buffer, nil
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// The buffer is copied, as it keeps changing after this call.
+	buffer := make([]containerstypes.LogLine, len(l.buffer))
+	copy(buffer, l.buffer)
+	return buffer, nil
+}
+
+// LoadBuildBuffer returns the latest build lines kept in memory.
+func (a *ContainerLogsFSAdapter) LoadBuildBuffer(uuid uuid.UUID) ([]containerstypes.LogLine, error) {
+	l, err := a.getLogger(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	buffer := make([]containerstypes.LogLine, len(l.buildBuffer))
+	copy(buffer, l.buildBuffer)
+	return buffer, nil
+}
+
+// LastLogTime returns the time of the last line printed by the container,
+// or the zero time if it didn't print anything since Vertex started.
+func (a *ContainerLogsFSAdapter) LastLogTime(uuid uuid.UUID) (time.Time, error) {
+	l, err := a.getLogger(uuid)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.lastTime, nil
 }
 
 func (a *ContainerLogsFSAdapter) UnregisterAll() error {
@@ -159,7 +267,7 @@ func (a *ContainerLogsFSAdapter) UnregisterAll() error {
 	a.loggersMutex.RUnlock()
 
 	for _, id := range ids {
-		log.Info("unregistering container logger", vlog.String("uuid", id.String()))
+		log.Debug("unregistering container logger", vlog.String("uuid", id.String()))
 		err := a.Unregister(id)
 		if err != nil {
 			return err
@@ -185,37 +293,84 @@ func (a *ContainerLogsFSAdapter) dir(uuid uuid.UUID) string {
 }
 
 func (l *ContainerLogger) Open() error {
-	filename := fmt.Sprintf("logs_%s.txt", time.Now().Format(time.DateOnly))
-	filepath := path.Join(l.dir, filename)
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.open()
+}
+
+func (l *ContainerLogger) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.close()
+}
 
-	file, err := os.OpenFile(filepath, os.O_RDWR|os.O_CREATE|os.O_APPEND, os.ModePerm)
+// rotate closes the log files of the day, and opens new ones. The build
+// file is only opened again if a build was logged.
+func (l *ContainerLogger) rotate() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	build := l.buildFile != nil
+
+	err := l.close()
+	if err != nil {
+		return err
+	}
+	err = l.open()
+	if err != nil {
+		return err
+	}
+
+	if build {
+		l.buildFile, err = l.openFile("build")
+	}
+	return err
+}
+
+func (l *ContainerLogger) open() error {
+	file, err := l.openFile("logs")
 	if err != nil {
 		return err
 	}
 	l.file = file
-	log.Info("opened container logger", vlog.String("uuid", l.uuid.String()))
+	log.Debug("opened container logger", vlog.String("uuid", l.uuid.String()))
 	return nil
 }
 
-func (l *ContainerLogger) Close() error {
+// openFile opens the log file of the day with the prefix, like
+// logs_2006-01-02.txt.
+func (l *ContainerLogger) openFile(prefix string) (*os.File, error) {
+	filename := fmt.Sprintf("%s_%s.txt", prefix, time.Now().Format(time.DateOnly))
+	filepath := path.Join(l.dir, filename)
+	return os.OpenFile(filepath, os.O_RDWR|os.O_CREATE|os.O_APPEND, os.ModePerm)
+}
+
+func (l *ContainerLogger) close() error {
+	if l.buildFile != nil {
+		err := l.buildFile.Close()
+		if err != nil {
+			return err
+		}
+		l.buildFile = nil
+	}
+
+	if l.file == nil {
+		return nil
+	}
+
 	err := l.file.Close()
 	if err != nil {
 		return err
 	}
 	l.file = nil
-	log.Info("closed container logger", vlog.String("uuid", l.uuid.String()))
+	log.Debug("closed container logger", vlog.String("uuid", l.uuid.String()))
 	return nil
 }
 
 func (l *ContainerLogger) startCron() error {
 	l.scheduler = gocron.NewScheduler(time.Local)
 	_, err := l.scheduler.Every(1).Day().At("00:00").Do(func() {
-		err := l.Close()
-		if err != nil {
-			log.Error(err)
-			return
-		}
-		err = l.Open()
+		err := l.rotate()
 		if err != nil {
 			log.Error(err)
 		}
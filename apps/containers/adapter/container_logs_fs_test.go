@@ -1,12 +1,17 @@
 package adapter
 
 import (
-	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
 )
 
 type ContainerLoggerTestSuite struct {
@@ -203,3 +208,153 @@ func (suite *ContainerLogsFSAdapterTestSuite) TestPop() {
 	suite.NoError(err)
 	suite.Len(l.buffer, 0)
 }
+
+func (suite *ContainerLogsFSAdapterTestSuite) TestPushDetectsLevel() {
+	instID := uuid.New()
+
+	err := suite.adapter.Register(instID)
+	suite.NoError(err)
+	defer func() {
+		err := suite.adapter.Unregister(instID)
+		suite.NoError(err)
+	}()
+
+	suite.adapter.Push(instID, containerstypes.LogLine{
+		Kind:    containerstypes.LogKindOut,
+		Message: containerstypes.NewLogLineMessageString("[WARN] disk almost full"),
+	})
+
+	err = suite.adapter.SetLevelRegex(instID, regexp.MustCompile(`level=(\w+)`))
+	suite.NoError(err)
+
+	suite.adapter.Push(instID, containerstypes.LogLine{
+		Kind:    containerstypes.LogKindOut,
+		Message: containerstypes.NewLogLineMessageString("ts=0 level=error msg=failed"),
+	})
+
+	l, err := suite.adapter.getLogger(instID)
+	suite.NoError(err)
+	suite.Len(l.buffer, 2)
+	suite.Equal(containerstypes.LogLevelWarn, l.buffer[0].Level)
+	suite.Equal(containerstypes.LogLevelError, l.buffer[1].Level)
+	suite.Equal("[WARN] disk almost full", l.buffer[0].Message.String())
+}
+
+func (suite *ContainerLogsFSAdapterTestSuite) TestConcurrentAccess() {
+	instID := uuid.New()
+
+	err := suite.adapter.Register(instID)
+	suite.NoError(err)
+
+	l, err := suite.adapter.getLogger(instID)
+	suite.NoError(err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				suite.adapter.Push(instID, containerstypes.LogLine{
+					Kind:    containerstypes.LogKindOut,
+					Message: containerstypes.NewLogLineMessageString("line"),
+				})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = suite.adapter.LoadBuffer(instID)
+		}()
+		go func() {
+			defer wg.Done()
+			suite.NoError(l.rotate())
+		}()
+	}
+	wg.Wait()
+
+	buffer, err := suite.adapter.LoadBuffer(instID)
+	suite.NoError(err)
+	suite.Len(buffer, bufferSize)
+
+	err = suite.adapter.Unregister(instID)
+	suite.NoError(err)
+	suite.ErrorIs(suite.adapter.Unregister(instID), ErrLoggerNotFound)
+}
+
+func (suite *ContainerLogsFSAdapterTestSuite) TestLastLogTime() {
+	instID := uuid.New()
+
+	err := suite.adapter.Register(instID)
+	suite.NoError(err)
+	defer func() {
+		err := suite.adapter.Unregister(instID)
+		suite.NoError(err)
+	}()
+
+	// The lines from Vertex are not printed by the container.
+	suite.adapter.Push(instID, containerstypes.LogLine{
+		Kind:    containerstypes.LogKindVertexOut,
+		Message: &containerstypes.LogLineMessageString{Value: "Starting container..."},
+	})
+	lastTime, err := suite.adapter.LastLogTime(instID)
+	suite.NoError(err)
+	suite.True(lastTime.IsZero())
+
+	before := time.Now()
+	suite.adapter.Push(instID, containerstypes.LogLine{
+		Kind:    containerstypes.LogKindOut,
+		Message: &containerstypes.LogLineMessageString{Value: "test"},
+	})
+	lastTime, err = suite.adapter.LastLogTime(instID)
+	suite.NoError(err)
+	suite.False(lastTime.Before(before))
+}
+
+func (suite *ContainerLogsFSAdapterTestSuite) TestPushBuild() {
+	instID := uuid.New()
+
+	err := suite.adapter.Register(instID)
+	suite.NoError(err)
+	defer func() {
+		err := suite.adapter.Unregister(instID)
+		suite.NoError(err)
+	}()
+
+	suite.adapter.Push(instID, containerstypes.LogLine{
+		Kind:    containerstypes.LogKindBuild,
+		Message: containerstypes.NewLogLineMessageString("Step 1/2 : FROM alpine"),
+	})
+	suite.adapter.Push(instID, containerstypes.LogLine{
+		Kind:    containerstypes.LogKindOut,
+		Message: containerstypes.NewLogLineMessageString("started"),
+	})
+
+	// The build lines are not in the runtime logs.
+	buffer, err := suite.adapter.LoadBuffer(instID)
+	suite.NoError(err)
+	suite.Len(buffer, 1)
+	suite.Equal("started", buffer[0].Message.String())
+
+	build, err := suite.adapter.LoadBuildBuffer(instID)
+	suite.NoError(err)
+	suite.Len(build, 1)
+	suite.Equal("Step 1/2 : FROM alpine", build[0].Message.String())
+
+	// They are written to their own file.
+	l, err := suite.adapter.getLogger(instID)
+	suite.NoError(err)
+	suite.Require().NotNil(l.buildFile)
+	content, err := os.ReadFile(l.buildFile.Name())
+	suite.NoError(err)
+	suite.Equal("Step 1/2 : FROM alpine\n", string(content))
+	suite.Contains(l.buildFile.Name(), "build_")
+
+	// The build file is rotated with the runtime file.
+	previous := l.buildFile
+	suite.Require().NoError(l.rotate())
+	suite.Require().NotNil(l.buildFile)
+	suite.NotSame(previous, l.buildFile)
+	suite.Equal(fmt.Sprintf("build_%s.txt", time.Now().Format(time.DateOnly)), filepath.Base(l.buildFile.Name()))
+	_, err = previous.Stat()
+	suite.ErrorIs(err, os.ErrClosed)
+}
@@ -0,0 +1,48 @@
+package adapter
+
+import (
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/vertex-center/vertex/config"
+	"github.com/vertex-center/vertex/core/types"
+)
+
+// isDockerHub returns true if the image is hosted on Docker Hub, like
+// postgres or docker.io/library/postgres.
+func isDockerHub(image string) bool {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return false
+	}
+	return ref.Context().RegistryStr() == name.DefaultRegistry
+}
+
+// registryAuth returns the credentials to send to the kernel to pull the
+// image, or nil to pull it anonymously. Only the Docker Hub images are
+// authenticated with the credentials of the configuration.
+func registryAuth(image string) *types.RegistryAuth {
+	if !config.Current.HasDockerHubAuth() || !isDockerHub(image) {
+		return nil
+	}
+	return &types.RegistryAuth{
+		Username: config.Current.DockerHubUsername,
+		Password: config.Current.DockerHubToken,
+	}
+}
+
+// craneOptions returns the options to query the registry of the image. The
+// other registries than Docker Hub keep the default keychain of crane, which
+// reads the credentials of the Docker CLI.
+func craneOptions(image string) []crane.Option {
+	auth := registryAuth(image)
+	if auth == nil {
+		return nil
+	}
+	return []crane.Option{
+		crane.WithAuth(&authn.Basic{
+			Username: auth.Username,
+			Password: auth.Password,
+		}),
+	}
+}
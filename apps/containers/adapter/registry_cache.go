@@ -0,0 +1,62 @@
+package adapter
+
+import (
+	"sync"
+	"time"
+)
+
+// registryCache caches the responses of the image registries by key, to
+// avoid their rate limits. The anonymous pulls from Docker Hub are limited,
+// and the update checks query every image.
+type registryCache[T any] struct {
+	entries map[string]registryCacheEntry[T]
+	mutex   sync.Mutex
+
+	// now is replaced in the tests.
+	now func() time.Time
+}
+
+type registryCacheEntry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+func newRegistryCache[T any]() *registryCache[T] {
+	return &registryCache[T]{
+		entries: map[string]registryCacheEntry[T]{},
+		now:     time.Now,
+	}
+}
+
+// get returns the cached value of the key if it is younger than ttl, or
+// fetches it. The errors are not cached. A ttl of 0 disables the cache.
+func (c *registryCache[T]) get(key string, ttl time.Duration, fetch func() (T, error)) (T, error) {
+	c.mutex.Lock()
+	entry, ok := c.entries[key]
+	c.mutex.Unlock()
+
+	if ok && c.now().Before(entry.expires) {
+		return entry.value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+
+	if ttl > 0 {
+		c.mutex.Lock()
+		c.entries[key] = registryCacheEntry[T]{
+			value:   value,
+			expires: c.now().Add(ttl),
+		}
+		c.mutex.Unlock()
+	}
+	return value, nil
+}
+
+func (c *registryCache[T]) forget(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, key)
+}
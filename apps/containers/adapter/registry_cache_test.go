@@ -0,0 +1,74 @@
+package adapter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RegistryCacheTestSuite struct {
+	suite.Suite
+
+	cache   *registryCache[[]string]
+	now     time.Time
+	fetches int
+}
+
+func TestRegistryCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(RegistryCacheTestSuite))
+}
+
+func (suite *RegistryCacheTestSuite) SetupTest() {
+	suite.now = time.Now()
+	suite.fetches = 0
+	suite.cache = newRegistryCache[[]string]()
+	suite.cache.now = func() time.Time {
+		return suite.now
+	}
+}
+
+func (suite *RegistryCacheTestSuite) fetch() ([]string, error) {
+	suite.fetches++
+	return []string{"latest"}, nil
+}
+
+func (suite *RegistryCacheTestSuite) TestGet() {
+	for i := 0; i < 2; i++ {
+		tags, err := suite.cache.get("postgres", time.Minute, suite.fetch)
+		suite.NoError(err)
+		suite.Equal([]string{"latest"}, tags)
+	}
+	suite.Equal(1, suite.fetches)
+
+	// The entry expires after the ttl.
+	suite.now = suite.now.Add(2 * time.Minute)
+	_, err := suite.cache.get("postgres", time.Minute, suite.fetch)
+	suite.NoError(err)
+	suite.Equal(2, suite.fetches)
+
+	suite.cache.forget("postgres")
+	_, err = suite.cache.get("postgres", time.Minute, suite.fetch)
+	suite.NoError(err)
+	suite.Equal(3, suite.fetches)
+}
+
+func (suite *RegistryCacheTestSuite) TestGetDisabled() {
+	for i := 0; i < 2; i++ {
+		_, err := suite.cache.get("postgres", 0, suite.fetch)
+		suite.NoError(err)
+	}
+	suite.Equal(2, suite.fetches)
+}
+
+func (suite *RegistryCacheTestSuite) TestGetErrorNotCached() {
+	_, err := suite.cache.get("postgres", time.Minute, func() ([]string, error) {
+		return nil, errors.New("rate limited")
+	})
+	suite.Error(err)
+
+	_, err = suite.cache.get("postgres", time.Minute, suite.fetch)
+	suite.NoError(err)
+	suite.Equal(1, suite.fetches)
+}
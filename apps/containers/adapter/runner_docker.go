@@ -1,16 +1,20 @@
 package adapter
 
 import (
+	"archive/tar"
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
 	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
 	"github.com/vertex-center/vertex/core/types"
 	"github.com/vertex-center/vertex/core/types/api"
 	"io"
 	"net/http"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
@@ -21,21 +25,59 @@ import (
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/go-connections/nat"
 	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/config"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vertex/pkg/router"
 	"github.com/vertex-center/vertex/pkg/storage"
 	"github.com/vertex-center/vertex/pkg/vdocker"
 	"github.com/vertex-center/vlog"
+	"golang.org/x/exp/slices"
 )
 
-type ContainerRunnerDockerAdapter struct{}
+type ContainerRunnerDockerAdapter struct {
+	// watchers are the goroutines following the logs and the status of
+	// each running container. They are cancelled when the container is
+	// stopped or deleted.
+	watchers      map[uuid.UUID]*watcher
+	watchersMutex sync.Mutex
+
+	// containers caches the Docker containers by the UUID of their Vertex
+	// container, to avoid querying the kernel on every operation.
+	containers      map[uuid.UUID]types.Container
+	containersMutex sync.RWMutex
+
+	// tags caches the tags of the images, and digests the digests of the
+	// images by name with tag, fetched from the registries.
+	tags    *registryCache[[]string]
+	digests *registryCache[string]
+
+	// builds limits the images built or pulled at the same time. It is nil
+	// if there is no limit.
+	builds chan struct{}
+}
+
+type watcher struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
 
-func NewContainerRunnerFSAdapter() ContainerRunnerDockerAdapter {
-	return ContainerRunnerDockerAdapter{}
+func NewContainerRunnerFSAdapter() port.ContainerRunnerAdapter {
+	a := &ContainerRunnerDockerAdapter{
+		watchers:   map[uuid.UUID]*watcher{},
+		containers: map[uuid.UUID]types.Container{},
+		tags:       newRegistryCache[[]string](),
+		digests:    newRegistryCache[string](),
+	}
+	if n := config.Current.MaxConcurrentBuildsCount(); n > 0 {
+		a.builds = make(chan struct{}, n)
+	}
+	return a
 }
 
-func (a ContainerRunnerDockerAdapter) Delete(inst *containerstypes.Container) error {
+func (a *ContainerRunnerDockerAdapter) Delete(inst *containerstypes.Container) error {
+	a.unwatch(inst.UUID, nil)
+
 	id, err := a.getContainerID(*inst)
 	if err != nil {
 		return err
@@ -47,6 +89,7 @@ func (a ContainerRunnerDockerAdapter) Delete(inst *containerstypes.Container) er
 		Delete().
 		ErrorJSON(&apiError).
 		Fetch(context.Background())
+	a.forget(inst.UUID)
 
 	if apiError.Code == api.ErrContainerNotFound {
 		return ErrContainerNotFound
@@ -54,13 +97,27 @@ func (a ContainerRunnerDockerAdapter) Delete(inst *containerstypes.Container) er
 	return err
 }
 
-func (a ContainerRunnerDockerAdapter) Start(inst *containerstypes.Container, setStatus func(status string)) (io.ReadCloser, io.ReadCloser, error) {
+func (a *ContainerRunnerDockerAdapter) Start(inst *containerstypes.Container, env containerstypes.ContainerEnvVariables, setStatus func(status containerstypes.ContainerStatus), setPorts func(ports map[string]string)) (io.ReadCloser, io.ReadCloser, error) {
 	rErr, wErr := io.Pipe()
 	rOut, wOut := io.Pipe()
 
+	w := a.watch(inst.UUID)
+
 	go func() {
+		defer a.unwatch(inst.UUID, w)
+		defer wOut.Close()
+		defer wErr.Close()
+
 		imageName := inst.DockerImageVertexName()
 
+		release, err := a.acquireBuild(w.ctx, setStatus)
+		if err != nil {
+			// The build was cancelled while queued.
+			return
+		}
+		// The slot is released once the image is ready, or on failure.
+		defer release()
+
 		setStatus(containerstypes.ContainerStatusBuilding)
 
 		containerPath := a.getPath(*inst)
@@ -69,16 +126,19 @@ func (a ContainerRunnerDockerAdapter) Start(inst *containerstypes.Container, set
 		log.Debug("building image", vlog.String("image", imageName))
 
 		// Build
-		var err error
 		var stdout, stderr io.ReadCloser
 		if service.Methods.Docker.Dockerfile != nil {
-			stdout, err = a.buildImageFromDockerfile(containerPath, imageName)
+			stdout, err = a.buildImageFromDockerfile(w.ctx, containerPath, imageName)
 		} else if service.Methods.Docker.Image != nil {
-			stdout, err = a.buildImageFromName(inst.GetImageNameWithTag())
+			stdout, err = a.buildImageFromName(w.ctx, inst.GetImageNameWithTag())
 		} else {
 			err = errors.New("no Docker methods found")
 		}
-		if err != nil {
+		if w.ctx.Err() != nil {
+			// The build was cancelled through Vertex, which already
+			// takes care of the status.
+			return
+		} else if err != nil {
 			log.Error(err)
 			setStatus(containerstypes.ContainerStatusError)
 			return
@@ -102,6 +162,18 @@ func (a ContainerRunnerDockerAdapter) Start(inst *containerstypes.Container, set
 					continue
 				}
 
+				// The output of a Dockerfile build is kept apart
+				// from the download progress of its base images.
+				if msg.Stream != "" || msg.Error != nil {
+					err = writeBuildLines(wOut, msg)
+					if err != nil {
+						log.Error(err, vlog.String("uuid", inst.UUID.String()))
+						setStatus(containerstypes.ContainerStatusError)
+						return
+					}
+					continue
+				}
+
 				progress := containerstypes.DownloadProgress{
 					ID:     msg.ID,
 					Status: msg.Status,
@@ -129,7 +201,7 @@ func (a ContainerRunnerDockerAdapter) Start(inst *containerstypes.Container, set
 					return
 				}
 			}
-			if scanner.Err() != nil {
+			if scanner.Err() != nil && w.ctx.Err() == nil {
 				log.Error(scanner.Err(),
 					vlog.String("uuid", inst.UUID.String()))
 				setStatus(containerstypes.ContainerStatusError)
@@ -148,11 +220,28 @@ func (a ContainerRunnerDockerAdapter) Start(inst *containerstypes.Container, set
 		//	}
 		//}()
 
-		log.Info("waiting for image to be built", vlog.String("uuid", inst.UUID.String()))
+		log.Debug("waiting for image to be built", vlog.String("uuid", inst.UUID.String()))
 
 		wg.Wait()
 
+		if w.ctx.Err() != nil {
+			log.Info("image build cancelled", vlog.String("uuid", inst.UUID.String()))
+			return
+		}
+
 		log.Info("image built", vlog.String("uuid", inst.UUID.String()))
+		release()
+
+		// The env file is written at each start, so the changes of the
+		// environment don't need to recreate the container.
+		if inst.HasEnvFile() {
+			err = writeEnvFile(containerPath, dockerEnv(service, env))
+			if err != nil {
+				log.Error(err, vlog.String("uuid", inst.UUID.String()))
+				setStatus(containerstypes.ContainerStatusError)
+				return
+			}
+		}
 
 		// Create
 		id, err := a.getContainerID(*inst)
@@ -170,23 +259,12 @@ func (a ContainerRunnerDockerAdapter) Start(inst *containerstypes.Container, set
 				Binds:         []string{},
 				Env:           []string{},
 				CapAdd:        []string{},
+				Labels:        inst.DockerLabels(),
 			}
 
 			// exposedPorts and portBindings
 			if service.Methods.Docker.Ports != nil {
-				var all []string
-
-				for in, out := range *service.Methods.Docker.Ports {
-					for _, e := range service.Env {
-						if e.Type == "port" && e.Default == out {
-							out = inst.Env[e.Name]
-							all = append(all, out+":"+in)
-							break
-						}
-					}
-				}
-
-				options.ExposedPorts, options.PortBindings, err = nat.ParsePortSpecs(all)
+				options.ExposedPorts, options.PortBindings, err = nat.ParsePortSpecs(inst.PortSpecs(env))
 				if err != nil {
 					return
 				}
@@ -195,10 +273,17 @@ func (a ContainerRunnerDockerAdapter) Start(inst *containerstypes.Container, set
 			// binds
 			if service.Methods.Docker.Volumes != nil {
 				for source, target := range *service.Methods.Docker.Volumes {
-					if !strings.HasPrefix(source, "/") {
-						source, err = filepath.Abs(path.Join(containerPath, "volumes", source))
+					if name, ok := strings.CutPrefix(source, containerstypes.VolumePrefix); ok {
+						options.Volumes = append(options.Volumes, types.VolumeMount{
+							Name:   inst.DockerVolumeName(name),
+							Target: target,
+						})
+						continue
 					}
+					source, err = bindSource(containerPath, source, service.Methods.Docker.VolumesOwner)
 					if err != nil {
+						log.Error(err, vlog.String("uuid", inst.UUID.String()))
+						setStatus(containerstypes.ContainerStatusError)
 						return
 					}
 					options.Binds = append(options.Binds, source+":"+target)
@@ -206,11 +291,10 @@ func (a ContainerRunnerDockerAdapter) Start(inst *containerstypes.Container, set
 			}
 
 			// env
-			if service.Methods.Docker.Environment != nil {
-				for in, out := range *service.Methods.Docker.Environment {
-					value := inst.Env[out]
-					options.Env = append(options.Env, in+"="+value)
-				}
+			if inst.HasEnvFile() {
+				options.Binds = append(options.Binds, envFilePath(containerPath)+":"+*inst.EnvFile+":ro")
+			} else {
+				options.Env = dockerEnv(service, env)
 			}
 
 			// capAdd
@@ -223,8 +307,36 @@ func (a ContainerRunnerDockerAdapter) Start(inst *containerstypes.Container, set
 				options.Sysctls = *service.Methods.Docker.Sysctls
 			}
 
+			// extraHosts
+			if service.Methods.Docker.ExtraHosts != nil {
+				options.ExtraHosts = *service.Methods.Docker.ExtraHosts
+			}
+
+			// shmSize
+			options.ShmSize, err = service.Methods.Docker.ShmSizeBytes()
+			if err != nil {
+				log.Error(err, vlog.String("uuid", inst.UUID.String()))
+				setStatus(containerstypes.ContainerStatusError)
+				return
+			}
+
+			// init
+			if service.Methods.Docker.Init != nil {
+				options.Init = *service.Methods.Docker.Init
+			}
+
+			// network
+			if service.Methods.Docker.Network != nil {
+				options.Network = *service.Methods.Docker.Network
+				if service.Methods.Docker.NetworkAliases != nil {
+					options.NetworkAliases = *service.Methods.Docker.NetworkAliases
+				}
+			}
+
 			// cmd
-			if service.Methods.Docker.Cmd != nil {
+			if inst.Command != nil {
+				options.Cmd = strings.Split(*inst.Command, " ")
+			} else if service.Methods.Docker.Cmd != nil {
 				options.Cmd = strings.Split(*service.Methods.Docker.Cmd, " ")
 			}
 
@@ -235,6 +347,7 @@ func (a ContainerRunnerDockerAdapter) Start(inst *containerstypes.Container, set
 				options.ImageName = inst.GetImageNameWithTag()
 				id, err = a.createContainer(options)
 			}
+			a.forget(inst.UUID)
 			if err != nil {
 				return
 			}
@@ -242,79 +355,137 @@ func (a ContainerRunnerDockerAdapter) Start(inst *containerstypes.Container, set
 			return
 		}
 
+		// Files are copied at each start, so the changes are picked up
+		// without recreating the container.
+		if service.Methods.Docker.Files != nil {
+			err = a.copyFiles(inst.UUID, id, containerPath, *service.Methods.Docker.Files)
+			if err != nil {
+				log.Error(err, vlog.String("uuid", inst.UUID.String()))
+				setStatus(containerstypes.ContainerStatusError)
+				return
+			}
+		}
+
 		// Start
+		setStatus(containerstypes.ContainerStatusStarting)
 		err = requests.URL(config.Current.KernelURL()).
 			Pathf("/api/docker/container/%s/start", id).
 			Post().
 			Fetch(context.Background())
+		err = a.checkNotFound(inst.UUID, err)
 		if err != nil {
 			setStatus(containerstypes.ContainerStatusError)
 			return
 		}
+
+		// The ports picked by Docker are only known once started.
+		info, err := a.infoContainer(inst.UUID, id)
+		if err != nil {
+			log.Warn("failed to get the ports of the container",
+				vlog.String("uuid", inst.UUID.String()),
+				vlog.String("reason", err.Error()),
+			)
+		} else {
+			setPorts(hostPorts(info))
+		}
 		setStatus(containerstypes.ContainerStatusRunning)
 
-		stdout, stderr, err = a.readLogs(id)
+		stdout, stderr, err = a.readLogs(w.ctx, id, types.LogsContainerOptions{})
 		if err != nil {
 			return
 		}
 
+		var logsWg sync.WaitGroup
+		logsWg.Add(2)
 		go func() {
+			defer logsWg.Done()
 			defer stdout.Close()
-			defer wOut.Close()
-
-			_, err := io.Copy(wOut, stdout)
-			if err != nil {
-				log.Error(err)
-				return
-			}
+			a.copyLogs(w.ctx, wOut, stdout)
 		}()
-
 		go func() {
+			defer logsWg.Done()
 			defer stderr.Close()
-			defer wErr.Close()
-
-			_, err := io.Copy(wOut, stdout)
-			if err != nil {
-				log.Error(err)
-				return
-			}
+			a.copyLogs(w.ctx, wErr, stderr)
 		}()
 
-		err = a.WaitCondition(inst, types.WaitContainerCondition(container.WaitConditionNotRunning))
-		if err != nil {
+		err = a.waitCondition(w.ctx, id, types.WaitContainerCondition(container.WaitConditionNotRunning))
+		switch {
+		case w.ctx.Err() != nil:
+			// The container was stopped or deleted through Vertex,
+			// which already takes care of its status.
+		case err != nil:
 			log.Error(err)
 			setStatus(containerstypes.ContainerStatusError)
-		} else {
+		default:
 			setStatus(containerstypes.ContainerStatusOff)
 		}
+
+		logsWg.Wait()
 	}()
 
 	return rOut, rErr, nil
 }
 
-func (a ContainerRunnerDockerAdapter) Stop(inst *containerstypes.Container) error {
+// CancelBuild cancels the build or the pull of the image of the container
+// started by Start. The container is not created.
+func (a *ContainerRunnerDockerAdapter) CancelBuild(inst *containerstypes.Container) error {
+	a.unwatch(inst.UUID, nil)
+	return nil
+}
+
+func (a *ContainerRunnerDockerAdapter) Stop(inst *containerstypes.Container) error {
 	id, err := a.getContainerID(*inst)
 	if err != nil {
 		return err
 	}
 
-	return requests.URL(config.Current.KernelURL()).
+	err = requests.URL(config.Current.KernelURL()).
 		Pathf("/api/docker/container/%s/stop", id).
 		Post().
 		Fetch(context.Background())
+	err = a.checkNotFound(inst.UUID, err)
+	if err != nil {
+		return err
+	}
+
+	a.unwatch(inst.UUID, nil)
+	return nil
 }
 
-func (a ContainerRunnerDockerAdapter) Info(inst containerstypes.Container) (map[string]any, error) {
-	id, err := a.getContainerID(inst)
+// Pause freezes the processes of the container, without stopping it.
+func (a *ContainerRunnerDockerAdapter) Pause(inst *containerstypes.Container) error {
+	id, err := a.getContainerID(*inst)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var info types.InfoContainerResponse
 	err = requests.URL(config.Current.KernelURL()).
-		Pathf("/api/docker/container/%s/info", id).
-		ToJSON(&info).
+		Pathf("/api/docker/container/%s/pause", id).
+		Post().
 		Fetch(context.Background())
+	return a.checkNotFound(inst.UUID, err)
+}
+
+func (a *ContainerRunnerDockerAdapter) Unpause(inst *containerstypes.Container) error {
+	id, err := a.getContainerID(*inst)
+	if err != nil {
+		return err
+	}
+
+	err = requests.URL(config.Current.KernelURL()).
+		Pathf("/api/docker/container/%s/unpause", id).
+		Post().
+		Fetch(context.Background())
+	return a.checkNotFound(inst.UUID, err)
+}
+
+func (a *ContainerRunnerDockerAdapter) Info(inst containerstypes.Container) (map[string]any, error) {
+	id, err := a.getContainerID(inst)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := a.infoContainer(inst.UUID, id)
 	if err != nil {
 		return nil, err
 	}
@@ -328,46 +499,97 @@ func (a ContainerRunnerDockerAdapter) Info(inst containerstypes.Container) (map[
 		return nil, err
 	}
 
+	// The URLs use the ports bound right now, like the ones picked by
+	// Docker since the last start.
+	inst.Ports = hostPorts(info)
+
 	return map[string]any{
 		"container": info,
 		"image":     imageInfo,
+		"urls":      inst.WebURLs(config.Current.Host),
 	}, nil
 }
 
-func (a ContainerRunnerDockerAdapter) CheckForUpdates(inst *containerstypes.Container) error {
+func (a *ContainerRunnerDockerAdapter) CheckForUpdates(inst *containerstypes.Container) error {
 	service := inst.Service
 
-	if service.Methods.Docker.Image == nil {
-		// TODO: Support Dockerfile updates
+	if service.Methods.Docker.Dockerfile != nil {
+		return a.checkForRepositoryUpdates(inst)
+	} else if service.Methods.Docker.Image == nil {
 		return nil
 	}
 
-	imageName := inst.GetImageNameWithTag()
+	// Only the digest of the manifest is fetched from the registry, so no
+	// layer is downloaded until the update is applied.
+	image := inst.GetImageNameWithTag()
+	latestDigest, err := a.digests.get(image, config.Current.RegistryCacheDuration(), func() (string, error) {
+		return crane.Digest(image, craneOptions(image)...)
+	})
+	if err != nil {
+		return err
+	}
 
-	res, err := a.pullImage(imageName)
+	currentImageID, err := a.getImageID(*inst)
 	if err != nil {
 		return err
 	}
-	defer res.Close()
 
 	var imageInfo types.InfoImageResponse
 	err = requests.URL(config.Current.KernelURL()).
-		Pathf("/api/docker/%s/info", imageName).
+		Pathf("/api/docker/image/%s/info", currentImageID).
 		ToJSON(&imageInfo).
 		Fetch(context.Background())
 	if err != nil {
 		return err
 	}
 
-	latestImageID := imageInfo.ID
+	var currentDigest string
+	for _, repoDigest := range imageInfo.Digests {
+		_, digest, ok := strings.Cut(repoDigest, "@")
+		if !ok {
+			continue
+		}
+		currentDigest = digest
+		if digest == latestDigest {
+			break
+		}
+	}
 
-	currentImageID, err := a.getImageID(*inst)
+	// The images built or loaded locally have no digest from a registry,
+	// so they can't be compared with the latest one.
+	if currentDigest == "" {
+		log.Debug("the local image has no registry digest",
+			vlog.String("uuid", inst.UUID.String()),
+		)
+		inst.Update = nil
+		return nil
+	}
+
+	setUpdate(inst, currentDigest, latestDigest)
+	return nil
+}
+
+// checkForRepositoryUpdates compares the commit of the cloned repository
+// with the latest commit of its remote, for the images built from a
+// Dockerfile.
+func (a *ContainerRunnerDockerAdapter) checkForRepositoryUpdates(inst *containerstypes.Container) error {
+	if inst.Service.Methods.Docker.Clone == nil {
+		// The build context isn't a repository.
+		return nil
+	}
+
+	current, latest, err := storage.RepositoryHeads(a.getPath(*inst))
 	if err != nil {
 		return err
 	}
 
-	if latestImageID == currentImageID {
-		log.Info("already up-to-date",
+	setUpdate(inst, current, latest)
+	return nil
+}
+
+func setUpdate(inst *containerstypes.Container, current string, latest string) {
+	if current == latest {
+		log.Debug("already up-to-date",
 			vlog.String("uuid", inst.UUID.String()),
 		)
 		inst.Update = nil
@@ -376,68 +598,258 @@ func (a ContainerRunnerDockerAdapter) CheckForUpdates(inst *containerstypes.Cont
 			vlog.String("uuid", inst.UUID.String()),
 		)
 		inst.Update = &containerstypes.ContainerUpdate{
-			CurrentVersion: currentImageID,
-			LatestVersion:  latestImageID,
+			CurrentVersion: current,
+			LatestVersion:  latest,
 		}
 	}
+}
 
-	return nil
+func (a *ContainerRunnerDockerAdapter) ApplyUpdate(inst *containerstypes.Container) error {
+	docker := inst.Service.Methods.Docker
+	if docker.Dockerfile != nil {
+		if docker.Clone == nil {
+			return nil
+		}
+		// The image is built again from the pulled sources when the
+		// container is recreated.
+		return storage.CloneOrPullRepository(docker.Clone.Repository, a.getPath(*inst))
+	} else if docker.Image == nil {
+		return nil
+	}
+
+	// The update pulls share the slots of the builds. The status of the
+	// container is left to the update.
+	release, err := a.acquireBuild(context.Background(), func(status containerstypes.ContainerStatus) {})
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	res, err := a.pullImage(context.Background(), inst.GetImageNameWithTag())
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+
+	// The pull only ends when the whole stream is read, and the failures
+	// are reported in the stream.
+	decoder := json.NewDecoder(res)
+	for {
+		var msg jsonmessage.JSONMessage
+		err := decoder.Decode(&msg)
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if msg.Error != nil {
+			return msg.Error
+		}
+	}
 }
 
-func (a ContainerRunnerDockerAdapter) GetAllVersions(inst containerstypes.Container) ([]string, error) {
+func (a *ContainerRunnerDockerAdapter) GetAllVersions(inst containerstypes.Container) ([]string, error) {
 	if inst.Service.Methods.Docker == nil {
 		return nil, errors.New("no Docker methods found")
 	}
+	if inst.Service.Methods.Docker.Image == nil {
+		return nil, errors.New("the service is built from a Dockerfile, it has no image tags")
+	}
 	image := *inst.Service.Methods.Docker.Image
-	log.Debug("querying all versions of image",
-		vlog.String("image", image),
-	)
-	return crane.ListTags(image)
+	tags, err := a.tags.get(image, config.Current.RegistryCacheDuration(), func() ([]string, error) {
+		log.Debug("querying all versions of image",
+			vlog.String("image", image),
+		)
+		return crane.ListTags(image, craneOptions(image)...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The cached tags are shared, so the caller gets its own copy.
+	return slices.Clone(tags), nil
 }
 
-func (a ContainerRunnerDockerAdapter) HasUpdateAvailable(inst containerstypes.Container) (bool, error) {
+func (a *ContainerRunnerDockerAdapter) ForgetRegistryCache(inst containerstypes.Container) {
+	if inst.Service.Methods.Docker == nil || inst.Service.Methods.Docker.Image == nil {
+		return
+	}
+	a.tags.forget(*inst.Service.Methods.Docker.Image)
+	a.digests.forget(inst.GetImageNameWithTag())
+}
+
+func (a *ContainerRunnerDockerAdapter) HasUpdateAvailable(inst containerstypes.Container) (bool, error) {
 	//TODO implement me
 	return false, nil
 }
 
-func (a ContainerRunnerDockerAdapter) WaitCondition(inst *containerstypes.Container, cond types.WaitContainerCondition) error {
+func (a *ContainerRunnerDockerAdapter) WaitCondition(inst *containerstypes.Container, cond types.WaitContainerCondition) error {
 	id, err := a.getContainerID(*inst)
 	if err != nil {
 		return err
 	}
+	err = a.waitCondition(context.Background(), id, cond)
+	return a.checkNotFound(inst.UUID, err)
+}
 
+func (a *ContainerRunnerDockerAdapter) waitCondition(ctx context.Context, id string, cond types.WaitContainerCondition) error {
 	return requests.URL(config.Current.KernelURL()).
 		Pathf("/api/docker/container/%s/wait/%s", id, cond).
-		Fetch(context.Background())
+		Fetch(ctx)
 }
 
-func (a ContainerRunnerDockerAdapter) getContainer(inst containerstypes.Container) (types.Container, error) {
-	var containers []types.Container
-	err := requests.URL(config.Current.KernelURL()).
-		Path("/api/docker/containers").
-		ToJSON(&containers).
-		Fetch(context.Background())
+// acquireBuild waits for a slot to build or pull an image, with the
+// container queued if all the slots are taken. It returns the func
+// releasing the slot, which can be called more than once, or the error of
+// ctx if it is done first.
+func (a *ContainerRunnerDockerAdapter) acquireBuild(ctx context.Context, setStatus func(status containerstypes.ContainerStatus)) (func(), error) {
+	if a.builds == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case a.builds <- struct{}{}:
+	default:
+		setStatus(containerstypes.ContainerStatusQueued)
+		select {
+		case a.builds <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-a.builds })
+	}, nil
+}
+
+// watch registers the goroutines watching the container, and cancels the
+// previous ones if any.
+func (a *ContainerRunnerDockerAdapter) watch(uuid uuid.UUID) *watcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &watcher{ctx: ctx, cancel: cancel}
+
+	a.watchersMutex.Lock()
+	defer a.watchersMutex.Unlock()
+	if previous, ok := a.watchers[uuid]; ok {
+		previous.cancel()
+	}
+	a.watchers[uuid] = w
+	return w
+}
+
+// unwatch cancels the goroutines watching the container. If w is not nil,
+// they are cancelled only if they are still the ones registered.
+func (a *ContainerRunnerDockerAdapter) unwatch(uuid uuid.UUID, w *watcher) {
+	a.watchersMutex.Lock()
+	defer a.watchersMutex.Unlock()
+	if current, ok := a.watchers[uuid]; ok && (w == nil || current == w) {
+		current.cancel()
+		delete(a.watchers, uuid)
+	}
+	if w != nil {
+		w.cancel()
+	}
+}
+
+// copyLogs copies the logs until the end of the stream. The errors caused
+// by the watcher being cancelled are expected, and are not logged.
+func (a *ContainerRunnerDockerAdapter) copyLogs(ctx context.Context, dst io.Writer, src io.Reader) {
+	_, err := io.Copy(dst, src)
+	if err != nil && ctx.Err() == nil && !errors.Is(err, io.ErrClosedPipe) {
+		log.Error(err)
+	}
+}
+
+// getContainer returns the Docker container of inst, from the cache if
+// possible.
+func (a *ContainerRunnerDockerAdapter) getContainer(inst containerstypes.Container) (types.Container, error) {
+	a.containersMutex.RLock()
+	c, ok := a.containers[inst.UUID]
+	a.containersMutex.RUnlock()
+	if ok {
+		return c, nil
+	}
+
+	c, err := a.findContainer(inst)
 	if err != nil {
 		return types.Container{}, err
 	}
 
-	var dockerContainer *types.Container
-	for _, c := range containers {
-		name := c.Names[0]
-		if name == "/"+inst.DockerContainerName() {
-			dockerContainer = &c
-			break
+	a.containersMutex.Lock()
+	defer a.containersMutex.Unlock()
+	a.containers[inst.UUID] = c
+	return c, nil
+}
+
+// forget removes the Docker container of the given container from the
+// cache. It is called when the Docker container is created or deleted.
+func (a *ContainerRunnerDockerAdapter) forget(uuid uuid.UUID) {
+	a.containersMutex.Lock()
+	defer a.containersMutex.Unlock()
+	delete(a.containers, uuid)
+}
+
+// checkNotFound forgets the cached Docker container if the kernel doesn't
+// find it anymore, for instance if it was deleted outside of Vertex.
+func (a *ContainerRunnerDockerAdapter) checkNotFound(uuid uuid.UUID, err error) error {
+	if requests.HasStatusErr(err, http.StatusNotFound) {
+		a.forget(uuid)
+		return ErrContainerNotFound
+	}
+	return err
+}
+
+func (a *ContainerRunnerDockerAdapter) findContainer(inst containerstypes.Container) (types.Container, error) {
+	containers, err := a.listContainers(types.ListContainersOptions{
+		Labels: inst.DockerLabels(),
+	})
+	if err != nil {
+		return types.Container{}, err
+	}
+	if len(containers) > 0 {
+		return containers[0], nil
+	}
+
+	// The containers created before the labels were added are found by
+	// name. The Docker name filter also matches partial names.
+	name := inst.DockerContainerName()
+	containers, err = a.listContainers(types.ListContainersOptions{
+		Name: name,
+	})
+	if err != nil {
+		return types.Container{}, err
+	}
+	for i := range containers {
+		if len(containers[i].Names) > 0 && containers[i].Names[0] == "/"+name {
+			return containers[i], nil
 		}
 	}
 
-	if dockerContainer == nil {
-		return types.Container{}, ErrContainerNotFound
+	return types.Container{}, ErrContainerNotFound
+}
+
+func (a *ContainerRunnerDockerAdapter) listContainers(options types.ListContainersOptions) ([]types.Container, error) {
+	var labels []string
+	for key, value := range options.Labels {
+		labels = append(labels, key+"="+value)
 	}
 
-	return *dockerContainer, nil
+	req := requests.URL(config.Current.KernelURL()).
+		Path("/api/docker/containers")
+	if len(labels) > 0 {
+		req.Param("label", labels...)
+	}
+	if options.Name != "" {
+		req.Param("name", options.Name)
+	}
+
+	var containers []types.Container
+	err := req.ToJSON(&containers).Fetch(context.Background())
+	return containers, err
 }
 
-func (a ContainerRunnerDockerAdapter) getContainerID(inst containerstypes.Container) (string, error) {
+func (a *ContainerRunnerDockerAdapter) getContainerID(inst containerstypes.Container) (string, error) {
 	c, err := a.getContainer(inst)
 	if err != nil {
 		return "", err
@@ -445,7 +857,7 @@ func (a ContainerRunnerDockerAdapter) getContainerID(inst containerstypes.Contai
 	return c.ID, nil
 }
 
-func (a ContainerRunnerDockerAdapter) getImageID(inst containerstypes.Container) (string, error) {
+func (a *ContainerRunnerDockerAdapter) getImageID(inst containerstypes.Container) (string, error) {
 	c, err := a.getContainer(inst)
 	if err != nil {
 		return "", err
@@ -453,14 +865,17 @@ func (a ContainerRunnerDockerAdapter) getImageID(inst containerstypes.Container)
 	return c.ImageID, nil
 }
 
-func (a ContainerRunnerDockerAdapter) pullImage(imageName string) (io.ReadCloser, error) {
-	options := types.PullImageOptions{Image: imageName}
+func (a *ContainerRunnerDockerAdapter) pullImage(ctx context.Context, imageName string) (io.ReadCloser, error) {
+	options := types.PullImageOptions{
+		Image: imageName,
+		Auth:  registryAuth(imageName),
+	}
 
 	req, err := requests.URL(config.Current.KernelURL()).
 		Path("/api/docker/image/pull").
 		Post().
 		BodyJSON(options).
-		Request(context.Background())
+		Request(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -474,15 +889,15 @@ func (a ContainerRunnerDockerAdapter) pullImage(imageName string) (io.ReadCloser
 	return nil, errors.New("failed to pull image")
 }
 
-func (a ContainerRunnerDockerAdapter) buildImageFromName(imageName string) (io.ReadCloser, error) {
-	res, err := a.pullImage(imageName)
+func (a *ContainerRunnerDockerAdapter) buildImageFromName(ctx context.Context, imageName string) (io.ReadCloser, error) {
+	res, err := a.pullImage(ctx, imageName)
 	if err != nil {
 		return nil, err
 	}
 	return res, nil
 }
 
-func (a ContainerRunnerDockerAdapter) buildImageFromDockerfile(containerPath string, imageName string) (io.ReadCloser, error) {
+func (a *ContainerRunnerDockerAdapter) buildImageFromDockerfile(ctx context.Context, containerPath string, imageName string) (io.ReadCloser, error) {
 	options := types.BuildImageOptions{
 		Dir:        containerPath,
 		Name:       imageName,
@@ -493,21 +908,187 @@ func (a ContainerRunnerDockerAdapter) buildImageFromDockerfile(containerPath str
 		Pathf("/api/docker/image/build").
 		Post().
 		BodyJSON(options).
-		Request(context.Background())
+		Request(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	res, err := http.DefaultClient.Do(req)
 	if err != nil {
-		log.Error(err)
+		return nil, err
 	}
 	return res.Body, nil
 }
 
-func (a ContainerRunnerDockerAdapter) createContainer(options types.CreateContainerOptions) (string, error) {
-	var res types.CreateContainerResponse
+// writeBuildLines writes the lines of a build message, each prefixed with
+// BUILD for the runner to tell them from the runtime logs.
+func writeBuildLines(w io.Writer, msg jsonmessage.JSONMessage) error {
+	text := msg.Stream
+	if msg.Error != nil {
+		text = msg.Error.Message
+	}
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		_, err := fmt.Fprintf(w, "%s %s\n", "BUILD", line)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *ContainerRunnerDockerAdapter) infoContainer(uuid uuid.UUID, id string) (types.InfoContainerResponse, error) {
+	var info types.InfoContainerResponse
 	err := requests.URL(config.Current.KernelURL()).
+		Pathf("/api/docker/container/%s/info", id).
+		ToJSON(&info).
+		Fetch(context.Background())
+	err = a.checkNotFound(uuid, err)
+	return info, err
+}
+
+// hostPorts returns the first host port bound to each port of the container.
+func hostPorts(info types.InfoContainerResponse) map[string]string {
+	ports := map[string]string{}
+	for port, hostPorts := range info.Ports {
+		if len(hostPorts) > 0 {
+			ports[port] = hostPorts[0]
+		}
+	}
+	return ports
+}
+
+// dockerEnv returns the environment variables of the container, with the
+// names expected by its image.
+func dockerEnv(service containerstypes.Service, env containerstypes.ContainerEnvVariables) []string {
+	var vars []string
+	if service.Methods.Docker.Environment != nil {
+		for in, out := range *service.Methods.Docker.Environment {
+			vars = append(vars, in+"="+env[out])
+		}
+	}
+	return vars
+}
+
+// envFilePath is the path of the environment file mounted in the container.
+// It is not the .env of the container, which uses the names of the service.
+func envFilePath(containerPath string) string {
+	p, _ := filepath.Abs(path.Join(containerPath, ".vertex", "docker.env"))
+	return p
+}
+
+func writeEnvFile(containerPath string, vars []string) error {
+	p := envFilePath(containerPath)
+	err := os.MkdirAll(filepath.Dir(p), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	// The file holds secrets, so only the owner can read it.
+	content := strings.Join(vars, "\n") + "\n"
+	return os.WriteFile(p, []byte(content), 0600)
+}
+
+// bindSource returns the host path of a bind mount. The relative sources are
+// in the volumes directory of the container, and are created if needed, so
+// Docker doesn't create them as root. If owner is set, they are chowned to
+// it. The absolute sources are left as is.
+func bindSource(containerPath string, source string, owner *containerstypes.ServiceVolumesOwner) (string, error) {
+	if filepath.IsAbs(source) {
+		return source, nil
+	}
+
+	p, err := filepath.Abs(path.Join(containerPath, "volumes", source))
+	if err != nil {
+		return "", err
+	}
+	err = os.MkdirAll(p, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	if owner != nil {
+		// Vertex doesn't run as root, so this only works if the user is
+		// allowed to chown. The container may still be able to write.
+		err = os.Chown(p, owner.UID, owner.GID)
+		if err != nil {
+			log.Warn("failed to chown the volume",
+				vlog.String("path", p),
+				vlog.Int("uid", owner.UID),
+				vlog.Int("gid", owner.GID),
+				vlog.String("reason", err.Error()),
+			)
+		}
+	}
+	return p, nil
+}
+
+// copyFiles copies the files of the container directory into the Docker
+// container. The files are the keys of the map, relative to the container
+// directory, and the values are their absolute paths in the Docker container.
+func (a *ContainerRunnerDockerAdapter) copyFiles(uuid uuid.UUID, id string, containerPath string, files map[string]string) error {
+	for source, target := range files {
+		archive, err := fileArchive(path.Join(containerPath, source), path.Base(target))
+		if err != nil {
+			return err
+		}
+
+		err = requests.URL(config.Current.KernelURL()).
+			Pathf("/api/docker/container/%s/copy", id).
+			Param("path", path.Dir(target)).
+			Post().
+			BodyReader(archive).
+			Fetch(context.Background())
+		err = a.checkNotFound(uuid, err)
+		if err != nil {
+			return fmt.Errorf("failed to copy %s to %s: %w", source, target, err)
+		}
+	}
+	return nil
+}
+
+// fileArchive returns a tar archive containing the file at p, named name.
+func fileArchive(p string, name string) (io.Reader, error) {
+	content, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	err = w.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    int64(stat.Mode().Perm()),
+		Size:    int64(len(content)),
+		ModTime: stat.ModTime(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = w.Write(content)
+	if err != nil {
+		return nil, err
+	}
+	err = w.Close()
+	return &buf, err
+}
+
+func (a *ContainerRunnerDockerAdapter) createContainer(options types.CreateContainerOptions) (string, error) {
+	// Checked here too, since the kernel only replies with a status code.
+	err := types.ValidateSysctls(options.Sysctls)
+	if err != nil {
+		return "", err
+	}
+	err = types.ValidateExtraHosts(options.ExtraHosts)
+	if err != nil {
+		return "", err
+	}
+
+	var res types.CreateContainerResponse
+	err = requests.URL(config.Current.KernelURL()).
 		Pathf("/api/docker/container").
 		Post().
 		BodyJSON(options).
@@ -525,18 +1106,38 @@ func (a ContainerRunnerDockerAdapter) createContainer(options types.CreateContai
 	return res.ID, err
 }
 
-func (a ContainerRunnerDockerAdapter) readLogs(containerID string) (stdout io.ReadCloser, stderr io.ReadCloser, err error) {
+// FollowLogs follows the logs of the container with the options, until ctx
+// is done.
+func (a *ContainerRunnerDockerAdapter) FollowLogs(ctx context.Context, inst containerstypes.Container, options types.LogsContainerOptions) (stdout io.ReadCloser, stderr io.ReadCloser, err error) {
+	id, err := a.getContainerID(inst)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a.readLogs(ctx, id, options)
+}
+
+// readLogs follows the stdout and the stderr of the container, starting
+// with its last options.Tail lines.
+func (a *ContainerRunnerDockerAdapter) readLogs(ctx context.Context, containerID string, options types.LogsContainerOptions) (stdout io.ReadCloser, stderr io.ReadCloser, err error) {
+	logsRequest := func(name string) (*http.Request, error) {
+		req := requests.URL(config.Current.KernelURL()).
+			Pathf("/api/docker/container/%s/logs/%s", containerID, name)
+		if options.Tail != "" {
+			req = req.Param("tail", options.Tail)
+		}
+		if options.Timestamps {
+			req = req.Param("timestamps", "true")
+		}
+		return req.Request(ctx)
+	}
+
 	var reqStdout, reqStderr *http.Request
-	reqStdout, err = requests.URL(config.Current.KernelURL()).
-		Pathf("/api/docker/container/%s/logs/stdout", containerID).
-		Request(context.Background())
+	reqStdout, err = logsRequest("stdout")
 	if err != nil {
 		return
 	}
 
-	reqStderr, err = requests.URL(config.Current.KernelURL()).
-		Pathf("/api/docker/container/%s/logs/stderr", containerID).
-		Request(context.Background())
+	reqStderr, err = logsRequest("stderr")
 	if err != nil {
 		return
 	}
@@ -547,42 +1148,36 @@ func (a ContainerRunnerDockerAdapter) readLogs(containerID string) (stdout io.Re
 	go func() {
 		res, err := http.DefaultClient.Do(reqStdout)
 		if err != nil {
+			_ = wOut.CloseWithError(err)
 			return
 		}
 		defer res.Body.Close()
 
 		_, err = io.Copy(wOut, res.Body)
-		if err != nil {
-			return
-		}
+		_ = wOut.CloseWithError(err)
 	}()
 
 	go func() {
 		res, err := http.DefaultClient.Do(reqStderr)
 		if err != nil {
+			_ = wErr.CloseWithError(err)
 			return
 		}
 		defer res.Body.Close()
 
 		_, err = io.Copy(wErr, res.Body)
-		if err != nil {
-			return
-		}
+		_ = wErr.CloseWithError(err)
 	}()
 
 	return rOut, rErr, nil
 }
 
-func (a ContainerRunnerDockerAdapter) getPath(inst containerstypes.Container) string {
+func (a *ContainerRunnerDockerAdapter) getPath(inst containerstypes.Container) string {
 	base := storage.Path
 
 	// If Vertex is running itself inside Docker, the containers are stored in the Vertex container volume.
 	if vdocker.RunningInDocker() {
-		var containers []types.Container
-		err := requests.URL(config.Current.KernelURL()).
-			Path("/api/docker/containers").
-			ToJSON(&containers).
-			Fetch(context.Background())
+		containers, err := a.listContainers(types.ListContainersOptions{})
 		if err != nil {
 			log.Error(err)
 		} else {
@@ -0,0 +1,447 @@
+package adapter
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/config"
+	"github.com/vertex-center/vertex/core/types"
+)
+
+type ContainerRunnerDockerAdapterTestSuite struct {
+	suite.Suite
+
+	adapter *ContainerRunnerDockerAdapter
+	inst    *containerstypes.Container
+	kernel  *httptest.Server
+	config  config.Config
+
+	// listed counts the requests listing the containers.
+	listed atomic.Int32
+	// gone makes the kernel reply that the container doesn't exist.
+	gone atomic.Bool
+	// digests are the repository digests of the local image.
+	digests []string
+	// pullError is the error reported while pulling the image.
+	pullError string
+	// pulled are the options of the last pull.
+	pulled types.PullImageOptions
+}
+
+func TestContainerRunnerDockerAdapterTestSuite(t *testing.T) {
+	suite.Run(t, new(ContainerRunnerDockerAdapterTestSuite))
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) SetupTest() {
+	image := "alpine"
+	suite.inst = &containerstypes.Container{
+		UUID: uuid.New(),
+		Service: containerstypes.Service{
+			Methods: containerstypes.ServiceMethods{
+				Docker: &containerstypes.ServiceMethodDocker{
+					Image: &image,
+				},
+			},
+		},
+	}
+
+	suite.adapter = NewContainerRunnerFSAdapter().(*ContainerRunnerDockerAdapter)
+	suite.listed.Store(0)
+	suite.gone.Store(false)
+	suite.digests = nil
+	suite.pullError = ""
+	suite.pulled = types.PullImageOptions{}
+	suite.kernel = httptest.NewServer(http.HandlerFunc(suite.serveKernel))
+
+	host, port, err := net.SplitHostPort(suite.kernel.Listener.Addr().String())
+	suite.Require().NoError(err)
+
+	suite.config = config.Current
+	config.Current.HostKernel = host
+	config.Current.PortKernel = port
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TearDownTest() {
+	suite.kernel.Close()
+	config.Current = suite.config
+}
+
+// serveKernel mimics the Docker routes of the Vertex Kernel. The logs and
+// the wait routes block like they do while a container is running.
+func (suite *ContainerRunnerDockerAdapterTestSuite) serveKernel(w http.ResponseWriter, r *http.Request) {
+	p := r.URL.Path
+	switch {
+	case suite.gone.Load() && strings.HasPrefix(p, "/api/docker/container/"):
+		w.WriteHeader(http.StatusNotFound)
+	case r.Method == http.MethodDelete:
+		w.WriteHeader(http.StatusOK)
+	case p == "/api/docker/image/pull":
+		var options types.PullImageOptions
+		_ = json.NewDecoder(r.Body).Decode(&options)
+		suite.pulled = options
+		_, _ = w.Write([]byte(`{"status":"Pulling"}` + "\n"))
+		if suite.pullError != "" {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"errorDetail": map[string]string{"message": suite.pullError},
+			})
+		}
+	case p == "/api/docker/containers":
+		suite.listed.Add(1)
+		_ = json.NewEncoder(w).Encode([]types.Container{{
+			ID:      "container",
+			ImageID: "image",
+			Names:   []string{"/" + suite.inst.DockerContainerName()},
+		}})
+	case strings.HasPrefix(p, "/api/docker/image/") && strings.HasSuffix(p, "/info"):
+		_ = json.NewEncoder(w).Encode(types.InfoImageResponse{
+			ID:      "image",
+			Digests: suite.digests,
+		})
+	case strings.HasPrefix(p, "/api/docker/container/") && strings.HasSuffix(p, "/info"):
+		_ = json.NewEncoder(w).Encode(types.InfoContainerResponse{
+			ID:    "container",
+			Image: "image",
+			Ports: map[string][]string{"80/tcp": {"49153"}},
+		})
+	case strings.HasSuffix(p, "/start"), strings.HasSuffix(p, "/stop"):
+		w.WriteHeader(http.StatusOK)
+	case strings.Contains(p, "/logs/"):
+		_, _ = w.Write([]byte("line\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	case strings.Contains(p, "/wait/"):
+		<-r.Context().Done()
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// startStop starts the container, calls whileRunning if set once it runs
+// with the host ports reported, and stops it.
+func (suite *ContainerRunnerDockerAdapterTestSuite) startStop(whileRunning func(ports map[string]string)) {
+	running := make(chan struct{})
+	var once sync.Once
+	setStatus := func(status containerstypes.ContainerStatus) {
+		if status == containerstypes.ContainerStatusRunning {
+			once.Do(func() { close(running) })
+		}
+	}
+	// The ports are reported before the container is running.
+	var ports map[string]string
+	setPorts := func(p map[string]string) {
+		ports = p
+	}
+
+	stdout, stderr, err := suite.adapter.Start(suite.inst, containerstypes.ContainerEnvVariables{}, setStatus, setPorts)
+	suite.Require().NoError(err)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(io.Discard, stdout)
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(io.Discard, stderr)
+	}()
+
+	select {
+	case <-running:
+	case <-time.After(5 * time.Second):
+		suite.FailNow("the container never started")
+	}
+	if whileRunning != nil {
+		whileRunning(ports)
+	}
+
+	err = suite.adapter.Stop(suite.inst)
+	suite.Require().NoError(err)
+
+	// The logs must end once the container is stopped.
+	wg.Wait()
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TestStartStopReleasesGoroutines() {
+	suite.startStop(nil)
+	http.DefaultClient.CloseIdleConnections()
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 20; i++ {
+		suite.startStop(nil)
+	}
+
+	suite.Eventually(func() bool {
+		http.DefaultClient.CloseIdleConnections()
+		return runtime.NumGoroutine() <= before+5
+	}, 5*time.Second, 50*time.Millisecond)
+
+	suite.adapter.watchersMutex.Lock()
+	defer suite.adapter.watchersMutex.Unlock()
+	suite.Empty(suite.adapter.watchers)
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TestStartRecordsPorts() {
+	suite.startStop(func(ports map[string]string) {
+		suite.Equal(map[string]string{"80/tcp": "49153"}, ports)
+	})
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TestContainerCached() {
+	id, err := suite.adapter.getContainerID(*suite.inst)
+	suite.Require().NoError(err)
+	suite.Equal("container", id)
+
+	_, err = suite.adapter.getContainerID(*suite.inst)
+	suite.Require().NoError(err)
+	suite.Equal(int32(1), suite.listed.Load())
+
+	// Deleting the container invalidates the cache.
+	err = suite.adapter.Delete(suite.inst)
+	suite.Require().NoError(err)
+
+	_, err = suite.adapter.getContainerID(*suite.inst)
+	suite.Require().NoError(err)
+	suite.Equal(int32(2), suite.listed.Load())
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TestContainerNotFoundInvalidatesCache() {
+	_, err := suite.adapter.getContainerID(*suite.inst)
+	suite.Require().NoError(err)
+
+	// The container was deleted outside of Vertex.
+	suite.gone.Store(true)
+
+	err = suite.adapter.Stop(suite.inst)
+	suite.ErrorIs(err, ErrContainerNotFound)
+	suite.NotContains(suite.adapter.containers, suite.inst.UUID)
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TestCheckForUpdates() {
+	reg := httptest.NewServer(registry.New())
+	defer reg.Close()
+
+	image := strings.TrimPrefix(reg.URL, "http://") + "/vertex/test"
+	suite.inst.Service.Methods.Docker.Image = &image
+
+	img, err := random.Image(64, 1)
+	suite.Require().NoError(err)
+	err = crane.Push(img, image+":latest")
+	suite.Require().NoError(err)
+	digest, err := img.Digest()
+	suite.Require().NoError(err)
+
+	// The local image is the latest one.
+	suite.digests = []string{image + "@" + digest.String()}
+	err = suite.adapter.CheckForUpdates(suite.inst)
+	suite.Require().NoError(err)
+	suite.Nil(suite.inst.Update)
+
+	// A new image is published.
+	img, err = random.Image(64, 1)
+	suite.Require().NoError(err)
+	err = crane.Push(img, image+":latest")
+	suite.Require().NoError(err)
+	latest, err := img.Digest()
+	suite.Require().NoError(err)
+
+	// The digest is cached until it expires or is forgotten.
+	err = suite.adapter.CheckForUpdates(suite.inst)
+	suite.Require().NoError(err)
+	suite.Nil(suite.inst.Update)
+
+	suite.adapter.ForgetRegistryCache(*suite.inst)
+	err = suite.adapter.CheckForUpdates(suite.inst)
+	suite.Require().NoError(err)
+	suite.Equal(&containerstypes.ContainerUpdate{
+		CurrentVersion: digest.String(),
+		LatestVersion:  latest.String(),
+	}, suite.inst.Update)
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TestCheckForUpdatesNoLocalDigest() {
+	reg := httptest.NewServer(registry.New())
+	defer reg.Close()
+
+	image := strings.TrimPrefix(reg.URL, "http://") + "/vertex/test"
+	suite.inst.Service.Methods.Docker.Image = &image
+
+	img, err := random.Image(64, 1)
+	suite.Require().NoError(err)
+	err = crane.Push(img, image+":latest")
+	suite.Require().NoError(err)
+
+	// The local image was built locally, so its digest is unknown.
+	suite.digests = nil
+	err = suite.adapter.CheckForUpdates(suite.inst)
+	suite.Require().NoError(err)
+	suite.Nil(suite.inst.Update)
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TestApplyUpdate() {
+	err := suite.adapter.ApplyUpdate(suite.inst)
+	suite.NoError(err)
+
+	suite.pullError = "manifest unknown"
+	err = suite.adapter.ApplyUpdate(suite.inst)
+	suite.EqualError(err, "manifest unknown")
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TestApplyUpdateWaitsForSlot() {
+	suite.adapter.builds = make(chan struct{}, 1)
+
+	release, err := suite.adapter.acquireBuild(context.Background(), func(containerstypes.ContainerStatus) {})
+	suite.Require().NoError(err)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- suite.adapter.ApplyUpdate(suite.inst)
+	}()
+
+	// The pull waits until a build releases its slot.
+	select {
+	case <-done:
+		suite.Fail("the update pulled the image without a slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case err := <-done:
+		suite.NoError(err)
+	case <-time.After(5 * time.Second):
+		suite.Fail("the update didn't pull the image")
+	}
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TestApplyUpdateDockerHubAuth() {
+	config.Current.DockerHubUsername = "user"
+	config.Current.DockerHubToken = "token"
+
+	err := suite.adapter.ApplyUpdate(suite.inst)
+	suite.Require().NoError(err)
+	suite.Equal(&types.RegistryAuth{Username: "user", Password: "token"}, suite.pulled.Auth)
+
+	// The other registries don't get the Docker Hub credentials.
+	image := "ghcr.io/vertex-center/vertex"
+	suite.inst.Service.Methods.Docker.Image = &image
+	err = suite.adapter.ApplyUpdate(suite.inst)
+	suite.Require().NoError(err)
+	suite.Nil(suite.pulled.Auth)
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TestIsDockerHub() {
+	suite.True(isDockerHub("postgres"))
+	suite.True(isDockerHub("vertexcenter/vertex:latest"))
+	suite.True(isDockerHub("docker.io/library/postgres"))
+	suite.False(isDockerHub("ghcr.io/vertex-center/vertex"))
+	suite.False(isDockerHub("localhost:5000/vertex"))
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TestBindSource() {
+	dir := suite.T().TempDir()
+
+	source, err := bindSource(dir, "data", nil)
+	suite.Require().NoError(err)
+	suite.Equal(filepath.Join(dir, "volumes", "data"), source)
+	suite.DirExists(source)
+
+	source, err = bindSource(dir, "/var/run/docker.sock", nil)
+	suite.Require().NoError(err)
+	suite.Equal("/var/run/docker.sock", source)
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TestBindSourceOwner() {
+	dir := suite.T().TempDir()
+	owner := &containerstypes.ServiceVolumesOwner{
+		UID: os.Getuid(),
+		GID: os.Getgid(),
+	}
+
+	// Chowning to the current user is always allowed.
+	source, err := bindSource(dir, "data", owner)
+	suite.Require().NoError(err)
+	suite.DirExists(source)
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TestWriteEnvFile() {
+	dir := suite.T().TempDir()
+	suite.inst.Service.Methods.Docker.Environment = &map[string]string{
+		"POSTGRES_PASSWORD": "PASSWORD",
+	}
+	env := containerstypes.ContainerEnvVariables{"PASSWORD": "secret"}
+
+	err := writeEnvFile(dir, dockerEnv(suite.inst.Service, env))
+	suite.Require().NoError(err)
+
+	content, err := os.ReadFile(envFilePath(dir))
+	suite.Require().NoError(err)
+	suite.Equal("POSTGRES_PASSWORD=secret\n", string(content))
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TestFileArchive() {
+	dir := suite.T().TempDir()
+	err := os.WriteFile(filepath.Join(dir, "config.ini"), []byte("key=value"), 0644)
+	suite.Require().NoError(err)
+
+	archive, err := fileArchive(filepath.Join(dir, "config.ini"), "app.ini")
+	suite.Require().NoError(err)
+
+	r := tar.NewReader(archive)
+	header, err := r.Next()
+	suite.Require().NoError(err)
+	suite.Equal("app.ini", header.Name)
+
+	content, err := io.ReadAll(r)
+	suite.Require().NoError(err)
+	suite.Equal("key=value", string(content))
+
+	_, err = r.Next()
+	suite.ErrorIs(err, io.EOF)
+}
+
+func (suite *ContainerRunnerDockerAdapterTestSuite) TestAcquireBuild() {
+	suite.adapter.builds = make(chan struct{}, 1)
+
+	var status containerstypes.ContainerStatus
+	setStatus := func(s containerstypes.ContainerStatus) { status = s }
+
+	release, err := suite.adapter.acquireBuild(context.Background(), setStatus)
+	suite.Require().NoError(err)
+	suite.Empty(status)
+
+	// All the slots are taken, so the container is queued until cancelled.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = suite.adapter.acquireBuild(ctx, setStatus)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Equal(containerstypes.ContainerStatusQueued, status)
+
+	// Releasing twice frees the slot only once.
+	release()
+	release()
+	status = ""
+	release, err = suite.adapter.acquireBuild(context.Background(), setStatus)
+	suite.Require().NoError(err)
+	suite.Empty(status)
+	release()
+}
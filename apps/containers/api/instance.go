@@ -42,6 +42,19 @@ func PatchContainer(ctx context.Context, uuid uuid.UUID, settings types2.Contain
 	return api.HandleError(err, apiError)
 }
 
+func PlanContainerSettings(ctx context.Context, uuid uuid.UUID, body types2.ContainerSettingsPlanBody) (*types2.ContainerSettingsPlan, *api.Error) {
+	var plan types2.ContainerSettingsPlan
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Pathf("./container/%s/settings/plan", uuid).
+		Post().
+		BodyJSON(&body).
+		ToJSON(&plan).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return &plan, api.HandleError(err, apiError)
+}
+
 func StartContainer(ctx context.Context, uuid uuid.UUID) *api.Error {
 	var apiError api.Error
 	err := api.AppRequest(containers.AppRoute).
@@ -62,6 +75,38 @@ func StopContainer(ctx context.Context, uuid uuid.UUID) *api.Error {
 	return api.HandleError(err, apiError)
 }
 
+// CancelBuildContainer cancels the build or the pull of the image of a
+// container being started.
+func CancelBuildContainer(ctx context.Context, uuid uuid.UUID) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Pathf("./container/%s/build/cancel", uuid).
+		Post().
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
+
+func PauseContainer(ctx context.Context, uuid uuid.UUID) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Pathf("./container/%s/pause", uuid).
+		Post().
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
+
+func UnpauseContainer(ctx context.Context, uuid uuid.UUID) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Pathf("./container/%s/unpause", uuid).
+		Post().
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
+
 func PatchContainerEnvironment(ctx context.Context, uuid uuid.UUID, env map[string]string) *api.Error {
 	var apiError api.Error
 	err := api.AppRequest(containers.AppRoute).
@@ -73,6 +118,27 @@ func PatchContainerEnvironment(ctx context.Context, uuid uuid.UUID, env map[stri
 	return api.HandleError(err, apiError)
 }
 
+func ResetContainerEnvironment(ctx context.Context, uuid uuid.UUID) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Pathf("./container/%s/environment/reset", uuid).
+		Post().
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
+
+func DescribeContainer(ctx context.Context, uuid uuid.UUID) (*types2.ContainerDescription, *api.Error) {
+	var description types2.ContainerDescription
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Pathf("./container/%s/describe", uuid).
+		ToJSON(&description).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return &description, api.HandleError(err, apiError)
+}
+
 func GetDocker(ctx context.Context, uuid uuid.UUID) (map[string]any, *api.Error) {
 	var info map[string]any
 	var apiError api.Error
@@ -115,6 +181,16 @@ func UpdateServiceContainer(ctx context.Context, uuid uuid.UUID) *api.Error {
 	return api.HandleError(err, apiError)
 }
 
+func ApplyUpdate(ctx context.Context, uuid uuid.UUID) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Pathf("./container/%s/update/apply", uuid).
+		Post().
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
+
 func GetVersions(ctx context.Context, uuid uuid.UUID) ([]string, *api.Error) {
 	var versions []string
 	var apiError api.Error
@@ -126,6 +202,25 @@ func GetVersions(ctx context.Context, uuid uuid.UUID) ([]string, *api.Error) {
 	return versions, api.HandleError(err, apiError)
 }
 
+// ExportContainer returns the Docker configuration of the container as a
+// docker-compose.yml or a docker run command. The secrets are redacted
+// unless secrets is true.
+func ExportContainer(ctx context.Context, uuid uuid.UUID, format types2.ExportFormat, secrets bool) (string, *api.Error) {
+	var export string
+	var apiError api.Error
+	req := api.AppRequest(containers.AppRoute).
+		Pathf("./container/%s/export", uuid).
+		Param("format", string(format))
+	if secrets {
+		req = req.Param("secrets", "true")
+	}
+	err := req.
+		ToString(&export).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return export, api.HandleError(err, apiError)
+}
+
 func WaitCondition(ctx context.Context, uuid uuid.UUID, condition container.WaitCondition) *api.Error {
 	var apiError api.Error
 	err := api.AppRequest(containers.AppRoute).
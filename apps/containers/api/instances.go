@@ -2,6 +2,8 @@ package containersapi
 
 import (
 	"context"
+	"strconv"
+
 	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/containers"
 	"github.com/vertex-center/vertex/apps/containers/core/types"
@@ -19,8 +21,25 @@ func GetContainers(ctx context.Context) (map[uuid.UUID]*types.Container, *api.Er
 	return insts, api.HandleError(err, apiError)
 }
 
-func CheckForUpdates(ctx context.Context) ([]types.Container, *api.Error) {
-	var insts []types.Container
+func GetContainersPage(ctx context.Context, query types.ContainerListQuery) (*types.ContainersPage, *api.Error) {
+	var page types.ContainersPage
+	var apiError api.Error
+	req := api.AppRequest(containers.AppRoute).
+		Path("./containers/page").
+		Param("offset", strconv.Itoa(query.Offset)).
+		Param("limit", strconv.Itoa(query.Limit))
+	if query.Summary {
+		req = req.Param("view", "summary")
+	}
+	err := req.
+		ToJSON(&page).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return &page, api.HandleError(err, apiError)
+}
+
+func CheckForUpdates(ctx context.Context) (map[uuid.UUID]types.Container, *api.Error) {
+	var insts map[uuid.UUID]types.Container
 	var apiError api.Error
 	err := api.AppRequest(containers.AppRoute).
 		Path("./containers/checkupdates").
@@ -29,3 +48,19 @@ func CheckForUpdates(ctx context.Context) ([]types.Container, *api.Error) {
 		Fetch(ctx)
 	return insts, api.HandleError(err, apiError)
 }
+
+func InstallFromImage(ctx context.Context, image string, name string) (*types.Container, *api.Error) {
+	var inst *types.Container
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Path("./containers/image").
+		Post().
+		BodyJSON(&types.ImageInstall{
+			Image: image,
+			Name:  name,
+		}).
+		ToJSON(&inst).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return inst, api.HandleError(err, apiError)
+}
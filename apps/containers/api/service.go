@@ -29,3 +29,18 @@ func InstallService(ctx context.Context, serviceId string) (*types2.Container, *
 		Fetch(ctx)
 	return inst, api.HandleError(err, apiError)
 }
+
+// InstallServiceWith installs a service, with the settings and the
+// environment of the install applied before the container is ever started.
+func InstallServiceWith(ctx context.Context, serviceId string, install types2.ServiceInstall) (*types2.Container, *api.Error) {
+	var inst *types2.Container
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Pathf("./service/%s/install", serviceId).
+		Post().
+		BodyJSON(&install).
+		ToJSON(&inst).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return inst, api.HandleError(err, apiError)
+}
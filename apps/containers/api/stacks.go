@@ -0,0 +1,69 @@
+package containersapi
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/core/types/api"
+)
+
+func InstallStack(ctx context.Context, stack types.Stack) (map[string]uuid.UUID, *api.Error) {
+	var ids map[string]uuid.UUID
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Path("./stacks").
+		Post().
+		BodyJSON(&stack).
+		ToJSON(&ids).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return ids, api.HandleError(err, apiError)
+}
+
+func ImportCompose(ctx context.Context, name string, content string) (*types.ComposeImportResult, *api.Error) {
+	var res types.ComposeImportResult
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Path("./stacks/compose").
+		Post().
+		BodyJSON(&types.ComposeImport{
+			Name:    name,
+			Content: content,
+		}).
+		ToJSON(&res).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return &res, api.HandleError(err, apiError)
+}
+
+func StartStack(ctx context.Context, name string) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Pathf("./stack/%s/start", name).
+		Post().
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
+
+func StopStack(ctx context.Context, name string) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Pathf("./stack/%s/stop", name).
+		Post().
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
+
+func DeleteStack(ctx context.Context, name string) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(containers.AppRoute).
+		Pathf("./stack/%s", name).
+		Delete().
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
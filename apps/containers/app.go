@@ -1,15 +1,18 @@
 package containers
 
 import (
+	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/containers/adapter"
 	"github.com/vertex-center/vertex/apps/containers/core/port"
 	"github.com/vertex-center/vertex/apps/containers/core/service"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
 	"github.com/vertex-center/vertex/apps/containers/handler"
 	apptypes "github.com/vertex-center/vertex/core/types/app"
 	"github.com/vertex-center/vertex/pkg/router"
 )
 
 const (
+	AppID    = "vx-containers"
 	AppRoute = "/vx-containers"
 )
 
@@ -28,6 +31,7 @@ var (
 	containerServiceService  port.ContainerServiceService
 	containerSettingsService port.ContainerSettingsService
 	serviceService           port.ServiceService
+	stackService             port.StackService
 )
 
 type App struct {
@@ -38,6 +42,10 @@ func NewApp() *App {
 	return &App{}
 }
 
+func (a *App) ID() string {
+	return AppID
+}
+
 func (a *App) Initialize(app *apptypes.App) error {
 	a.App = app
 
@@ -50,7 +58,16 @@ func (a *App) Initialize(app *apptypes.App) error {
 
 	containerEnvService = service.NewContainerEnvService(containerEnvAdapter)
 	containerLogsService = service.NewContainerLogsService(app.Context(), containerLogsAdapter)
-	containerRunnerService = service.NewContainerRunnerService(app.Context(), containerRunnerAdapter)
+	containerRunnerService = service.NewContainerRunnerService(service.ContainerRunnerServiceParams{
+		Ctx:     app.Context(),
+		Adapter: containerRunnerAdapter,
+		GetContainer: func(uuid uuid.UUID) (*types.Container, error) {
+			return containerService.Get(uuid)
+		},
+		GetContainers: func() map[uuid.UUID]*types.Container {
+			return containerService.GetAll()
+		},
+	})
 	containerServiceService = service.NewContainerServiceService(containerServiceAdapter)
 	containerSettingsService = service.NewContainerSettingsService(containerSettingsAdapter)
 	containerService = service.NewContainerService(service.ContainerServiceParams{
@@ -62,10 +79,25 @@ func (a *App) Initialize(app *apptypes.App) error {
 		ContainerSettingsService: containerSettingsService,
 	})
 	serviceService = service.NewServiceService()
+	stackService = service.NewStackService(service.StackServiceParams{
+		ContainerService:         containerService,
+		ContainerEnvService:      containerEnvService,
+		ContainerRunnerService:   containerRunnerService,
+		ContainerSettingsService: containerSettingsService,
+		ServiceService:           serviceService,
+	})
 	service.NewMetricsService(app.Context())
+	service.NewContainerIdleService(service.ContainerIdleServiceParams{
+		Ctx:                    app.Context(),
+		ContainerRunnerService: containerRunnerService,
+		ContainerLogsService:   containerLogsService,
+		GetContainers: func() map[uuid.UUID]*types.Container {
+			return containerService.GetAll()
+		},
+	})
 
 	app.Register(apptypes.Meta{
-		ID:          "vx-containers",
+		ID:          AppID,
 		Name:        "Vertex Containers",
 		Description: "Create and manage containers.",
 		Icon:        "deployed_code",
@@ -86,24 +118,48 @@ func (a *App) Initialize(app *apptypes.App) error {
 		container.GET("", containerHandler.Get)
 		container.DELETE("", containerHandler.Delete)
 		container.PATCH("", containerHandler.Patch)
+		container.POST("/settings/plan", containerHandler.Plan)
 		container.POST("/start", containerHandler.Start)
 		container.POST("/stop", containerHandler.Stop)
+		container.POST("/build/cancel", containerHandler.CancelBuild)
+		container.POST("/pause", containerHandler.Pause)
+		container.POST("/unpause", containerHandler.Unpause)
 		container.PATCH("/environment", containerHandler.PatchEnvironment)
+		container.POST("/environment/reset", containerHandler.ResetEnvironment)
 		container.GET("/events", apptypes.HeadersSSE, containerHandler.Events)
+		container.GET("/events/ws", containerHandler.EventsWebSocket)
+		container.GET("/describe", containerHandler.Describe)
 		container.GET("/docker", containerHandler.GetDocker)
+		container.GET("/export", containerHandler.Export)
 		container.POST("/docker/recreate", containerHandler.RecreateDocker)
 		container.GET("/logs", containerHandler.GetLogs)
+		container.GET("/logs/build", containerHandler.GetBuildLogs)
+		container.GET("/logs/follow", apptypes.HeadersSSE, containerHandler.FollowLogs)
 		container.POST("/update/service", containerHandler.UpdateService)
+		container.POST("/update/apply", containerHandler.ApplyUpdate)
 		container.GET("/versions", containerHandler.GetVersions)
 		container.GET("/wait", containerHandler.Wait)
 
 		containersHandler := handler.NewContainersHandler(app.Context(), containerService)
 		containers := r.Group("/containers")
 		containers.GET("", containersHandler.Get)
+		containers.GET("/page", containersHandler.GetPage)
 		containers.GET("/tags", containersHandler.GetTags)
 		containers.GET("/search", containersHandler.Search)
 		containers.GET("/checkupdates", containersHandler.CheckForUpdates)
+		containers.POST("/image", containersHandler.InstallFromImage)
 		containers.GET("/events", apptypes.HeadersSSE, containersHandler.Events)
+		containers.GET("/events/ws", containersHandler.EventsWebSocket)
+		containers.GET("/logs", apptypes.HeadersSSE, containersHandler.Logs)
+		containers.GET("/logs/ws", containersHandler.LogsWebSocket)
+
+		stacksHandler := handler.NewStacksHandler(stackService)
+		r.POST("/stacks", stacksHandler.Install)
+		r.POST("/stacks/compose", stacksHandler.ImportCompose)
+		stack := r.Group("/stack/:stack_name")
+		stack.POST("/start", stacksHandler.Start)
+		stack.POST("/stop", stacksHandler.Stop)
+		stack.DELETE("", stacksHandler.Delete)
 
 		serviceHandler := handler.NewServiceHandler(serviceService, containerService)
 		serv := r.Group("/service/:service_id")
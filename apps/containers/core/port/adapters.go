@@ -1,10 +1,13 @@
 package port
 
 import (
+	"context"
 	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/containers/core/types"
 	types2 "github.com/vertex-center/vertex/core/types"
 	"io"
+	"regexp"
+	"time"
 )
 
 type ContainerAdapter interface {
@@ -34,23 +37,57 @@ type ContainerLogsAdapter interface {
 	Unregister(uuid uuid.UUID) error
 	UnregisterAll() error
 
+	// SetLevelRegex overrides the regex used by Push to detect the level
+	// of the log lines.
+	SetLevelRegex(uuid uuid.UUID, levelRegex *regexp.Regexp) error
+
 	Push(uuid uuid.UUID, line types.LogLine)
 	Pop(uuid uuid.UUID) (types.LogLine, error)
 
 	// LoadBuffer will load the latest logs kept in memory.
 	LoadBuffer(uuid uuid.UUID) ([]types.LogLine, error)
+
+	// LoadBuildBuffer loads the latest build logs kept in memory, which
+	// are not in the runtime logs.
+	LoadBuildBuffer(uuid uuid.UUID) ([]types.LogLine, error)
+
+	// LastLogTime returns the time of the last line printed by the
+	// container, or the zero time if it printed nothing yet.
+	LastLogTime(uuid uuid.UUID) (time.Time, error)
 }
 
 type ContainerRunnerAdapter interface {
 	Delete(inst *types.Container) error
-	Start(inst *types.Container, setStatus func(status string)) (stdout io.ReadCloser, stderr io.ReadCloser, err error)
+	// Start starts the container with the given environment, where the
+	// references to other containers are already resolved. setPorts is
+	// called with the host ports bound once the container runs.
+	Start(inst *types.Container, env types.ContainerEnvVariables, setStatus func(status types.ContainerStatus), setPorts func(ports map[string]string)) (stdout io.ReadCloser, stderr io.ReadCloser, err error)
+
+	// Stop stops the container. The host ports are cleared by the caller.
 	Stop(inst *types.Container) error
+
+	// CancelBuild cancels the build or the pull of the image started by
+	// Start, which then ends without creating the container.
+	CancelBuild(inst *types.Container) error
+	Pause(inst *types.Container) error
+	Unpause(inst *types.Container) error
 	Info(inst types.Container) (map[string]any, error)
+
+	// FollowLogs follows the logs of the container like docker logs -f,
+	// with the tail and the timestamps of the options, until ctx is done.
+	FollowLogs(ctx context.Context, inst types.Container, options types2.LogsContainerOptions) (stdout io.ReadCloser, stderr io.ReadCloser, err error)
 	WaitCondition(inst *types.Container, cond types2.WaitContainerCondition) error
 
 	CheckForUpdates(inst *types.Container) error
+	// ApplyUpdate downloads the update found by CheckForUpdates. The
+	// container must be recreated to use it.
+	ApplyUpdate(inst *types.Container) error
 	HasUpdateAvailable(inst types.Container) (bool, error)
 	GetAllVersions(inst types.Container) ([]string, error)
+
+	// ForgetRegistryCache forgets the tags and the digest of the image of
+	// the container, so they are fetched again from the registry.
+	ForgetRegistryCache(inst types.Container)
 }
 
 type ServiceAdapter interface {
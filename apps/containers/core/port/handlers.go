@@ -7,24 +7,48 @@ type (
 		Get(c *router.Context)
 		Delete(c *router.Context)
 		Patch(c *router.Context)
+		Plan(c *router.Context)
 		Start(c *router.Context)
 		Stop(c *router.Context)
+		CancelBuild(c *router.Context)
+		Pause(c *router.Context)
+		Unpause(c *router.Context)
 		PatchEnvironment(c *router.Context)
+		ResetEnvironment(c *router.Context)
+		Describe(c *router.Context)
 		GetDocker(c *router.Context)
+		Export(c *router.Context)
 		RecreateDocker(c *router.Context)
 		GetLogs(c *router.Context)
+		GetBuildLogs(c *router.Context)
+		FollowLogs(c *router.Context)
 		UpdateService(c *router.Context)
+		ApplyUpdate(c *router.Context)
 		GetVersions(c *router.Context)
 		Wait(c *router.Context)
 		Events(c *router.Context)
+		EventsWebSocket(c *router.Context)
 	}
 
 	ContainersHandler interface {
 		Get(c *router.Context)
+		GetPage(c *router.Context)
 		GetTags(c *router.Context)
 		Search(c *router.Context)
 		CheckForUpdates(c *router.Context)
+		InstallFromImage(c *router.Context)
 		Events(c *router.Context)
+		EventsWebSocket(c *router.Context)
+		Logs(c *router.Context)
+		LogsWebSocket(c *router.Context)
+	}
+
+	StacksHandler interface {
+		Install(c *router.Context)
+		ImportCompose(c *router.Context)
+		Start(c *router.Context)
+		Stop(c *router.Context)
+		Delete(c *router.Context)
 	}
 
 	ServiceHandler interface {
@@ -1,6 +1,10 @@
 package port
 
 import (
+	"context"
+	"io"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/containers/core/types"
 	vtypes "github.com/vertex-center/vertex/core/types"
@@ -10,6 +14,7 @@ type (
 	ContainerService interface {
 		Get(uuid uuid.UUID) (*types.Container, error)
 		GetAll() map[uuid.UUID]*types.Container
+		List(query types.ContainerListQuery) types.ContainersPage
 		GetTags() []string
 		Search(query types.ContainerSearchQuery) map[uuid.UUID]*types.Container
 		Exists(uuid uuid.UUID) bool
@@ -19,8 +24,22 @@ type (
 		LoadAll()
 		DeleteAll()
 		Install(service types.Service, method string) (*types.Container, error)
-		CheckForUpdates() (map[uuid.UUID]*types.Container, error)
+
+		// InstallWith installs a service like Install, with the settings and
+		// the environment of the install applied before the container is
+		// ever started.
+		InstallWith(service types.Service, method string, install types.ServiceInstall) (*types.Container, error)
+
+		// InstallFromImage installs a container running a Docker image, like
+		// nginx:latest, without a service definition.
+		InstallFromImage(image string, name string) (*types.Container, error)
+		CheckForUpdates(ctx context.Context) (map[uuid.UUID]*types.Container, error)
+		ForgetRegistryCache()
 		SetDatabases(inst *types.Container, databases map[string]uuid.UUID) error
+
+		// ResetEnv resets the environment of a stopped container to the
+		// defaults of its service.
+		ResetEnv(inst *types.Container) error
 	}
 
 	ContainerEnvService interface {
@@ -29,7 +48,17 @@ type (
 	}
 
 	ContainerLogsService interface {
-		GetLatestLogs(uuid uuid.UUID) ([]types.LogLine, error)
+		// GetLatestLogs returns the latest logs of a container. If minLevel is
+		// not empty, only the lines at least as severe are returned.
+		GetLatestLogs(uuid uuid.UUID, minLevel types.LogLevel) ([]types.LogLine, error)
+
+		// GetBuildLogs returns the latest lines of the builds of the
+		// Dockerfile of a container, which are not in the latest logs.
+		GetBuildLogs(uuid uuid.UUID) ([]types.LogLine, error)
+
+		// LastLogTime returns the time of the last line printed by the
+		// container, or the zero time if it printed nothing yet.
+		LastLogTime(uuid uuid.UUID) (time.Time, error)
 	}
 
 	ContainerRunnerService interface {
@@ -37,9 +66,37 @@ type (
 		Delete(inst *types.Container) error
 		Start(inst *types.Container) error
 		Stop(inst *types.Container) error
+
+		// CancelBuild cancels the build or the pull of the image of a
+		// container being started, and turns it back off. It returns
+		// ErrContainerNotBuilding if the container is not queued or building.
+		CancelBuild(inst *types.Container) error
+		Pause(inst *types.Container) error
+		Unpause(inst *types.Container) error
 		GetDockerContainerInfo(inst types.Container) (map[string]any, error)
+
+		// FollowLogs follows the logs of the container from Docker, with the
+		// tail and the timestamps of the options, until ctx is done.
+		FollowLogs(ctx context.Context, inst types.Container, options vtypes.LogsContainerOptions) (stdout io.ReadCloser, stderr io.ReadCloser, err error)
+
+		// Export returns the Docker configuration of the container as a
+		// docker-compose.yml or a docker run command. If redact is true, the
+		// secrets are redacted.
+		Export(inst *types.Container, format types.ExportFormat, redact bool) (string, error)
+
+		// GetAllVersions returns the tags of the image of the container,
+		// sorted with types.SortVersions.
 		GetAllVersions(inst *types.Container, useCache bool) ([]string, error)
+
+		// CheckVersion returns types.ErrVersionNotFound if the version is
+		// not a tag of the image of the container.
+		CheckVersion(inst *types.Container, version string) error
 		CheckForUpdates(inst *types.Container) error
+
+		// ForgetRegistryCache forgets the tags and the digest of the image
+		// of the container cached from its registry.
+		ForgetRegistryCache(inst *types.Container)
+		ApplyUpdate(inst *types.Container) error
 		RecreateContainer(inst *types.Container) error
 		WaitCondition(inst *types.Container, condition vtypes.WaitContainerCondition) error
 	}
@@ -55,14 +112,41 @@ type (
 		Save(inst *types.Container, settings types.ContainerSettings) error
 		Load(inst *types.Container) error
 		SetLaunchOnStartup(inst *types.Container, value bool) error
+		SetPinned(inst *types.Container, value bool) error
 		SetDisplayName(inst *types.Container, value string) error
 		SetDatabases(inst *types.Container, databases map[string]uuid.UUID) error
 		SetVersion(inst *types.Container, value string) error
 		SetTags(inst *types.Container, tags []string) error
+		SetCommand(inst *types.Container, command string) error
+
+		// SetEnvFile sets the path of the environment file in the
+		// container. An empty path passes the environment as variables.
+		SetEnvFile(inst *types.Container, value string) error
+
+		// SetIdleTimeout sets the minutes without activity after which the
+		// container is stopped. 0 disables the idle timeout.
+		SetIdleTimeout(inst *types.Container, minutes int) error
+		SetIdleAutoStart(inst *types.Container, value bool) error
+		SetProxyAutoRegister(inst *types.Container, value bool) error
 	}
 
 	MetricsService interface{}
 
+	ContainerIdleService interface{}
+
+	StackService interface {
+		// Install installs all the services of a stack, and returns the
+		// UUID of each container by service name.
+		Install(stack types.Stack) (map[string]uuid.UUID, error)
+
+		// ImportCompose installs the services of a docker-compose.yml file
+		// as a stack, with their dependencies first.
+		ImportCompose(name string, content []byte) (types.ComposeImportResult, error)
+		Start(name string) error
+		Stop(name string) error
+		Delete(name string) error
+	}
+
 	ServiceService interface {
 		GetAll() []types.Service
 		GetById(id string) (types.Service, error)
@@ -1,8 +1,13 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/vertex-center/vertex/apps/containers/core/port"
 	"github.com/vertex-center/vertex/apps/containers/core/types"
@@ -13,12 +18,16 @@ import (
 	"github.com/vertex-center/vertex/config"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vertex/pkg/net"
+	"github.com/vertex-center/vlog"
 )
 
 var (
 	ErrContainerAlreadyExists     = errors.New("container already exists")
 	ErrContainerAlreadyRunning    = errors.New("the container is already running")
 	ErrContainerNotRunning        = errors.New("the container is not running")
+	ErrContainerAlreadyPaused     = errors.New("the container is already paused")
+	ErrContainerNotPaused         = errors.New("the container is not paused")
+	ErrContainerNotBuilding       = errors.New("the image of the container is not being built or pulled")
 	ErrInstallMethodDoesNotExists = errors.New("this install method doesn't exist for this service")
 )
 
@@ -35,6 +44,13 @@ type ContainerService struct {
 
 	containers      map[uuid.UUID]*types.Container
 	containersMutex *sync.RWMutex
+
+	// checkUpdatesWorkers is the number of containers checked for
+	// updates at the same time.
+	checkUpdatesWorkers int
+	// checkUpdatesTimeout is the time after which CheckForUpdates gives
+	// up on the containers not checked yet.
+	checkUpdatesTimeout time.Duration
 }
 
 type ContainerServiceParams struct {
@@ -62,6 +78,9 @@ func NewContainerService(params ContainerServiceParams) port.ContainerService {
 
 		containers:      make(map[uuid.UUID]*types.Container),
 		containersMutex: &sync.RWMutex{},
+
+		checkUpdatesWorkers: 4,
+		checkUpdatesTimeout: 2 * time.Minute,
 	}
 
 	s.ctx.AddListener(s)
@@ -80,11 +99,56 @@ func (s *ContainerService) Get(uuid uuid.UUID) (*types.Container, error) {
 	return container, nil
 }
 
+// GetAll returns a copy of the map of the containers, so it can be ranged
+// over while containers are installed or deleted.
 func (s *ContainerService) GetAll() map[uuid.UUID]*types.Container {
 	s.containersMutex.RLock()
 	defer s.containersMutex.RUnlock()
 
-	return s.containers
+	containers := make(map[uuid.UUID]*types.Container, len(s.containers))
+	for id, inst := range s.containers {
+		containers[id] = inst
+	}
+	return containers
+}
+
+// List returns a page of the containers, sorted by name.
+func (s *ContainerService) List(query types.ContainerListQuery) types.ContainersPage {
+	s.containersMutex.RLock()
+	containers := make([]*types.Container, 0, len(s.containers))
+	for _, inst := range s.containers {
+		containers = append(containers, inst)
+	}
+	s.containersMutex.RUnlock()
+
+	sort.Slice(containers, func(i, j int) bool {
+		a, b := containers[i], containers[j]
+		if a.Name() != b.Name() {
+			return a.Name() < b.Name()
+		}
+		return a.UUID.String() < b.UUID.String()
+	})
+
+	page := types.ContainersPage{
+		Containers: []*types.Container{},
+		Total:      len(containers),
+	}
+
+	if query.Offset >= len(containers) {
+		return page
+	}
+	containers = containers[query.Offset:]
+	if query.Limit > 0 && query.Limit < len(containers) {
+		containers = containers[:query.Limit]
+	}
+
+	for _, inst := range containers {
+		if query.Summary {
+			inst = inst.Summary()
+		}
+		page.Containers = append(page.Containers, inst)
+	}
+	return page
 }
 
 func (s *ContainerService) GetTags() []string {
@@ -191,13 +255,14 @@ func (s *ContainerService) StartAll() {
 		return
 	}
 
-	log.Info("trying to ping Google...")
-
 	// Wait for internet connection
-	err := net.Wait("google.com:80")
-	if err != nil {
-		log.Error(err)
-		return
+	if config.Current.ConnectivityCheck != "" {
+		log.Info("checking the internet connection...", vlog.String("address", config.Current.ConnectivityCheck))
+
+		err := net.Wait(config.Current.ConnectivityCheck)
+		if err != nil {
+			log.Warn("no internet connection, starting the containers anyway", vlog.String("reason", err.Error()))
+		}
 	}
 
 	// Start them
@@ -263,6 +328,10 @@ func (s *ContainerService) DeleteAll() {
 }
 
 func (s *ContainerService) Install(service types.Service, method string) (*types.Container, error) {
+	return s.InstallWith(service, method, types.ServiceInstall{})
+}
+
+func (s *ContainerService) InstallWith(service types.Service, method string, install types.ServiceInstall) (*types.Container, error) {
 	id := uuid.New()
 	err := s.containerAdapter.Create(id)
 	if err != nil {
@@ -295,12 +364,24 @@ func (s *ContainerService) Install(service types.Service, method string) (*types
 	}
 
 	inst.ContainerSettings.InstallMethod = &method
+	if install.DisplayName != nil {
+		inst.ContainerSettings.DisplayName = *install.DisplayName
+	}
+	if install.Tags != nil {
+		inst.ContainerSettings.Tags = install.Tags
+	}
+	if install.Command != nil {
+		inst.ContainerSettings.Command = install.Command
+	}
 	err = s.containerSettingsService.Save(inst, inst.ContainerSettings)
 	if err != nil {
 		return nil, err
 	}
 
 	inst.ResetDefaultEnv()
+	for name, value := range install.Environment {
+		inst.Env[name] = value
+	}
 	err = s.containerEnvService.Save(inst, inst.Env)
 	if err != nil {
 		return nil, err
@@ -312,15 +393,130 @@ func (s *ContainerService) Install(service types.Service, method string) (*types
 	return inst, nil
 }
 
-func (s *ContainerService) CheckForUpdates() (map[uuid.UUID]*types.Container, error) {
-	for _, inst := range s.GetAll() {
-		err := s.containerRunnerService.CheckForUpdates(inst)
-		if err != nil {
-			return s.GetAll(), err
+// InstallFromImage installs a container running the image with Docker,
+// from a minimal service synthesized with types.NewImageService. The tag of
+// the image becomes the version of the container.
+func (s *ContainerService) InstallFromImage(image string, name string) (*types.Container, error) {
+	imageName, tag, err := types.ParseImage(image)
+	if err != nil {
+		return nil, err
+	}
+
+	inst, err := s.Install(types.NewImageService(imageName, strings.TrimSpace(name)), "docker")
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.containerSettingsService.SetVersion(inst, tag)
+	if err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+// ResetEnv resets the environment of the container to the defaults of its
+// service. If the container is still running, it returns
+// ErrContainerStillRunning, so it doesn't restart unexpectedly.
+func (s *ContainerService) ResetEnv(inst *types.Container) error {
+	if inst.IsRunning() {
+		return types.ErrContainerStillRunning
+	}
+
+	inst.ResetDefaultEnv()
+	err := s.containerEnvService.Save(inst, inst.Env)
+	if err != nil {
+		return err
+	}
+
+	// The environment is only applied when the Docker container is
+	// created, so it is deleted to be recreated at the next start.
+	err = s.containerRunnerService.Delete(inst)
+	if err != nil && !errors.Is(err, adapter.ErrContainerNotFound) {
+		return err
+	}
+	return nil
+}
+
+// ForgetRegistryCache forgets the tags and the digests of the images of all
+// the containers, so the next update check queries the registries.
+func (s *ContainerService) ForgetRegistryCache() {
+	s.containersMutex.RLock()
+	defer s.containersMutex.RUnlock()
+
+	for _, inst := range s.containers {
+		s.containerRunnerService.ForgetRegistryCache(inst)
+	}
+}
+
+// CheckForUpdates checks all the containers for updates, a few at a time. It
+// returns the containers checked successfully, even if some checks failed or
+// didn't finish before the timeout. The pinned containers are returned
+// without being checked.
+func (s *ContainerService) CheckForUpdates(ctx context.Context) (map[uuid.UUID]*types.Container, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.checkUpdatesTimeout)
+	defer cancel()
+
+	checked := map[uuid.UUID]*types.Container{}
+
+	s.containersMutex.RLock()
+	var containers []*types.Container
+	for _, inst := range s.containers {
+		if inst.Pinned {
+			checked[inst.UUID] = inst
+			continue
+		}
+		containers = append(containers, inst)
+	}
+	s.containersMutex.RUnlock()
+
+	type result struct {
+		inst *types.Container
+		err  error
+	}
+
+	jobs := make(chan *types.Container)
+	// The results are buffered, so the workers never block once the
+	// timeout is reached.
+	results := make(chan result, len(containers))
+
+	for i := 0; i < s.checkUpdatesWorkers; i++ {
+		go func() {
+			for inst := range jobs {
+				results <- result{
+					inst: inst,
+					err:  s.containerRunnerService.CheckForUpdates(inst),
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, inst := range containers {
+			select {
+			case jobs <- inst:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var errs []error
+	for i := 0; i < len(containers); i++ {
+		select {
+		case r := <-results:
+			if r.err != nil {
+				errs = append(errs, fmt.Errorf("container %s: %w", r.inst.UUID, r.err))
+				continue
+			}
+			checked[r.inst.UUID] = r.inst
+		case <-ctx.Done():
+			errs = append(errs, fmt.Errorf("%d containers not checked: %w", len(containers)-i, ctx.Err()))
+			return checked, errors.Join(errs...)
 		}
 	}
 
-	return s.GetAll(), nil
+	return checked, errors.Join(errs...)
 }
 
 func (s *ContainerService) load(uuid uuid.UUID) error {
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+// idleCheckInterval is the interval between two checks of the idle containers.
+const idleCheckInterval = time.Minute
+
+// ContainerIdleService stops the containers without activity for longer than
+// their idle timeout. The activity is the requests received through the
+// reverse proxy and the logs of the container.
+type ContainerIdleService struct {
+	uuid uuid.UUID
+	ctx  *app.Context
+
+	containerRunnerService port.ContainerRunnerService
+	containerLogsService   port.ContainerLogsService
+	getContainers          func() map[uuid.UUID]*types.Container
+
+	// activity is the time of the last request received by each container,
+	// or of its start if it didn't receive any since.
+	activity map[uuid.UUID]time.Time
+	// idleStopped are the containers stopped by this service, which are
+	// started again by the next request if they have idle auto-start.
+	idleStopped map[uuid.UUID]bool
+	mutex       sync.Mutex
+
+	done chan struct{}
+}
+
+type ContainerIdleServiceParams struct {
+	Ctx                    *app.Context
+	ContainerRunnerService port.ContainerRunnerService
+	ContainerLogsService   port.ContainerLogsService
+	GetContainers          func() map[uuid.UUID]*types.Container
+}
+
+func NewContainerIdleService(params ContainerIdleServiceParams) port.ContainerIdleService {
+	s := &ContainerIdleService{
+		uuid:                   uuid.New(),
+		ctx:                    params.Ctx,
+		containerRunnerService: params.ContainerRunnerService,
+		containerLogsService:   params.ContainerLogsService,
+		getContainers:          params.GetContainers,
+		activity:               map[uuid.UUID]time.Time{},
+		idleStopped:            map[uuid.UUID]bool{},
+	}
+	s.ctx.AddListener(s)
+	return s
+}
+
+func (s *ContainerIdleService) GetUUID() uuid.UUID {
+	return s.uuid
+}
+
+func (s *ContainerIdleService) OnEvent(e interface{}) {
+	switch e := e.(type) {
+	case vtypes.EventServerStart:
+		s.done = make(chan struct{})
+		go s.monitor(s.done)
+	case vtypes.EventServerStop:
+		if s.done != nil {
+			close(s.done)
+			s.done = nil
+		}
+	case types.EventContainerStatusChange:
+		if e.Status == types.ContainerStatusRunning {
+			s.mutex.Lock()
+			s.activity[e.ContainerUUID] = time.Now()
+			delete(s.idleStopped, e.ContainerUUID)
+			s.mutex.Unlock()
+		}
+	case types.EventContainerActivity:
+		s.onActivity(e.ContainerUUID, time.Now())
+	case types.EventContainerDeleted:
+		s.mutex.Lock()
+		delete(s.activity, e.ContainerUUID)
+		delete(s.idleStopped, e.ContainerUUID)
+		s.mutex.Unlock()
+	}
+}
+
+func (s *ContainerIdleService) monitor(done chan struct{}) {
+	ticker := time.NewTicker(idleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			s.stopIdle(now)
+		}
+	}
+}
+
+// onActivity records a request received by the container, and starts it
+// again if it was stopped for being idle.
+func (s *ContainerIdleService) onActivity(id uuid.UUID, now time.Time) {
+	s.mutex.Lock()
+	s.activity[id] = now
+	restart := s.idleStopped[id]
+	delete(s.idleStopped, id)
+	s.mutex.Unlock()
+
+	inst, ok := s.getContainers()[id]
+	if !restart || !ok || !inst.IdleAutoStart || inst.IsRunning() {
+		return
+	}
+
+	log.Info("starting idle container on request", vlog.String("uuid", id.String()))
+	go func() {
+		err := s.containerRunnerService.Start(inst)
+		if err != nil {
+			log.Error(err, vlog.String("uuid", id.String()))
+		}
+	}()
+}
+
+// stopIdle stops the running containers without activity since longer than
+// their idle timeout.
+func (s *ContainerIdleService) stopIdle(now time.Time) {
+	for id, inst := range s.getContainers() {
+		if inst.IdleTimeout <= 0 || inst.Status != types.ContainerStatusRunning {
+			continue
+		}
+
+		last := s.lastActivity(id)
+		if last.IsZero() || now.Sub(last) < time.Duration(inst.IdleTimeout)*time.Minute {
+			continue
+		}
+
+		log.Info("stopping idle container",
+			vlog.String("uuid", id.String()),
+			vlog.String("last_activity", last.String()),
+		)
+
+		err := s.containerRunnerService.Stop(inst)
+		if err != nil {
+			log.Error(err, vlog.String("uuid", id.String()))
+			continue
+		}
+
+		s.mutex.Lock()
+		s.idleStopped[id] = true
+		s.mutex.Unlock()
+	}
+}
+
+// lastActivity returns the time of the last request or log line of the
+// container, whichever is the latest.
+func (s *ContainerIdleService) lastActivity(id uuid.UUID) time.Time {
+	s.mutex.Lock()
+	last := s.activity[id]
+	s.mutex.Unlock()
+
+	lastLog, err := s.containerLogsService.LastLogTime(id)
+	if err == nil && lastLog.After(last) {
+		last = lastLog
+	}
+	return last
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	types2 "github.com/vertex-center/vertex/apps/containers/core/types"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+)
+
+type ContainerIdleServiceTestSuite struct {
+	suite.Suite
+	service *ContainerIdleService
+
+	container types2.Container
+	lastLog   time.Time
+	stopped   chan uuid.UUID
+	started   chan uuid.UUID
+}
+
+func TestContainerIdleServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ContainerIdleServiceTestSuite))
+}
+
+func (suite *ContainerIdleServiceTestSuite) SetupTest() {
+	suite.container = types2.Container{
+		UUID:   uuid.New(),
+		Status: types2.ContainerStatusRunning,
+		ContainerSettings: types2.ContainerSettings{
+			IdleTimeout:   10,
+			IdleAutoStart: true,
+		},
+	}
+	suite.lastLog = time.Time{}
+	suite.stopped = make(chan uuid.UUID, 1)
+	suite.started = make(chan uuid.UUID, 1)
+
+	suite.service = NewContainerIdleService(ContainerIdleServiceParams{
+		Ctx: app.NewContext(vtypes.NewVertexContext()),
+		ContainerRunnerService: &fakeRunnerService{
+			start: func(inst *types2.Container) error {
+				inst.Status = types2.ContainerStatusRunning
+				suite.started <- inst.UUID
+				return nil
+			},
+			stop: func(inst *types2.Container) error {
+				inst.Status = types2.ContainerStatusOff
+				suite.stopped <- inst.UUID
+				return nil
+			},
+		},
+		ContainerLogsService: &fakeLogsService{lastLogTime: func() time.Time {
+			return suite.lastLog
+		}},
+		GetContainers: func() map[uuid.UUID]*types2.Container {
+			return map[uuid.UUID]*types2.Container{suite.container.UUID: &suite.container}
+		},
+	}).(*ContainerIdleService)
+}
+
+func (suite *ContainerIdleServiceTestSuite) TestStopIdle() {
+	now := time.Now()
+	suite.service.onActivity(suite.container.UUID, now)
+
+	suite.service.stopIdle(now.Add(5 * time.Minute))
+	suite.Empty(suite.stopped)
+
+	// The logs count as activity too.
+	suite.lastLog = now.Add(8 * time.Minute)
+	suite.service.stopIdle(now.Add(15 * time.Minute))
+	suite.Empty(suite.stopped)
+
+	suite.service.stopIdle(now.Add(20 * time.Minute))
+	suite.Equal(suite.container.UUID, <-suite.stopped)
+}
+
+func (suite *ContainerIdleServiceTestSuite) TestStopIdleDisabled() {
+	now := time.Now()
+	suite.container.IdleTimeout = 0
+	suite.service.onActivity(suite.container.UUID, now)
+
+	suite.service.stopIdle(now.Add(time.Hour))
+	suite.Empty(suite.stopped)
+}
+
+func (suite *ContainerIdleServiceTestSuite) TestAutoStart() {
+	now := time.Now()
+	suite.service.onActivity(suite.container.UUID, now)
+	suite.service.stopIdle(now.Add(time.Hour))
+	<-suite.stopped
+
+	suite.service.onActivity(suite.container.UUID, now.Add(2*time.Hour))
+	select {
+	case id := <-suite.started:
+		suite.Equal(suite.container.UUID, id)
+	case <-time.After(5 * time.Second):
+		suite.Fail("the container was not started")
+	}
+}
+
+type fakeLogsService struct {
+	port.ContainerLogsService
+	lastLogTime func() time.Time
+}
+
+func (f *fakeLogsService) LastLogTime(uuid uuid.UUID) (time.Time, error) {
+	return f.lastLogTime(), nil
+}
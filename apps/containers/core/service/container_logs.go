@@ -1,6 +1,8 @@
 package service
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/containers/core/port"
 	"github.com/vertex-center/vertex/apps/containers/core/types"
@@ -21,6 +23,25 @@ func NewContainerLogsService(ctx *app.Context, adapter port.ContainerLogsAdapter
 	return s
 }
 
-func (s *ContainerLogsService) GetLatestLogs(uuid uuid.UUID) ([]types.LogLine, error) {
-	return s.adapter.LoadBuffer(uuid)
+func (s *ContainerLogsService) GetLatestLogs(uuid uuid.UUID, minLevel types.LogLevel) ([]types.LogLine, error) {
+	logs, err := s.adapter.LoadBuffer(uuid)
+	if err != nil || minLevel == "" {
+		return logs, err
+	}
+
+	var filtered []types.LogLine
+	for _, line := range logs {
+		if line.Level.IsAtLeast(minLevel) {
+			filtered = append(filtered, line)
+		}
+	}
+	return filtered, nil
+}
+
+func (s *ContainerLogsService) GetBuildLogs(uuid uuid.UUID) ([]types.LogLine, error) {
+	return s.adapter.LoadBuildBuffer(uuid)
+}
+
+func (s *ContainerLogsService) LastLogTime(uuid uuid.UUID) (time.Time, error) {
+	return s.adapter.LastLogTime(uuid)
 }
@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	types2 "github.com/vertex-center/vertex/apps/containers/core/types"
+	"regexp"
 
 	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/pkg/log"
@@ -18,21 +19,22 @@ func (s *ContainerLogsService) OnEvent(e interface{}) {
 	case types2.EventContainerLog:
 		s.onLogReceived(e)
 	case types2.EventContainerLoaded:
-		log.Info("registering container logs", vlog.String("uuid", e.Container.UUID.String()))
+		log.Debug("registering container logs", vlog.String("uuid", e.Container.UUID.String()))
 		err := s.adapter.Register(e.Container.UUID)
 		if err != nil {
 			log.Error(err)
 			return
 		}
+		s.setLevelRegex(e.Container)
 	case types2.EventContainerDeleted:
-		log.Info("unregistering container logs", vlog.String("uuid", e.ContainerUUID.String()))
+		log.Debug("unregistering container logs", vlog.String("uuid", e.ContainerUUID.String()))
 		err := s.adapter.Unregister(e.ContainerUUID)
 		if err != nil {
 			log.Error(err)
 			return
 		}
 	case types2.EventContainersStopped:
-		log.Info("unregistering all container logs")
+		log.Debug("unregistering all container logs")
 		err := s.adapter.UnregisterAll()
 		if err != nil {
 			log.Error(err)
@@ -69,3 +71,21 @@ func (s *ContainerLogsService) onLogReceived(e types2.EventContainerLog) {
 		})
 	}
 }
+
+func (s *ContainerLogsService) setLevelRegex(inst *types2.Container) {
+	logs := inst.Service.Logs
+	if logs == nil || logs.LevelRegex == nil {
+		return
+	}
+
+	levelRegex, err := regexp.Compile(*logs.LevelRegex)
+	if err != nil {
+		log.Error(err, vlog.String("uuid", inst.UUID.String()))
+		return
+	}
+
+	err = s.adapter.SetLevelRegex(inst.UUID, levelRegex)
+	if err != nil {
+		log.Error(err)
+	}
+}
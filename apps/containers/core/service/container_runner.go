@@ -2,9 +2,15 @@ package service
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"sync"
 
@@ -18,17 +24,45 @@ import (
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vertex/pkg/storage"
 	"github.com/vertex-center/vlog"
+	"golang.org/x/exp/slices"
 )
 
 type ContainerRunnerService struct {
 	ctx     *app.Context
 	adapter port.ContainerRunnerAdapter
+
+	// getContainer is used to resolve the environment references
+	// to other containers.
+	getContainer func(uuid uuid.UUID) (*types2.Container, error)
+
+	// getContainers is used to find the ports already bound by the other
+	// containers. It returns a copy of the map, safe to range over while
+	// containers are installed or deleted.
+	getContainers func() map[uuid.UUID]*types2.Container
+
+	// isPortFree checks that no other process of the host binds a port.
+	isPortFree func(port string) bool
+
+	// statusMutex serializes the status changes, which come from the
+	// requests and from the goroutines watching the containers.
+	statusMutex sync.Mutex
+}
+
+type ContainerRunnerServiceParams struct {
+	Ctx     *app.Context
+	Adapter port.ContainerRunnerAdapter
+
+	GetContainer  func(uuid uuid.UUID) (*types2.Container, error)
+	GetContainers func() map[uuid.UUID]*types2.Container
 }
 
-func NewContainerRunnerService(ctx *app.Context, adapter port.ContainerRunnerAdapter) port.ContainerRunnerService {
+func NewContainerRunnerService(params ContainerRunnerServiceParams) port.ContainerRunnerService {
 	return &ContainerRunnerService{
-		ctx:     ctx,
-		adapter: adapter,
+		ctx:           params.Ctx,
+		adapter:       params.Adapter,
+		getContainer:  params.GetContainer,
+		getContainers: params.GetContainers,
+		isPortFree:    isPortFree,
 	}
 }
 
@@ -37,9 +71,9 @@ func (s *ContainerRunnerService) Install(uuid uuid.UUID, service types2.Service)
 		return ErrInstallMethodDoesNotExists
 	}
 
-	dir := path.Join(storage.Path, uuid.String())
+	dir := containerPath(uuid)
 	if service.Methods.Docker.Clone != nil {
-		err := storage.CloneRepository(dir, service.Methods.Docker.Clone.Repository)
+		err := storage.CloneRepository(service.Methods.Docker.Clone.Repository, dir)
 		if err != nil {
 			return err
 		}
@@ -79,11 +113,49 @@ func (s *ContainerRunnerService) Start(inst *types2.Container) error {
 		return ErrContainerAlreadyRunning
 	}
 
-	setStatus := func(status string) {
+	env, err := inst.Env.Resolve(s.getContainer)
+	if err != nil {
+		s.ctx.DispatchEvent(types2.EventContainerLog{
+			ContainerUUID: inst.UUID,
+			Kind:          types2.LogKindVertexErr,
+			Message:       types2.NewLogLineMessageString(fmt.Sprintf("Failed to resolve the environment: %s", err.Error())),
+		})
+		s.setStatus(inst, types2.ContainerStatusError)
+		return err
+	}
+
+	err = s.checkPorts(inst, env)
+	if err != nil {
+		s.ctx.DispatchEvent(types2.EventContainerLog{
+			ContainerUUID: inst.UUID,
+			Kind:          types2.LogKindVertexErr,
+			Message:       types2.NewLogLineMessageString(err.Error()),
+		})
+		s.setStatus(inst, types2.ContainerStatusError)
+		return err
+	}
+
+	if inst.Service.Hooks != nil && inst.Service.Hooks.PreStart != nil {
+		err = s.runHook(inst, containerPath(inst.UUID), "pre-start", *inst.Service.Hooks.PreStart, env)
+		if err != nil {
+			s.ctx.DispatchEvent(types2.EventContainerLog{
+				ContainerUUID: inst.UUID,
+				Kind:          types2.LogKindVertexErr,
+				Message:       types2.NewLogLineMessageString(err.Error()),
+			})
+			s.setStatus(inst, types2.ContainerStatusError)
+			return err
+		}
+	}
+
+	setStatus := func(status types2.ContainerStatus) {
 		s.setStatus(inst, status)
 	}
+	setPorts := func(ports map[string]string) {
+		s.setPorts(inst, ports)
+	}
 
-	stdout, stderr, err := s.adapter.Start(inst, setStatus)
+	stdout, stderr, err := s.adapter.Start(inst, env, setStatus, setPorts)
 	if err != nil {
 		s.setStatus(inst, types2.ContainerStatusError)
 		return err
@@ -119,6 +191,15 @@ func (s *ContainerRunnerService) Start(inst *types2.Container) error {
 				continue
 			}
 
+			if msg, ok := strings.CutPrefix(scanner.Text(), "BUILD "); ok {
+				s.ctx.DispatchEvent(types2.EventContainerLog{
+					ContainerUUID: inst.UUID,
+					Kind:          types2.LogKindBuild,
+					Message:       types2.NewLogLineMessageString(msg),
+				})
+				continue
+			}
+
 			s.ctx.DispatchEvent(types2.EventContainerLog{
 				ContainerUUID: inst.UUID,
 				Kind:          types2.LogKindOut,
@@ -160,6 +241,31 @@ func (s *ContainerRunnerService) Start(inst *types2.Container) error {
 	return nil
 }
 
+// CancelBuild cancels the build or the pull of the image of a container
+// being started, or queued to be, and turns it back off.
+func (s *ContainerRunnerService) CancelBuild(inst *types2.Container) error {
+	if inst.Status != types2.ContainerStatusQueued && inst.Status != types2.ContainerStatusBuilding {
+		return ErrContainerNotBuilding
+	}
+
+	err := s.adapter.CancelBuild(inst)
+	if err != nil {
+		return err
+	}
+
+	s.ctx.DispatchEvent(types2.EventContainerLog{
+		ContainerUUID: inst.UUID,
+		Kind:          types2.LogKindVertexOut,
+		Message:       types2.NewLogLineMessageString("Build cancelled."),
+	})
+	log.Info("container build cancelled",
+		vlog.String("uuid", inst.UUID.String()),
+	)
+
+	s.setStatus(inst, types2.ContainerStatusOff)
+	return nil
+}
+
 // Stop stops an container by its UUID.
 // If the container does not exist, it returns ErrContainerNotFound.
 // If the container is not running, it returns ErrContainerNotRunning.
@@ -177,10 +283,14 @@ func (s *ContainerRunnerService) Stop(inst *types2.Container) error {
 		return ErrContainerNotRunning
 	}
 
+	// A paused container can be stopped too, and goes back to its
+	// previous status if it fails.
+	previous := inst.Status
 	s.setStatus(inst, types2.ContainerStatusStopping)
 
 	err := s.adapter.Stop(inst)
 	if err == nil {
+		s.setPorts(inst, nil)
 		s.ctx.DispatchEvent(types2.EventContainerLog{
 			ContainerUUID: inst.UUID,
 			Kind:          types2.LogKindVertexOut,
@@ -192,33 +302,166 @@ func (s *ContainerRunnerService) Stop(inst *types2.Container) error {
 		)
 
 		s.setStatus(inst, types2.ContainerStatusOff)
+
+		// The container is stopped anyway, so a failing hook is only logged.
+		if inst.Service.Hooks != nil && inst.Service.Hooks.PostStop != nil {
+			env, err := inst.Env.Resolve(s.getContainer)
+			if err == nil {
+				err = s.runHook(inst, containerPath(inst.UUID), "post-stop", *inst.Service.Hooks.PostStop, env)
+			}
+			if err != nil {
+				s.ctx.DispatchEvent(types2.EventContainerLog{
+					ContainerUUID: inst.UUID,
+					Kind:          types2.LogKindVertexErr,
+					Message:       types2.NewLogLineMessageString(err.Error()),
+				})
+			}
+		}
 	} else {
-		s.setStatus(inst, types2.ContainerStatusRunning)
+		s.setStatus(inst, previous)
 	}
 
 	return err
 }
 
+// Pause freezes a running container, to free its CPU without losing its state.
+// If the container is not running, it returns ErrContainerNotRunning.
+// If the container is already paused, it returns ErrContainerAlreadyPaused.
+func (s *ContainerRunnerService) Pause(inst *types2.Container) error {
+	if inst.IsBusy() {
+		return nil
+	}
+
+	if !inst.IsRunning() {
+		return ErrContainerNotRunning
+	} else if inst.IsPaused() {
+		return ErrContainerAlreadyPaused
+	}
+
+	err := s.adapter.Pause(inst)
+	if err != nil {
+		return err
+	}
+
+	s.ctx.DispatchEvent(types2.EventContainerLog{
+		ContainerUUID: inst.UUID,
+		Kind:          types2.LogKindVertexOut,
+		Message:       types2.NewLogLineMessageString("Container paused."),
+	})
+	s.setStatus(inst, types2.ContainerStatusPaused)
+	return nil
+}
+
+// Unpause resumes a paused container.
+// If the container is not paused, it returns ErrContainerNotPaused.
+func (s *ContainerRunnerService) Unpause(inst *types2.Container) error {
+	if !inst.IsPaused() {
+		return ErrContainerNotPaused
+	}
+
+	err := s.adapter.Unpause(inst)
+	if err != nil {
+		return err
+	}
+
+	s.ctx.DispatchEvent(types2.EventContainerLog{
+		ContainerUUID: inst.UUID,
+		Kind:          types2.LogKindVertexOut,
+		Message:       types2.NewLogLineMessageString("Container unpaused."),
+	})
+	s.setStatus(inst, types2.ContainerStatusRunning)
+	return nil
+}
+
 func (s *ContainerRunnerService) GetDockerContainerInfo(inst types2.Container) (map[string]any, error) {
 	return s.adapter.Info(inst)
 }
 
+func (s *ContainerRunnerService) FollowLogs(ctx context.Context, inst types2.Container, options vtypes.LogsContainerOptions) (io.ReadCloser, io.ReadCloser, error) {
+	return s.adapter.FollowLogs(ctx, inst, options)
+}
+
+// Export returns the Docker configuration of the container in the format,
+// with the relative volumes in the volumes directory of the container.
+func (s *ContainerRunnerService) Export(inst *types2.Container, format types2.ExportFormat, redact bool) (string, error) {
+	volumesDir, err := filepath.Abs(path.Join(containerPath(inst.UUID), "volumes"))
+	if err != nil {
+		return "", err
+	}
+	return inst.Export(format, volumesDir, redact, s.getContainer)
+}
+
 func (s *ContainerRunnerService) GetAllVersions(inst *types2.Container, useCache bool) ([]string, error) {
+	if !useCache {
+		s.adapter.ForgetRegistryCache(*inst)
+	}
 	if !useCache || len(inst.CacheVersions) == 0 {
 		versions, err := s.adapter.GetAllVersions(*inst)
 		if err != nil {
 			return nil, err
 		}
+		types2.SortVersions(versions)
 		inst.CacheVersions = versions
 	}
 
 	return inst.CacheVersions, nil
 }
 
+// CheckVersion returns ErrVersionNotFound if the version is not a tag of the
+// image of the container. The tags are reloaded once if the version is not
+// in the cached ones, in case it was pushed since. The services built from
+// a Dockerfile are not checked.
+func (s *ContainerRunnerService) CheckVersion(inst *types2.Container, version string) error {
+	docker := inst.Service.Methods.Docker
+	if docker == nil || docker.Image == nil {
+		return nil
+	}
+
+	for _, useCache := range []bool{true, false} {
+		versions, err := s.GetAllVersions(inst, useCache)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(versions, version) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s:%s", types2.ErrVersionNotFound, *docker.Image, version)
+}
+
+func (s *ContainerRunnerService) ForgetRegistryCache(inst *types2.Container) {
+	s.adapter.ForgetRegistryCache(*inst)
+}
+
 func (s *ContainerRunnerService) CheckForUpdates(inst *types2.Container) error {
 	return s.adapter.CheckForUpdates(inst)
 }
 
+// ApplyUpdate downloads the update of the container, and recreates the
+// container to use it. The volumes are kept, as they are bound again from
+// the service definition.
+func (s *ContainerRunnerService) ApplyUpdate(inst *types2.Container) error {
+	if inst.Pinned {
+		return types2.ErrContainerPinned
+	} else if inst.Update == nil {
+		return types2.ErrNoUpdateAvailable
+	}
+
+	err := s.adapter.ApplyUpdate(inst)
+	if err != nil {
+		return err
+	}
+
+	err = s.RecreateContainer(inst)
+	if err != nil {
+		return err
+	}
+
+	inst.Update = nil
+	s.ctx.DispatchEvent(types2.EventContainersChange{})
+	return nil
+}
+
 // RecreateContainer recreates a container by its UUID.
 func (s *ContainerRunnerService) RecreateContainer(inst *types2.Container) error {
 	if inst.IsRunning() {
@@ -226,6 +469,10 @@ func (s *ContainerRunnerService) RecreateContainer(inst *types2.Container) error
 		if err != nil {
 			return err
 		}
+		// The adapter leaves the status of the containers stopped through
+		// Vertex to the service.
+		s.setPorts(inst, nil)
+		s.setStatus(inst, types2.ContainerStatusOff)
 	}
 
 	err := s.adapter.Delete(inst)
@@ -248,18 +495,171 @@ func (s *ContainerRunnerService) WaitCondition(inst *types2.Container, cond vtyp
 	return s.adapter.WaitCondition(inst, cond)
 }
 
-func (s *ContainerRunnerService) setStatus(inst *types2.Container, status string) {
+// checkPorts checks that the host ports of the container are not already
+// bound by another running container, or by another process of the host,
+// so the user gets a clear error instead of the one from Docker.
+func (s *ContainerRunnerService) checkPorts(inst *types2.Container, env types2.ContainerEnvVariables) error {
+	ports, err := inst.HostPorts(env)
+	if err != nil {
+		return err
+	}
+	if len(ports) == 0 {
+		return nil
+	}
+
+	used := map[string]*types2.Container{}
+	for _, other := range s.getContainers() {
+		s.statusMutex.Lock()
+		running := other.IsRunning()
+		s.statusMutex.Unlock()
+
+		if other.UUID == inst.UUID || !running {
+			continue
+		}
+		otherPorts, err := other.HostPorts(other.Env)
+		if err != nil {
+			continue
+		}
+		for _, port := range otherPorts {
+			used[port] = other
+		}
+	}
+
+	for _, port := range ports {
+		if other, ok := used[port]; ok {
+			return &types2.PortConflictError{
+				Port:          port,
+				ContainerUUID: other.UUID,
+				ContainerName: other.Name(),
+			}
+		}
+		if !s.isPortFree(port) {
+			return &types2.PortConflictError{Port: port}
+		}
+	}
+	return nil
+}
+
+// runHook runs a hook command of the service on the host, in the directory
+// dir, with the environment of the container. Its output is sent to the logs
+// of the container.
+func (s *ContainerRunnerService) runHook(inst *types2.Container, dir string, name string, command string, env types2.ContainerEnvVariables) error {
+	s.ctx.DispatchEvent(types2.EventContainerLog{
+		ContainerUUID: inst.UUID,
+		Kind:          types2.LogKindVertexOut,
+		Message:       types2.NewLogLineMessageString(fmt.Sprintf("Running the %s hook...", name)),
+	})
+
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	cmd := hookCommand(command)
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	for key, value := range env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return fmt.Errorf("failed to run the %s hook: %w", name, err)
+	}
+
+	var wg sync.WaitGroup
+	for kind, r := range map[string]io.Reader{types2.LogKindOut: stdout, types2.LogKindErr: stderr} {
+		wg.Add(1)
+		go func(kind string, r io.Reader) {
+			defer wg.Done()
+			scanner := bufio.NewScanner(r)
+			for scanner.Scan() {
+				s.ctx.DispatchEvent(types2.EventContainerLog{
+					ContainerUUID: inst.UUID,
+					Kind:          kind,
+					Message:       types2.NewLogLineMessageString(scanner.Text()),
+				})
+			}
+		}(kind, r)
+	}
+	// The pipes must be read before waiting for the command.
+	wg.Wait()
+
+	err = cmd.Wait()
+	if err != nil {
+		return fmt.Errorf("the %s hook failed: %w", name, err)
+	}
+	return nil
+}
+
+// containerPath is the directory of the container on the host.
+func containerPath(uuid uuid.UUID) string {
+	return path.Join(storage.Path, "apps", "vx-containers", uuid.String())
+}
+
+// isPortFree tries to bind the port, like 8080/tcp, on all the interfaces.
+func isPortFree(port string) bool {
+	number, proto, _ := strings.Cut(port, "/")
+	addr := ":" + number
+	if proto == "udp" {
+		conn, err := net.ListenPacket("udp", addr)
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return false
+	}
+	_ = l.Close()
+	return true
+}
+
+// setStatus changes the status of the container, and notifies the listeners.
+// The invalid transitions are rejected, as they are bugs of the runner.
+// setPorts sets the host ports bound by the container, under the lock of
+// the status as they change with it.
+func (s *ContainerRunnerService) setPorts(inst *types2.Container, ports map[string]string) {
+	s.statusMutex.Lock()
+	defer s.statusMutex.Unlock()
+	inst.Ports = ports
+}
+
+func (s *ContainerRunnerService) setStatus(inst *types2.Container, status types2.ContainerStatus) {
+	s.statusMutex.Lock()
 	if inst.Status == status {
+		s.statusMutex.Unlock()
+		return
+	}
+	err := inst.Status.Transition(status)
+	if err != nil {
+		s.statusMutex.Unlock()
+		log.Error(err, vlog.String("uuid", inst.UUID.String()))
 		return
 	}
-
 	inst.Status = status
+	container := *inst
+	s.statusMutex.Unlock()
+
+	// The events are dispatched without holding the lock, as the
+	// listeners may change the status again.
 	s.ctx.DispatchEvent(types2.EventContainersChange{})
 	s.ctx.DispatchEvent(types2.EventContainerStatusChange{
-		ContainerUUID: inst.UUID,
-		ServiceID:     inst.Service.ID,
-		Container:     *inst,
-		Name:          inst.DisplayName,
+		ContainerUUID: container.UUID,
+		ServiceID:     container.Service.ID,
+		Container:     container,
+		Name:          container.DisplayName,
 		Status:        status,
 	})
 }
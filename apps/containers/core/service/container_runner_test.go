@@ -0,0 +1,273 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	types2 "github.com/vertex-center/vertex/apps/containers/core/types"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type ContainerRunnerServiceTestSuite struct {
+	suite.Suite
+	service *ContainerRunnerService
+
+	containers map[uuid.UUID]*types2.Container
+	// busyPorts are the ports used by other processes of the host.
+	busyPorts map[string]bool
+}
+
+func TestContainerRunnerServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ContainerRunnerServiceTestSuite))
+}
+
+func (suite *ContainerRunnerServiceTestSuite) SetupTest() {
+	suite.containers = map[uuid.UUID]*types2.Container{}
+	suite.busyPorts = map[string]bool{}
+	suite.service = NewContainerRunnerService(ContainerRunnerServiceParams{
+		Ctx:     app.NewContext(vtypes.NewVertexContext()),
+		Adapter: &fakeRunnerAdapter{},
+		GetContainers: func() map[uuid.UUID]*types2.Container {
+			return suite.containers
+		},
+	}).(*ContainerRunnerService)
+	suite.service.isPortFree = func(port string) bool {
+		return !suite.busyPorts[port]
+	}
+}
+
+func (suite *ContainerRunnerServiceTestSuite) newContainer(name string, port string, status types2.ContainerStatus) *types2.Container {
+	c := &types2.Container{
+		UUID:   uuid.New(),
+		Status: status,
+		Env:    types2.ContainerEnvVariables{"PORT": port},
+		Service: types2.Service{
+			Name: name,
+			Env: []types2.ServiceEnv{
+				{Type: "port", Name: "PORT", Default: "8080"},
+			},
+			Methods: types2.ServiceMethods{
+				Docker: &types2.ServiceMethodDocker{
+					Ports: &map[string]string{"80": "8080"},
+				},
+			},
+		},
+	}
+	suite.containers[c.UUID] = c
+	return c
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestCheckPorts() {
+	running := suite.newContainer("running", "9090", types2.ContainerStatusRunning)
+	suite.newContainer("off", "9091", types2.ContainerStatusOff)
+
+	inst := suite.newContainer("new", "9091", types2.ContainerStatusOff)
+	err := suite.service.checkPorts(inst, inst.Env)
+	suite.NoError(err)
+
+	inst.Env["PORT"] = "9090"
+	err = suite.service.checkPorts(inst, inst.Env)
+	suite.ErrorIs(err, types2.ErrPortConflict)
+	suite.Equal(&types2.PortConflictError{
+		Port:          "9090/tcp",
+		ContainerUUID: running.UUID,
+		ContainerName: "running",
+	}, err)
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestCheckPortsWhileInstalling() {
+	containers := NewContainerService(ContainerServiceParams{
+		Ctx: app.NewContext(vtypes.NewVertexContext()),
+	}).(*ContainerService)
+	suite.service.getContainers = containers.GetAll
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			id := uuid.New()
+			containers.containersMutex.Lock()
+			containers.containers[id] = &types2.Container{UUID: id, Status: types2.ContainerStatusOff}
+			containers.containersMutex.Unlock()
+		}
+	}()
+
+	inst := suite.newContainer("new", "9098", types2.ContainerStatusOff)
+	for i := 0; i < 100; i++ {
+		suite.NoError(suite.service.checkPorts(inst, inst.Env))
+	}
+	<-done
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestCheckPortsUsedByHost() {
+	suite.busyPorts["9092/tcp"] = true
+
+	inst := suite.newContainer("new", "9092", types2.ContainerStatusOff)
+	err := suite.service.checkPorts(inst, inst.Env)
+	suite.Equal(&types2.PortConflictError{Port: "9092/tcp"}, err)
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestPauseUnpause() {
+	inst := suite.newContainer("paused", "9093", types2.ContainerStatusRunning)
+
+	err := suite.service.Pause(inst)
+	suite.Require().NoError(err)
+	suite.Equal(types2.ContainerStatusPaused, inst.Status)
+	suite.True(inst.IsRunning())
+
+	err = suite.service.Pause(inst)
+	suite.ErrorIs(err, ErrContainerAlreadyPaused)
+
+	err = suite.service.Unpause(inst)
+	suite.Require().NoError(err)
+	suite.Equal(types2.ContainerStatusRunning, inst.Status)
+
+	err = suite.service.Unpause(inst)
+	suite.ErrorIs(err, ErrContainerNotPaused)
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestPauseNotRunning() {
+	inst := suite.newContainer("off", "9094", types2.ContainerStatusOff)
+
+	err := suite.service.Pause(inst)
+	suite.ErrorIs(err, ErrContainerNotRunning)
+	suite.Equal(types2.ContainerStatusOff, inst.Status)
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestStartStopPorts() {
+	inst := suite.newContainer("ports", "9099", types2.ContainerStatusOff)
+	started := make(chan struct{})
+	suite.service.adapter = &fakeRunnerAdapter{started: started}
+
+	err := suite.service.Start(inst)
+	suite.Require().NoError(err)
+	<-started
+	suite.Equal(map[string]string{"80/tcp": "8080"}, inst.Ports)
+
+	err = suite.service.Stop(inst)
+	suite.Require().NoError(err)
+	suite.Nil(inst.Ports)
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestStopPausedFails() {
+	inst := suite.newContainer("paused", "9095", types2.ContainerStatusPaused)
+	suite.service.adapter = &fakeRunnerAdapter{stopErr: errors.New("docker unreachable")}
+
+	err := suite.service.Stop(inst)
+	suite.Error(err)
+	suite.Equal(types2.ContainerStatusPaused, inst.Status)
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestCancelBuild() {
+	inst := suite.newContainer("building", "9096", types2.ContainerStatusBuilding)
+
+	err := suite.service.CancelBuild(inst)
+	suite.Require().NoError(err)
+	suite.Equal(types2.ContainerStatusOff, inst.Status)
+
+	err = suite.service.CancelBuild(inst)
+	suite.ErrorIs(err, ErrContainerNotBuilding)
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestRecreateContainer() {
+	inst := suite.newContainer("running", "9097", types2.ContainerStatusRunning)
+	started := make(chan struct{})
+	suite.service.adapter = &fakeRunnerAdapter{started: started}
+
+	err := suite.service.RecreateContainer(inst)
+	suite.Require().NoError(err)
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		suite.Fail("the container was not started again")
+	}
+	suite.Equal(types2.ContainerStatusRunning, inst.Status)
+}
+
+type fakeRunnerAdapter struct {
+	port.ContainerRunnerAdapter
+	stopErr  error
+	versions []string
+	// started is closed once the container is started.
+	started chan struct{}
+}
+
+func (f *fakeRunnerAdapter) Start(inst *types2.Container, env types2.ContainerEnvVariables, setStatus func(status types2.ContainerStatus), setPorts func(ports map[string]string)) (io.ReadCloser, io.ReadCloser, error) {
+	setStatus(types2.ContainerStatusBuilding)
+	setStatus(types2.ContainerStatusStarting)
+	setPorts(map[string]string{"80/tcp": "8080"})
+	setStatus(types2.ContainerStatusRunning)
+	if f.started != nil {
+		close(f.started)
+	}
+	return io.NopCloser(strings.NewReader("")), io.NopCloser(strings.NewReader("")), nil
+}
+
+func (f *fakeRunnerAdapter) Stop(inst *types2.Container) error {
+	return f.stopErr
+}
+
+func (f *fakeRunnerAdapter) Delete(inst *types2.Container) error {
+	return nil
+}
+
+func (f *fakeRunnerAdapter) CancelBuild(inst *types2.Container) error {
+	return nil
+}
+
+func (f *fakeRunnerAdapter) Pause(inst *types2.Container) error {
+	return nil
+}
+
+func (f *fakeRunnerAdapter) Unpause(inst *types2.Container) error {
+	return nil
+}
+
+func (f *fakeRunnerAdapter) GetAllVersions(inst types2.Container) ([]string, error) {
+	return f.versions, nil
+}
+
+func (f *fakeRunnerAdapter) ForgetRegistryCache(inst types2.Container) {}
+
+func (suite *ContainerRunnerServiceTestSuite) TestCheckVersion() {
+	image := "postgres"
+	inst := suite.newContainer("postgres", "5432", types2.ContainerStatusOff)
+	inst.Service.Methods.Docker.Image = &image
+	inst.CacheVersions = []string{"15"}
+	suite.service.adapter = &fakeRunnerAdapter{versions: []string{"15", "16"}}
+
+	suite.NoError(suite.service.CheckVersion(inst, "15"))
+
+	// The tags are reloaded if the version is not cached.
+	suite.NoError(suite.service.CheckVersion(inst, "16"))
+	suite.Equal([]string{"16", "15"}, inst.CacheVersions)
+
+	err := suite.service.CheckVersion(inst, "1666")
+	suite.ErrorIs(err, types2.ErrVersionNotFound)
+
+	// The services built from a Dockerfile have no tags.
+	inst.Service.Methods.Docker.Image = nil
+	suite.NoError(suite.service.CheckVersion(inst, "1666"))
+}
+
+func (suite *ContainerRunnerServiceTestSuite) TestRunHook() {
+	dir := suite.T().TempDir()
+	inst := suite.newContainer("hook", "9096", types2.ContainerStatusOff)
+
+	err := suite.service.runHook(inst, dir, "pre-start", "echo hello > hook.txt", inst.Env)
+	suite.Require().NoError(err)
+	suite.FileExists(filepath.Join(dir, "hook.txt"))
+
+	err = suite.service.runHook(inst, dir, "pre-start", "exit 1", inst.Env)
+	suite.Error(err)
+}
@@ -38,6 +38,14 @@ func (s *ContainerSettingsService) SetLaunchOnStartup(inst *types.Container, val
 	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
 }
 
+func (s *ContainerSettingsService) SetPinned(inst *types.Container, value bool) error {
+	inst.ContainerSettings.Pinned = value
+	if value {
+		inst.Update = nil
+	}
+	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
+}
+
 func (s *ContainerSettingsService) SetDisplayName(inst *types.Container, value string) error {
 	inst.ContainerSettings.DisplayName = value
 	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
@@ -53,6 +61,35 @@ func (s *ContainerSettingsService) SetVersion(inst *types.Container, value strin
 	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
 }
 
+func (s *ContainerSettingsService) SetCommand(inst *types.Container, command string) error {
+	inst.Command = &command
+	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
+}
+
+func (s *ContainerSettingsService) SetEnvFile(inst *types.Container, value string) error {
+	if value == "" {
+		inst.EnvFile = nil
+	} else {
+		inst.EnvFile = &value
+	}
+	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
+}
+
+func (s *ContainerSettingsService) SetIdleTimeout(inst *types.Container, minutes int) error {
+	inst.IdleTimeout = minutes
+	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
+}
+
+func (s *ContainerSettingsService) SetProxyAutoRegister(inst *types.Container, value bool) error {
+	inst.ProxyAutoRegister = value
+	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
+}
+
+func (s *ContainerSettingsService) SetIdleAutoStart(inst *types.Container, value bool) error {
+	inst.IdleAutoStart = value
+	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
+}
+
 func (s *ContainerSettingsService) SetTags(inst *types.Container, tags []string) error {
 	inst.Tags = tags
 	return s.adapter.Save(inst.UUID, inst.ContainerSettings)
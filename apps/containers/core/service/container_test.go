@@ -1,8 +1,14 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/vertex-center/vertex/apps/containers/core/port"
 	types2 "github.com/vertex-center/vertex/apps/containers/core/types"
 	vtypes "github.com/vertex-center/vertex/core/types"
 	"github.com/vertex-center/vertex/core/types/app"
@@ -155,3 +161,199 @@ func (suite *ContainerServiceTestSuite) TestGetTags() {
 	suite.Contains(tags, "Service A Tag 0")
 	suite.Contains(tags, "Service A Tag 1")
 }
+
+func (suite *ContainerServiceTestSuite) TestGetAll() {
+	all := suite.service.GetAll()
+	suite.Len(all, 2)
+	suite.Same(&suite.containerA, all[suite.containerA.UUID])
+
+	// The containers can be ranged over while others are installed.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			id := uuid.New()
+			suite.service.containersMutex.Lock()
+			suite.service.containers[id] = &types2.Container{UUID: id}
+			suite.service.containersMutex.Unlock()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		for range suite.service.GetAll() {
+		}
+	}
+	<-done
+
+	delete(all, suite.containerA.UUID)
+	suite.Len(suite.service.GetAll(), 102)
+}
+
+func (suite *ContainerServiceTestSuite) TestList() {
+	suite.containerA.Env = types2.ContainerEnvVariables{"PASSWORD": "secret"}
+
+	page := suite.service.List(types2.ContainerListQuery{})
+	suite.Equal(2, page.Total)
+	suite.Equal([]*types2.Container{&suite.containerA, &suite.containerB}, page.Containers)
+
+	page = suite.service.List(types2.ContainerListQuery{Offset: 1, Limit: 1})
+	suite.Equal(2, page.Total)
+	suite.Equal([]*types2.Container{&suite.containerB}, page.Containers)
+
+	page = suite.service.List(types2.ContainerListQuery{Offset: 5})
+	suite.Equal(2, page.Total)
+	suite.Empty(page.Containers)
+
+	page = suite.service.List(types2.ContainerListQuery{Limit: 1, Summary: true})
+	suite.Len(page.Containers, 1)
+	suite.Equal(suite.containerA.UUID, page.Containers[0].UUID)
+	suite.Nil(page.Containers[0].Env)
+	suite.NotNil(suite.containerA.Env)
+}
+
+func (suite *ContainerServiceTestSuite) TestCheckForUpdates() {
+	var running, maxRunning atomic.Int32
+	suite.service.checkUpdatesWorkers = 2
+	suite.service.containerRunnerService = &fakeRunnerService{
+		checkForUpdates: func(inst *types2.Container) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			if inst.UUID == suite.containerB.UUID {
+				return errors.New("registry unreachable")
+			}
+			return nil
+		},
+	}
+
+	checked, err := suite.service.CheckForUpdates(context.Background())
+	suite.Error(err)
+	suite.Contains(checked, suite.containerA.UUID)
+	suite.NotContains(checked, suite.containerB.UUID)
+	suite.LessOrEqual(maxRunning.Load(), int32(2))
+}
+
+func (suite *ContainerServiceTestSuite) TestCheckForUpdatesTimeout() {
+	release := make(chan struct{})
+
+	// The check of B outlives CheckForUpdates, and must be done before the
+	// next test resets the containers.
+	var wg sync.WaitGroup
+	wg.Add(2)
+	defer wg.Wait()
+	defer close(release)
+
+	suite.service.checkUpdatesTimeout = 50 * time.Millisecond
+	suite.service.containerRunnerService = &fakeRunnerService{
+		checkForUpdates: func(inst *types2.Container) error {
+			defer wg.Done()
+			if inst.UUID == suite.containerB.UUID {
+				<-release
+			}
+			return nil
+		},
+	}
+
+	checked, err := suite.service.CheckForUpdates(context.Background())
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Equal(map[uuid.UUID]*types2.Container{
+		suite.containerA.UUID: &suite.containerA,
+	}, checked)
+}
+
+func (suite *ContainerServiceTestSuite) TestCheckForUpdatesSkipsPinned() {
+	suite.containerB.Pinned = true
+	suite.service.containerRunnerService = &fakeRunnerService{
+		checkForUpdates: func(inst *types2.Container) error {
+			suite.NotEqual(suite.containerB.UUID, inst.UUID)
+			return nil
+		},
+	}
+
+	checked, err := suite.service.CheckForUpdates(context.Background())
+	suite.NoError(err)
+	suite.Len(checked, 2)
+}
+
+func (suite *ContainerServiceTestSuite) TestResetEnv() {
+	var deleted bool
+	suite.service.containerRunnerService = &fakeRunnerService{
+		delete: func(inst *types2.Container) error {
+			deleted = true
+			return nil
+		},
+	}
+	suite.service.containerEnvService = &fakeEnvService{}
+
+	inst := &types2.Container{
+		UUID:   uuid.New(),
+		Status: types2.ContainerStatusOff,
+		Env:    types2.ContainerEnvVariables{"PORT": "1234", "OLD": "value"},
+		Service: types2.Service{
+			Env: []types2.ServiceEnv{{Type: "port", Name: "PORT", Default: "8080"}},
+		},
+	}
+
+	err := suite.service.ResetEnv(inst)
+	suite.NoError(err)
+	suite.Equal(types2.ContainerEnvVariables{"PORT": "8080"}, inst.Env)
+	suite.True(deleted)
+}
+
+func (suite *ContainerServiceTestSuite) TestResetEnvRunning() {
+	inst := &types2.Container{
+		Status: types2.ContainerStatusRunning,
+		Env:    types2.ContainerEnvVariables{"PORT": "1234"},
+	}
+
+	err := suite.service.ResetEnv(inst)
+	suite.ErrorIs(err, types2.ErrContainerStillRunning)
+	suite.Equal("1234", inst.Env["PORT"])
+}
+
+func (suite *ContainerServiceTestSuite) TestInstallFromImageInvalid() {
+	_, err := suite.service.InstallFromImage("nginx@sha256:abc", "")
+	suite.ErrorIs(err, types2.ErrInvalidImage)
+	suite.Len(suite.service.containers, 2)
+}
+
+// fakeRunnerService overrides the runner methods used by the tests. The
+// other methods panic.
+type fakeRunnerService struct {
+	port.ContainerRunnerService
+	checkForUpdates func(inst *types2.Container) error
+	delete          func(inst *types2.Container) error
+	start           func(inst *types2.Container) error
+	stop            func(inst *types2.Container) error
+}
+
+func (f *fakeRunnerService) CheckForUpdates(inst *types2.Container) error {
+	return f.checkForUpdates(inst)
+}
+
+func (f *fakeRunnerService) Delete(inst *types2.Container) error {
+	return f.delete(inst)
+}
+
+func (f *fakeRunnerService) Start(inst *types2.Container) error {
+	return f.start(inst)
+}
+
+func (f *fakeRunnerService) Stop(inst *types2.Container) error {
+	return f.stop(inst)
+}
+
+// fakeEnvService keeps the environment in memory.
+type fakeEnvService struct {
+	port.ContainerEnvService
+}
+
+func (f *fakeEnvService) Save(inst *types2.Container, env types2.ContainerEnvVariables) error {
+	inst.Env = env
+	return nil
+}
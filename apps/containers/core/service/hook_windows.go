@@ -0,0 +1,10 @@
+//go:build windows
+
+package service
+
+import "os/exec"
+
+// hookCommand returns the command running a hook in the shell of the host.
+func hookCommand(command string) *exec.Cmd {
+	return exec.Command("cmd", "/C", command)
+}
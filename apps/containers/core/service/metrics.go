@@ -77,7 +77,7 @@ func (s *MetricsService) OnEvent(e interface{}) {
 	}
 }
 
-func (s *MetricsService) updateStatus(uuid uuid.UUID, serviceId string, status string) {
+func (s *MetricsService) updateStatus(uuid uuid.UUID, serviceId string, status types.ContainerStatus) {
 	switch status {
 	case types.ContainerStatusRunning:
 		s.ctx.DispatchEvent(monitoringtypes.EventSetMetric{
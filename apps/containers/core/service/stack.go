@@ -0,0 +1,242 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+type StackService struct {
+	containerService         port.ContainerService
+	containerEnvService      port.ContainerEnvService
+	containerRunnerService   port.ContainerRunnerService
+	containerSettingsService port.ContainerSettingsService
+	serviceService           port.ServiceService
+}
+
+type StackServiceParams struct {
+	ContainerService         port.ContainerService
+	ContainerEnvService      port.ContainerEnvService
+	ContainerRunnerService   port.ContainerRunnerService
+	ContainerSettingsService port.ContainerSettingsService
+	ServiceService           port.ServiceService
+}
+
+func NewStackService(params StackServiceParams) port.StackService {
+	return &StackService{
+		containerService:         params.ContainerService,
+		containerEnvService:      params.ContainerEnvService,
+		containerRunnerService:   params.ContainerRunnerService,
+		containerSettingsService: params.ContainerSettingsService,
+		serviceService:           params.ServiceService,
+	}
+}
+
+// Install installs all the services of the stack, and returns the UUID of
+// each container by service name. If an installation fails, the containers
+// already installed are deleted.
+func (s *StackService) Install(stack types.Stack) (map[string]uuid.UUID, error) {
+	err := stack.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.get(stack.Name)) > 0 {
+		return nil, fmt.Errorf("%w: the stack '%s' already exists", types.ErrStackInvalid, stack.Name)
+	}
+
+	containers := map[string]*types.Container{}
+	ids := map[string]uuid.UUID{}
+
+	err = func() error {
+		for _, serv := range stack.Services {
+			service, err := s.serviceService.GetById(serv.ServiceID)
+			if err != nil {
+				return err
+			}
+
+			// The containers of the stack reach each other by their name
+			// on the network of the stack.
+			if service.Methods.Docker != nil && service.Methods.Docker.Network == nil {
+				network := types.StackNetwork(stack.Name)
+				docker := *service.Methods.Docker
+				docker.Network = &network
+				docker.NetworkAliases = &[]string{serv.Name}
+				service.Methods.Docker = &docker
+			}
+
+			inst, err := s.containerService.Install(service, types.ContainerInstallMethodDocker)
+			if err != nil {
+				return err
+			}
+			containers[serv.Name] = inst
+			ids[serv.Name] = inst.UUID
+
+			err = s.containerSettingsService.SetTags(inst, append(inst.Tags, types.StackTag(stack.Name)))
+			if err != nil {
+				return err
+			}
+		}
+
+		// The UUIDs are only known once all the containers are installed.
+		for _, serv := range stack.Services {
+			inst := containers[serv.Name]
+			for name, value := range serv.Env.ToContainerReferences(ids) {
+				inst.Env[name] = value
+			}
+			err := s.containerEnvService.Save(inst, inst.Env)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+
+	if err != nil {
+		for _, inst := range containers {
+			err := s.containerService.Delete(inst)
+			if err != nil {
+				log.Error(err, vlog.String("uuid", inst.UUID.String()))
+			}
+		}
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// ImportCompose installs the services of the compose file as a stack, in the
+// order of their dependencies. The containers share the network of the
+// stack, where they reach each other by their compose service name. If an
+// installation fails, the containers already installed are deleted.
+func (s *StackService) ImportCompose(name string, content []byte) (types.ComposeImportResult, error) {
+	if name == "" {
+		return types.ComposeImportResult{}, fmt.Errorf("%w: the name is missing", types.ErrStackInvalid)
+	}
+	if len(s.get(name)) > 0 {
+		return types.ComposeImportResult{}, fmt.Errorf("%w: the stack '%s' already exists", types.ErrStackInvalid, name)
+	}
+
+	project, err := types.ParseCompose(name, content)
+	if err != nil {
+		return types.ComposeImportResult{}, err
+	}
+
+	var containers []*types.Container
+	ids := map[string]uuid.UUID{}
+
+	err = func() error {
+		for _, servName := range project.Order {
+			serv := project.Services[servName]
+
+			inst, err := s.containerService.Install(serv.Service, types.ContainerInstallMethodDocker)
+			if err != nil {
+				return err
+			}
+			containers = append(containers, inst)
+			ids[servName] = inst.UUID
+
+			err = s.containerSettingsService.SetVersion(inst, serv.Version)
+			if err != nil {
+				return err
+			}
+
+			err = s.containerSettingsService.SetTags(inst, append(inst.Tags, types.StackTag(name)))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+
+	if err != nil {
+		for _, inst := range containers {
+			err := s.containerService.Delete(inst)
+			if err != nil {
+				log.Error(err, vlog.String("uuid", inst.UUID.String()))
+			}
+		}
+		return types.ComposeImportResult{}, err
+	}
+
+	return types.ComposeImportResult{
+		Containers: ids,
+		Order:      project.Order,
+		Warnings:   project.Warnings,
+	}, nil
+}
+
+func (s *StackService) Start(name string) error {
+	containers := s.get(name)
+	if len(containers) == 0 {
+		return types.ErrStackNotFound
+	}
+
+	for _, inst := range containers {
+		if inst.IsRunning() {
+			continue
+		}
+		go func(inst *types.Container) {
+			err := s.containerRunnerService.Start(inst)
+			if err != nil {
+				log.Error(err, vlog.String("uuid", inst.UUID.String()))
+			}
+		}(inst)
+	}
+	return nil
+}
+
+func (s *StackService) Stop(name string) error {
+	containers := s.get(name)
+	if len(containers) == 0 {
+		return types.ErrStackNotFound
+	}
+
+	var errs []error
+	for _, inst := range containers {
+		if !inst.IsRunning() {
+			continue
+		}
+		err := s.containerRunnerService.Stop(inst)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Delete deletes all the containers of the stack. If a container is still
+// running, nothing is deleted and it returns ErrContainerStillRunning.
+func (s *StackService) Delete(name string) error {
+	containers := s.get(name)
+	if len(containers) == 0 {
+		return types.ErrStackNotFound
+	}
+
+	for _, inst := range containers {
+		if inst.IsRunning() {
+			return types.ErrContainerStillRunning
+		}
+	}
+
+	var errs []error
+	for _, inst := range containers {
+		err := s.containerService.Delete(inst)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *StackService) get(name string) map[uuid.UUID]*types.Container {
+	tags := []string{types.StackTag(name)}
+	return s.containerService.Search(types.ContainerSearchQuery{
+		Tags: &tags,
+	})
+}
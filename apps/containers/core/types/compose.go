@@ -0,0 +1,457 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrComposeInvalid = errors.New("the compose file is invalid")
+
+// ComposeImport is the request to import a docker-compose.yml file as a
+// stack.
+type ComposeImport struct {
+	// Name is the name of the stack created from the compose file.
+	Name string `json:"name"`
+
+	// Content is the content of the compose file.
+	Content string `json:"content"`
+}
+
+type ComposeImportResult struct {
+	// Containers are the UUIDs of the containers by compose service name.
+	Containers map[string]uuid.UUID `json:"containers"`
+
+	// Order is the order the services were installed in, the dependencies
+	// first.
+	Order []string `json:"order"`
+
+	// Warnings are the features of the compose file that were ignored.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ComposeProject is a compose file converted to Vertex services.
+type ComposeProject struct {
+	// Services are the converted services by compose service name.
+	Services map[string]ComposeService
+
+	// Order lists the services with their dependencies first.
+	Order []string
+
+	// Warnings are the features of the compose file that were ignored.
+	Warnings []string
+}
+
+type ComposeService struct {
+	Service Service
+
+	// Version is the tag of the image, used as the container version.
+	Version string
+
+	// DependsOn are the names of the services this one depends on.
+	DependsOn []string
+}
+
+// composeWarn reports a feature of the compose file that was ignored.
+type composeWarn func(format string, args ...any)
+
+// composeNameRegex matches the characters not allowed in the Docker network
+// names and in the environment variable names generated from the ports.
+var composeNameRegex = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// StackNetwork returns the Docker network shared by the containers of a
+// stack.
+func StackNetwork(name string) string {
+	return "vertex_stack_" + composeNameRegex.ReplaceAllString(name, "_")
+}
+
+// ParseCompose converts the services of a compose file to Vertex services
+// running their image with Docker, in the network of the stack. The
+// supported keys are image, ports, volumes, environment, depends_on,
+// command, cap_add, extra_hosts, shm_size, init and sysctls. The other
+// keys are ignored with a warning.
+func ParseCompose(stack string, content []byte) (ComposeProject, error) {
+	var file map[string]any
+	err := yaml.Unmarshal(content, &file)
+	if err != nil {
+		return ComposeProject{}, fmt.Errorf("%w: %w", ErrComposeInvalid, err)
+	}
+
+	project := ComposeProject{
+		Services: map[string]ComposeService{},
+	}
+	warn := composeWarn(func(format string, args ...any) {
+		project.Warnings = append(project.Warnings, fmt.Sprintf(format, args...))
+	})
+
+	services, ok := file["services"].(map[string]any)
+	if !ok || len(services) == 0 {
+		return ComposeProject{}, fmt.Errorf("%w: the file has no services", ErrComposeInvalid)
+	}
+
+	for _, key := range sortedKeys(file) {
+		switch key {
+		case "services", "version", "name", "volumes":
+		case "networks":
+			warn("networks are ignored: all the services join the network %s", StackNetwork(stack))
+		default:
+			warn("%s is not supported and was ignored", key)
+		}
+	}
+	if strings.Contains(string(content), "${") {
+		warn("variable interpolation is not supported: the ${...} values are kept as is")
+	}
+
+	volumeUsers := map[string][]string{}
+	for _, name := range sortedKeys(services) {
+		def, ok := services[name].(map[string]any)
+		if !ok {
+			return ComposeProject{}, fmt.Errorf("%w: the service %s is not a mapping", ErrComposeInvalid, name)
+		}
+		serv, err := parseComposeService(stack, name, def, warn)
+		if err != nil {
+			return ComposeProject{}, err
+		}
+		project.Services[name] = serv
+
+		if serv.Service.Methods.Docker.Volumes != nil {
+			for source := range *serv.Service.Methods.Docker.Volumes {
+				if volume, ok := strings.CutPrefix(source, VolumePrefix); ok {
+					volumeUsers[volume] = append(volumeUsers[volume], name)
+				}
+			}
+		}
+	}
+
+	for _, volume := range sortedKeys(volumeUsers) {
+		if len(volumeUsers[volume]) > 1 {
+			warn("the volume %s is not shared: each of %s gets its own copy", volume, strings.Join(volumeUsers[volume], ", "))
+		}
+	}
+
+	project.Order, err = composeOrder(project.Services)
+	if err != nil {
+		return ComposeProject{}, err
+	}
+	return project, nil
+}
+
+func parseComposeService(stack string, name string, def map[string]any, warn composeWarn) (ComposeService, error) {
+	image, ok := def["image"].(string)
+	if !ok {
+		return ComposeService{}, fmt.Errorf("%w: the service %s has no image; building images is not supported", ErrComposeInvalid, name)
+	}
+	imageName, tag, err := ParseImage(image)
+	if err != nil {
+		return ComposeService{}, fmt.Errorf("%w: service %s: %w", ErrComposeInvalid, name, err)
+	}
+
+	serv := ComposeService{
+		Service: NewImageService(imageName, name),
+		Version: tag,
+	}
+	docker := serv.Service.Methods.Docker
+
+	network := StackNetwork(stack)
+	docker.Network = &network
+	docker.NetworkAliases = &[]string{name}
+
+	for _, key := range sortedKeys(def) {
+		value := def[key]
+		switch key {
+		case "image":
+		case "ports":
+			parseComposePorts(&serv.Service, name, value, warn)
+		case "volumes":
+			parseComposeVolumes(docker, name, value, warn)
+		case "environment":
+			parseComposeEnv(&serv.Service, name, value, warn)
+		case "depends_on":
+			serv.DependsOn = parseComposeDependsOn(name, value, warn)
+		case "command":
+			cmd := composeCommand(name, value, warn)
+			docker.Cmd = &cmd
+		case "cap_add":
+			caps := composeStrings(value, "=")
+			docker.Capabilities = &caps
+		case "extra_hosts":
+			hosts := composeStrings(value, ":")
+			docker.ExtraHosts = &hosts
+		case "sysctls":
+			sysctls := map[string]string{}
+			for _, sysctl := range composeStrings(value, "=") {
+				k, v, _ := strings.Cut(sysctl, "=")
+				sysctls[k] = v
+			}
+			docker.Sysctls = &sysctls
+		case "shm_size":
+			size := fmt.Sprint(value)
+			docker.ShmSize = &size
+		case "init":
+			enabled, _ := value.(bool)
+			docker.Init = &enabled
+		default:
+			warn("service %s: %s is not supported and was ignored", name, key)
+		}
+	}
+	return serv, nil
+}
+
+// parseComposePorts adds the ports, like "8080:80/udp" or "[::]:8080:80", to
+// the service. Each host port is a port environment variable, named after the
+// container port.
+func parseComposePorts(service *Service, name string, value any, warn composeWarn) {
+	entries, _ := value.([]any)
+	ports := map[string]string{}
+	for _, entry := range entries {
+		spec, ok := composeScalar(entry)
+		if !ok {
+			warn("service %s: the long syntax of ports is not supported and was ignored", name)
+			continue
+		}
+
+		spec, proto, _ := strings.Cut(spec, "/")
+		hostIP, rest := "", spec
+		if strings.HasPrefix(spec, "[") || strings.Count(spec, ":") == 2 {
+			hostIP, rest = splitHostIP(spec)
+		}
+		parts := strings.Split(rest, ":")
+		host, containerPort := PortAuto, parts[len(parts)-1]
+		switch len(parts) {
+		case 1:
+		case 2:
+			host = parts[0]
+		default:
+			warn("service %s: the port %s is invalid and was ignored", name, spec)
+			continue
+		}
+		if host == "" {
+			host = PortAuto
+		}
+
+		in := containerPort
+		env := "PORT_" + composeNameRegex.ReplaceAllString(strings.ReplaceAll(containerPort, "-", "_"), "_")
+		if proto != "" {
+			in += "/" + proto
+			env += "_" + strings.ToUpper(proto)
+		}
+		ports[in] = joinHostIP(hostIP, host)
+		service.Env = append(service.Env, ServiceEnv{
+			Type:        "port",
+			Name:        env,
+			DisplayName: "Port " + in,
+			Default:     host,
+		})
+	}
+	if len(ports) > 0 {
+		service.Methods.Docker.Ports = &ports
+	}
+}
+
+// parseComposeVolumes adds the volumes, like "./data:/data" or
+// "db:/var/lib/postgresql/data", to the service. The relative paths are in
+// the directory of the container.
+func parseComposeVolumes(docker *ServiceMethodDocker, name string, value any, warn composeWarn) {
+	entries, _ := value.([]any)
+	volumes := map[string]string{}
+	for _, entry := range entries {
+		spec, ok := composeScalar(entry)
+		if !ok {
+			warn("service %s: the long syntax of volumes is not supported and was ignored", name)
+			continue
+		}
+
+		parts := strings.Split(spec, ":")
+		if len(parts) < 2 {
+			warn("service %s: the anonymous volume %s is not supported and was ignored", name, spec)
+			continue
+		}
+		if len(parts) > 2 {
+			warn("service %s: the mode of the volume %s is not supported and was ignored", name, spec)
+		}
+
+		source, target := parts[0], parts[1]
+		switch {
+		case strings.HasPrefix(source, "/"):
+		case strings.HasPrefix(source, "."):
+			source = path.Clean(source)
+			if source == ".." || strings.HasPrefix(source, "../") {
+				warn("service %s: the volume %s is outside of the container directory and was ignored", name, spec)
+				continue
+			}
+			warn("service %s: the volume %s starts empty in the directory of the container", name, spec)
+		case strings.HasPrefix(source, "~"):
+			warn("service %s: the volume %s in the home directory is not supported and was ignored", name, spec)
+			continue
+		default:
+			source = VolumePrefix + source
+		}
+		volumes[source] = target
+	}
+	if len(volumes) > 0 {
+		docker.Volumes = &volumes
+	}
+}
+
+// parseComposeEnv adds the environment, as a list or a mapping, to the
+// service. The values become the defaults of the container environment.
+func parseComposeEnv(service *Service, name string, value any, warn composeWarn) {
+	env := map[string]string{}
+	var missing []string
+
+	switch value := value.(type) {
+	case []any:
+		for _, entry := range value {
+			k, v, ok := strings.Cut(fmt.Sprint(entry), "=")
+			if !ok {
+				missing = append(missing, k)
+			}
+			env[k] = v
+		}
+	case map[string]any:
+		for k, v := range value {
+			if v == nil {
+				missing = append(missing, k)
+				v = ""
+			}
+			env[k] = fmt.Sprint(v)
+		}
+	}
+
+	sort.Strings(missing)
+	for _, k := range missing {
+		warn("service %s: the variable %s has no value; set it in the container environment", name, k)
+	}
+
+	mapping := map[string]string{}
+	for _, k := range sortedKeys(env) {
+		mapping[k] = k
+		service.Env = append(service.Env, ServiceEnv{
+			Type:        "string",
+			Name:        k,
+			DisplayName: k,
+			Default:     env[k],
+		})
+	}
+	if len(mapping) > 0 {
+		service.Methods.Docker.Environment = &mapping
+	}
+}
+
+// parseComposeDependsOn returns the dependencies, as a list or a mapping
+// of conditions. Only the service_started condition is supported.
+func parseComposeDependsOn(name string, value any, warn composeWarn) []string {
+	var deps []string
+	switch value := value.(type) {
+	case []any:
+		for _, dep := range value {
+			deps = append(deps, fmt.Sprint(dep))
+		}
+	case map[string]any:
+		for _, dep := range sortedKeys(value) {
+			deps = append(deps, dep)
+			def, _ := value[dep].(map[string]any)
+			if cond, ok := def["condition"].(string); ok && cond != "service_started" {
+				warn("service %s: the condition %s on %s is not supported; it only waits for the installation", name, cond, dep)
+			}
+		}
+	}
+	return deps
+}
+
+func composeCommand(name string, value any, warn composeWarn) string {
+	args, ok := value.([]any)
+	if !ok {
+		return fmt.Sprint(value)
+	}
+
+	var parts []string
+	for _, arg := range args {
+		s := fmt.Sprint(arg)
+		if strings.Contains(s, " ") {
+			warn("service %s: the command argument %q contains spaces, which are split into several arguments", name, s)
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " ")
+}
+
+// composeStrings returns the values of a list, or the entries of a mapping
+// joined by sep.
+func composeStrings(value any, sep string) []string {
+	var values []string
+	switch value := value.(type) {
+	case []any:
+		for _, v := range value {
+			values = append(values, fmt.Sprint(v))
+		}
+	case map[string]any:
+		for _, k := range sortedKeys(value) {
+			values = append(values, k+sep+fmt.Sprint(value[k]))
+		}
+	}
+	return values
+}
+
+// composeScalar returns the short syntax of an entry, which can be a number
+// like a port.
+func composeScalar(value any) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case int:
+		return fmt.Sprint(value), true
+	}
+	return "", false
+}
+
+// composeOrder sorts the services with their dependencies first. The
+// services without dependencies between them are sorted by name.
+func composeOrder(services map[string]ComposeService) ([]string, error) {
+	for _, name := range sortedKeys(services) {
+		for _, dep := range services[name].DependsOn {
+			if _, ok := services[dep]; !ok {
+				return nil, fmt.Errorf("%w: the service %s depends on the unknown service %s", ErrComposeInvalid, name, dep)
+			}
+		}
+	}
+
+	var order []string
+	done := map[string]bool{}
+	for len(order) < len(services) {
+		progress := false
+		for _, name := range sortedKeys(services) {
+			if done[name] {
+				continue
+			}
+			ready := true
+			for _, dep := range services[name].DependsOn {
+				ready = ready && done[dep]
+			}
+			if ready {
+				order = append(order, name)
+				done[name] = true
+				progress = true
+			}
+		}
+		if !progress {
+			return nil, fmt.Errorf("%w: the dependencies of the services form a cycle", ErrComposeInvalid)
+		}
+	}
+	return order, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
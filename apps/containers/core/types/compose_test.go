@@ -0,0 +1,136 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ComposeTestSuite struct {
+	suite.Suite
+}
+
+func TestComposeTestSuite(t *testing.T) {
+	suite.Run(t, new(ComposeTestSuite))
+}
+
+const composeFile = `
+version: "3.8"
+services:
+  app:
+    image: ghcr.io/owner/app:1.2
+    ports:
+      - "8080:80"
+      - 9000
+      - "127.0.0.1:5353:53/udp"
+      - "[::]:8443:443"
+    volumes:
+      - ./data:/data
+      - db:/var/lib/data:ro
+    environment:
+      DB_HOST: db
+      DB_PORT: 5432
+      TOKEN:
+    depends_on:
+      db:
+        condition: service_healthy
+    command: ["serve", "--port", "80"]
+    restart: always
+  db:
+    image: postgres
+    environment:
+      - POSTGRES_PASSWORD=secret
+    volumes:
+      - db:/var/lib/postgresql/data
+networks:
+  default:
+`
+
+func (suite *ComposeTestSuite) TestParseCompose() {
+	project, err := ParseCompose("blog", []byte(composeFile))
+	suite.Require().NoError(err)
+
+	suite.Equal([]string{"db", "app"}, project.Order)
+
+	app := project.Services["app"]
+	suite.Equal("1.2", app.Version)
+	suite.Equal([]string{"db"}, app.DependsOn)
+	suite.Equal("image:ghcr.io/owner/app", app.Service.ID)
+
+	docker := app.Service.Methods.Docker
+	suite.Equal("ghcr.io/owner/app", *docker.Image)
+	suite.Equal("vertex_stack_blog", *docker.Network)
+	suite.Equal([]string{"app"}, *docker.NetworkAliases)
+	suite.Equal(map[string]string{
+		"80":     "8080",
+		"9000":   PortAuto,
+		"53/udp": "127.0.0.1:5353",
+		"443":    "[::]:8443",
+	}, *docker.Ports)
+	suite.Equal(map[string]string{
+		"data":      "/data",
+		"volume:db": "/var/lib/data",
+	}, *docker.Volumes)
+	suite.Equal(map[string]string{
+		"DB_HOST": "DB_HOST",
+		"DB_PORT": "DB_PORT",
+		"TOKEN":   "TOKEN",
+	}, *docker.Environment)
+	suite.Equal("serve --port 80", *docker.Cmd)
+
+	defaults := map[string]string{}
+	for _, env := range app.Service.Env {
+		defaults[env.Name] = env.Default
+	}
+	suite.Equal(map[string]string{
+		"PORT_80":     "8080",
+		"PORT_9000":   PortAuto,
+		"PORT_53_UDP": "5353",
+		"PORT_443":    "8443",
+		"DB_HOST":     "db",
+		"DB_PORT":     "5432",
+		"TOKEN":       "",
+	}, defaults)
+
+	db := project.Services["db"]
+	suite.Equal("latest", db.Version)
+	suite.Equal(map[string]string{"POSTGRES_PASSWORD": "POSTGRES_PASSWORD"}, *db.Service.Methods.Docker.Environment)
+
+	suite.Equal([]string{
+		"networks are ignored: all the services join the network vertex_stack_blog",
+		"service app: the condition service_healthy on db is not supported; it only waits for the installation",
+		"service app: the variable TOKEN has no value; set it in the container environment",
+		"service app: restart is not supported and was ignored",
+		"service app: the volume ./data:/data starts empty in the directory of the container",
+		"service app: the mode of the volume db:/var/lib/data:ro is not supported and was ignored",
+		"the volume db is not shared: each of app, db gets its own copy",
+	}, project.Warnings)
+}
+
+func (suite *ComposeTestSuite) TestParseComposeInvalid() {
+	for name, content := range map[string]string{
+		"not yaml":    "services: [",
+		"no services": "version: '3'",
+		"no image":    "services:\n  app:\n    build: .",
+		"unknown dependency": `
+services:
+  app:
+    image: nginx
+    depends_on: [db]`,
+		"cycle": `
+services:
+  a:
+    image: nginx
+    depends_on: [b]
+  b:
+    image: nginx
+    depends_on: [a]`,
+	} {
+		_, err := ParseCompose("stack", []byte(content))
+		suite.ErrorIs(err, ErrComposeInvalid, name)
+	}
+}
+
+func (suite *ComposeTestSuite) TestStackNetwork() {
+	suite.Equal("vertex_stack_my_blog", StackNetwork("my blog"))
+}
@@ -2,26 +2,33 @@ package types
 
 import (
 	"errors"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-const (
-	ContainerStatusOff      = "off"
-	ContainerStatusBuilding = "building"
-	ContainerStatusStarting = "starting"
-	ContainerStatusRunning  = "running"
-	ContainerStatusStopping = "stopping"
-	ContainerStatusError    = "error"
-)
-
 const (
 	ContainerInstallMethodDocker = "docker"
 )
 
+var nonSlugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// RedactedEnvValue replaces the values of the secret variables in the
+// redacted environments.
+const RedactedEnvValue = "********"
+
+// DockerLabelContainerUUID is the Docker label holding the UUID of the
+// container in Vertex.
+const DockerLabelContainerUUID = "vertex.container.uuid"
+
 var (
 	ErrContainerNotFound     = errors.New("container not found")
 	ErrContainerStillRunning = errors.New("container still running")
+	ErrNoUpdateAvailable     = errors.New("no update available for this container")
+	ErrContainerPinned       = errors.New("the container is pinned to its current version")
+	ErrVersionNotFound       = errors.New("the version is not a tag of the image")
 )
 
 type Container struct {
@@ -29,20 +36,50 @@ type Container struct {
 
 	Service Service               `json:"service"`
 	UUID    uuid.UUID             `json:"uuid"`
-	Status  string                `json:"status"`
+	Status  ContainerStatus       `json:"status"`
 	Env     ContainerEnvVariables `json:"environment,omitempty"`
 
+	// Ports are the host ports bound at the last start, by container port
+	// like 80/tcp. They include the ports picked by Docker.
+	Ports map[string]string `json:"ports,omitempty"`
+
 	Update        *ContainerUpdate `json:"update,omitempty"`
 	ServiceUpdate ServiceUpdate    `json:"service_update,omitempty"`
 
 	CacheVersions []string `json:"cache_versions,omitempty"`
 }
 
+// ContainerDescription gathers everything known about a container, for its
+// details page. Docker is nil when the Docker container can't be inspected,
+// like when it was never started.
+type ContainerDescription struct {
+	Container   *Container     `json:"container"`
+	Docker      map[string]any `json:"docker,omitempty"`
+	Logs        []LogLine      `json:"logs"`
+	LastLogTime *time.Time     `json:"last_log_time,omitempty"`
+}
+
 type ContainerSearchQuery struct {
 	Tags     *[]string `json:"tags,omitempty"`
 	Features *[]string `json:"features,omitempty"`
 }
 
+// ContainerListQuery selects a page of the containers. A Limit of 0 returns
+// all the containers after Offset.
+type ContainerListQuery struct {
+	Offset int
+	Limit  int
+
+	// Summary omits the heavy fields of the containers, like the
+	// environment. The full container is available with a GET on it.
+	Summary bool
+}
+
+type ContainersPage struct {
+	Containers []*Container `json:"containers"`
+	Total      int          `json:"total"`
+}
+
 type ContainerUpdate struct {
 	CurrentVersion string `json:"current_version"`
 	LatestVersion  string `json:"latest_version"`
@@ -64,6 +101,56 @@ func NewContainer(id uuid.UUID, service Service) Container {
 	}
 }
 
+// Summary returns a copy of the container without its environment and
+// cached versions, for the list views.
+func (i *Container) Summary() *Container {
+	summary := *i
+	summary.Env = nil
+	summary.CacheVersions = nil
+	return &summary
+}
+
+// Redacted returns a copy of the container where the values of the secret
+// environment variables are replaced by RedactedEnvValue.
+func (i *Container) Redacted() *Container {
+	redacted := *i
+	redacted.Env = ContainerEnvVariables{}
+	for name, value := range i.Env {
+		redacted.Env[name] = value
+	}
+	for name := range redacted.Env {
+		if i.IsSecretEnv(name) {
+			redacted.Env[name] = RedactedEnvValue
+		}
+	}
+	return &redacted
+}
+
+// IsSecretEnv returns true if the service declares the variable as secret.
+func (i *Container) IsSecretEnv(name string) bool {
+	for _, env := range i.Service.Env {
+		if env.Name == name {
+			return env.Secret != nil && *env.Secret
+		}
+	}
+	return false
+}
+
+// ProxyHostname returns the hostname of the container in the reverse proxy,
+// like my-blog.example.com for the display name "My Blog".
+func (i *Container) ProxyHostname(baseDomain string) string {
+	slug := nonSlugRegex.ReplaceAllString(strings.ToLower(i.Name()), "-")
+	return strings.Trim(slug, "-") + "." + baseDomain
+}
+
+// Name returns the name displayed to the user.
+func (i *Container) Name() string {
+	if i.DisplayName != "" {
+		return i.DisplayName
+	}
+	return i.Service.Name
+}
+
 func (i *Container) DockerImageVertexName() string {
 	return "vertex_image_" + i.UUID.String()
 }
@@ -72,12 +159,38 @@ func (i *Container) DockerContainerName() string {
 	return "VERTEX_CONTAINER_" + i.UUID.String()
 }
 
+// DockerVolumeName is the name of a named Docker volume of the container.
+// It is prefixed by the container UUID, so two containers of the same
+// service don't share their data.
+func (i *Container) DockerVolumeName(name string) string {
+	return "vertex_volume_" + i.UUID.String() + "_" + name
+}
+
+// DockerLabels are the labels added to the Docker container, to find it
+// without listing all the containers of the host.
+func (i *Container) DockerLabels() map[string]string {
+	return map[string]string{
+		DockerLabelContainerUUID: i.UUID.String(),
+	}
+}
+
+// IsRunning returns true if the container exists in Docker. A paused
+// container is still running, as it keeps its ports and can be stopped.
 func (i *Container) IsRunning() bool {
 	return i.Status != ContainerStatusOff && i.Status != ContainerStatusError
 }
 
+func (i *Container) IsPaused() bool {
+	return i.Status == ContainerStatusPaused
+}
+
 func (i *Container) IsBusy() bool {
-	return i.Status == ContainerStatusBuilding || i.Status == ContainerStatusStarting || i.Status == ContainerStatusStopping
+	return i.Status == ContainerStatusQueued || i.Status == ContainerStatusBuilding || i.Status == ContainerStatusStarting || i.Status == ContainerStatusStopping
+}
+
+// HasEnvFile returns true if the environment is mounted as a file.
+func (i *Container) HasEnvFile() bool {
+	return i.EnvFile != nil && *i.EnvFile != ""
 }
 
 func (i *Container) LaunchOnStartup() bool {
@@ -1,3 +1,170 @@
 package types
 
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEnvReferenceInvalid  = errors.New("the environment reference is invalid")
+	ErrEnvReferenceNotFound = errors.New("the referenced environment variable doesn't exist")
+	ErrEnvReferenceCycle    = errors.New("the environment variables reference each other")
+)
+
+// envReferenceRegex matches the references to the environment of another
+// container, like ${container:<uuid>:POSTGRES_PASSWORD}.
+var envReferenceRegex = regexp.MustCompile(`\$\{container:([^:}]*):([^}]*)}`)
+
+// envLocalReferenceRegex matches the references to another variable of the
+// same container, like ${DB_PASSWORD}.
+var envLocalReferenceRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)}`)
+
 type ContainerEnvVariables map[string]string
+
+// Resolve returns a copy of the variables, where the references to other
+// variables of the container are expanded, and then the references to the
+// environment of other containers are replaced by their values. The
+// references to other containers are not resolved recursively.
+func (env ContainerEnvVariables) Resolve(getContainer func(uuid uuid.UUID) (*Container, error)) (ContainerEnvVariables, error) {
+	expanded, err := env.expandLocal()
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := ContainerEnvVariables{}
+	for name, value := range expanded {
+		var err error
+		resolved[name] = envReferenceRegex.ReplaceAllStringFunc(value, func(ref string) string {
+			if err != nil {
+				return ref
+			}
+
+			var v string
+			v, err = resolveEnvReference(ref, getContainer)
+			if err != nil {
+				err = fmt.Errorf("%s: %w", name, err)
+			}
+			return v
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resolved, nil
+}
+
+// expandLocal returns a copy of the variables, where the references to other
+// variables of the container are expanded recursively. The references to
+// unknown variables are kept as is.
+func (env ContainerEnvVariables) expandLocal() (ContainerEnvVariables, error) {
+	expanded := ContainerEnvVariables{}
+	expanding := map[string]bool{}
+
+	var expand func(name string) (string, error)
+	expand = func(name string) (string, error) {
+		if v, ok := expanded[name]; ok {
+			return v, nil
+		}
+		if expanding[name] {
+			return "", fmt.Errorf("%w: %s", ErrEnvReferenceCycle, name)
+		}
+		expanding[name] = true
+
+		var err error
+		v := envLocalReferenceRegex.ReplaceAllStringFunc(env[name], func(ref string) string {
+			if err != nil {
+				return ref
+			}
+			other := envLocalReferenceRegex.FindStringSubmatch(ref)[1]
+			if _, ok := env[other]; !ok {
+				return ref
+			}
+
+			var v string
+			v, err = expand(other)
+			return v
+		})
+		if err != nil {
+			return "", err
+		}
+
+		delete(expanding, name)
+		expanded[name] = v
+		return v, nil
+	}
+
+	for name := range env {
+		_, err := expand(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return expanded, nil
+}
+
+// secretEnv returns the variables of the container that are secret, or
+// whose value is resolved from a secret variable of the container or of
+// another container.
+func (i *Container) secretEnv(getContainer func(uuid uuid.UUID) (*Container, error)) map[string]bool {
+	secrets := map[string]bool{}
+	visiting := map[string]bool{}
+
+	var isSecret func(name string) bool
+	isSecret = func(name string) bool {
+		if secret, ok := secrets[name]; ok {
+			return secret
+		}
+		if visiting[name] {
+			return false
+		}
+		visiting[name] = true
+
+		secret := i.IsSecretEnv(name)
+		for _, ref := range envLocalReferenceRegex.FindAllStringSubmatch(i.Env[name], -1) {
+			if _, ok := i.Env[ref[1]]; ok && isSecret(ref[1]) {
+				secret = true
+			}
+		}
+		for _, ref := range envReferenceRegex.FindAllStringSubmatch(i.Env[name], -1) {
+			id, err := uuid.Parse(ref[1])
+			if err != nil {
+				continue
+			}
+			other, err := getContainer(id)
+			if err == nil && other.IsSecretEnv(ref[2]) {
+				secret = true
+			}
+		}
+
+		secrets[name] = secret
+		return secret
+	}
+
+	for name := range i.Env {
+		isSecret(name)
+	}
+	return secrets
+}
+
+func resolveEnvReference(ref string, getContainer func(uuid uuid.UUID) (*Container, error)) (string, error) {
+	matches := envReferenceRegex.FindStringSubmatch(ref)
+
+	id, err := uuid.Parse(matches[1])
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %w", ErrEnvReferenceInvalid, ref, err)
+	}
+
+	inst, err := getContainer(id)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", ref, err)
+	}
+
+	value, ok := inst.Env[matches[2]]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrEnvReferenceNotFound, ref)
+	}
+	return value, nil
+}
@@ -0,0 +1,276 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrExportFormatInvalid = errors.New("the export format is invalid")
+	ErrExportNotSupported  = errors.New("the container doesn't run with Docker")
+)
+
+type ExportFormat string
+
+const (
+	// ExportFormatCompose exports the container as a docker-compose.yml.
+	ExportFormatCompose ExportFormat = "compose"
+	// ExportFormatRun exports the container as a docker run command.
+	ExportFormatRun ExportFormat = "run"
+)
+
+// containerExport is the Docker configuration of a container, as created by
+// the runner.
+type containerExport struct {
+	name           string
+	image          string
+	ports          []string
+	volumes        []string
+	namedVolumes   []string
+	env            []string
+	capAdd         []string
+	sysctls        []string
+	extraHosts     []string
+	shmSize        string
+	init           bool
+	cmd            []string
+	network        string
+	networkAliases []string
+}
+
+// Export returns the Docker configuration of the container as a
+// docker-compose.yml or as a docker run command, to run it without Vertex.
+// The relative volumes are in volumesDir, and the references of the
+// environment are resolved with getContainer, like when the container
+// starts. If redact is true, the values of the secret environment variables,
+// and the ones resolved from a secret, are replaced by RedactedEnvValue.
+func (i *Container) Export(format ExportFormat, volumesDir string, redact bool, getContainer func(uuid uuid.UUID) (*Container, error)) (string, error) {
+	export, err := i.export(volumesDir, redact, getContainer)
+	if err != nil {
+		return "", err
+	}
+
+	switch format {
+	case ExportFormatCompose:
+		return export.compose()
+	case ExportFormatRun:
+		return export.run(), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrExportFormatInvalid, format)
+}
+
+func (i *Container) export(volumesDir string, redact bool, getContainer func(uuid uuid.UUID) (*Container, error)) (containerExport, error) {
+	docker := i.Service.Methods.Docker
+	if docker == nil {
+		return containerExport{}, ErrExportNotSupported
+	}
+
+	env, err := i.Env.Resolve(getContainer)
+	if err != nil {
+		return containerExport{}, err
+	}
+	secrets := i.secretEnv(getContainer)
+
+	name := strings.Trim(nonSlugRegex.ReplaceAllString(strings.ToLower(i.Name()), "-"), "-")
+	if name == "" {
+		name = "container"
+	}
+	export := containerExport{
+		name: name,
+	}
+
+	if docker.Dockerfile != nil {
+		export.image = i.DockerImageVertexName()
+	} else if docker.Image != nil {
+		export.image = i.GetImageNameWithTag()
+	}
+
+	export.ports = i.PortSpecs(env)
+	sort.Strings(export.ports)
+
+	if docker.Volumes != nil {
+		for source, target := range *docker.Volumes {
+			if volume, ok := strings.CutPrefix(source, VolumePrefix); ok {
+				volume = i.DockerVolumeName(volume)
+				export.namedVolumes = append(export.namedVolumes, volume)
+				source = volume
+			} else if !filepath.IsAbs(source) {
+				source = path.Join(volumesDir, source)
+			}
+			export.volumes = append(export.volumes, source+":"+target)
+		}
+		sort.Strings(export.volumes)
+		sort.Strings(export.namedVolumes)
+	}
+
+	if docker.Environment != nil {
+		for in, out := range *docker.Environment {
+			value := env[out]
+			if redact && secrets[out] {
+				value = RedactedEnvValue
+			}
+			export.env = append(export.env, in+"="+value)
+		}
+		sort.Strings(export.env)
+	}
+
+	if docker.Capabilities != nil {
+		export.capAdd = *docker.Capabilities
+	}
+	if docker.Sysctls != nil {
+		for k, v := range *docker.Sysctls {
+			export.sysctls = append(export.sysctls, k+"="+v)
+		}
+		sort.Strings(export.sysctls)
+	}
+	if docker.ExtraHosts != nil {
+		export.extraHosts = *docker.ExtraHosts
+	}
+	if docker.ShmSize != nil {
+		export.shmSize = *docker.ShmSize
+	}
+	if docker.Init != nil {
+		export.init = *docker.Init
+	}
+
+	if i.Command != nil {
+		export.cmd = strings.Split(*i.Command, " ")
+	} else if docker.Cmd != nil {
+		export.cmd = strings.Split(*docker.Cmd, " ")
+	}
+
+	if docker.Network != nil {
+		export.network = *docker.Network
+		if docker.NetworkAliases != nil {
+			export.networkAliases = *docker.NetworkAliases
+		}
+	}
+
+	return export, nil
+}
+
+func (e containerExport) compose() (string, error) {
+	service := map[string]any{
+		"image": e.image,
+	}
+	if len(e.ports) > 0 {
+		service["ports"] = e.ports
+	}
+	if len(e.volumes) > 0 {
+		service["volumes"] = e.volumes
+	}
+	if len(e.env) > 0 {
+		service["environment"] = e.env
+	}
+	if len(e.capAdd) > 0 {
+		service["cap_add"] = e.capAdd
+	}
+	if len(e.sysctls) > 0 {
+		service["sysctls"] = e.sysctls
+	}
+	if len(e.extraHosts) > 0 {
+		service["extra_hosts"] = e.extraHosts
+	}
+	if e.shmSize != "" {
+		service["shm_size"] = e.shmSize
+	}
+	if e.init {
+		service["init"] = true
+	}
+	if len(e.cmd) > 0 {
+		service["command"] = e.cmd
+	}
+
+	file := map[string]any{
+		"services": map[string]any{
+			e.name: service,
+		},
+	}
+
+	if e.network != "" {
+		network := map[string]any{}
+		if len(e.networkAliases) > 0 {
+			network["aliases"] = e.networkAliases
+		}
+		service["networks"] = map[string]any{
+			e.network: network,
+		}
+		file["networks"] = map[string]any{
+			e.network: map[string]any{},
+		}
+	}
+
+	if len(e.namedVolumes) > 0 {
+		volumes := map[string]any{}
+		for _, volume := range e.namedVolumes {
+			volumes[volume] = map[string]any{}
+		}
+		file["volumes"] = volumes
+	}
+
+	b, err := yaml.Marshal(file)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (e containerExport) run() string {
+	args := []string{"docker", "run", "-d", "--name", e.name}
+	for _, port := range e.ports {
+		args = append(args, "-p", port)
+	}
+	for _, volume := range e.volumes {
+		args = append(args, "-v", volume)
+	}
+	for _, env := range e.env {
+		args = append(args, "-e", env)
+	}
+	for _, capability := range e.capAdd {
+		args = append(args, "--cap-add", capability)
+	}
+	for _, sysctl := range e.sysctls {
+		args = append(args, "--sysctl", sysctl)
+	}
+	for _, host := range e.extraHosts {
+		args = append(args, "--add-host", host)
+	}
+	if e.shmSize != "" {
+		args = append(args, "--shm-size", e.shmSize)
+	}
+	if e.init {
+		args = append(args, "--init")
+	}
+	if e.network != "" {
+		args = append(args, "--network", e.network)
+		for _, alias := range e.networkAliases {
+			args = append(args, "--network-alias", alias)
+		}
+	}
+	args = append(args, e.image)
+	args = append(args, e.cmd...)
+
+	for i, arg := range args {
+		args[i] = shellQuote(arg)
+	}
+	return strings.Join(args, " ") + "\n"
+}
+
+// shellSafeRegex matches the arguments that don't need to be quoted.
+var shellSafeRegex = regexp.MustCompile(`^[a-zA-Z0-9_./:=@%+,-]+$`)
+
+// shellQuote quotes an argument for a POSIX shell.
+func shellQuote(arg string) string {
+	if shellSafeRegex.MatchString(arg) {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
@@ -0,0 +1,138 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type ContainerExportTestSuite struct {
+	suite.Suite
+
+	container *Container
+	database  *Container
+}
+
+func TestContainerExportTestSuite(t *testing.T) {
+	suite.Run(t, new(ContainerExportTestSuite))
+}
+
+func (suite *ContainerExportTestSuite) SetupTest() {
+	image := "postgres"
+	version := "16"
+	secret := true
+	capabilities := []string{"NET_ADMIN"}
+	shmSize := "256m"
+	network := "vertex_stack_blog"
+	aliases := []string{"db"}
+
+	suite.container = &Container{
+		Service: Service{
+			Name: "Postgres",
+			Env: []ServiceEnv{
+				{Type: "port", Name: "PORT", Default: "5432"},
+				{Name: "USER", Default: "postgres"},
+				{Name: "PASSWORD", Secret: &secret},
+			},
+			Methods: ServiceMethods{
+				Docker: &ServiceMethodDocker{
+					Image:          &image,
+					Ports:          &map[string]string{"5432": "5432"},
+					Volumes:        &map[string]string{"data": "/var/lib/postgresql/data", "volume:config": "/etc/postgresql"},
+					Environment:    &map[string]string{"POSTGRES_USER": "USER", "POSTGRES_PASSWORD": "PASSWORD"},
+					Capabilities:   &capabilities,
+					Sysctls:        &map[string]string{"net.core.somaxconn": "1024"},
+					ShmSize:        &shmSize,
+					Network:        &network,
+					NetworkAliases: &aliases,
+				},
+			},
+		},
+		ContainerSettings: ContainerSettings{
+			DisplayName: "My Database",
+			Version:     &version,
+		},
+		Env: ContainerEnvVariables{
+			"PORT":     "5433",
+			"USER":     "admin",
+			"PASSWORD": "it's a secret",
+		},
+	}
+}
+
+func (suite *ContainerExportTestSuite) getContainer(id uuid.UUID) (*Container, error) {
+	if suite.database != nil && id == suite.database.UUID {
+		return suite.database, nil
+	}
+	return nil, ErrContainerNotFound
+}
+
+func (suite *ContainerExportTestSuite) TestExportRun() {
+	export, err := suite.container.Export(ExportFormatRun, "/vertex/volumes", true, suite.getContainer)
+	suite.Require().NoError(err)
+	suite.Equal("docker run -d --name my-database"+
+		" -p 5433:5432"+
+		" -v /vertex/volumes/data:/var/lib/postgresql/data"+
+		" -v vertex_volume_"+suite.container.UUID.String()+"_config:/etc/postgresql"+
+		" -e 'POSTGRES_PASSWORD=********' -e POSTGRES_USER=admin"+
+		" --cap-add NET_ADMIN --sysctl net.core.somaxconn=1024 --shm-size 256m"+
+		" --network vertex_stack_blog --network-alias db"+
+		" postgres:16\n", export)
+
+	export, err = suite.container.Export(ExportFormatRun, "/vertex/volumes", false, suite.getContainer)
+	suite.Require().NoError(err)
+	suite.Contains(export, ` -e 'POSTGRES_PASSWORD=it'\''s a secret' `)
+}
+
+func (suite *ContainerExportTestSuite) TestExportCompose() {
+	export, err := suite.container.Export(ExportFormatCompose, "/vertex/volumes", true, suite.getContainer)
+	suite.Require().NoError(err)
+
+	project, err := ParseCompose("blog", []byte(export))
+	suite.Require().NoError(err)
+	suite.Require().Len(project.Services, 1)
+	suite.Contains(export, "POSTGRES_PASSWORD=********")
+	suite.Contains(export, "vertex_stack_blog")
+	suite.Contains(export, "shm_size: 256m")
+}
+
+func (suite *ContainerExportTestSuite) TestExportResolvesEnv() {
+	secret := true
+	suite.database = &Container{
+		UUID: uuid.New(),
+		Service: Service{
+			Env: []ServiceEnv{{Name: "ROOT_PASSWORD", Secret: &secret}},
+		},
+		Env: ContainerEnvVariables{"ROOT_PASSWORD": "root"},
+	}
+	suite.container.Service.Env = append(suite.container.Service.Env, ServiceEnv{Name: "URL"})
+	(*suite.container.Service.Methods.Docker.Environment)["DATABASE_URL"] = "URL"
+	suite.container.Env["USER"] = "${container:" + suite.database.UUID.String() + ":ROOT_PASSWORD}"
+	suite.container.Env["URL"] = "postgres://${USER}:${PASSWORD}@db"
+
+	// The values resolved from a secret are redacted too.
+	export, err := suite.container.Export(ExportFormatRun, "/vertex/volumes", true, suite.getContainer)
+	suite.Require().NoError(err)
+	suite.Contains(export, " -e 'DATABASE_URL=********' ")
+	suite.Contains(export, " -e 'POSTGRES_USER=********' ")
+
+	export, err = suite.container.Export(ExportFormatRun, "/vertex/volumes", false, suite.getContainer)
+	suite.Require().NoError(err)
+	suite.Contains(export, ` -e 'DATABASE_URL=postgres://root:it'\''s a secret@db' `)
+	suite.Contains(export, " -e POSTGRES_USER=root ")
+	suite.NotContains(export, "${")
+
+	suite.container.Env["USER"] = "${container:" + uuid.NewString() + ":ROOT_PASSWORD}"
+	_, err = suite.container.Export(ExportFormatRun, "/vertex/volumes", false, suite.getContainer)
+	suite.ErrorIs(err, ErrContainerNotFound)
+}
+
+func (suite *ContainerExportTestSuite) TestExportInvalid() {
+	_, err := suite.container.Export("kubernetes", "/vertex/volumes", true, suite.getContainer)
+	suite.ErrorIs(err, ErrExportFormatInvalid)
+
+	suite.container.Service.Methods.Docker = nil
+	_, err = suite.container.Export(ExportFormatRun, "/vertex/volumes", true, suite.getContainer)
+	suite.ErrorIs(err, ErrExportNotSupported)
+}
@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/vertex-center/vertex/pkg/log"
 )
@@ -15,14 +18,105 @@ const (
 	LogKindDownloads = "downloads"
 	LogKindVertexOut = "vertex_out"
 	LogKindVertexErr = "vertex_err"
+
+	// LogKindBuild is the output of the build of a Dockerfile, kept apart
+	// from the runtime logs.
+	LogKindBuild = "build"
 )
 
-var ErrBufferEmpty = errors.New("the buffer is empty")
+const (
+	LogLevelDebug LogLevel = "debug"
+	LogLevelInfo  LogLevel = "info"
+	LogLevelWarn  LogLevel = "warn"
+	LogLevelError LogLevel = "error"
+)
+
+var (
+	ErrBufferEmpty     = errors.New("the buffer is empty")
+	ErrLogLevelInvalid = errors.New("the log level is invalid")
+)
+
+var logLevelRegex = regexp.MustCompile(`^\W*([a-zA-Z]+)`)
 
 type LogLine struct {
 	Id      int            `json:"id"`
 	Kind    string         `json:"kind"`
+	Level   LogLevel       `json:"level,omitempty"`
 	Message LogLineMessage `json:"message"`
+
+	// Time is the time Docker received the line, if the timestamps were
+	// requested.
+	Time *time.Time `json:"time,omitempty"`
+}
+
+// SplitLogTimestamp splits the timestamp added by Docker at the beginning of
+// a line, like "2006-01-02T15:04:05.999999999Z message". It returns false,
+// with the line as the message, if the line doesn't start with a timestamp.
+func SplitLogTimestamp(line string) (time.Time, string, bool) {
+	ts, message, _ := strings.Cut(line, " ")
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, line, false
+	}
+	return t, message, true
+}
+
+// LogLevel is the severity of a log line. It is empty when the level
+// could not be detected.
+type LogLevel string
+
+// ParseLogLevel converts a level name like "WARNING" or "err" to a LogLevel.
+func ParseLogLevel(s string) (LogLevel, error) {
+	switch strings.ToUpper(s) {
+	case "TRACE", "DEBUG", "DBG":
+		return LogLevelDebug, nil
+	case "INFO", "INF", "NOTICE":
+		return LogLevelInfo, nil
+	case "WARN", "WARNING", "WRN":
+		return LogLevelWarn, nil
+	case "ERROR", "ERR", "FATAL", "CRITICAL", "CRIT", "PANIC":
+		return LogLevelError, nil
+	}
+	return "", ErrLogLevelInvalid
+}
+
+// DetectLogLevel reads the level from the beginning of the message, like
+// "INFO ..." or "[WARN] ...". If levelRegex is not nil, it is used instead
+// and the level is read from its first capture group. It returns an empty
+// level if none was found.
+func DetectLogLevel(message string, levelRegex *regexp.Regexp) LogLevel {
+	if levelRegex == nil {
+		levelRegex = logLevelRegex
+	}
+	matches := levelRegex.FindStringSubmatch(message)
+	if len(matches) < 2 {
+		return ""
+	}
+	level, err := ParseLogLevel(matches[1])
+	if err != nil {
+		return ""
+	}
+	return level
+}
+
+func (l LogLevel) priority() int {
+	switch l {
+	case LogLevelDebug:
+		return 1
+	case LogLevelInfo:
+		return 2
+	case LogLevelWarn:
+		return 3
+	case LogLevelError:
+		return 4
+	}
+	return 0
+}
+
+// IsAtLeast returns true if the level is at least as severe as min.
+// Lines without a level are never kept by a filter.
+func (l LogLevel) IsAtLeast(min LogLevel) bool {
+	return l.priority() > 0 && l.priority() >= min.priority()
 }
 
 type LogLineMessage interface {
@@ -0,0 +1,32 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ContainerLogsTestSuite struct {
+	suite.Suite
+}
+
+func TestContainerLogsTestSuite(t *testing.T) {
+	suite.Run(t, new(ContainerLogsTestSuite))
+}
+
+func (suite *ContainerLogsTestSuite) TestSplitLogTimestamp() {
+	t, message, ok := SplitLogTimestamp("2023-10-16T03:24:28.123456789Z INFO server started")
+	suite.True(ok)
+	suite.Equal(time.Date(2023, 10, 16, 3, 24, 28, 123456789, time.UTC), t)
+	suite.Equal("INFO server started", message)
+
+	// An empty line only has its timestamp.
+	_, message, ok = SplitLogTimestamp("2023-10-16T03:24:28Z")
+	suite.True(ok)
+	suite.Equal("", message)
+
+	_, message, ok = SplitLogTimestamp("INFO server started")
+	suite.False(ok)
+	suite.Equal("INFO server started", message)
+}
@@ -0,0 +1,247 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrPortConflict = errors.New("the port is already in use")
+)
+
+// PortAuto is the value of a port environment variable letting Docker pick
+// a free host port. The port 0 is accepted too.
+const PortAuto = "auto"
+
+// IsPortAuto returns true if the host port is picked by Docker.
+func IsPortAuto(port string) bool {
+	return port == PortAuto || port == "0"
+}
+
+// PortConflictError is returned when a host port of a container is already
+// bound by another container, or by another process of the host.
+type PortConflictError struct {
+	// Port is the host port in conflict, like 8080/tcp.
+	Port string
+
+	// ContainerUUID and ContainerName describe the container binding the
+	// port. They are empty if the port is used by another process.
+	ContainerUUID uuid.UUID
+	ContainerName string
+}
+
+func (e *PortConflictError) Error() string {
+	if e.ContainerName == "" {
+		return fmt.Sprintf("the port %s is already in use by another process", e.Port)
+	}
+	return fmt.Sprintf("the port %s is already in use by the container %s (%s)", e.Port, e.ContainerName, e.ContainerUUID)
+}
+
+func (e *PortConflictError) Is(target error) bool {
+	return target == ErrPortConflict
+}
+
+// isPortEnv returns true if the variable holds a host port of the container.
+func (i *Container) isPortEnv(name string) bool {
+	for _, e := range i.Service.Env {
+		if e.Name == name {
+			return e.Type == "port"
+		}
+	}
+	return false
+}
+
+// splitHostIP splits the output port of a port mapping, like [::]:8080 or
+// 127.0.0.1:8080/udp, into its host IP and its port. The IPv6 addresses are
+// in brackets, like in the Docker port specs.
+func splitHostIP(out string) (ip string, port string) {
+	if strings.HasPrefix(out, "[") {
+		if ip, port, ok := strings.Cut(out[1:], "]:"); ok {
+			return ip, port
+		}
+		return "", out
+	}
+	if ip, port, ok := strings.Cut(out, ":"); ok {
+		return ip, port
+	}
+	return "", out
+}
+
+// joinHostIP prefixes the port with the host IP, if any.
+func joinHostIP(ip string, port string) string {
+	if strings.Contains(ip, ":") {
+		return "[" + ip + "]:" + port
+	} else if ip != "" {
+		return ip + ":" + port
+	}
+	return port
+}
+
+// PortSpecs returns the port specs of the container, in the
+// [ip:]host:container/protocol form parsed by nat.ParsePortSpecs. The host
+// port is the value of the port environment variable whose default is the
+// output port. The ports can be ranges like 7000-7010, and the protocol, tcp
+// by default, is set with a /udp suffix on either side. The output port can
+// start with the host IP to bind, like [::]:8080 for IPv6.
+func (i *Container) PortSpecs(env ContainerEnvVariables) []string {
+	if i.Service.Methods.Docker == nil || i.Service.Methods.Docker.Ports == nil {
+		return nil
+	}
+
+	var specs []string
+	for in, out := range *i.Service.Methods.Docker.Ports {
+		containerPort, proto, _ := strings.Cut(in, "/")
+		hostIP, out := splitHostIP(out)
+		hostPort, hostProto, _ := strings.Cut(out, "/")
+		if proto == "" {
+			proto = hostProto
+		}
+
+		for _, e := range i.Service.Env {
+			if e.Type != "port" || e.Default != hostPort {
+				continue
+			}
+			spec := containerPort
+			if !IsPortAuto(env[e.Name]) {
+				spec = env[e.Name] + ":" + spec
+			} else if hostIP != "" {
+				spec = ":" + spec
+			}
+			spec = joinHostIP(hostIP, spec)
+			if proto != "" {
+				spec += "/" + proto
+			}
+			specs = append(specs, spec)
+			break
+		}
+	}
+	return specs
+}
+
+// HostPort returns the host port of a port environment variable. If the
+// port is picked by Docker, it is the port allocated at the last start.
+func (i *Container) HostPort(name string) (string, bool) {
+	port, ok := i.Env[name]
+	if !ok || port == "" {
+		for _, e := range i.Service.Env {
+			if e.Name == name {
+				port = e.Default
+			}
+		}
+	}
+	if !IsPortAuto(port) {
+		return port, port != ""
+	}
+
+	if i.Service.Methods.Docker == nil || i.Service.Methods.Docker.Ports == nil {
+		return "", false
+	}
+	for _, e := range i.Service.Env {
+		if e.Name != name {
+			continue
+		}
+		for in, out := range *i.Service.Methods.Docker.Ports {
+			containerPort, proto, _ := strings.Cut(in, "/")
+			_, out := splitHostIP(out)
+			hostPort, hostProto, _ := strings.Cut(out, "/")
+			if hostPort != e.Default {
+				continue
+			}
+			if proto == "" {
+				proto = hostProto
+			}
+			if proto == "" {
+				proto = "tcp"
+			}
+			port, ok := i.Ports[containerPort+"/"+proto]
+			return port, ok
+		}
+	}
+	return "", false
+}
+
+// WebHostPort returns the host port of the web page of the container. It is
+// the port of the first client URL of the service, or of its first URL.
+func (i *Container) WebHostPort() (string, bool) {
+	if len(i.Service.URLs) == 0 {
+		return "", false
+	}
+	u := i.Service.URLs[0]
+	for _, candidate := range i.Service.URLs {
+		if candidate.Kind == "client" {
+			u = candidate
+			break
+		}
+	}
+
+	// The port of the URL is the default value of its port variable.
+	for _, e := range i.Service.Env {
+		if e.Type == "port" && e.Default == u.Port {
+			return i.HostPort(e.Name)
+		}
+	}
+	return "", false
+}
+
+// ContainerURL is a URL of the service, on the host port where the
+// container is published.
+type ContainerURL struct {
+	Name string `json:"name,omitempty"`
+	Kind string `json:"kind,omitempty"`
+	Port string `json:"port"`
+	URL  string `json:"url"`
+}
+
+// WebURLs returns the URLs of the service on the given host, like
+// http://192.168.1.10:9090/, skipping the ones whose port isn't published.
+func (i *Container) WebURLs(host string) []ContainerURL {
+	var urls []ContainerURL
+	for _, u := range i.Service.URLs {
+		for _, e := range i.Service.Env {
+			if e.Type != "port" || e.Default != u.Port {
+				continue
+			}
+			port, ok := i.HostPort(e.Name)
+			if !ok {
+				break
+			}
+			home := "/"
+			if u.HomeRoute != nil {
+				home = "/" + strings.TrimPrefix(*u.HomeRoute, "/")
+			}
+			urls = append(urls, ContainerURL{
+				Name: u.Name,
+				Kind: u.Kind,
+				Port: port,
+				URL:  "http://" + net.JoinHostPort(host, port) + home,
+			})
+			break
+		}
+	}
+	return urls
+}
+
+// HostPorts returns the host ports bound by the container, like 8080/tcp.
+// The ranges are expanded to each of their ports.
+func (i *Container) HostPorts(env ContainerEnvVariables) ([]string, error) {
+	_, bindings, err := nat.ParsePortSpecs(i.PortSpecs(env))
+	if err != nil {
+		return nil, err
+	}
+
+	var ports []string
+	for port, bs := range bindings {
+		for _, b := range bs {
+			if b.HostPort == "" {
+				continue
+			}
+			ports = append(ports, b.HostPort+"/"+port.Proto())
+		}
+	}
+	return ports, nil
+}
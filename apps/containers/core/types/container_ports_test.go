@@ -0,0 +1,164 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/suite"
+)
+
+type ContainerPortsTestSuite struct {
+	suite.Suite
+
+	container *Container
+}
+
+func TestContainerPortsTestSuite(t *testing.T) {
+	suite.Run(t, new(ContainerPortsTestSuite))
+}
+
+func (suite *ContainerPortsTestSuite) SetupTest() {
+	suite.container = &Container{
+		Service: Service{
+			Env: []ServiceEnv{
+				{Type: "port", Name: "PORT", Default: "8080"},
+				{Type: "port", Name: "PORT_GAME", Default: "27015"},
+				{Type: "port", Name: "PORT_RANGE", Default: "7000-7001"},
+			},
+			Methods: ServiceMethods{
+				Docker: &ServiceMethodDocker{},
+			},
+		},
+	}
+}
+
+func (suite *ContainerPortsTestSuite) TestPortSpecs() {
+	env := ContainerEnvVariables{
+		"PORT":       "9090",
+		"PORT_GAME":  "27016",
+		"PORT_RANGE": "8000-8001",
+	}
+
+	tests := []struct {
+		in, out string
+		spec    string
+	}{
+		{"80", "8080", "9090:80"},
+		{"27015/udp", "27015", "27016:27015/udp"},
+		{"27015", "27015/udp", "27016:27015/udp"},
+		{"7000-7001/udp", "7000-7001", "8000-8001:7000-7001/udp"},
+		{"80", "[::]:8080", "[::]:9090:80"},
+		{"80", "127.0.0.1:8080/udp", "127.0.0.1:9090:80/udp"},
+	}
+	for _, test := range tests {
+		suite.container.Service.Methods.Docker.Ports = &map[string]string{test.in: test.out}
+		suite.Equal([]string{test.spec}, suite.container.PortSpecs(env))
+	}
+
+	// The auto ports are picked by Docker.
+	suite.container.Service.Methods.Docker.Ports = &map[string]string{"80": "8080"}
+	suite.Equal([]string{"80"}, suite.container.PortSpecs(ContainerEnvVariables{"PORT": "auto"}))
+	suite.Equal([]string{"80"}, suite.container.PortSpecs(ContainerEnvVariables{"PORT": "0"}))
+	suite.container.Service.Methods.Docker.Ports = &map[string]string{"80": "[::]:8080"}
+	suite.Equal([]string{"[::]::80"}, suite.container.PortSpecs(ContainerEnvVariables{"PORT": "auto"}))
+
+	// The ports without a port environment variable are not bound.
+	suite.container.Service.Methods.Docker.Ports = &map[string]string{"80": "1234"}
+	suite.Empty(suite.container.PortSpecs(env))
+}
+
+func (suite *ContainerPortsTestSuite) TestHostPorts() {
+	suite.container.Service.Methods.Docker.Ports = &map[string]string{
+		"80":            "8080",
+		"7000-7001/udp": "7000-7001",
+	}
+	env := ContainerEnvVariables{
+		"PORT":       "9090",
+		"PORT_RANGE": "8000-8001",
+	}
+
+	ports, err := suite.container.HostPorts(env)
+	suite.Require().NoError(err)
+	suite.ElementsMatch([]string{"9090/tcp", "8000/udp", "8001/udp"}, ports)
+}
+
+func (suite *ContainerPortsTestSuite) TestPortSpecsHostIP() {
+	suite.container.Service.Methods.Docker.Ports = &map[string]string{"80": "[::]:8080"}
+
+	_, bindings, err := nat.ParsePortSpecs(suite.container.PortSpecs(ContainerEnvVariables{"PORT": "9090"}))
+	suite.Require().NoError(err)
+	suite.Equal([]nat.PortBinding{{HostIP: "::", HostPort: "9090"}}, bindings["80/tcp"])
+
+	port, ok := suite.container.HostPort("PORT")
+	suite.True(ok)
+	suite.Equal("8080", port)
+}
+
+func (suite *ContainerPortsTestSuite) TestHostPort() {
+	suite.container.Service.Methods.Docker.Ports = &map[string]string{
+		"80":        "8080",
+		"27015/udp": "27015",
+	}
+	suite.container.Env = ContainerEnvVariables{
+		"PORT":      "9090",
+		"PORT_GAME": PortAuto,
+	}
+
+	port, ok := suite.container.HostPort("PORT")
+	suite.True(ok)
+	suite.Equal("9090", port)
+
+	// The auto port is unknown until the container starts.
+	_, ok = suite.container.HostPort("PORT_GAME")
+	suite.False(ok)
+
+	suite.container.Ports = map[string]string{"27015/udp": "49153"}
+	port, ok = suite.container.HostPort("PORT_GAME")
+	suite.True(ok)
+	suite.Equal("49153", port)
+
+	// The default is used if the variable is not set.
+	port, ok = suite.container.HostPort("PORT_RANGE")
+	suite.True(ok)
+	suite.Equal("7000-7001", port)
+}
+
+func (suite *ContainerPortsTestSuite) TestWebHostPort() {
+	suite.container.Env = ContainerEnvVariables{"PORT": "9090", "PORT_GAME": "27016"}
+
+	_, ok := suite.container.WebHostPort()
+	suite.False(ok)
+
+	suite.container.Service.URLs = []URL{
+		{Port: "27015", Kind: "server"},
+		{Port: "8080", Kind: "client"},
+	}
+	port, ok := suite.container.WebHostPort()
+	suite.True(ok)
+	suite.Equal("9090", port)
+}
+
+func (suite *ContainerPortsTestSuite) TestWebURLs() {
+	home := "admin"
+	suite.container.Env = ContainerEnvVariables{"PORT": "9090", "PORT_GAME": "auto"}
+	suite.container.Service.URLs = []URL{
+		{Name: "Dashboard", Port: "8080", Kind: "client", HomeRoute: &home},
+		{Name: "Game", Port: "27015", Kind: "server"},
+	}
+
+	// The port picked by Docker is unknown until the container starts.
+	suite.Equal([]ContainerURL{
+		{Name: "Dashboard", Kind: "client", Port: "9090", URL: "http://192.168.1.10:9090/admin"},
+	}, suite.container.WebURLs("192.168.1.10"))
+
+	suite.container.Service.Methods.Docker.Ports = &map[string]string{"27015": "27015"}
+	suite.container.Ports = map[string]string{"27015/tcp": "49153"}
+	urls := suite.container.WebURLs("::1")
+	suite.Require().Len(urls, 2)
+	suite.Equal("http://[::1]:49153/", urls[1].URL)
+}
+
+func (suite *ContainerPortsTestSuite) TestProxyHostname() {
+	suite.container.DisplayName = "My Blog (v2)"
+	suite.Equal("my-blog-v2.example.com", suite.container.ProxyHostname("example.com"))
+}
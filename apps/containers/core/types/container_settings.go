@@ -23,4 +23,30 @@ type ContainerSettings struct {
 
 	// Tags are the tags assigned to the container.
 	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// Command overrides the command of the service when running with Docker.
+	Command *string `json:"command,omitempty" yaml:"command,omitempty"`
+
+	// Pinned indicates that the container must stay on its current version.
+	// It is not checked for updates, and its updates can't be applied.
+	Pinned bool `json:"pinned,omitempty" yaml:"pinned,omitempty"`
+
+	// EnvFile is the path where the environment is mounted as a file in the
+	// container. If set, the environment is not passed as variables, so it
+	// doesn't appear in docker inspect.
+	EnvFile *string `json:"env_file,omitempty" yaml:"env_file,omitempty"`
+
+	// IdleTimeout is the number of minutes without requests through the
+	// reverse proxy nor logs after which the container is stopped.
+	// The container is never stopped if it is 0.
+	IdleTimeout int `json:"idle_timeout,omitempty" yaml:"idle_timeout,omitempty"`
+
+	// IdleAutoStart starts the container again on the next request through
+	// the reverse proxy, if it was stopped by its idle timeout.
+	IdleAutoStart bool `json:"idle_auto_start,omitempty" yaml:"idle_auto_start,omitempty"`
+
+	// ProxyAutoRegister adds a redirect of the reverse proxy to the web
+	// port of the container when it starts, and removes it when the
+	// container is deleted.
+	ProxyAutoRegister bool `json:"proxy_auto_register,omitempty" yaml:"proxy_auto_register,omitempty"`
 }
@@ -0,0 +1,170 @@
+package types
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+)
+
+// SettingsApply tells how a change of the settings is applied to the
+// container. The values are ordered from the least to the most disruptive.
+type SettingsApply string
+
+const (
+	// SettingsApplyHot changes are applied without touching the container.
+	SettingsApplyHot SettingsApply = "hot"
+	// SettingsApplyRestart changes are applied at the next start.
+	SettingsApplyRestart SettingsApply = "restart"
+	// SettingsApplyRecreate changes need the Docker container to be
+	// recreated, which restarts it.
+	SettingsApplyRecreate SettingsApply = "recreate"
+)
+
+var settingsApplyOrder = []SettingsApply{
+	SettingsApplyHot,
+	SettingsApplyRestart,
+	SettingsApplyRecreate,
+}
+
+// ContainerSettingsPatch is a partial change of the settings of a container.
+// The nil fields are kept as is.
+type ContainerSettingsPatch struct {
+	LaunchOnStartup *bool                `json:"launch_on_startup,omitempty"`
+	DisplayName     *string              `json:"display_name,omitempty"`
+	Databases       map[string]uuid.UUID `json:"databases,omitempty"`
+	Version         *string              `json:"version,omitempty"`
+	Tags            []string             `json:"tags,omitempty"`
+	Command         *string              `json:"command,omitempty"`
+	Pinned          *bool                `json:"pinned,omitempty"`
+	EnvFile         *string              `json:"env_file,omitempty"`
+	IdleTimeout     *int                 `json:"idle_timeout,omitempty"`
+	IdleAutoStart   *bool                `json:"idle_auto_start,omitempty"`
+
+	ProxyAutoRegister *bool `json:"proxy_auto_register,omitempty"`
+}
+
+// ContainerSettingsPlanBody is a change of the settings and the environment
+// of a container, to plan before saving it.
+type ContainerSettingsPlanBody struct {
+	ContainerSettingsPatch
+	Environment ContainerEnvVariables `json:"environment,omitempty"`
+}
+
+// ContainerSettingsChange is a field whose value would change.
+type ContainerSettingsChange struct {
+	Field    string        `json:"field"`
+	Current  any           `json:"current"`
+	Proposed any           `json:"proposed"`
+	Apply    SettingsApply `json:"apply"`
+}
+
+// ContainerSettingsPlan lists the changes of a patch, and how the most
+// disruptive of them is applied.
+type ContainerSettingsPlan struct {
+	Changes []ContainerSettingsChange `json:"changes"`
+	Apply   SettingsApply             `json:"apply"`
+}
+
+// Plan compares the patch with the current settings and environment of the
+// container, without changing it. The values of the secret variables are
+// redacted.
+func (b ContainerSettingsPlanBody) Plan(inst *Container) ContainerSettingsPlan {
+	plan := ContainerSettingsPlan{
+		Changes: []ContainerSettingsChange{},
+		Apply:   SettingsApplyHot,
+	}
+
+	change := func(field string, current, proposed any, apply SettingsApply) {
+		plan.Changes = append(plan.Changes, ContainerSettingsChange{
+			Field:    field,
+			Current:  current,
+			Proposed: proposed,
+			Apply:    apply,
+		})
+		if slices.Index(settingsApplyOrder, apply) > slices.Index(settingsApplyOrder, plan.Apply) {
+			plan.Apply = apply
+		}
+	}
+	add := func(field string, current, proposed any, apply SettingsApply) {
+		if !reflect.DeepEqual(current, proposed) {
+			change(field, current, proposed, apply)
+		}
+	}
+
+	// The environment is passed to Docker when the container is created,
+	// unless it is mounted as a file written at each start.
+	envApply := SettingsApplyRecreate
+	if inst.HasEnvFile() {
+		envApply = SettingsApplyRestart
+	}
+
+	p := b.ContainerSettingsPatch
+	if p.LaunchOnStartup != nil {
+		add("launch_on_startup", inst.LaunchOnStartup(), *p.LaunchOnStartup, SettingsApplyHot)
+	}
+	if p.Pinned != nil {
+		add("pinned", inst.Pinned, *p.Pinned, SettingsApplyHot)
+	}
+	if p.DisplayName != nil && *p.DisplayName != "" {
+		add("display_name", inst.DisplayName, *p.DisplayName, SettingsApplyHot)
+	}
+	if p.Databases != nil {
+		add("databases", inst.Databases, p.Databases, envApply)
+	}
+	if p.Version != nil {
+		add("version", inst.GetVersion(), *p.Version, SettingsApplyRecreate)
+	}
+	if p.Tags != nil {
+		add("tags", inst.Tags, p.Tags, SettingsApplyHot)
+	}
+	if p.IdleTimeout != nil {
+		add("idle_timeout", inst.IdleTimeout, *p.IdleTimeout, SettingsApplyHot)
+	}
+	if p.IdleAutoStart != nil {
+		add("idle_auto_start", inst.IdleAutoStart, *p.IdleAutoStart, SettingsApplyHot)
+	}
+	if p.ProxyAutoRegister != nil {
+		// The redirect is registered when the container starts.
+		add("proxy_auto_register", inst.ProxyAutoRegister, *p.ProxyAutoRegister, SettingsApplyRestart)
+	}
+	if p.EnvFile != nil {
+		current := ""
+		if inst.EnvFile != nil {
+			current = *inst.EnvFile
+		}
+		add("env_file", current, *p.EnvFile, SettingsApplyRecreate)
+	}
+	if p.Command != nil {
+		current := ""
+		if inst.Command != nil {
+			current = *inst.Command
+		}
+		add("command", current, *p.Command, SettingsApplyRecreate)
+	}
+
+	for name, proposed := range b.Environment {
+		current := inst.Env[name]
+		if current == proposed {
+			continue
+		}
+
+		// The ports are bound when the container is created, even with an
+		// environment file.
+		apply := envApply
+		if inst.isPortEnv(name) {
+			apply = SettingsApplyRecreate
+		}
+
+		if inst.IsSecretEnv(name) {
+			current, proposed = RedactedEnvValue, RedactedEnvValue
+		}
+		change("environment."+name, current, proposed, apply)
+	}
+
+	slices.SortStableFunc(plan.Changes, func(a, b ContainerSettingsChange) int {
+		return strings.Compare(a.Field, b.Field)
+	})
+	return plan
+}
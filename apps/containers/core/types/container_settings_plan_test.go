@@ -0,0 +1,84 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ContainerSettingsPlanTestSuite struct {
+	suite.Suite
+
+	inst *Container
+}
+
+func TestContainerSettingsPlanTestSuite(t *testing.T) {
+	suite.Run(t, new(ContainerSettingsPlanTestSuite))
+}
+
+func (suite *ContainerSettingsPlanTestSuite) SetupTest() {
+	secret := true
+	suite.inst = &Container{
+		ContainerSettings: ContainerSettings{DisplayName: "Postgres"},
+		Service: Service{
+			Env: []ServiceEnv{
+				{Type: "port", Name: "PORT", Default: "5432"},
+				{Type: "string", Name: "PASSWORD", Secret: &secret},
+				{Type: "string", Name: "USER"},
+			},
+		},
+		Env: ContainerEnvVariables{"PORT": "5432", "PASSWORD": "secret", "USER": "admin"},
+	}
+}
+
+func (suite *ContainerSettingsPlanTestSuite) TestPlanHot() {
+	name := "Database"
+	plan := ContainerSettingsPlanBody{
+		ContainerSettingsPatch: ContainerSettingsPatch{DisplayName: &name},
+		Environment:            ContainerEnvVariables{"USER": "admin"},
+	}.Plan(suite.inst)
+
+	suite.Equal(SettingsApplyHot, plan.Apply)
+	suite.Equal([]ContainerSettingsChange{
+		{Field: "display_name", Current: "Postgres", Proposed: "Database", Apply: SettingsApplyHot},
+	}, plan.Changes)
+
+	// The container is kept.
+	suite.Equal("Postgres", suite.inst.DisplayName)
+}
+
+func (suite *ContainerSettingsPlanTestSuite) TestPlanEnvironment() {
+	plan := ContainerSettingsPlanBody{
+		Environment: ContainerEnvVariables{"PASSWORD": "other"},
+	}.Plan(suite.inst)
+
+	suite.Equal(SettingsApplyRecreate, plan.Apply)
+	suite.Equal([]ContainerSettingsChange{
+		{Field: "environment.PASSWORD", Current: RedactedEnvValue, Proposed: RedactedEnvValue, Apply: SettingsApplyRecreate},
+	}, plan.Changes)
+
+	// With an environment file, only the ports need a recreate.
+	envFile := "/app/.env"
+	suite.inst.EnvFile = &envFile
+	plan = ContainerSettingsPlanBody{
+		Environment: ContainerEnvVariables{"USER": "root"},
+	}.Plan(suite.inst)
+	suite.Equal(SettingsApplyRestart, plan.Apply)
+
+	plan = ContainerSettingsPlanBody{
+		Environment: ContainerEnvVariables{"USER": "root", "PORT": "5433"},
+	}.Plan(suite.inst)
+	suite.Equal(SettingsApplyRecreate, plan.Apply)
+	suite.Len(plan.Changes, 2)
+	suite.Equal("environment.PORT", plan.Changes[0].Field)
+}
+
+func (suite *ContainerSettingsPlanTestSuite) TestPlanVersion() {
+	version := "16"
+	plan := ContainerSettingsPlanBody{
+		ContainerSettingsPatch: ContainerSettingsPatch{Version: &version},
+	}.Plan(suite.inst)
+
+	suite.Equal(SettingsApplyRecreate, plan.Apply)
+	suite.Equal("latest", plan.Changes[0].Current)
+}
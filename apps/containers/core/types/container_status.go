@@ -0,0 +1,73 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/slices"
+)
+
+// ContainerStatus is the status of a container. It is sent to the clients
+// as a string.
+type ContainerStatus string
+
+const (
+	ContainerStatusOff      ContainerStatus = "off"
+	ContainerStatusQueued   ContainerStatus = "queued"
+	ContainerStatusBuilding ContainerStatus = "building"
+	ContainerStatusStarting ContainerStatus = "starting"
+	ContainerStatusRunning  ContainerStatus = "running"
+	ContainerStatusPaused   ContainerStatus = "paused"
+	ContainerStatusStopping ContainerStatus = "stopping"
+	ContainerStatusError    ContainerStatus = "error"
+)
+
+var (
+	ErrInvalidContainerStatus  = errors.New("invalid container status")
+	ErrInvalidStatusTransition = errors.New("invalid container status transition")
+)
+
+// statusTransitions are the statuses a container can go to from each status.
+// Any status can go to error. A container waits in queued while too many
+// images are built, and a cancelled build goes back to off.
+var statusTransitions = map[ContainerStatus][]ContainerStatus{
+	ContainerStatusOff:      {ContainerStatusQueued, ContainerStatusBuilding},
+	ContainerStatusQueued:   {ContainerStatusBuilding, ContainerStatusOff},
+	ContainerStatusBuilding: {ContainerStatusStarting, ContainerStatusOff},
+	ContainerStatusStarting: {ContainerStatusRunning},
+	ContainerStatusRunning:  {ContainerStatusPaused, ContainerStatusStopping, ContainerStatusOff},
+	ContainerStatusPaused:   {ContainerStatusRunning, ContainerStatusStopping, ContainerStatusOff},
+	// A failed stop goes back to the previous status.
+	ContainerStatusStopping: {ContainerStatusOff, ContainerStatusRunning, ContainerStatusPaused},
+	ContainerStatusError:    {ContainerStatusQueued, ContainerStatusBuilding},
+}
+
+// Validate returns ErrInvalidContainerStatus if the status is unknown.
+func (s ContainerStatus) Validate() error {
+	if _, ok := statusTransitions[s]; !ok {
+		return fmt.Errorf("%w: %s", ErrInvalidContainerStatus, s)
+	}
+	return nil
+}
+
+// CanTransitionTo returns true if a container can go from the status s to
+// the status next. Staying in the same status is always allowed.
+func (s ContainerStatus) CanTransitionTo(next ContainerStatus) bool {
+	if s == next || next == ContainerStatusError {
+		return next.Validate() == nil
+	}
+	return slices.Contains(statusTransitions[s], next)
+}
+
+// Transition returns ErrInvalidStatusTransition if a container can't go
+// from the status s to the status next.
+func (s ContainerStatus) Transition(next ContainerStatus) error {
+	err := next.Validate()
+	if err != nil {
+		return err
+	}
+	if !s.CanTransitionTo(next) {
+		return fmt.Errorf("%w: from %s to %s", ErrInvalidStatusTransition, s, next)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ContainerStatusTestSuite struct {
+	suite.Suite
+}
+
+func TestContainerStatusTestSuite(t *testing.T) {
+	suite.Run(t, new(ContainerStatusTestSuite))
+}
+
+func (suite *ContainerStatusTestSuite) TestValidate() {
+	suite.NoError(ContainerStatusPaused.Validate())
+	suite.ErrorIs(ContainerStatus("sleeping").Validate(), ErrInvalidContainerStatus)
+}
+
+func (suite *ContainerStatusTestSuite) TestTransition() {
+	suite.NoError(ContainerStatusOff.Transition(ContainerStatusBuilding))
+	suite.NoError(ContainerStatusBuilding.Transition(ContainerStatusStarting))
+	suite.NoError(ContainerStatusBuilding.Transition(ContainerStatusOff))
+	suite.NoError(ContainerStatusOff.Transition(ContainerStatusQueued))
+	suite.NoError(ContainerStatusQueued.Transition(ContainerStatusBuilding))
+	suite.NoError(ContainerStatusStarting.Transition(ContainerStatusRunning))
+	suite.NoError(ContainerStatusStopping.Transition(ContainerStatusPaused))
+	suite.NoError(ContainerStatusRunning.Transition(ContainerStatusError))
+
+	suite.ErrorIs(ContainerStatusOff.Transition(ContainerStatusRunning), ErrInvalidStatusTransition)
+	suite.ErrorIs(ContainerStatusBuilding.Transition(ContainerStatusRunning), ErrInvalidStatusTransition)
+	suite.ErrorIs(ContainerStatusOff.Transition(ContainerStatusPaused), ErrInvalidStatusTransition)
+	suite.ErrorIs(ContainerStatusQueued.Transition(ContainerStatusRunning), ErrInvalidStatusTransition)
+	suite.ErrorIs(ContainerStatusOff.Transition("sleeping"), ErrInvalidContainerStatus)
+}
+
+func (suite *ContainerStatusTestSuite) TestJSON() {
+	b, err := json.Marshal(Container{Status: ContainerStatusRunning})
+	suite.Require().NoError(err)
+	suite.Contains(string(b), `"status":"running"`)
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+type imageVersion struct {
+	parts   []int
+	variant string
+}
+
+// parseImageVersion parses a tag like v1.2.3 or 16-alpine. It returns false
+// if the tag is not a version, like latest.
+func parseImageVersion(tag string) (imageVersion, bool) {
+	core, variant, _ := strings.Cut(strings.TrimPrefix(tag, "v"), "-")
+
+	var v imageVersion
+	for _, field := range strings.Split(core, ".") {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return imageVersion{}, false
+		}
+		v.parts = append(v.parts, n)
+	}
+	v.variant = variant
+	return v, true
+}
+
+// SortVersions sorts the tags of an image for the version selection. The
+// tags that are not versions, like latest, come first in alphabetical order.
+// Then, the versions come from the newest to the oldest, comparing each
+// number, so 1.10 is newer than 1.9. The variants like 16-alpine come after
+// the plain version.
+func SortVersions(tags []string) {
+	slices.SortStableFunc(tags, func(a, b string) int {
+		va, okA := parseImageVersion(a)
+		vb, okB := parseImageVersion(b)
+		switch {
+		case !okA && !okB:
+			return strings.Compare(a, b)
+		case !okA:
+			return -1
+		case !okB:
+			return 1
+		}
+
+		for i := 0; i < len(va.parts) && i < len(vb.parts); i++ {
+			if va.parts[i] != vb.parts[i] {
+				return vb.parts[i] - va.parts[i]
+			}
+		}
+		if len(va.parts) != len(vb.parts) {
+			return len(vb.parts) - len(va.parts)
+		}
+
+		switch {
+		case va.variant == vb.variant:
+			return strings.Compare(a, b)
+		case va.variant == "":
+			return -1
+		case vb.variant == "":
+			return 1
+		}
+		return strings.Compare(va.variant, vb.variant)
+	})
+}
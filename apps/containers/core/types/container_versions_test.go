@@ -0,0 +1,23 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ContainerVersionsTestSuite struct {
+	suite.Suite
+}
+
+func TestContainerVersionsTestSuite(t *testing.T) {
+	suite.Run(t, new(ContainerVersionsTestSuite))
+}
+
+func (suite *ContainerVersionsTestSuite) TestSortVersions() {
+	tags := []string{"1.9", "alpine", "1.10", "16-alpine", "16", "latest", "v2.0.1", "16-bookworm", "1.10.1"}
+
+	SortVersions(tags)
+
+	suite.Equal([]string{"alpine", "latest", "16", "16-alpine", "16-bookworm", "v2.0.1", "1.10.1", "1.10", "1.9"}, tags)
+}
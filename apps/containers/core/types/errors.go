@@ -6,14 +6,24 @@ const (
 	ErrCodeContainerUuidInvalid           router.ErrCode = "container_uuid_invalid"
 	ErrCodeContainerUuidMissing           router.ErrCode = "container_uuid_missing"
 	ErrCodeContainerNotFound              router.ErrCode = "container_not_found"
+	ErrCodePaginationInvalid              router.ErrCode = "pagination_invalid"
 	ErrCodeContainerAlreadyRunning        router.ErrCode = "container_already_running"
 	ErrCodeContainerStillRunning          router.ErrCode = "container_still_running"
 	ErrCodeContainerNotRunning            router.ErrCode = "container_not_running"
+	ErrCodeContainerAlreadyPaused         router.ErrCode = "container_already_paused"
+	ErrCodeContainerNotPaused             router.ErrCode = "container_not_paused"
+	ErrCodeContainerNotBuilding           router.ErrCode = "container_not_building"
+	ErrCodePortConflict                   router.ErrCode = "port_conflict"
 	ErrCodeFailedToGetContainer           router.ErrCode = "failed_to_get_container"
 	ErrCodeFailedToStartContainer         router.ErrCode = "failed_to_start_container"
 	ErrCodeFailedToStopContainer          router.ErrCode = "failed_to_stop_container"
+	ErrCodeFailedToCancelBuild            router.ErrCode = "failed_to_cancel_build"
+	ErrCodeFailedToPauseContainer         router.ErrCode = "failed_to_pause_container"
+	ErrCodeFailedToUnpauseContainer       router.ErrCode = "failed_to_unpause_container"
 	ErrCodeFailedToDeleteContainer        router.ErrCode = "failed_to_delete_container"
 	ErrCodeFailedToGetContainerLogs       router.ErrCode = "failed_to_get_logs"
+	ErrCodeLogLevelInvalid                router.ErrCode = "log_level_invalid"
+	ErrCodeLogsTailInvalid                router.ErrCode = "logs_tail_invalid"
 	ErrCodeFailedToUpdateServiceContainer router.ErrCode = "failed_to_update_service_container"
 	ErrCodeFailedToGetVersions            router.ErrCode = "failed_to_get_versions"
 	ErrCodeFailedToWaitContainer          router.ErrCode = "failed_to_wait_container"
@@ -22,10 +32,33 @@ const (
 	ErrCodeFailedToSetDatabase            router.ErrCode = "failed_to_set_database"
 	ErrCodeFailedToSetVersion             router.ErrCode = "failed_to_set_version"
 	ErrCodeFailedToSetTags                router.ErrCode = "failed_to_set_tags"
+	ErrCodeFailedToSetCommand             router.ErrCode = "failed_to_set_command"
 	ErrCodeFailedToSetEnv                 router.ErrCode = "failed_to_set_env"
+	ErrCodeFailedToSetEnvFile             router.ErrCode = "failed_to_set_env_file"
+	ErrCodeFailedToResetEnv               router.ErrCode = "failed_to_reset_env"
+	ErrCodeIdleTimeoutInvalid             router.ErrCode = "idle_timeout_invalid"
+	ErrCodeFailedToSetIdleTimeout         router.ErrCode = "failed_to_set_idle_timeout"
+	ErrCodeFailedToSetProxyAutoRegister   router.ErrCode = "failed_to_set_proxy_auto_register"
 	ErrCodeFailedToCheckForUpdates        router.ErrCode = "failed_to_check_for_updates"
+	ErrCodeNoUpdateAvailable              router.ErrCode = "no_update_available"
+	ErrCodeFailedToApplyUpdate            router.ErrCode = "failed_to_apply_update"
+	ErrCodeContainerPinned                router.ErrCode = "container_pinned"
+	ErrCodeFailedToSetPinned              router.ErrCode = "failed_to_set_pinned"
+	ErrCodeExportFormatInvalid            router.ErrCode = "export_format_invalid"
+	ErrCodeExportNotSupported             router.ErrCode = "export_not_supported"
+	ErrCodeFailedToExportContainer        router.ErrCode = "failed_to_export_container"
+
+	ErrCodeStackNameMissing     router.ErrCode = "stack_name_missing"
+	ErrCodeStackNotFound        router.ErrCode = "stack_not_found"
+	ErrCodeStackInvalid         router.ErrCode = "stack_invalid"
+	ErrCodeFailedToInstallStack router.ErrCode = "failed_to_install_stack"
+	ErrCodeComposeInvalid       router.ErrCode = "compose_invalid"
+	ErrCodeFailedToStartStack   router.ErrCode = "failed_to_start_stack"
+	ErrCodeFailedToStopStack    router.ErrCode = "failed_to_stop_stack"
+	ErrCodeFailedToDeleteStack  router.ErrCode = "failed_to_delete_stack"
 
 	ErrCodeServiceIdMissing       router.ErrCode = "service_id_missing"
 	ErrCodeServiceNotFound        router.ErrCode = "service_not_found"
 	ErrCodeFailedToInstallService router.ErrCode = "failed_to_install_service"
+	ErrCodeImageInvalid           router.ErrCode = "image_invalid"
 )
@@ -8,6 +8,7 @@ const (
 	EventNameContainerStdout       = "stdout"
 	EventNameContainerStderr       = "stderr"
 	EventNameContainerDownload     = "download"
+	EventNameContainerBuild        = "build"
 )
 
 type (
@@ -26,7 +27,13 @@ type (
 		ServiceID     string
 		Container     Container
 		Name          string
-		Status        string
+		Status        ContainerStatus
+	}
+
+	// EventContainerActivity is dispatched when a container receives a
+	// request, to delay its idle timeout.
+	EventContainerActivity struct {
+		ContainerUUID uuid.UUID
 	}
 
 	EventContainerCreated struct{}
@@ -44,3 +51,14 @@ type (
 
 	EventContainersStopped struct{}
 )
+
+// CoalesceKey allows a slow listener to only receive the latest status of
+// each container.
+func (e EventContainerStatusChange) CoalesceKey() string {
+	return "status_change/" + e.ContainerUUID.String()
+}
+
+// CoalesceKey allows a slow listener to receive rapid changes only once.
+func (e EventContainersChange) CoalesceKey() string {
+	return "containers_change"
+}
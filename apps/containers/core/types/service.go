@@ -2,7 +2,10 @@ package types
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
+	"github.com/docker/go-units"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vlog"
 )
@@ -11,10 +14,20 @@ const (
 	MaxSupportedVersion Version = 1
 )
 
+// VolumePrefix is the prefix of the named Docker volumes in the volumes
+// of a service.
+const VolumePrefix = "volume:"
+
 var (
 	ErrServiceNotFound = errors.New("the service was not found")
+	ErrInvalidShmSize  = errors.New("the shm size is invalid")
+	ErrInvalidImage    = errors.New("the image is invalid")
 )
 
+// ImageServicePrefix is the prefix of the IDs of the services created from
+// an image, which are not in the services repository.
+const ImageServicePrefix = "image:"
+
 type Version int
 
 type ServiceVersioning struct {
@@ -55,6 +68,12 @@ type Service struct {
 	// URLs defines all service urls.
 	URLs []URL `yaml:"urls,omitempty" json:"urls,omitempty"`
 
+	// Logs describes how Vertex should read the logs of the service.
+	Logs *ServiceLogs `yaml:"logs,omitempty" json:"logs,omitempty"`
+
+	// Hooks are commands run on the host around the lifecycle of the container.
+	Hooks *ServiceHooks `yaml:"hooks,omitempty" json:"hooks,omitempty"`
+
 	// Methods defines different methods to install the service.
 	Methods ServiceMethods `yaml:"methods" json:"methods"`
 }
@@ -136,6 +155,22 @@ type DatabaseFeature struct {
 	Password *string `yaml:"password" json:"password"`
 }
 
+type ServiceLogs struct {
+	// LevelRegex overrides the detection of the log level. The level is read
+	// from the first capture group, for example `^\[(\w+)\]`.
+	LevelRegex *string `yaml:"level_regex,omitempty" json:"level_regex,omitempty"`
+}
+
+type ServiceHooks struct {
+	// PreStart is run before each start, in the directory of the container.
+	// The container doesn't start if it fails.
+	PreStart *string `yaml:"pre_start,omitempty" json:"pre_start,omitempty"`
+
+	// PostStop is run after the container is stopped by Vertex, in the
+	// directory of the container.
+	PostStop *string `yaml:"post_stop,omitempty" json:"post_stop,omitempty"`
+}
+
 type Features struct {
 	// The database feature describes the database made available
 	// by this service.
@@ -197,12 +232,27 @@ type ServiceMethodDocker struct {
 
 	// Ports is a map containing docker port as a key, and output port as a value.
 	// The output port is automatically adjusted with PORT environment variables.
+	// The ports can be ranges like 7000-7010, and end with /udp for UDP ports.
+	// A PORT environment variable set to auto lets Docker pick a free port.
+	// The output port can start with the host IP to bind, like 127.0.0.1:8080,
+	// or [::]:8080 to be reachable over IPv6.
 	Ports *map[string]string `yaml:"ports,omitempty" json:"ports,omitempty"`
 
 	// Volumes is a map containing output folder as a key, and input folder from Docker
-	// as a string value.
+	// as a string value. An output folder prefixed by volume: is a named Docker
+	// volume instead of a folder of the host.
 	Volumes *map[string]string `yaml:"volumes,omitempty" json:"volumes,omitempty"`
 
+	// VolumesOwner is the owner of the volumes directories created on the
+	// host, if the container doesn't run as the Vertex user.
+	VolumesOwner *ServiceVolumesOwner `yaml:"volumes_owner,omitempty" json:"volumes_owner,omitempty"`
+
+	// Files is a map containing a file of the container directory as a key,
+	// and its path in the Docker container as a value. The files are copied
+	// before each start, for images reading a file where a bind mount would
+	// hide the other files of its directory.
+	Files *map[string]string `yaml:"files,omitempty" json:"files,omitempty"`
+
 	// Environment is a map containing docker environment variable as a key, and
 	// its corresponding service environment name as a value.
 	Environment *map[string]string `yaml:"environment,omitempty" json:"environment,omitempty"`
@@ -213,8 +263,30 @@ type ServiceMethodDocker struct {
 	// Sysctls allows to modify kernel parameters.
 	Sysctls *map[string]string `yaml:"sysctls,omitempty" json:"sysctls,omitempty"`
 
+	// ExtraHosts are additional entries of /etc/hosts, in the host:ip form.
+	// The ip can be host-gateway to reach the host running Vertex.
+	ExtraHosts *[]string `yaml:"extra_hosts,omitempty" json:"extra_hosts,omitempty"`
+
+	// ShmSize is the size of /dev/shm, like 256m or 1g.
+	ShmSize *string `yaml:"shm_size,omitempty" json:"shm_size,omitempty"`
+
+	// Init runs an init process as PID 1 in the container, which forwards
+	// the signals and reaps the zombie processes.
+	Init *bool `yaml:"init,omitempty" json:"init,omitempty"`
+
 	// Cmd is the command to run in the container.
 	Cmd *string `yaml:"command,omitempty" json:"command,omitempty"`
+
+	// Network is a Docker network shared with other containers, created
+	// at the first start. The other containers of the network reach this
+	// one by its NetworkAliases.
+	Network        *string   `yaml:"network,omitempty" json:"network,omitempty"`
+	NetworkAliases *[]string `yaml:"network_aliases,omitempty" json:"network_aliases,omitempty"`
+}
+
+type ServiceVolumesOwner struct {
+	UID int `yaml:"uid" json:"uid"`
+	GID int `yaml:"gid" json:"gid"`
 }
 
 type ServiceMethods struct {
@@ -248,3 +320,88 @@ type URL struct {
 	// It can be: client, server.
 	Kind string `yaml:"kind" json:"kind"`
 }
+
+// ShmSizeBytes returns the size of /dev/shm in bytes, or 0 if it is not set.
+func (m *ServiceMethodDocker) ShmSizeBytes() (int64, error) {
+	if m.ShmSize == nil {
+		return 0, nil
+	}
+	size, err := units.RAMInBytes(*m.ShmSize)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidShmSize, err)
+	}
+	if size <= 0 {
+		return 0, fmt.Errorf("%w: %s must be positive", ErrInvalidShmSize, *m.ShmSize)
+	}
+	return size, nil
+}
+
+// ImageInstall is the request to install a container from a Docker image,
+// without a service definition.
+type ImageInstall struct {
+	// Image is the image to run, like nginx:latest.
+	Image string `json:"image"`
+
+	// Name is the name of the container. It defaults to the image name.
+	Name string `json:"name,omitempty"`
+}
+
+// ServiceInstall configures the container installed from a service. It is
+// applied before the container is ever started, so it doesn't have to be
+// recreated to apply it.
+type ServiceInstall struct {
+	DisplayName *string  `json:"display_name,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+
+	// Command overrides the command of the service.
+	Command *string `json:"command,omitempty"`
+
+	// Environment overrides the default values of the environment of the
+	// service.
+	Environment ContainerEnvVariables `json:"environment,omitempty"`
+}
+
+// ParseImage splits an image reference like nginx:latest or
+// ghcr.io/owner/app:1.2 into its name and its tag. The tag defaults to
+// latest. The references by digest are not supported, because the version
+// of a container is a tag.
+func ParseImage(image string) (name string, tag string, err error) {
+	image = strings.TrimSpace(image)
+	if image == "" {
+		return "", "", fmt.Errorf("%w: the image is missing", ErrInvalidImage)
+	}
+	if strings.ContainsAny(image, "@ \t") {
+		return "", "", fmt.Errorf("%w: %s", ErrInvalidImage, image)
+	}
+
+	name, tag = image, "latest"
+	// The colon of a registry port, like localhost:5000/app, is not a tag.
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		name, tag = image[:i], image[i+1:]
+	}
+	if name == "" || tag == "" || strings.HasSuffix(name, "/") {
+		return "", "", fmt.Errorf("%w: %s", ErrInvalidImage, image)
+	}
+	return name, tag, nil
+}
+
+// NewImageService returns a minimal service running the image with Docker,
+// for the containers installed without a service definition. The name
+// defaults to the image name.
+func NewImageService(image string, name string) Service {
+	if name == "" {
+		name = image
+	}
+	return Service{
+		ServiceVersioning: ServiceVersioning{
+			Version: MaxSupportedVersion,
+		},
+		ID:   ImageServicePrefix + image,
+		Name: name,
+		Methods: ServiceMethods{
+			Docker: &ServiceMethodDocker{
+				Image: &image,
+			},
+		},
+	}
+}
@@ -0,0 +1,67 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ServiceTestSuite struct {
+	suite.Suite
+}
+
+func TestServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ServiceTestSuite))
+}
+
+func (suite *ServiceTestSuite) TestShmSizeBytes() {
+	m := ServiceMethodDocker{}
+	size, err := m.ShmSizeBytes()
+	suite.NoError(err)
+	suite.Equal(int64(0), size)
+
+	shmSize := "256m"
+	m.ShmSize = &shmSize
+	size, err = m.ShmSizeBytes()
+	suite.NoError(err)
+	suite.Equal(int64(256*1024*1024), size)
+}
+
+func (suite *ServiceTestSuite) TestShmSizeBytesInvalid() {
+	for _, shmSize := range []string{"big", "0", "-1m"} {
+		m := ServiceMethodDocker{ShmSize: &shmSize}
+		_, err := m.ShmSizeBytes()
+		suite.ErrorIs(err, ErrInvalidShmSize, shmSize)
+	}
+}
+
+func (suite *ServiceTestSuite) TestParseImage() {
+	for image, expected := range map[string][2]string{
+		"nginx":                    {"nginx", "latest"},
+		"nginx:1.25":               {"nginx", "1.25"},
+		"ghcr.io/owner/app:v2":     {"ghcr.io/owner/app", "v2"},
+		"localhost:5000/app":       {"localhost:5000/app", "latest"},
+		"localhost:5000/app:1.0.0": {"localhost:5000/app", "1.0.0"},
+	} {
+		name, tag, err := ParseImage(image)
+		suite.NoError(err, image)
+		suite.Equal(expected[0], name, image)
+		suite.Equal(expected[1], tag, image)
+	}
+
+	for _, image := range []string{"", "nginx:", ":latest", "nginx@sha256:abc", "my image"} {
+		_, _, err := ParseImage(image)
+		suite.ErrorIs(err, ErrInvalidImage, image)
+	}
+}
+
+func (suite *ServiceTestSuite) TestNewImageService() {
+	service := NewImageService("nginx", "")
+	suite.Equal("image:nginx", service.ID)
+	suite.Equal("nginx", service.Name)
+	suite.Require().NotNil(service.Methods.Docker)
+	suite.Equal("nginx", *service.Methods.Docker.Image)
+
+	service = NewImageService("nginx", "Web server")
+	suite.Equal("Web server", service.Name)
+}
@@ -0,0 +1,96 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+// StackTagPrefix is the prefix of the tag given to all the containers
+// of a stack.
+const StackTagPrefix = "Vertex Stack - "
+
+var (
+	ErrStackNotFound = errors.New("stack not found")
+	ErrStackInvalid  = errors.New("the stack is invalid")
+)
+
+// stackReferenceRegex matches the references to the environment of another
+// container of the same stack, like ${stack:<name>:POSTGRES_PASSWORD}.
+var stackReferenceRegex = regexp.MustCompile(`\$\{stack:([^:}]*):([^}]*)}`)
+
+type Stack struct {
+	// Name is the unique name of the stack.
+	Name string `json:"name"`
+
+	// Services are the services to install in the stack.
+	Services []StackService `json:"services"`
+}
+
+type StackService struct {
+	// Name identifies the container in the stack.
+	Name string `json:"name"`
+
+	// ServiceID is the ID of the service to install.
+	ServiceID string `json:"service_id"`
+
+	// Env overrides the default environment of the service. The values can
+	// reference the environment of another container of the stack with
+	// ${stack:<name>:<variable>}.
+	Env ContainerEnvVariables `json:"environment,omitempty"`
+}
+
+func StackTag(name string) string {
+	return StackTagPrefix + name
+}
+
+func (s Stack) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("%w: the name is missing", ErrStackInvalid)
+	}
+	if len(s.Services) == 0 {
+		return fmt.Errorf("%w: the stack has no services", ErrStackInvalid)
+	}
+
+	names := map[string]bool{}
+	for _, serv := range s.Services {
+		if serv.Name == "" || serv.ServiceID == "" {
+			return fmt.Errorf("%w: each service needs a name and a service_id", ErrStackInvalid)
+		}
+		if names[serv.Name] {
+			return fmt.Errorf("%w: the name '%s' is used twice", ErrStackInvalid, serv.Name)
+		}
+		names[serv.Name] = true
+	}
+
+	for _, serv := range s.Services {
+		for _, value := range serv.Env {
+			for _, matches := range stackReferenceRegex.FindAllStringSubmatch(value, -1) {
+				if !names[matches[1]] {
+					return fmt.Errorf("%w: '%s' references an unknown service", ErrStackInvalid, matches[0])
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ToContainerReferences replaces the references to the stack services by
+// references to their containers, which are resolved when a container starts.
+func (env ContainerEnvVariables) ToContainerReferences(containers map[string]uuid.UUID) ContainerEnvVariables {
+	converted := ContainerEnvVariables{}
+	for name, value := range env {
+		converted[name] = stackReferenceRegex.ReplaceAllStringFunc(value, func(ref string) string {
+			matches := stackReferenceRegex.FindStringSubmatch(ref)
+			id, ok := containers[matches[1]]
+			if !ok {
+				return ref
+			}
+			return fmt.Sprintf("${container:%s:%s}", id, matches[2])
+		})
+	}
+	return converted
+}
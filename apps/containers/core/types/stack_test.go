@@ -0,0 +1,55 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type StackTestSuite struct {
+	suite.Suite
+}
+
+func TestStackTestSuite(t *testing.T) {
+	suite.Run(t, new(StackTestSuite))
+}
+
+func (suite *StackTestSuite) TestValidate() {
+	stack := Stack{
+		Name: "monitoring",
+		Services: []StackService{
+			{Name: "db", ServiceID: "postgres"},
+			{Name: "app", ServiceID: "grafana", Env: ContainerEnvVariables{
+				"PASSWORD": "${stack:db:POSTGRES_PASSWORD}",
+			}},
+		},
+	}
+	suite.NoError(stack.Validate())
+
+	stack.Services[1].Env["HOST"] = "${stack:unknown:HOST}"
+	suite.ErrorIs(stack.Validate(), ErrStackInvalid)
+
+	suite.ErrorIs(Stack{Name: "empty"}.Validate(), ErrStackInvalid)
+	suite.ErrorIs(Stack{
+		Name: "duplicate",
+		Services: []StackService{
+			{Name: "db", ServiceID: "postgres"},
+			{Name: "db", ServiceID: "postgres"},
+		},
+	}.Validate(), ErrStackInvalid)
+}
+
+func (suite *StackTestSuite) TestToContainerReferences() {
+	id := uuid.New()
+	env := ContainerEnvVariables{
+		"PASSWORD": "${stack:db:POSTGRES_PASSWORD}",
+		"PORT":     "8080",
+	}
+
+	converted := env.ToContainerReferences(map[string]uuid.UUID{"db": id})
+	suite.Equal(ContainerEnvVariables{
+		"PASSWORD": "${container:" + id.String() + ":POSTGRES_PASSWORD}",
+		"PORT":     "8080",
+	}, converted)
+}
@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"strconv"
+	"sync"
 
 	"github.com/vertex-center/vertex/apps/containers/core/port"
-	"github.com/vertex-center/vertex/apps/containers/core/service"
 	types3 "github.com/vertex-center/vertex/apps/containers/core/types"
 	types2 "github.com/vertex-center/vertex/core/types"
 	"github.com/vertex-center/vertex/core/types/api"
@@ -16,6 +19,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vertex/pkg/router"
+	"github.com/vertex-center/vlog"
 )
 
 type ContainerHandler struct {
@@ -84,18 +88,10 @@ func (h *ContainerHandler) getContainer(c *router.Context) *types3.Container {
 	}
 
 	container, err := h.containerService.Get(*containerUUID)
-	if err != nil && errors.Is(err, types3.ErrContainerNotFound) {
-		c.NotFound(router.Error{
-			Code:           types3.ErrCodeContainerNotFound,
-			PublicMessage:  fmt.Sprintf("The container '%s' could not be found.", containerUUID),
-			PrivateMessage: err.Error(),
-		})
-		return nil
-	} else if err != nil {
-		c.Abort(router.Error{
-			Code:           types3.ErrCodeFailedToGetContainer,
-			PublicMessage:  fmt.Sprintf("Failed to retrieve container '%s'.", containerUUID),
-			PrivateMessage: err.Error(),
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types3.ErrCodeFailedToGetContainer,
+			PublicMessage: fmt.Sprintf("Failed to retrieve container '%s'.", containerUUID),
 		})
 		return nil
 	}
@@ -118,18 +114,10 @@ func (h *ContainerHandler) Delete(c *router.Context) {
 	}
 
 	err := h.containerService.Delete(inst)
-	if err != nil && errors.Is(err, types3.ErrContainerStillRunning) {
-		c.Conflict(router.Error{
-			Code:           types3.ErrCodeContainerStillRunning,
-			PublicMessage:  fmt.Sprintf("The container '%s' is still running. Stop it first before deleting.", inst.DisplayName),
-			PrivateMessage: err.Error(),
-		})
-		return
-	} else if err != nil {
-		c.Abort(router.Error{
-			Code:           types3.ErrCodeFailedToDeleteContainer,
-			PublicMessage:  fmt.Sprintf("The container '%s' could not be deleted.", inst.DisplayName),
-			PrivateMessage: err.Error(),
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types3.ErrCodeFailedToDeleteContainer,
+			PublicMessage: fmt.Sprintf("The container '%s' could not be deleted.", inst.DisplayName),
 		})
 		return
 	}
@@ -137,26 +125,43 @@ func (h *ContainerHandler) Delete(c *router.Context) {
 	c.OK()
 }
 
-type PatchBody struct {
-	LaunchOnStartup *bool                `json:"launch_on_startup,omitempty"`
-	DisplayName     *string              `json:"display_name,omitempty"`
-	Databases       map[string]uuid.UUID `json:"databases,omitempty"`
-	Version         *string              `json:"version,omitempty"`
-	Tags            []string             `json:"tags,omitempty"`
-}
-
 func (h *ContainerHandler) Patch(c *router.Context) {
 	inst := h.getContainer(c)
 	if inst == nil {
 		return
 	}
 
-	var body PatchBody
+	var body types3.ContainerSettingsPatch
 	err := c.ParseBody(&body)
 	if err != nil {
 		return
 	}
 
+	// The version is checked before anything is saved, so an unknown
+	// version doesn't leave the patch half applied. The registry can be
+	// private or unreachable, so the check can be skipped.
+	if body.Version != nil && c.Query("skip_version_check") != "true" {
+		err = h.containerRunnerService.CheckVersion(inst, *body.Version)
+		if err != nil {
+			c.Fail(err, router.Error{
+				Code:          types3.ErrCodeFailedToSetVersion,
+				PublicMessage: "Failed to check the version against the tags of the image. Use skip_version_check=true to skip the check.",
+			})
+			return
+		}
+	}
+
+	// Like in the plan, the values equal to the current ones are not a
+	// change, and don't recreate the container.
+	recreate := false
+	plan := types3.ContainerSettingsPlanBody{ContainerSettingsPatch: body}.Plan(inst)
+	for _, change := range plan.Changes {
+		switch change.Field {
+		case "command", "env_file", "version":
+			recreate = true
+		}
+	}
+
 	if body.LaunchOnStartup != nil {
 		err = h.containerSettingsService.SetLaunchOnStartup(inst, *body.LaunchOnStartup)
 		if err != nil {
@@ -169,6 +174,18 @@ func (h *ContainerHandler) Patch(c *router.Context) {
 		}
 	}
 
+	if body.Pinned != nil {
+		err = h.containerSettingsService.SetPinned(inst, *body.Pinned)
+		if err != nil {
+			c.Abort(router.Error{
+				Code:           types3.ErrCodeFailedToSetPinned,
+				PublicMessage:  "Failed to change the pin.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		}
+	}
+
 	if body.DisplayName != nil && *body.DisplayName != "" {
 		err = h.containerSettingsService.SetDisplayName(inst, *body.DisplayName)
 		if err != nil {
@@ -217,9 +234,109 @@ func (h *ContainerHandler) Patch(c *router.Context) {
 		}
 	}
 
+	if body.IdleTimeout != nil {
+		if *body.IdleTimeout < 0 {
+			c.BadRequest(router.Error{
+				Code:          types3.ErrCodeIdleTimeoutInvalid,
+				PublicMessage: "The idle timeout can't be negative.",
+			})
+			return
+		}
+
+		err = h.containerSettingsService.SetIdleTimeout(inst, *body.IdleTimeout)
+		if err != nil {
+			c.Abort(router.Error{
+				Code:           types3.ErrCodeFailedToSetIdleTimeout,
+				PublicMessage:  "Failed to change the idle timeout.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		}
+	}
+
+	if body.IdleAutoStart != nil {
+		err = h.containerSettingsService.SetIdleAutoStart(inst, *body.IdleAutoStart)
+		if err != nil {
+			c.Abort(router.Error{
+				Code:           types3.ErrCodeFailedToSetIdleTimeout,
+				PublicMessage:  "Failed to change the idle auto-start.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		}
+	}
+
+	if body.ProxyAutoRegister != nil {
+		err = h.containerSettingsService.SetProxyAutoRegister(inst, *body.ProxyAutoRegister)
+		if err != nil {
+			c.Abort(router.Error{
+				Code:           types3.ErrCodeFailedToSetProxyAutoRegister,
+				PublicMessage:  "Failed to change the proxy auto-registration.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		}
+	}
+
+	if body.EnvFile != nil {
+		err = h.containerSettingsService.SetEnvFile(inst, *body.EnvFile)
+		if err != nil {
+			c.Abort(router.Error{
+				Code:           types3.ErrCodeFailedToSetEnvFile,
+				PublicMessage:  "Failed to change the environment file.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		}
+	}
+
+	if body.Command != nil {
+		err = h.containerSettingsService.SetCommand(inst, *body.Command)
+		if err != nil {
+			c.Abort(router.Error{
+				Code:           types3.ErrCodeFailedToSetCommand,
+				PublicMessage:  "Failed to change command.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		}
+	}
+
+	if recreate {
+		// The command, the mounts and the image are only applied when the
+		// Docker container is created.
+		err = h.containerRunnerService.RecreateContainer(inst)
+		if err != nil {
+			c.Abort(router.Error{
+				Code:           api.ErrFailedToRecreateContainer,
+				PublicMessage:  "Failed to recreate container.",
+				PrivateMessage: err.Error(),
+			})
+			return
+		}
+	}
+
 	c.OK()
 }
 
+// Plan returns the changes that a patch of the settings and the environment
+// would make, and whether they need to restart or recreate the container.
+// Nothing is saved.
+func (h *ContainerHandler) Plan(c *router.Context) {
+	inst := h.getContainer(c)
+	if inst == nil {
+		return
+	}
+
+	var body types3.ContainerSettingsPlanBody
+	err := c.ParseBody(&body)
+	if err != nil {
+		return
+	}
+
+	c.JSON(body.Plan(inst))
+}
+
 func (h *ContainerHandler) Start(c *router.Context) {
 	inst := h.getContainer(c)
 	if inst == nil {
@@ -227,25 +344,18 @@ func (h *ContainerHandler) Start(c *router.Context) {
 	}
 
 	err := h.containerRunnerService.Start(inst)
-	if err != nil && errors.Is(err, types3.ErrContainerNotFound) {
-		c.NotFound(router.Error{
-			Code:           types3.ErrCodeContainerNotFound,
-			PublicMessage:  fmt.Sprintf("Container '%s' not found.", inst.UUID),
-			PrivateMessage: err.Error(),
-		})
-		return
-	} else if err != nil && errors.Is(err, service.ErrContainerAlreadyRunning) {
+	var conflict *types3.PortConflictError
+	if errors.As(err, &conflict) {
 		c.Conflict(router.Error{
-			Code:           types3.ErrCodeContainerAlreadyRunning,
-			PublicMessage:  fmt.Sprintf("Container %s is already running.", inst.UUID),
+			Code:           types3.ErrCodePortConflict,
+			PublicMessage:  fmt.Sprintf("The container can't start, %s.", conflict.Error()),
 			PrivateMessage: err.Error(),
 		})
 		return
 	} else if err != nil {
-		c.Abort(router.Error{
-			Code:           types3.ErrCodeFailedToStartContainer,
-			PublicMessage:  fmt.Sprintf("Failed to start container %s.", inst.UUID),
-			PrivateMessage: err.Error(),
+		c.Fail(err, router.Error{
+			Code:          types3.ErrCodeFailedToStartContainer,
+			PublicMessage: fmt.Sprintf("Failed to start container %s.", inst.UUID),
 		})
 		return
 	}
@@ -260,18 +370,66 @@ func (h *ContainerHandler) Stop(c *router.Context) {
 	}
 
 	err := h.containerRunnerService.Stop(inst)
-	if err != nil && errors.Is(err, service.ErrContainerNotRunning) {
-		c.Conflict(router.Error{
-			Code:           types3.ErrCodeContainerNotRunning,
-			PublicMessage:  fmt.Sprintf("Container %s is not running.", inst.UUID),
-			PrivateMessage: err.Error(),
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types3.ErrCodeFailedToStopContainer,
+			PublicMessage: fmt.Sprintf("Failed to stop container %s.", inst.UUID),
 		})
 		return
-	} else if err != nil {
-		c.Abort(router.Error{
-			Code:           types3.ErrCodeFailedToStopContainer,
-			PublicMessage:  fmt.Sprintf("Failed to stop container %s.", inst.UUID),
-			PrivateMessage: err.Error(),
+	}
+
+	c.OK()
+}
+
+// CancelBuild cancels the build or the pull of the image of a container
+// being started.
+func (h *ContainerHandler) CancelBuild(c *router.Context) {
+	inst := h.getContainer(c)
+	if inst == nil {
+		return
+	}
+
+	err := h.containerRunnerService.CancelBuild(inst)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types3.ErrCodeFailedToCancelBuild,
+			PublicMessage: fmt.Sprintf("Failed to cancel the build of container %s.", inst.UUID),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+func (h *ContainerHandler) Pause(c *router.Context) {
+	inst := h.getContainer(c)
+	if inst == nil {
+		return
+	}
+
+	err := h.containerRunnerService.Pause(inst)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types3.ErrCodeFailedToPauseContainer,
+			PublicMessage: fmt.Sprintf("Failed to pause container %s.", inst.UUID),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+func (h *ContainerHandler) Unpause(c *router.Context) {
+	inst := h.getContainer(c)
+	if inst == nil {
+		return
+	}
+
+	err := h.containerRunnerService.Unpause(inst)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types3.ErrCodeFailedToUnpauseContainer,
+			PublicMessage: fmt.Sprintf("Failed to unpause container %s.", inst.UUID),
 		})
 		return
 	}
@@ -314,18 +472,46 @@ func (h *ContainerHandler) PatchEnvironment(c *router.Context) {
 	c.OK()
 }
 
+func (h *ContainerHandler) ResetEnvironment(c *router.Context) {
+	inst := h.getContainer(c)
+	if inst == nil {
+		return
+	}
+
+	err := h.containerService.ResetEnv(inst)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types3.ErrCodeFailedToResetEnv,
+			PublicMessage: "Failed to reset the environment.",
+		})
+		return
+	}
+
+	c.OK()
+}
+
 func (h *ContainerHandler) Events(c *router.Context) {
 	inst := h.getContainer(c)
 	if inst == nil {
 		return
 	}
 
-	eventsChan := make(chan sse.Event)
-	defer close(eventsChan)
+	h.ctx.StreamSSE(c, eventsFilter(inst))
+}
 
-	done := c.Request.Context().Done()
+func (h *ContainerHandler) EventsWebSocket(c *router.Context) {
+	inst := h.getContainer(c)
+	if inst == nil {
+		return
+	}
 
-	listener := types2.NewTempListener(func(e interface{}) {
+	h.ctx.StreamWebSocket(c, eventsFilter(inst))
+}
+
+// eventsFilter returns the events of the given container, sent by both
+// the SSE and the WebSocket endpoints.
+func eventsFilter(inst *types3.Container) apptypes.EventFilter {
+	return func(e interface{}) *sse.Event {
 		switch e := e.(type) {
 		case types3.EventContainerLog:
 			if inst.UUID != e.ContainerUUID {
@@ -333,20 +519,25 @@ func (h *ContainerHandler) Events(c *router.Context) {
 			}
 
 			if e.Kind == types3.LogKindOut || e.Kind == types3.LogKindVertexOut {
-				eventsChan <- sse.Event{
+				return &sse.Event{
 					Event: types3.EventNameContainerStdout,
 					Data:  e.Message,
 				}
 			} else if e.Kind == types3.LogKindErr || e.Kind == types3.LogKindVertexErr {
-				eventsChan <- sse.Event{
+				return &sse.Event{
 					Event: types3.EventNameContainerStderr,
 					Data:  e.Message,
 				}
 			} else if e.Kind == types3.LogKindDownload {
-				eventsChan <- sse.Event{
+				return &sse.Event{
 					Event: types3.EventNameContainerDownload,
 					Data:  e.Message,
 				}
+			} else if e.Kind == types3.LogKindBuild {
+				return &sse.Event{
+					Event: types3.EventNameContainerBuild,
+					Data:  e.Message,
+				}
 			}
 
 		case types3.EventContainerStatusChange:
@@ -354,43 +545,13 @@ func (h *ContainerHandler) Events(c *router.Context) {
 				break
 			}
 
-			eventsChan <- sse.Event{
+			return &sse.Event{
 				Event: types3.EventNameContainerStatusChange,
 				Data:  e.Status,
 			}
 		}
-	})
-
-	h.ctx.AddListener(listener)
-	defer h.ctx.RemoveListener(listener)
-
-	first := true
-
-	c.Stream(func(w io.Writer) bool {
-		if first {
-			err := sse.Encode(w, sse.Event{
-				Event: "open",
-			})
-
-			if err != nil {
-				log.Error(err)
-				return false
-			}
-			first = false
-			return true
-		}
-
-		select {
-		case e := <-eventsChan:
-			err := sse.Encode(w, e)
-			if err != nil {
-				log.Error(err)
-			}
-			return true
-		case <-done:
-			return false
-		}
-	})
+		return nil
+	}
 }
 
 func (h *ContainerHandler) GetDocker(c *router.Context) {
@@ -409,9 +570,81 @@ func (h *ContainerHandler) GetDocker(c *router.Context) {
 		return
 	}
 
+	// The time of the last log line helps to detect the containers
+	// running but hung.
+	lastLogTime, err := h.containerLogsService.LastLogTime(inst.UUID)
+	if err == nil && !lastLogTime.IsZero() {
+		info["last_log_time"] = lastLogTime
+	}
+
 	c.JSON(info)
 }
 
+// Describe returns the container with its secrets redacted, its Docker
+// info and its latest logs in a single payload.
+func (h *ContainerHandler) Describe(c *router.Context) {
+	inst := h.getContainer(c)
+	if inst == nil {
+		return
+	}
+
+	logs, err := h.containerLogsService.GetLatestLogs(inst.UUID, "")
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           types3.ErrCodeFailedToGetContainerLogs,
+			PublicMessage:  fmt.Sprintf("Failed to get logs for container %s.", inst.UUID),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	description := types3.ContainerDescription{
+		Container: inst.Redacted(),
+		Logs:      logs,
+	}
+
+	// The container may not exist in Docker yet, so the rest of the
+	// description is still useful without it.
+	description.Docker, err = h.containerRunnerService.GetDockerContainerInfo(*inst)
+	if err != nil {
+		log.Warn("failed to get docker info for the description",
+			vlog.String("uuid", inst.UUID.String()),
+			vlog.String("msg", err.Error()),
+		)
+	}
+
+	lastLogTime, err := h.containerLogsService.LastLogTime(inst.UUID)
+	if err == nil && !lastLogTime.IsZero() {
+		description.LastLogTime = &lastLogTime
+	}
+
+	c.JSON(description)
+}
+
+// Export returns the Docker configuration of the container as a
+// docker-compose.yml (format=compose, the default) or as a docker run command
+// (format=run). The secrets are redacted, unless secrets=true.
+func (h *ContainerHandler) Export(c *router.Context) {
+	inst := h.getContainer(c)
+	if inst == nil {
+		return
+	}
+
+	format := types3.ExportFormat(c.DefaultQuery("format", string(types3.ExportFormatCompose)))
+	redact := c.Query("secrets") != "true"
+
+	export, err := h.containerRunnerService.Export(inst, format, redact)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types3.ErrCodeFailedToExportContainer,
+			PublicMessage: fmt.Sprintf("Failed to export container %s.", inst.UUID),
+		})
+		return
+	}
+
+	c.String(http.StatusOK, export)
+}
+
 func (h *ContainerHandler) RecreateDocker(c *router.Context) {
 	inst := h.getContainer(c)
 	if inst == nil {
@@ -431,13 +664,45 @@ func (h *ContainerHandler) RecreateDocker(c *router.Context) {
 	c.OK()
 }
 
+func (h *ContainerHandler) ApplyUpdate(c *router.Context) {
+	inst := h.getContainer(c)
+	if inst == nil {
+		return
+	}
+
+	err := h.containerRunnerService.ApplyUpdate(inst)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types3.ErrCodeFailedToApplyUpdate,
+			PublicMessage: fmt.Sprintf("Failed to apply the update of container %s.", inst.UUID),
+		})
+		return
+	}
+
+	c.OK()
+}
+
 func (h *ContainerHandler) GetLogs(c *router.Context) {
 	uid := h.getParamContainerUUID(c)
 	if uid == nil {
 		return
 	}
 
-	logs, err := h.containerLogsService.GetLatestLogs(*uid)
+	var level types3.LogLevel
+	if c.Query("level") != "" {
+		var err error
+		level, err = types3.ParseLogLevel(c.Query("level"))
+		if err != nil {
+			c.BadRequest(router.Error{
+				Code:           types3.ErrCodeLogLevelInvalid,
+				PublicMessage:  fmt.Sprintf("Invalid log level: '%s'.", c.Query("level")),
+				PrivateMessage: err.Error(),
+			})
+			return
+		}
+	}
+
+	logs, err := h.containerLogsService.GetLatestLogs(*uid, level)
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           types3.ErrCodeFailedToGetContainerLogs,
@@ -450,6 +715,117 @@ func (h *ContainerHandler) GetLogs(c *router.Context) {
 	c.JSON(logs)
 }
 
+// GetBuildLogs returns the latest lines of the builds of the Dockerfile of
+// the container, apart from its runtime logs.
+func (h *ContainerHandler) GetBuildLogs(c *router.Context) {
+	uid := h.getParamContainerUUID(c)
+	if uid == nil {
+		return
+	}
+
+	logs, err := h.containerLogsService.GetBuildLogs(*uid)
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           types3.ErrCodeFailedToGetContainerLogs,
+			PublicMessage:  fmt.Sprintf("Failed to get build logs for container %s.", uid),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(logs)
+}
+
+// FollowLogs streams the logs of the container from Docker, like
+// docker logs --tail N -f. It starts with the last tail lines of the
+// container (0 by default, or all), then follows the new ones. The lines are
+// sent as LogLine, with their Docker timestamp if timestamps=true.
+func (h *ContainerHandler) FollowLogs(c *router.Context) {
+	inst := h.getContainer(c)
+	if inst == nil {
+		return
+	}
+
+	tail := c.DefaultQuery("tail", "0")
+	if n, err := strconv.Atoi(tail); tail != "all" && (err != nil || n < 0) {
+		c.BadRequest(router.Error{
+			Code:          types3.ErrCodeLogsTailInvalid,
+			PublicMessage: fmt.Sprintf("Invalid tail: '%s'. Use a number of lines or 'all'.", tail),
+		})
+		return
+	}
+
+	options := types2.LogsContainerOptions{
+		Tail:       tail,
+		Timestamps: c.Query("timestamps") == "true",
+	}
+
+	ctx := c.Request.Context()
+	stdout, stderr, err := h.containerRunnerService.FollowLogs(ctx, *inst, options)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types3.ErrCodeFailedToGetContainerLogs,
+			PublicMessage: fmt.Sprintf("Failed to get logs for container %s.", inst.UUID),
+		})
+		return
+	}
+	defer stdout.Close()
+	defer stderr.Close()
+
+	lines := make(chan types3.LogLine)
+	var wg sync.WaitGroup
+	read := func(r io.Reader, kind string) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := types3.LogLine{Kind: kind}
+			message := scanner.Text()
+			if options.Timestamps {
+				if t, m, ok := types3.SplitLogTimestamp(message); ok {
+					line.Time = &t
+					message = m
+				}
+			}
+			line.Message = types3.NewLogLineMessageString(message)
+			line.Level = types3.DetectLogLevel(message, nil)
+
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+	wg.Add(2)
+	go read(stdout, types3.LogKindOut)
+	go read(stderr, types3.LogKindErr)
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	id := 0
+	c.Stream(func(w io.Writer) bool {
+		line, ok := <-lines
+		if !ok {
+			return false
+		}
+		line.Id = id
+		id++
+
+		e := sse.Event{Event: types3.EventNameContainerStdout, Data: line}
+		if line.Kind == types3.LogKindErr {
+			e.Event = types3.EventNameContainerStderr
+		}
+		err := sse.Encode(w, e)
+		if err != nil {
+			log.Error(err)
+			return false
+		}
+		return true
+	})
+}
+
 func (h *ContainerHandler) UpdateService(c *router.Context) {
 	inst := h.getContainer(c)
 	if inst == nil {
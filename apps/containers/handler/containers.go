@@ -1,16 +1,19 @@
 package handler
 
 import (
-	"io"
+	"fmt"
+	"strconv"
 
 	"github.com/vertex-center/vertex/apps/containers/core/port"
 	types2 "github.com/vertex-center/vertex/apps/containers/core/types"
-	vtypes "github.com/vertex-center/vertex/core/types"
 	apptypes "github.com/vertex-center/vertex/core/types/app"
 
 	"github.com/gin-contrib/sse"
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/pkg/ginutils"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vertex/pkg/router"
+	"github.com/vertex-center/vlog"
 )
 
 type ContainersHandler struct {
@@ -25,9 +28,54 @@ func NewContainersHandler(ctx *apptypes.Context, containerService port.Container
 	}
 }
 
+// Get returns all the installed containers, by UUID. The response has an
+// ETag, so a client polling the list only receives it when it changed.
 func (h *ContainersHandler) Get(c *router.Context) {
-	installed := h.containerService.GetAll()
-	c.JSON(installed)
+	c.JSONWithETag(h.containerService.GetAll())
+}
+
+// GetPage returns a page of the installed containers, with their total
+// count. The page is selected with the offset and limit query parameters,
+// and view=summary omits the heavy fields of each container. Like Get, the
+// response has an ETag.
+func (h *ContainersHandler) GetPage(c *router.Context) {
+	query := types2.ContainerListQuery{
+		Summary: c.Query("view") == "summary",
+	}
+
+	var err error
+	query.Offset, err = queryInt(c, "offset")
+	if err != nil {
+		return
+	}
+	query.Limit, err = queryInt(c, "limit")
+	if err != nil {
+		return
+	}
+
+	c.JSONWithETag(h.containerService.List(query))
+}
+
+// queryInt parses a positive integer query parameter, that defaults to 0.
+func queryInt(c *router.Context, name string) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err == nil && i < 0 {
+		err = fmt.Errorf("%s must be positive", name)
+	}
+	if err != nil {
+		c.BadRequest(router.Error{
+			Code:           types2.ErrCodePaginationInvalid,
+			PublicMessage:  fmt.Sprintf("Invalid %s: '%s'.", name, value),
+			PrivateMessage: err.Error(),
+		})
+		return 0, err
+	}
+	return i, nil
 }
 
 func (h *ContainersHandler) GetTags(c *router.Context) {
@@ -53,62 +101,152 @@ func (h *ContainersHandler) Search(c *router.Context) {
 }
 
 func (h *ContainersHandler) CheckForUpdates(c *router.Context) {
-	containers, err := h.containerService.CheckForUpdates()
-	if err != nil {
+	// The tags and digests are cached to avoid the rate limits of the
+	// registries, so reload=true is needed to see an image pushed since.
+	if c.Query("reload") == "true" {
+		h.containerService.ForgetRegistryCache()
+	}
+
+	// The check gives up on its own after its timeout, longer than the
+	// one of the requests.
+	ginutils.DisableTimeout(c.Context)
+
+	containers, err := h.containerService.CheckForUpdates(c.Request.Context())
+	if err != nil && len(containers) == 0 {
 		c.Abort(router.Error{
 			Code:           types2.ErrCodeFailedToCheckForUpdates,
 			PublicMessage:  "Failed to check for updates.",
 			PrivateMessage: err.Error(),
 		})
 		return
+	} else if err != nil {
+		// Reply with the containers that were checked.
+		log.Warn("some containers were not checked for updates", vlog.String("error", err.Error()))
 	}
 
 	c.JSON(containers)
 }
 
+// InstallFromImage installs a container running a Docker image, without a
+// service definition.
+func (h *ContainersHandler) InstallFromImage(c *router.Context) {
+	var body types2.ImageInstall
+	err := c.ParseBody(&body)
+	if err != nil {
+		return
+	}
+
+	inst, err := h.containerService.InstallFromImage(body.Image, body.Name)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types2.ErrCodeFailedToInstallService,
+			PublicMessage: fmt.Sprintf("Failed to install image '%s'.", body.Image),
+		})
+		return
+	}
+
+	c.JSON(inst)
+}
+
 func (h *ContainersHandler) Events(c *router.Context) {
-	eventsChan := make(chan sse.Event)
-	defer close(eventsChan)
+	h.ctx.StreamSSE(c, containersEventsFilter)
+}
 
-	done := c.Request.Context().Done()
+func (h *ContainersHandler) EventsWebSocket(c *router.Context) {
+	h.ctx.StreamWebSocket(c, containersEventsFilter)
+}
 
-	listener := vtypes.NewTempListener(func(e interface{}) {
-		switch e.(type) {
-		case types2.EventContainersChange:
-			eventsChan <- sse.Event{
-				Event: types2.EventNameContainersChange,
-			}
+func containersEventsFilter(e interface{}) *sse.Event {
+	switch e.(type) {
+	case types2.EventContainersChange:
+		return &sse.Event{
+			Event: types2.EventNameContainersChange,
 		}
-	})
+	}
+	return nil
+}
+
+// Logs streams the logs of multiple containers, merged in the order they are
+// received. The containers are selected with the uuids[] and tags[] query
+// parameters. Each line is prefixed with the container name.
+func (h *ContainersHandler) Logs(c *router.Context) {
+	names := h.getLogsContainers(c)
+	if names == nil {
+		return
+	}
 
-	h.ctx.AddListener(listener)
-	defer h.ctx.RemoveListener(listener)
+	h.ctx.StreamSSE(c, logsFilter(names))
+}
 
-	first := true
+func (h *ContainersHandler) LogsWebSocket(c *router.Context) {
+	names := h.getLogsContainers(c)
+	if names == nil {
+		return
+	}
 
-	c.Stream(func(w io.Writer) bool {
-		if first {
-			err := sse.Encode(w, sse.Event{
-				Event: "open",
+	h.ctx.StreamWebSocket(c, logsFilter(names))
+}
+
+// getLogsContainers returns the names of the containers selected by the
+// query, by UUID. It returns nil if the query is invalid.
+func (h *ContainersHandler) getLogsContainers(c *router.Context) map[uuid.UUID]string {
+	names := map[uuid.UUID]string{}
+
+	for _, id := range c.QueryArray("uuids[]") {
+		containerUUID, err := uuid.Parse(id)
+		if err != nil {
+			c.BadRequest(router.Error{
+				Code:           types2.ErrCodeContainerUuidInvalid,
+				PublicMessage:  fmt.Sprintf("Invalid container UUID: '%s'.", id),
+				PrivateMessage: err.Error(),
 			})
+			return nil
+		}
 
-			if err != nil {
-				log.Error(err)
-				return false
-			}
-			first = false
-			return true
+		inst, err := h.containerService.Get(containerUUID)
+		if err != nil {
+			c.NotFound(router.Error{
+				Code:           types2.ErrCodeContainerNotFound,
+				PublicMessage:  fmt.Sprintf("Container '%s' not found.", containerUUID),
+				PrivateMessage: err.Error(),
+			})
+			return nil
 		}
+		names[inst.UUID] = inst.DisplayName
+	}
 
-		select {
-		case e := <-eventsChan:
-			err := sse.Encode(w, e)
-			if err != nil {
-				log.Error(err)
+	tags := c.QueryArray("tags[]")
+	if len(tags) > 0 {
+		for _, inst := range h.containerService.Search(types2.ContainerSearchQuery{Tags: &tags}) {
+			names[inst.UUID] = inst.DisplayName
+		}
+	}
+
+	return names
+}
+
+func logsFilter(names map[uuid.UUID]string) apptypes.EventFilter {
+	return func(e interface{}) *sse.Event {
+		switch e := e.(type) {
+		case types2.EventContainerLog:
+			name, ok := names[e.ContainerUUID]
+			if !ok {
+				break
+			}
+
+			data := fmt.Sprintf("%s | %s", name, e.Message)
+			if e.Kind == types2.LogKindOut || e.Kind == types2.LogKindVertexOut {
+				return &sse.Event{
+					Event: types2.EventNameContainerStdout,
+					Data:  data,
+				}
+			} else if e.Kind == types2.LogKindErr || e.Kind == types2.LogKindVertexErr {
+				return &sse.Event{
+					Event: types2.EventNameContainerStderr,
+					Data:  data,
+				}
 			}
-			return true
-		case <-done:
-			return false
 		}
-	})
+		return nil
+	}
 }
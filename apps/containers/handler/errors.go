@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/vertex-center/vertex/apps/containers/core/service"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+func init() {
+	router.RegisterError(types.ErrContainerNotFound, http.StatusNotFound, router.Error{
+		Code:          types.ErrCodeContainerNotFound,
+		PublicMessage: "The container could not be found.",
+	})
+	router.RegisterError(types.ErrContainerStillRunning, http.StatusConflict, router.Error{
+		Code:          types.ErrCodeContainerStillRunning,
+		PublicMessage: "The container is still running. Stop it first.",
+	})
+	router.RegisterError(service.ErrContainerAlreadyRunning, http.StatusConflict, router.Error{
+		Code:          types.ErrCodeContainerAlreadyRunning,
+		PublicMessage: "The container is already running.",
+	})
+	router.RegisterError(service.ErrContainerNotRunning, http.StatusConflict, router.Error{
+		Code:          types.ErrCodeContainerNotRunning,
+		PublicMessage: "The container is not running.",
+	})
+	router.RegisterError(service.ErrContainerAlreadyPaused, http.StatusConflict, router.Error{
+		Code:          types.ErrCodeContainerAlreadyPaused,
+		PublicMessage: "The container is already paused.",
+	})
+	router.RegisterError(service.ErrContainerNotPaused, http.StatusConflict, router.Error{
+		Code:          types.ErrCodeContainerNotPaused,
+		PublicMessage: "The container is not paused.",
+	})
+	router.RegisterError(service.ErrContainerNotBuilding, http.StatusConflict, router.Error{
+		Code:          types.ErrCodeContainerNotBuilding,
+		PublicMessage: "The image of the container is not being built or pulled.",
+	})
+	router.RegisterError(types.ErrPortConflict, http.StatusConflict, router.Error{
+		Code:          types.ErrCodePortConflict,
+		PublicMessage: "A port of the container is already in use.",
+	})
+	router.RegisterError(types.ErrNoUpdateAvailable, http.StatusConflict, router.Error{
+		Code:          types.ErrCodeNoUpdateAvailable,
+		PublicMessage: "There is no update available for this container.",
+	})
+	router.RegisterError(types.ErrContainerPinned, http.StatusConflict, router.Error{
+		Code:          types.ErrCodeContainerPinned,
+		PublicMessage: "The container is pinned to its current version. Unpin it first.",
+	})
+	router.RegisterError(types.ErrVersionNotFound, http.StatusBadRequest, router.Error{
+		Code:          types.ErrCodeFailedToSetVersion,
+		PublicMessage: "The version is not a tag of the image. Check the available versions, or use skip_version_check=true for a private registry.",
+	})
+	router.RegisterError(types.ErrServiceNotFound, http.StatusNotFound, router.Error{
+		Code:          types.ErrCodeServiceNotFound,
+		PublicMessage: "The service could not be found.",
+	})
+	router.RegisterError(types.ErrInvalidImage, http.StatusBadRequest, router.Error{
+		Code:          types.ErrCodeImageInvalid,
+		PublicMessage: "The image is invalid. Use a reference like nginx:latest.",
+	})
+	router.RegisterError(types.ErrExportFormatInvalid, http.StatusBadRequest, router.Error{
+		Code:          types.ErrCodeExportFormatInvalid,
+		PublicMessage: "The export format is invalid. Use compose or run.",
+	})
+	router.RegisterError(types.ErrExportNotSupported, http.StatusBadRequest, router.Error{
+		Code:          types.ErrCodeExportNotSupported,
+		PublicMessage: "The container doesn't run with Docker, so it can't be exported.",
+	})
+	router.RegisterError(types.ErrStackNotFound, http.StatusNotFound, router.Error{
+		Code:          types.ErrCodeStackNotFound,
+		PublicMessage: "The stack could not be found.",
+	})
+	router.RegisterError(types.ErrStackInvalid, http.StatusBadRequest, router.Error{
+		Code:          types.ErrCodeStackInvalid,
+		PublicMessage: "The stack is invalid.",
+	})
+	router.RegisterError(types.ErrComposeInvalid, http.StatusBadRequest, router.Error{
+		Code:          types.ErrCodeComposeInvalid,
+		PublicMessage: "The compose file is invalid.",
+	})
+}
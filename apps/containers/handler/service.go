@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/vertex-center/vertex/apps/containers/core/port"
@@ -67,19 +66,21 @@ func (h *ServiceHandler) Install(c *router.Context) {
 		return
 	}
 
-	inst, err := h.containerService.Install(service, "docker")
-	if err != nil && errors.Is(err, types2.ErrServiceNotFound) {
-		c.NotFound(router.Error{
-			Code:           types2.ErrCodeServiceNotFound,
-			PublicMessage:  fmt.Sprintf("Service not found: %s.", serviceID),
-			PrivateMessage: err.Error(),
-		})
-		return
-	} else if err != nil {
-		c.Abort(router.Error{
-			Code:           types2.ErrCodeFailedToInstallService,
-			PublicMessage:  fmt.Sprintf("Failed to install service '%s'.", service.Name),
-			PrivateMessage: err.Error(),
+	// The body is optional, and configures the container before it is
+	// ever started.
+	var body types2.ServiceInstall
+	if c.Request.ContentLength != 0 {
+		err = c.ParseBody(&body)
+		if err != nil {
+			return
+		}
+	}
+
+	inst, err := h.containerService.InstallWith(service, "docker", body)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types2.ErrCodeFailedToInstallService,
+			PublicMessage: fmt.Sprintf("Failed to install service '%s'.", service.Name),
 		})
 		return
 	}
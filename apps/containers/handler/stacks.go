@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	"github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type StacksHandler struct {
+	stackService port.StackService
+}
+
+func NewStacksHandler(stackService port.StackService) port.StacksHandler {
+	return &StacksHandler{
+		stackService: stackService,
+	}
+}
+
+func getStackName(c *router.Context) (string, error) {
+	name := c.Param("stack_name")
+	if name == "" {
+		c.BadRequest(router.Error{
+			Code:           types.ErrCodeStackNameMissing,
+			PublicMessage:  "The request was missing the stack name.",
+			PrivateMessage: "Field 'stack_name' is required.",
+		})
+		return "", errors.New("stack name missing")
+	}
+	return name, nil
+}
+
+func (h *StacksHandler) Install(c *router.Context) {
+	var stack types.Stack
+	err := c.ParseBody(&stack)
+	if err != nil {
+		return
+	}
+
+	ids, err := h.stackService.Install(stack)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types.ErrCodeFailedToInstallStack,
+			PublicMessage: fmt.Sprintf("Failed to install stack '%s'.", stack.Name),
+		})
+		return
+	}
+
+	c.JSON(ids)
+}
+
+// ImportCompose installs the services of a docker-compose.yml file as a
+// stack. The response lists the features of the file that were ignored.
+func (h *StacksHandler) ImportCompose(c *router.Context) {
+	var body types.ComposeImport
+	err := c.ParseBody(&body)
+	if err != nil {
+		return
+	}
+
+	res, err := h.stackService.ImportCompose(body.Name, []byte(body.Content))
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types.ErrCodeFailedToInstallStack,
+			PublicMessage: fmt.Sprintf("Failed to import the compose file as stack '%s'.", body.Name),
+		})
+		return
+	}
+
+	c.JSON(res)
+}
+
+func (h *StacksHandler) Start(c *router.Context) {
+	name, err := getStackName(c)
+	if err != nil {
+		return
+	}
+
+	err = h.stackService.Start(name)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types.ErrCodeFailedToStartStack,
+			PublicMessage: fmt.Sprintf("Failed to start stack '%s'.", name),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+func (h *StacksHandler) Stop(c *router.Context) {
+	name, err := getStackName(c)
+	if err != nil {
+		return
+	}
+
+	err = h.stackService.Stop(name)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types.ErrCodeFailedToStopStack,
+			PublicMessage: fmt.Sprintf("Failed to stop stack '%s'.", name),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+func (h *StacksHandler) Delete(c *router.Context) {
+	name, err := getStackName(c)
+	if err != nil {
+		return
+	}
+
+	err = h.stackService.Delete(name)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types.ErrCodeFailedToDeleteStack,
+			PublicMessage: fmt.Sprintf("Failed to delete stack '%s'.", name),
+		})
+		return
+	}
+
+	c.OK()
+}
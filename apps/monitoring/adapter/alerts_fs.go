@@ -0,0 +1,148 @@
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/monitoring/core/port"
+	"github.com/vertex-center/vertex/apps/monitoring/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/storage"
+	"github.com/vertex-center/vlog"
+)
+
+var (
+	errAlertsNotFound       = errors.New("alerts.json doesn't exists or could not be found")
+	errAlertsFailedToRead   = errors.New("failed to read alerts.json")
+	errAlertsFailedToDecode = errors.New("failed to decode alerts.json")
+)
+
+type AlertsFSAdapter struct {
+	alerts      types.AlertRules
+	alertsMutex sync.RWMutex
+
+	alertsPath string
+}
+
+type AlertsFSAdapterParams struct {
+	alertsPath string
+}
+
+func NewAlertsFSAdapter(params *AlertsFSAdapterParams) port.AlertsAdapter {
+	if params == nil {
+		params = &AlertsFSAdapterParams{}
+	}
+	if params.alertsPath == "" {
+		params.alertsPath = path.Join(storage.Path, "apps", "vx-monitoring")
+	}
+
+	err := os.MkdirAll(params.alertsPath, os.ModePerm)
+	if err != nil && !os.IsExist(err) {
+		log.Error(err,
+			vlog.String("message", "failed to create directory"),
+			vlog.String("path", params.alertsPath),
+		)
+		os.Exit(1)
+	}
+
+	adapter := &AlertsFSAdapter{
+		alerts:      types.AlertRules{},
+		alertsMutex: sync.RWMutex{},
+
+		alertsPath: params.alertsPath,
+	}
+
+	err = adapter.read()
+	if errors.Is(err, errAlertsFailedToDecode) {
+		log.Error(err)
+	}
+
+	return adapter
+}
+
+func (a *AlertsFSAdapter) GetAlerts() types.AlertRules {
+	a.alertsMutex.RLock()
+	defer a.alertsMutex.RUnlock()
+
+	alerts := types.AlertRules{}
+	for id, rule := range a.alerts {
+		alerts[id] = rule
+	}
+	return alerts
+}
+
+func (a *AlertsFSAdapter) GetAlert(id uuid.UUID) (types.AlertRule, error) {
+	a.alertsMutex.RLock()
+	defer a.alertsMutex.RUnlock()
+
+	rule, ok := a.alerts[id]
+	if !ok {
+		return types.AlertRule{}, types.ErrAlertNotFound
+	}
+	return rule, nil
+}
+
+func (a *AlertsFSAdapter) SetAlert(id uuid.UUID, rule types.AlertRule) error {
+	func() {
+		a.alertsMutex.Lock()
+		defer a.alertsMutex.Unlock()
+		a.alerts[id] = rule
+	}()
+	return a.write()
+}
+
+func (a *AlertsFSAdapter) RemoveAlert(id uuid.UUID) error {
+	err := func() error {
+		a.alertsMutex.Lock()
+		defer a.alertsMutex.Unlock()
+		if _, ok := a.alerts[id]; !ok {
+			return types.ErrAlertNotFound
+		}
+		delete(a.alerts, id)
+		return nil
+	}()
+	if err != nil {
+		return err
+	}
+	return a.write()
+}
+
+func (a *AlertsFSAdapter) read() error {
+	p := path.Join(a.alertsPath, "alerts.json")
+	file, err := os.ReadFile(p)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return errAlertsNotFound
+	} else if err != nil {
+		return fmt.Errorf("%w: %w", errAlertsFailedToRead, err)
+	}
+
+	a.alertsMutex.Lock()
+	defer a.alertsMutex.Unlock()
+
+	err = json.Unmarshal(file, &a.alerts)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errAlertsFailedToDecode, err)
+	}
+
+	return nil
+}
+
+func (a *AlertsFSAdapter) write() error {
+	p := path.Join(a.alertsPath, "alerts.json")
+
+	a.alertsMutex.RLock()
+	defer a.alertsMutex.RUnlock()
+
+	bytes, err := json.MarshalIndent(a.alerts, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(p, bytes, os.ModePerm)
+}
@@ -0,0 +1,112 @@
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/vertex-center/vertex/apps/monitoring/core/port"
+	"github.com/vertex-center/vertex/apps/monitoring/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/storage"
+	"github.com/vertex-center/vlog"
+)
+
+var (
+	errCollectorSettingsNotFound       = errors.New("collector.json doesn't exists or could not be found")
+	errCollectorSettingsFailedToRead   = errors.New("failed to read collector.json")
+	errCollectorSettingsFailedToDecode = errors.New("failed to decode collector.json")
+)
+
+type CollectorSettingsFSAdapter struct {
+	settings      types.CollectorSettings
+	settingsMutex sync.RWMutex
+
+	settingsPath string
+}
+
+type CollectorSettingsFSAdapterParams struct {
+	settingsPath string
+}
+
+func NewCollectorSettingsFSAdapter(params *CollectorSettingsFSAdapterParams) port.CollectorSettingsAdapter {
+	if params == nil {
+		params = &CollectorSettingsFSAdapterParams{}
+	}
+	if params.settingsPath == "" {
+		params.settingsPath = path.Join(storage.Path, "apps", "vx-monitoring")
+	}
+
+	err := os.MkdirAll(params.settingsPath, os.ModePerm)
+	if err != nil && !os.IsExist(err) {
+		log.Error(err,
+			vlog.String("message", "failed to create directory"),
+			vlog.String("path", params.settingsPath),
+		)
+		os.Exit(1)
+	}
+
+	adapter := &CollectorSettingsFSAdapter{
+		settingsPath: params.settingsPath,
+	}
+
+	err = adapter.read()
+	if errors.Is(err, errCollectorSettingsFailedToDecode) {
+		log.Error(err)
+	}
+
+	return adapter
+}
+
+func (a *CollectorSettingsFSAdapter) GetSettings() types.CollectorSettings {
+	a.settingsMutex.RLock()
+	defer a.settingsMutex.RUnlock()
+
+	return a.settings
+}
+
+func (a *CollectorSettingsFSAdapter) SetSettings(settings types.CollectorSettings) error {
+	a.settingsMutex.Lock()
+	a.settings = settings
+	a.settingsMutex.Unlock()
+
+	return a.write()
+}
+
+func (a *CollectorSettingsFSAdapter) read() error {
+	p := path.Join(a.settingsPath, "collector.json")
+	file, err := os.ReadFile(p)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return errCollectorSettingsNotFound
+	} else if err != nil {
+		return fmt.Errorf("%w: %w", errCollectorSettingsFailedToRead, err)
+	}
+
+	a.settingsMutex.Lock()
+	defer a.settingsMutex.Unlock()
+
+	err = json.Unmarshal(file, &a.settings)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCollectorSettingsFailedToDecode, err)
+	}
+
+	return nil
+}
+
+func (a *CollectorSettingsFSAdapter) write() error {
+	p := path.Join(a.settingsPath, "collector.json")
+
+	a.settingsMutex.RLock()
+	defer a.settingsMutex.RUnlock()
+
+	bytes, err := json.MarshalIndent(a.settings, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(p, bytes, os.ModePerm)
+}
@@ -1,14 +1,19 @@
 package adapter
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	metricstypes "github.com/vertex-center/vertex/apps/monitoring/core/types"
 	"net/http"
 	"os"
 	"path"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/carlmjohnson/requests"
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -28,12 +33,16 @@ type PrometheusAdapter struct {
 	// mutex for all maps
 	mutex *sync.RWMutex
 
-	reg *prometheus.Registry
+	reg    *prometheus.Registry
+	server *http.Server
 }
 
 func NewMetricsPrometheusAdapter() *PrometheusAdapter {
 	reg := prometheus.NewRegistry()
 
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
 	a := &PrometheusAdapter{
 		gauges:    map[string]prometheus.Gauge{},
 		gaugeVecs: map[string]*prometheus.GaugeVec{},
@@ -41,12 +50,15 @@ func NewMetricsPrometheusAdapter() *PrometheusAdapter {
 		mutex: &sync.RWMutex{},
 
 		reg: reg,
+		server: &http.Server{
+			Addr:    ":" + config.Current.PortPrometheus,
+			Handler: mux,
+		},
 	}
 
 	go func() {
-		http.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
-		err := http.ListenAndServe(":2112", nil)
-		if err != nil {
+		err := a.server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Error(err)
 		}
 	}()
@@ -54,8 +66,15 @@ func NewMetricsPrometheusAdapter() *PrometheusAdapter {
 	return a
 }
 
+// Close stops the server exposing the metrics to the collector.
+func (a *PrometheusAdapter) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return a.server.Shutdown(ctx)
+}
+
 func (a *PrometheusAdapter) ConfigureContainer(uuid uuid.UUID) error {
-	dir := path.Join(storage.Path, "apps", "vx-containers", uuid.String(), "volumes", "config")
+	dir := a.configDir(uuid)
 	p := path.Join(dir, "prometheus.yml")
 
 	err := os.MkdirAll(dir, 0755)
@@ -66,6 +85,7 @@ func (a *PrometheusAdapter) ConfigureContainer(uuid uuid.UUID) error {
 	url := fmt.Sprintf("%s:%s", config.Current.Host, config.Current.PortPrometheus)
 
 	data := map[string]interface{}{
+		"rule_files": []string{"alerts.yml"},
 		"scrape_configs": []map[string]interface{}{
 			{
 				"job_name":        "vertex",
@@ -87,6 +107,91 @@ func (a *PrometheusAdapter) ConfigureContainer(uuid uuid.UUID) error {
 	return os.WriteFile(p, bytes, 0644)
 }
 
+func (a *PrometheusAdapter) ConfigureAlerts(uuid uuid.UUID, alerts metricstypes.AlertRules) error {
+	dir := a.configDir(uuid)
+	p := path.Join(dir, "alerts.yml")
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	bytes, err := renderAlerts(alerts)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(p, bytes, 0644)
+}
+
+// renderAlerts returns the Prometheus rules file of the alerts. The rules
+// are sorted by name, so the file only changes with the alerts.
+func renderAlerts(alerts metricstypes.AlertRules) ([]byte, error) {
+	ids := make([]uuid.UUID, 0, len(alerts))
+	for id := range alerts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := alerts[ids[i]], alerts[ids[j]]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return ids[i].String() < ids[j].String()
+	})
+
+	rules := []map[string]interface{}{}
+	for _, id := range ids {
+		alert := alerts[id]
+		rule := map[string]interface{}{
+			"alert": alert.Name,
+			"expr":  alert.Expr(),
+			"labels": map[string]string{
+				"vertex_alert_id": id.String(),
+			},
+		}
+		if alert.Duration != "" {
+			rule["for"] = alert.Duration
+		}
+		rules = append(rules, rule)
+	}
+
+	data := map[string]interface{}{
+		"groups": []map[string]interface{}{
+			{
+				"name":  "vertex",
+				"rules": rules,
+			},
+		},
+	}
+
+	return yaml.Marshal(data)
+}
+
+// Command returns the Prometheus flags. The lifecycle API is enabled so
+// the configuration can be reloaded without restarting the container.
+func (a *PrometheusAdapter) Command(settings metricstypes.CollectorSettings) string {
+	flags := []string{
+		"--config.file=/etc/prometheus/prometheus.yml",
+		"--storage.tsdb.path=/prometheus",
+		"--web.enable-lifecycle",
+	}
+	if settings.RetentionTime != "" {
+		flags = append(flags, "--storage.tsdb.retention.time="+settings.RetentionTime)
+	}
+	if settings.RetentionSize != "" {
+		flags = append(flags, "--storage.tsdb.retention.size="+settings.RetentionSize)
+	}
+	return strings.Join(flags, " ")
+}
+
+// Reload requires Prometheus to be started with --web.enable-lifecycle.
+func (a *PrometheusAdapter) Reload(url string) error {
+	return requests.URL(url).
+		Path("/-/reload").
+		Post().
+		Fetch(context.Background())
+}
+
 func (a *PrometheusAdapter) RegisterMetrics(metrics []metricstypes.Metric) {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
@@ -155,3 +260,7 @@ func (a *PrometheusAdapter) Dec(metricID string, labels ...string) {
 		log.Error(ErrMetricNotFound, vlog.String("metric_id", metricID))
 	}
 }
+
+func (a *PrometheusAdapter) configDir(uuid uuid.UUID) string {
+	return path.Join(storage.Path, "apps", "vx-containers", uuid.String(), "volumes", "config")
+}
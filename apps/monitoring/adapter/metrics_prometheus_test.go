@@ -0,0 +1,71 @@
+package adapter
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/apps/monitoring/core/types"
+)
+
+type PrometheusAdapterTestSuite struct {
+	suite.Suite
+}
+
+func TestPrometheusAdapterTestSuite(t *testing.T) {
+	suite.Run(t, new(PrometheusAdapterTestSuite))
+}
+
+func (suite *PrometheusAdapterTestSuite) TestRenderAlerts() {
+	down := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	cpu := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+
+	rules, err := renderAlerts(types.AlertRules{
+		cpu: {
+			Name:       "high_cpu",
+			MetricID:   "vertex_cpu_usage",
+			Comparison: types.AlertComparisonGreater,
+			Threshold:  0.9,
+			Duration:   "1d",
+		},
+		down: {
+			Name:       "container_down",
+			MetricID:   "vertex_container_status",
+			Comparison: types.AlertComparisonEqual,
+			Threshold:  0,
+		},
+	})
+	suite.Require().NoError(err)
+	suite.Equal(`groups:
+    - name: vertex
+      rules:
+        - alert: container_down
+          expr: vertex_container_status == 0
+          labels:
+            vertex_alert_id: 11111111-1111-1111-1111-111111111111
+        - alert: high_cpu
+          expr: vertex_cpu_usage > 0.9
+          for: 1d
+          labels:
+            vertex_alert_id: 22222222-2222-2222-2222-222222222222
+`, string(rules))
+}
+
+func (suite *PrometheusAdapterTestSuite) TestRenderNoAlerts() {
+	rules, err := renderAlerts(types.AlertRules{})
+	suite.Require().NoError(err)
+	suite.Equal(`groups:
+    - name: vertex
+      rules: []
+`, string(rules))
+}
+
+func (suite *PrometheusAdapterTestSuite) TestCommand() {
+	a := &PrometheusAdapter{}
+
+	suite.Equal("--config.file=/etc/prometheus/prometheus.yml --storage.tsdb.path=/prometheus --web.enable-lifecycle",
+		a.Command(types.CollectorSettings{}))
+	suite.Equal("--config.file=/etc/prometheus/prometheus.yml --storage.tsdb.path=/prometheus --web.enable-lifecycle"+
+		" --storage.tsdb.retention.time=15d --storage.tsdb.retention.size=512MB",
+		a.Command(types.CollectorSettings{RetentionTime: "15d", RetentionSize: "512MB"}))
+}
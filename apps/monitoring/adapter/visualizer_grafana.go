@@ -0,0 +1,59 @@
+package adapter
+
+import (
+	_ "embed"
+	"os"
+	"path"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/pkg/storage"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed grafana/vertex.json
+var grafanaDashboard []byte
+
+type GrafanaAdapter struct{}
+
+func NewVisualizerGrafanaAdapter() *GrafanaAdapter {
+	return &GrafanaAdapter{}
+}
+
+// ConfigureContainer provisions the Vertex dashboard in the Grafana container.
+// The config volume is expected to be mounted on /etc/grafana, so the files
+// are picked up from Grafana's default provisioning directory.
+func (a *GrafanaAdapter) ConfigureContainer(uuid uuid.UUID) error {
+	dir := path.Join(storage.Path, "apps", "vx-containers", uuid.String(), "volumes", "config", "provisioning", "dashboards")
+	dashboardsDir := path.Join(dir, "vertex")
+
+	err := os.MkdirAll(dashboardsDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"apiVersion": 1,
+		"providers": []map[string]interface{}{
+			{
+				"name":   "vertex",
+				"folder": "Vertex",
+				"type":   "file",
+				"options": map[string]interface{}{
+					"path": "/etc/grafana/provisioning/dashboards/vertex",
+				},
+			},
+		},
+	}
+
+	bytes, err := yaml.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path.Join(dir, "vertex.yml"), bytes, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path.Join(dashboardsDir, "vertex.json"), grafanaDashboard, 0644)
+}
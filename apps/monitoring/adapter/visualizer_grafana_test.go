@@ -0,0 +1,87 @@
+package adapter
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/pkg/storage"
+	"gopkg.in/yaml.v3"
+)
+
+type GrafanaAdapterTestSuite struct {
+	suite.Suite
+
+	wd string
+}
+
+func TestGrafanaAdapterTestSuite(t *testing.T) {
+	suite.Run(t, new(GrafanaAdapterTestSuite))
+}
+
+// SetupTest moves to a temporary directory, as the containers are stored
+// relatively to the working directory.
+func (suite *GrafanaAdapterTestSuite) SetupTest() {
+	var err error
+	suite.wd, err = os.Getwd()
+	suite.Require().NoError(err)
+	suite.Require().NoError(os.Chdir(suite.T().TempDir()))
+}
+
+func (suite *GrafanaAdapterTestSuite) TearDownTest() {
+	suite.Require().NoError(os.Chdir(suite.wd))
+}
+
+func (suite *GrafanaAdapterTestSuite) TestConfigureContainer() {
+	id := uuid.New()
+	err := NewVisualizerGrafanaAdapter().ConfigureContainer(id)
+	suite.Require().NoError(err)
+
+	dir := path.Join(storage.Path, "apps", "vx-containers", id.String(), "volumes", "config", "provisioning", "dashboards")
+
+	// The provider points to the dashboards, as seen from the container.
+	content, err := os.ReadFile(path.Join(dir, "vertex.yml"))
+	suite.Require().NoError(err)
+	var provisioning struct {
+		Providers []struct {
+			Type    string `yaml:"type"`
+			Options struct {
+				Path string `yaml:"path"`
+			} `yaml:"options"`
+		} `yaml:"providers"`
+	}
+	suite.Require().NoError(yaml.Unmarshal(content, &provisioning))
+	suite.Require().Len(provisioning.Providers, 1)
+	suite.Equal("file", provisioning.Providers[0].Type)
+	suite.Equal("/etc/grafana/provisioning/dashboards/vertex", provisioning.Providers[0].Options.Path)
+
+	content, err = os.ReadFile(path.Join(dir, "vertex", "vertex.json"))
+	suite.Require().NoError(err)
+	suite.Equal(grafanaDashboard, content)
+}
+
+func (suite *GrafanaAdapterTestSuite) TestDashboard() {
+	var dashboard struct {
+		UID    string `json:"uid"`
+		Panels []struct {
+			Targets []struct {
+				Expr string `json:"expr"`
+			} `json:"targets"`
+		} `json:"panels"`
+	}
+	err := json.Unmarshal(grafanaDashboard, &dashboard)
+	suite.Require().NoError(err)
+	suite.Equal("vertex", dashboard.UID)
+
+	var exprs []string
+	for _, panel := range dashboard.Panels {
+		for _, target := range panel.Targets {
+			exprs = append(exprs, target.Expr)
+		}
+	}
+	suite.Contains(exprs, "vertex_containers_count")
+	suite.Contains(exprs, "vertex_container_status")
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/monitoring"
 	metricstypes "github.com/vertex-center/vertex/apps/monitoring/core/types"
 	"github.com/vertex-center/vertex/core/types/api"
@@ -37,3 +38,68 @@ func InstallVisualizer(ctx context.Context, visualizer string) *api.Error {
 		Fetch(ctx)
 	return api.HandleError(err, apiError)
 }
+
+func GetCollectorSettings(ctx context.Context, collector string) (metricstypes.CollectorSettings, *api.Error) {
+	var settings metricstypes.CollectorSettings
+	var apiError api.Error
+	err := api.AppRequest(monitoring.AppRoute).
+		Pathf("./collector/%s/settings", collector).
+		ToJSON(&settings).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return settings, api.HandleError(err, apiError)
+}
+
+func PatchCollectorSettings(ctx context.Context, collector string, settings metricstypes.CollectorSettings) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(monitoring.AppRoute).
+		Pathf("./collector/%s/settings", collector).
+		Patch().
+		BodyJSON(&settings).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
+
+func GetAlerts(ctx context.Context) (metricstypes.AlertRules, *api.Error) {
+	var alerts metricstypes.AlertRules
+	var apiError api.Error
+	err := api.AppRequest(monitoring.AppRoute).
+		Path("./alerts").
+		ToJSON(&alerts).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return alerts, api.HandleError(err, apiError)
+}
+
+func AddAlert(ctx context.Context, rule metricstypes.AlertRule) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(monitoring.AppRoute).
+		Path("./alert").
+		Post().
+		BodyJSON(&rule).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
+
+func UpdateAlert(ctx context.Context, id uuid.UUID, rule metricstypes.AlertRule) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(monitoring.AppRoute).
+		Pathf("./alert/%s", id).
+		Put().
+		BodyJSON(&rule).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
+
+func RemoveAlert(ctx context.Context, id uuid.UUID) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(monitoring.AppRoute).
+		Pathf("./alert/%s", id).
+		Delete().
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
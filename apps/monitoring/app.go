@@ -10,11 +10,15 @@ import (
 )
 
 const (
+	AppID    = "vx-monitoring"
 	AppRoute = "/vx-monitoring"
 )
 
 var (
-	prometheusAdapter port.MetricsAdapter
+	prometheusAdapter  port.MetricsAdapter
+	alertsFSAdapter    port.AlertsAdapter
+	grafanaAdapter     port.VisualizerAdapter
+	collectorFSAdapter port.CollectorSettingsAdapter
 
 	metricsService port.MetricsService
 )
@@ -27,15 +31,27 @@ func NewApp() *App {
 	return &App{}
 }
 
+func (a *App) ID() string {
+	return AppID
+}
+
 func (a *App) Initialize(app *apptypes.App) error {
 	a.App = app
 
 	prometheusAdapter = adapter.NewMetricsPrometheusAdapter()
+	alertsFSAdapter = adapter.NewAlertsFSAdapter(nil)
+	grafanaAdapter = adapter.NewVisualizerGrafanaAdapter()
+	collectorFSAdapter = adapter.NewCollectorSettingsFSAdapter(nil)
 
-	metricsService = service.NewMetricsService(app.Context(), prometheusAdapter)
+	metricsService = service.NewMetricsService(app.Context(), service.MetricsServiceParams{
+		MetricsAdapter:           prometheusAdapter,
+		AlertsAdapter:            alertsFSAdapter,
+		VisualizerAdapter:        grafanaAdapter,
+		CollectorSettingsAdapter: collectorFSAdapter,
+	})
 
 	app.Register(apptypes.Meta{
-		ID:          "vx-monitoring",
+		ID:          AppID,
 		Name:        "Vertex Monitoring",
 		Description: "Create and manage containers.",
 		Icon:        "monitoring",
@@ -46,8 +62,20 @@ func (a *App) Initialize(app *apptypes.App) error {
 
 		r.GET("/metrics", metricsHandler.Get)
 		r.POST("/collector/:collector/install", metricsHandler.InstallCollector)
+		r.GET("/collector/:collector/settings", metricsHandler.GetCollectorSettings)
+		r.PATCH("/collector/:collector/settings", metricsHandler.PatchCollectorSettings)
 		r.POST("/visualizer/:visualizer/install", metricsHandler.InstallVisualizer)
+
+		alertsHandler := handler.NewAlertsHandler(metricsService)
+		r.GET("/alerts", alertsHandler.Get)
+		r.POST("/alert", alertsHandler.Add)
+		r.PUT("/alert/:id", alertsHandler.Update)
+		r.DELETE("/alert/:id", alertsHandler.Remove)
 	})
 
 	return nil
 }
+
+func (a *App) Uninitialize() error {
+	return prometheusAdapter.Close()
+}
@@ -9,10 +9,41 @@ type MetricsAdapter interface {
 	// ConfigureContainer configures an container to monitor the metrics of Vertex.
 	ConfigureContainer(uuid uuid.UUID) error
 
+	// ConfigureAlerts writes the alerting rules in the container configuration.
+	ConfigureAlerts(uuid uuid.UUID, alerts types.AlertRules) error
+
+	// Command returns the command to start the collector with the given settings.
+	Command(settings types.CollectorSettings) string
+
+	// Reload asks the collector running at the given url to reload its configuration.
+	Reload(url string) error
+
 	// RegisterMetrics registers the metrics that can be monitored.
 	RegisterMetrics(metrics []types.Metric)
 
 	Set(metricID string, value interface{}, labels ...string)
 	Inc(metricID string, labels ...string)
 	Dec(metricID string, labels ...string)
+
+	// Close stops the server exposing the metrics.
+	Close() error
+}
+
+type VisualizerAdapter interface {
+	// ConfigureContainer provisions the Vertex dashboards in a visualizer container.
+	ConfigureContainer(uuid uuid.UUID) error
+}
+
+type CollectorSettingsAdapter interface {
+	GetSettings() types.CollectorSettings
+	SetSettings(settings types.CollectorSettings) error
+}
+
+type AlertsAdapter interface {
+	GetAlerts() types.AlertRules
+	// GetAlert returns the alert with the given id, or ErrAlertNotFound.
+	GetAlert(id uuid.UUID) (types.AlertRule, error)
+	SetAlert(id uuid.UUID, rule types.AlertRule) error
+	// RemoveAlert removes the alert with the given id, or returns ErrAlertNotFound.
+	RemoveAlert(id uuid.UUID) error
 }
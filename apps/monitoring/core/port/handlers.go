@@ -7,5 +7,14 @@ type (
 		Get(c *router.Context)
 		InstallCollector(c *router.Context)
 		InstallVisualizer(c *router.Context)
+		GetCollectorSettings(c *router.Context)
+		PatchCollectorSettings(c *router.Context)
+	}
+
+	AlertsHandler interface {
+		Get(c *router.Context)
+		Add(c *router.Context)
+		Update(c *router.Context)
+		Remove(c *router.Context)
 	}
 )
@@ -1,6 +1,7 @@
 package port
 
 import (
+	"github.com/google/uuid"
 	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
 	"github.com/vertex-center/vertex/apps/monitoring/core/types"
 )
@@ -10,5 +11,25 @@ type (
 		GetMetrics() []types.Metric
 		ConfigureVisualizer(inst *containerstypes.Container) error
 		ConfigureCollector(inst *containerstypes.Container) error
+
+		// GetCollectorSettings returns the settings of the collector.
+		GetCollectorSettings() types.CollectorSettings
+		// SetCollectorSettings validates and saves the settings of the collector.
+		SetCollectorSettings(settings types.CollectorSettings) error
+		// GetCollectorCommand returns the command to start the collector
+		// with the current settings.
+		GetCollectorCommand() string
+
+		// GetAlerts returns all the alerting rules.
+		GetAlerts() types.AlertRules
+		// AddAlert adds an alerting rule and applies it to the collector, if
+		// the collector is installed. The collector can be nil.
+		AddAlert(collector *containerstypes.Container, rule types.AlertRule) (uuid.UUID, error)
+		// UpdateAlert replaces an alerting rule. It returns ErrAlertNotFound if
+		// the rule doesn't exist.
+		UpdateAlert(collector *containerstypes.Container, id uuid.UUID, rule types.AlertRule) error
+		// RemoveAlert removes an alerting rule. It returns ErrAlertNotFound if
+		// the rule doesn't exist.
+		RemoveAlert(collector *containerstypes.Container, id uuid.UUID) error
 	}
 )
@@ -11,16 +11,29 @@ import (
 )
 
 type MetricsService struct {
-	uuid    uuid.UUID
-	adapter port.MetricsAdapter
-	metrics []types.Metric
+	uuid              uuid.UUID
+	adapter           port.MetricsAdapter
+	alertsAdapter     port.AlertsAdapter
+	visualizerAdapter port.VisualizerAdapter
+	settingsAdapter   port.CollectorSettingsAdapter
+	metrics           []types.Metric
 }
 
-func NewMetricsService(ctx *app.Context, metricsAdapter port.MetricsAdapter) port.MetricsService {
+type MetricsServiceParams struct {
+	MetricsAdapter           port.MetricsAdapter
+	AlertsAdapter            port.AlertsAdapter
+	VisualizerAdapter        port.VisualizerAdapter
+	CollectorSettingsAdapter port.CollectorSettingsAdapter
+}
+
+func NewMetricsService(ctx *app.Context, params MetricsServiceParams) port.MetricsService {
 	s := &MetricsService{
-		uuid:    uuid.New(),
-		adapter: metricsAdapter,
-		metrics: []types.Metric{},
+		uuid:              uuid.New(),
+		adapter:           params.MetricsAdapter,
+		alertsAdapter:     params.AlertsAdapter,
+		visualizerAdapter: params.VisualizerAdapter,
+		settingsAdapter:   params.CollectorSettingsAdapter,
+		metrics:           []types.Metric{},
 	}
 	ctx.AddListener(s)
 	return s
@@ -32,12 +45,32 @@ func (s *MetricsService) GetMetrics() []types.Metric {
 
 // ConfigureCollector will configure a container to monitor the metrics of Vertex.
 func (s *MetricsService) ConfigureCollector(inst *containerstypes.Container) error {
-	return s.adapter.ConfigureContainer(inst.UUID)
+	err := s.adapter.ConfigureContainer(inst.UUID)
+	if err != nil {
+		return err
+	}
+	return s.adapter.ConfigureAlerts(inst.UUID, s.alertsAdapter.GetAlerts())
+}
+
+func (s *MetricsService) GetCollectorSettings() types.CollectorSettings {
+	return s.settingsAdapter.GetSettings()
+}
+
+func (s *MetricsService) SetCollectorSettings(settings types.CollectorSettings) error {
+	err := settings.Validate()
+	if err != nil {
+		return err
+	}
+	return s.settingsAdapter.SetSettings(settings)
+}
+
+func (s *MetricsService) GetCollectorCommand() string {
+	return s.adapter.Command(s.settingsAdapter.GetSettings())
 }
 
+// ConfigureVisualizer will provision the Vertex dashboards in a visualizer container.
 func (s *MetricsService) ConfigureVisualizer(inst *containerstypes.Container) error {
-	// TODO: Implement
-	return nil
+	return s.visualizerAdapter.ConfigureContainer(inst.UUID)
 }
 
 func (s *MetricsService) GetUUID() uuid.UUID {
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/common/model"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/apps/monitoring/core/types"
+	"github.com/vertex-center/vertex/config"
+)
+
+func (s *MetricsService) GetAlerts() types.AlertRules {
+	return s.alertsAdapter.GetAlerts()
+}
+
+func (s *MetricsService) AddAlert(collector *containerstypes.Container, rule types.AlertRule) (uuid.UUID, error) {
+	err := validateAlert(rule)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	id := uuid.New()
+	err = s.alertsAdapter.SetAlert(id, rule)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	return id, s.applyAlerts(collector)
+}
+
+func (s *MetricsService) UpdateAlert(collector *containerstypes.Container, id uuid.UUID, rule types.AlertRule) error {
+	err := validateAlert(rule)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.alertsAdapter.GetAlert(id)
+	if err != nil {
+		return err
+	}
+
+	err = s.alertsAdapter.SetAlert(id, rule)
+	if err != nil {
+		return err
+	}
+	return s.applyAlerts(collector)
+}
+
+func (s *MetricsService) RemoveAlert(collector *containerstypes.Container, id uuid.UUID) error {
+	err := s.alertsAdapter.RemoveAlert(id)
+	if err != nil {
+		return err
+	}
+	return s.applyAlerts(collector)
+}
+
+// applyAlerts writes the alerting rules in the collector configuration, and
+// reloads the collector if it is running.
+func (s *MetricsService) applyAlerts(collector *containerstypes.Container) error {
+	if collector == nil {
+		return nil
+	}
+
+	err := s.adapter.ConfigureAlerts(collector.UUID, s.alertsAdapter.GetAlerts())
+	if err != nil {
+		return err
+	}
+
+	if !collector.IsRunning() {
+		return nil
+	}
+
+	url, err := collectorURL(collector)
+	if err != nil {
+		return err
+	}
+
+	err = s.adapter.Reload(url)
+	if err != nil {
+		return fmt.Errorf("%w: %w", types.ErrFailedToReloadCollector, err)
+	}
+	return nil
+}
+
+// validateAlert checks the rule like Prometheus does when it loads it, so
+// a rule can't break the reload of the collector.
+func validateAlert(rule types.AlertRule) error {
+	if rule.Name == "" {
+		return types.ErrAlertNameMissing
+	}
+	if !model.IsValidMetricName(model.LabelValue(rule.Name)) {
+		return fmt.Errorf("%w: %s", types.ErrAlertNameInvalid, rule.Name)
+	}
+	if rule.MetricID == "" {
+		return types.ErrAlertMetricMissing
+	}
+	// The metric is pasted in the expression of the rule, so it must be a
+	// plain metric name.
+	if !model.IsValidMetricName(model.LabelValue(rule.MetricID)) {
+		return fmt.Errorf("%w: %s", types.ErrAlertMetricInvalid, rule.MetricID)
+	}
+	if !rule.Comparison.IsValid() {
+		return types.ErrAlertComparisonInvalid
+	}
+	if rule.Duration != "" {
+		_, err := model.ParseDuration(rule.Duration)
+		if err != nil {
+			return fmt.Errorf("%w: %w", types.ErrAlertDurationInvalid, err)
+		}
+	}
+	return nil
+}
+
+// collectorURL returns the url of the collector, using the first port
+// declared by its service.
+func collectorURL(collector *containerstypes.Container) (string, error) {
+	for _, env := range collector.Service.Env {
+		if env.Type != "port" {
+			continue
+		}
+		port, ok := collector.HostPort(env.Name)
+		if !ok {
+			break
+		}
+		return fmt.Sprintf("http://%s:%s", config.Current.Host, port), nil
+	}
+	return "", types.ErrCollectorPortNotFound
+}
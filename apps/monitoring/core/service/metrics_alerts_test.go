@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/apps/monitoring/core/port"
+	"github.com/vertex-center/vertex/apps/monitoring/core/types"
+	"github.com/vertex-center/vertex/config"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+)
+
+type MetricsAlertsTestSuite struct {
+	suite.Suite
+
+	service   *MetricsService
+	adapter   *fakeMetricsAdapter
+	alerts    *fakeAlertsAdapter
+	collector containerstypes.Container
+	rule      types.AlertRule
+}
+
+func TestMetricsAlertsTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsAlertsTestSuite))
+}
+
+func (suite *MetricsAlertsTestSuite) SetupTest() {
+	suite.adapter = &fakeMetricsAdapter{}
+	suite.alerts = &fakeAlertsAdapter{alerts: types.AlertRules{}}
+	suite.service = NewMetricsService(app.NewContext(vtypes.NewVertexContext()), MetricsServiceParams{
+		MetricsAdapter: suite.adapter,
+		AlertsAdapter:  suite.alerts,
+	}).(*MetricsService)
+
+	suite.collector = containerstypes.Container{
+		UUID:   uuid.New(),
+		Status: containerstypes.ContainerStatusRunning,
+		Env:    containerstypes.ContainerEnvVariables{"PORT": "9091"},
+		Service: containerstypes.Service{
+			Env: []containerstypes.ServiceEnv{{Type: "port", Name: "PORT", Default: "9090"}},
+		},
+	}
+	suite.rule = types.AlertRule{
+		Name:       "container_down",
+		MetricID:   "vertex_container_status",
+		Comparison: types.AlertComparisonEqual,
+		Duration:   "5m",
+	}
+}
+
+func (suite *MetricsAlertsTestSuite) TestValidateAlert() {
+	valid := []string{"", "5m", "1h30m", "1d", "2w", "1y"}
+	for _, duration := range valid {
+		suite.rule.Duration = duration
+		suite.NoError(validateAlert(suite.rule), duration)
+	}
+
+	// Go durations that Prometheus rejects.
+	invalid := []string{"1.5h", "-5m", "5 minutes", "1us"}
+	for _, duration := range invalid {
+		suite.rule.Duration = duration
+		suite.ErrorIs(validateAlert(suite.rule), types.ErrAlertDurationInvalid, duration)
+	}
+	suite.rule.Duration = ""
+
+	rule := suite.rule
+	rule.Name = ""
+	suite.ErrorIs(validateAlert(rule), types.ErrAlertNameMissing)
+
+	rule = suite.rule
+	rule.Name = "Container down"
+	suite.ErrorIs(validateAlert(rule), types.ErrAlertNameInvalid)
+
+	rule = suite.rule
+	rule.MetricID = ""
+	suite.ErrorIs(validateAlert(rule), types.ErrAlertMetricMissing)
+
+	// PromQL fragments would break the whole rules file.
+	for _, metric := range []string{"up or vector(1)", "rate(up[5m])", "up{job=\"a\"}", "1up"} {
+		rule = suite.rule
+		rule.MetricID = metric
+		suite.ErrorIs(validateAlert(rule), types.ErrAlertMetricInvalid, metric)
+	}
+
+	rule = suite.rule
+	rule.Comparison = "=>"
+	suite.ErrorIs(validateAlert(rule), types.ErrAlertComparisonInvalid)
+}
+
+func (suite *MetricsAlertsTestSuite) TestAddAlert() {
+	id, err := suite.service.AddAlert(&suite.collector, suite.rule)
+	suite.Require().NoError(err)
+
+	// The rules are written in the collector, which is then reloaded.
+	suite.Equal(suite.collector.UUID, suite.adapter.configured)
+	suite.Equal(types.AlertRules{id: suite.rule}, suite.adapter.rules)
+	suite.Equal([]string{"http://" + config.Current.Host + ":9091"}, suite.adapter.reloaded)
+}
+
+func (suite *MetricsAlertsTestSuite) TestAddAlertInvalid() {
+	suite.rule.Name = ""
+	_, err := suite.service.AddAlert(&suite.collector, suite.rule)
+	suite.ErrorIs(err, types.ErrAlertNameMissing)
+	suite.Empty(suite.alerts.alerts)
+	suite.Nil(suite.adapter.rules)
+}
+
+func (suite *MetricsAlertsTestSuite) TestAddAlertCollectorStopped() {
+	suite.collector.Status = containerstypes.ContainerStatusOff
+
+	_, err := suite.service.AddAlert(&suite.collector, suite.rule)
+	suite.Require().NoError(err)
+	suite.Len(suite.adapter.rules, 1)
+	suite.Empty(suite.adapter.reloaded)
+}
+
+func (suite *MetricsAlertsTestSuite) TestAddAlertReloadFailed() {
+	suite.adapter.reloadErr = errors.New("lifecycle API is not enabled")
+
+	_, err := suite.service.AddAlert(&suite.collector, suite.rule)
+	suite.ErrorIs(err, types.ErrFailedToReloadCollector)
+	suite.Len(suite.alerts.alerts, 1)
+}
+
+func (suite *MetricsAlertsTestSuite) TestUpdateAlert() {
+	err := suite.service.UpdateAlert(&suite.collector, uuid.New(), suite.rule)
+	suite.ErrorIs(err, types.ErrAlertNotFound)
+
+	id, err := suite.service.AddAlert(&suite.collector, suite.rule)
+	suite.Require().NoError(err)
+
+	suite.rule.Threshold = 1
+	err = suite.service.UpdateAlert(&suite.collector, id, suite.rule)
+	suite.Require().NoError(err)
+	suite.Equal(types.AlertRules{id: suite.rule}, suite.adapter.rules)
+
+	err = suite.service.RemoveAlert(&suite.collector, id)
+	suite.Require().NoError(err)
+	suite.Empty(suite.adapter.rules)
+}
+
+type fakeMetricsAdapter struct {
+	port.MetricsAdapter
+
+	configured uuid.UUID
+	rules      types.AlertRules
+	reloaded   []string
+	reloadErr  error
+}
+
+func (a *fakeMetricsAdapter) ConfigureAlerts(uuid uuid.UUID, alerts types.AlertRules) error {
+	a.configured = uuid
+	a.rules = types.AlertRules{}
+	for id, rule := range alerts {
+		a.rules[id] = rule
+	}
+	return nil
+}
+
+func (a *fakeMetricsAdapter) Reload(url string) error {
+	a.reloaded = append(a.reloaded, url)
+	return a.reloadErr
+}
+
+type fakeAlertsAdapter struct {
+	alerts types.AlertRules
+}
+
+func (a *fakeAlertsAdapter) GetAlerts() types.AlertRules {
+	return a.alerts
+}
+
+func (a *fakeAlertsAdapter) GetAlert(id uuid.UUID) (types.AlertRule, error) {
+	rule, ok := a.alerts[id]
+	if !ok {
+		return types.AlertRule{}, types.ErrAlertNotFound
+	}
+	return rule, nil
+}
+
+func (a *fakeAlertsAdapter) SetAlert(id uuid.UUID, rule types.AlertRule) error {
+	a.alerts[id] = rule
+	return nil
+}
+
+func (a *fakeAlertsAdapter) RemoveAlert(id uuid.UUID) error {
+	if _, ok := a.alerts[id]; !ok {
+		return types.ErrAlertNotFound
+	}
+	delete(a.alerts, id)
+	return nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/apps/monitoring/core/types"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+)
+
+type MetricsServiceTestSuite struct {
+	suite.Suite
+
+	service  *MetricsService
+	settings *fakeCollectorSettingsAdapter
+}
+
+func TestMetricsServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsServiceTestSuite))
+}
+
+func (suite *MetricsServiceTestSuite) SetupTest() {
+	suite.settings = &fakeCollectorSettingsAdapter{}
+	suite.service = NewMetricsService(app.NewContext(vtypes.NewVertexContext()), MetricsServiceParams{
+		MetricsAdapter:           &fakeMetricsAdapter{},
+		CollectorSettingsAdapter: suite.settings,
+	}).(*MetricsService)
+}
+
+func (suite *MetricsServiceTestSuite) TestSetCollectorSettings() {
+	settings := types.CollectorSettings{RetentionTime: "1y2w", RetentionSize: "10GB"}
+	err := suite.service.SetCollectorSettings(settings)
+	suite.Require().NoError(err)
+	suite.Equal(settings, suite.service.GetCollectorSettings())
+
+	err = suite.service.SetCollectorSettings(types.CollectorSettings{})
+	suite.Require().NoError(err)
+	suite.Equal(types.CollectorSettings{}, suite.service.GetCollectorSettings())
+}
+
+func (suite *MetricsServiceTestSuite) TestSetCollectorSettingsInvalid() {
+	suite.settings.settings = types.CollectorSettings{RetentionTime: "15d"}
+
+	for _, retention := range []string{"15", "15 days", "1.5d"} {
+		err := suite.service.SetCollectorSettings(types.CollectorSettings{RetentionTime: retention})
+		suite.ErrorIs(err, types.ErrRetentionTimeInvalid, retention)
+	}
+	for _, size := range []string{"512", "512M", "-1GB"} {
+		err := suite.service.SetCollectorSettings(types.CollectorSettings{RetentionSize: size})
+		suite.ErrorIs(err, types.ErrRetentionSizeInvalid, size)
+	}
+
+	// The invalid settings are not saved.
+	suite.Equal(types.CollectorSettings{RetentionTime: "15d"}, suite.service.GetCollectorSettings())
+}
+
+type fakeCollectorSettingsAdapter struct {
+	settings types.CollectorSettings
+}
+
+func (a *fakeCollectorSettingsAdapter) GetSettings() types.CollectorSettings {
+	return a.settings
+}
+
+func (a *fakeCollectorSettingsAdapter) SetSettings(settings types.CollectorSettings) error {
+	a.settings = settings
+	return nil
+}
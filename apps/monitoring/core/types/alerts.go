@@ -0,0 +1,72 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAlertNotFound           = errors.New("alert not found")
+	ErrAlertNameMissing        = errors.New("the alert name is missing")
+	ErrAlertNameInvalid        = errors.New("the alert name is invalid")
+	ErrAlertMetricMissing      = errors.New("the alert metric is missing")
+	ErrAlertMetricInvalid      = errors.New("the alert metric is invalid")
+	ErrAlertComparisonInvalid  = errors.New("the alert comparison is invalid")
+	ErrAlertDurationInvalid    = errors.New("the alert duration is invalid")
+	ErrCollectorPortNotFound   = errors.New("the metrics collector port could not be found")
+	ErrFailedToReloadCollector = errors.New("failed to reload the metrics collector")
+)
+
+type AlertComparison string
+
+const (
+	AlertComparisonGreater        AlertComparison = ">"
+	AlertComparisonGreaterOrEqual AlertComparison = ">="
+	AlertComparisonLess           AlertComparison = "<"
+	AlertComparisonLessOrEqual    AlertComparison = "<="
+	AlertComparisonEqual          AlertComparison = "=="
+	AlertComparisonNotEqual       AlertComparison = "!="
+)
+
+func (c AlertComparison) IsValid() bool {
+	switch c {
+	case AlertComparisonGreater,
+		AlertComparisonGreaterOrEqual,
+		AlertComparisonLess,
+		AlertComparisonLessOrEqual,
+		AlertComparisonEqual,
+		AlertComparisonNotEqual:
+		return true
+	}
+	return false
+}
+
+type AlertRules map[uuid.UUID]AlertRule
+
+type AlertRule struct {
+	// Name is the name of the alert, as displayed by Prometheus. Like a
+	// metric name, it is made of letters, digits, underscores and colons.
+	Name string `json:"name" yaml:"name"`
+
+	// MetricID is the name of the metric to watch, for example
+	// vertex_container_status. It must be a valid metric name.
+	MetricID string `json:"metric_id" yaml:"metric_id"`
+
+	// Comparison is the operator used to compare the metric with the Threshold.
+	Comparison AlertComparison `json:"comparison" yaml:"comparison"`
+
+	// Threshold is the value the metric is compared to.
+	Threshold float64 `json:"threshold" yaml:"threshold"`
+
+	// Duration is how long the condition must be true before the alert
+	// fires, as a Prometheus duration like 5m or 1d. An empty duration fires
+	// immediately.
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+}
+
+// Expr returns the PromQL expression of the rule.
+func (r AlertRule) Expr() string {
+	return fmt.Sprintf("%s %s %v", r.MetricID, r.Comparison, r.Threshold)
+}
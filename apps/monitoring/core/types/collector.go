@@ -0,0 +1,36 @@
+package types
+
+import (
+	"errors"
+	"regexp"
+)
+
+var (
+	ErrRetentionTimeInvalid = errors.New("the retention time is invalid")
+	ErrRetentionSizeInvalid = errors.New("the retention size is invalid")
+)
+
+var (
+	retentionTimeRegex = regexp.MustCompile(`^([0-9]+(y|w|d|h|m|s|ms))+$`)
+	retentionSizeRegex = regexp.MustCompile(`^[0-9]+(B|KB|MB|GB|TB|PB|EB)$`)
+)
+
+type CollectorSettings struct {
+	// RetentionTime is how long the collector keeps the metrics, for example 15d.
+	// An empty value uses the collector default.
+	RetentionTime string `json:"retention_time,omitempty"`
+
+	// RetentionSize is the maximum size of the stored metrics, for example 512MB.
+	// An empty value means no limit.
+	RetentionSize string `json:"retention_size,omitempty"`
+}
+
+func (s CollectorSettings) Validate() error {
+	if s.RetentionTime != "" && !retentionTimeRegex.MatchString(s.RetentionTime) {
+		return ErrRetentionTimeInvalid
+	}
+	if s.RetentionSize != "" && !retentionSizeRegex.MatchString(s.RetentionSize) {
+		return ErrRetentionSizeInvalid
+	}
+	return nil
+}
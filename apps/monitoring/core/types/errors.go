@@ -6,4 +6,16 @@ const (
 	ErrCodeCollectorNotFound                 router.ErrCode = "collector_not_found"
 	ErrCodeVisualizerNotFound                router.ErrCode = "visualizer_not_found"
 	ErrCodeFailedToConfigureMetricsContainer router.ErrCode = "failed_to_configure_metrics_container"
+	ErrCodeCollectorSettingsInvalid          router.ErrCode = "collector_settings_invalid"
+	ErrCodeFailedToSetCollectorSettings      router.ErrCode = "failed_to_set_collector_settings"
+
+	ErrCodeAlertUuidMissing      router.ErrCode = "alert_uuid_missing"
+	ErrCodeAlertUuidInvalid      router.ErrCode = "alert_uuid_invalid"
+	ErrCodeAlertNotFound         router.ErrCode = "alert_not_found"
+	ErrCodeAlertInvalid          router.ErrCode = "alert_invalid"
+	ErrCodeAlertMetricInvalid    router.ErrCode = "alert_metric_invalid"
+	ErrCodeFailedToAddAlert      router.ErrCode = "failed_to_add_alert"
+	ErrCodeFailedToUpdateAlert   router.ErrCode = "failed_to_update_alert"
+	ErrCodeFailedToRemoveAlert   router.ErrCode = "failed_to_remove_alert"
+	ErrCodeFailedToReloadMetrics router.ErrCode = "failed_to_reload_metrics"
 )
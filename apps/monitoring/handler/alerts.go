@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	containersapi "github.com/vertex-center/vertex/apps/containers/api"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/apps/monitoring/core/port"
+	"github.com/vertex-center/vertex/apps/monitoring/core/types"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type AlertsHandler struct {
+	metricsService port.MetricsService
+}
+
+func NewAlertsHandler(metricsService port.MetricsService) port.AlertsHandler {
+	return &AlertsHandler{
+		metricsService: metricsService,
+	}
+}
+
+func getAlertID(c *router.Context) (uuid.UUID, error) {
+	idString := c.Param("id")
+	if idString == "" {
+		c.BadRequest(router.Error{
+			Code:           types.ErrCodeAlertUuidMissing,
+			PublicMessage:  "The request is missing the alert UUID.",
+			PrivateMessage: "Field 'id' is required.",
+		})
+		return uuid.UUID{}, errors.New("alert uuid missing")
+	}
+
+	id, err := uuid.Parse(idString)
+	if err != nil {
+		c.BadRequest(router.Error{
+			Code:           types.ErrCodeAlertUuidInvalid,
+			PublicMessage:  "The alert UUID is invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return uuid.UUID{}, err
+	}
+
+	return id, nil
+}
+
+// getCollectorContainer returns the Prometheus collector container, or nil
+// if the collector is not installed.
+func getCollectorContainer(c *router.Context) (*containerstypes.Container, error) {
+	insts, apiError := containersapi.GetContainers(c)
+	if apiError != nil {
+		c.AbortWithCode(apiError.HttpCode, apiError.RouterError())
+		return nil, apiError.RouterError()
+	}
+
+	for _, inst := range insts {
+		if inst.HasTag("Vertex Monitoring - Prometheus Collector") {
+			return inst, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *AlertsHandler) Get(c *router.Context) {
+	c.JSON(r.metricsService.GetAlerts())
+}
+
+func (r *AlertsHandler) Add(c *router.Context) {
+	var rule types.AlertRule
+	err := c.ParseBody(&rule)
+	if err != nil {
+		return
+	}
+
+	collector, err := getCollectorContainer(c)
+	if err != nil {
+		return
+	}
+
+	_, err = r.metricsService.AddAlert(collector, rule)
+	if err != nil {
+		r.abortAlertError(c, err, types.ErrCodeFailedToAddAlert, fmt.Sprintf("Failed to add alert '%s'.", rule.Name))
+		return
+	}
+
+	c.OK()
+}
+
+func (r *AlertsHandler) Update(c *router.Context) {
+	id, err := getAlertID(c)
+	if err != nil {
+		return
+	}
+
+	var rule types.AlertRule
+	err = c.ParseBody(&rule)
+	if err != nil {
+		return
+	}
+
+	collector, err := getCollectorContainer(c)
+	if err != nil {
+		return
+	}
+
+	err = r.metricsService.UpdateAlert(collector, id, rule)
+	if err != nil {
+		r.abortAlertError(c, err, types.ErrCodeFailedToUpdateAlert, fmt.Sprintf("Failed to update alert '%s'.", id))
+		return
+	}
+
+	c.OK()
+}
+
+func (r *AlertsHandler) Remove(c *router.Context) {
+	id, err := getAlertID(c)
+	if err != nil {
+		return
+	}
+
+	collector, err := getCollectorContainer(c)
+	if err != nil {
+		return
+	}
+
+	err = r.metricsService.RemoveAlert(collector, id)
+	if err != nil {
+		r.abortAlertError(c, err, types.ErrCodeFailedToRemoveAlert, fmt.Sprintf("Failed to remove alert '%s'.", id))
+		return
+	}
+
+	c.OK()
+}
+
+func (r *AlertsHandler) abortAlertError(c *router.Context, err error, code router.ErrCode, message string) {
+	if errors.Is(err, types.ErrAlertNotFound) {
+		c.NotFound(router.Error{
+			Code:           types.ErrCodeAlertNotFound,
+			PublicMessage:  "Alert not found.",
+			PrivateMessage: err.Error(),
+		})
+	} else if errors.Is(err, types.ErrAlertMetricInvalid) {
+		c.BadRequest(router.Error{
+			Code:           types.ErrCodeAlertMetricInvalid,
+			PublicMessage:  "The metric of the alert must be a metric name.",
+			PrivateMessage: err.Error(),
+		})
+	} else if errors.Is(err, types.ErrAlertNameMissing) ||
+		errors.Is(err, types.ErrAlertNameInvalid) ||
+		errors.Is(err, types.ErrAlertMetricMissing) ||
+		errors.Is(err, types.ErrAlertComparisonInvalid) ||
+		errors.Is(err, types.ErrAlertDurationInvalid) {
+		c.BadRequest(router.Error{
+			Code:           types.ErrCodeAlertInvalid,
+			PublicMessage:  "The alert is invalid.",
+			PrivateMessage: err.Error(),
+		})
+	} else if errors.Is(err, types.ErrFailedToReloadCollector) {
+		c.Abort(router.Error{
+			Code:           types.ErrCodeFailedToReloadMetrics,
+			PublicMessage:  "The alerts were saved, but Prometheus could not be reloaded.",
+			PrivateMessage: err.Error(),
+		})
+	} else {
+		c.Abort(router.Error{
+			Code:           code,
+			PublicMessage:  message,
+			PrivateMessage: err.Error(),
+		})
+	}
+}
@@ -79,8 +79,10 @@ func (r *MetricsHandler) InstallCollector(c *router.Context) {
 		return
 	}
 
+	command := r.metricsService.GetCollectorCommand()
 	apiError = containersapi.PatchContainer(c, inst.UUID, containerstypes.ContainerSettings{
-		Tags: []string{"Vertex Monitoring", "Vertex Monitoring - Prometheus Collector"},
+		Tags:    []string{"Vertex Monitoring", "Vertex Monitoring - Prometheus Collector"},
+		Command: &command,
 	})
 	if apiError != nil {
 		c.AbortWithCode(apiError.HttpCode, apiError.RouterError())
@@ -128,3 +130,62 @@ func (r *MetricsHandler) InstallVisualizer(c *router.Context) {
 
 	c.OK()
 }
+
+func (r *MetricsHandler) GetCollectorSettings(c *router.Context) {
+	_, err := getCollector(c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(r.metricsService.GetCollectorSettings())
+}
+
+// PatchCollectorSettings saves the collector settings. If the collector is
+// installed, its container is recreated to apply them.
+func (r *MetricsHandler) PatchCollectorSettings(c *router.Context) {
+	_, err := getCollector(c)
+	if err != nil {
+		return
+	}
+
+	var settings types.CollectorSettings
+	err = c.ParseBody(&settings)
+	if err != nil {
+		return
+	}
+
+	err = r.metricsService.SetCollectorSettings(settings)
+	if err != nil && (errors.Is(err, types.ErrRetentionTimeInvalid) || errors.Is(err, types.ErrRetentionSizeInvalid)) {
+		c.BadRequest(router.Error{
+			Code:           types.ErrCodeCollectorSettingsInvalid,
+			PublicMessage:  "The collector settings are invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           types.ErrCodeFailedToSetCollectorSettings,
+			PublicMessage:  "Failed to save the collector settings.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	collector, err := getCollectorContainer(c)
+	if err != nil {
+		return
+	}
+
+	if collector != nil {
+		command := r.metricsService.GetCollectorCommand()
+		apiError := containersapi.PatchContainer(c, collector.UUID, containerstypes.ContainerSettings{
+			Command: &command,
+		})
+		if apiError != nil {
+			c.AbortWithCode(apiError.HttpCode, apiError.RouterError())
+			return
+		}
+	}
+
+	c.OK()
+}
@@ -69,7 +69,22 @@ func (a *ProxyFSAdapter) GetRedirects() types.ProxyRedirects {
 	a.redirectsMutex.RLock()
 	defer a.redirectsMutex.RUnlock()
 
-	return a.redirects
+	redirects := types.ProxyRedirects{}
+	for id, redirect := range a.redirects {
+		redirects[id] = redirect
+	}
+	return redirects
+}
+
+func (a *ProxyFSAdapter) GetRedirect(id uuid.UUID) (types.ProxyRedirect, error) {
+	a.redirectsMutex.RLock()
+	defer a.redirectsMutex.RUnlock()
+
+	redirect, ok := a.redirects[id]
+	if !ok {
+		return types.ProxyRedirect{}, types.ErrRedirectNotFound
+	}
+	return redirect, nil
 }
 
 func (a *ProxyFSAdapter) GetRedirectByHost(host string) *types.ProxyRedirect {
@@ -93,12 +108,35 @@ func (a *ProxyFSAdapter) AddRedirect(id uuid.UUID, redirect types.ProxyRedirect)
 	return a.write()
 }
 
+func (a *ProxyFSAdapter) UpdateRedirect(id uuid.UUID, redirect types.ProxyRedirect) error {
+	err := func() error {
+		a.redirectsMutex.Lock()
+		defer a.redirectsMutex.Unlock()
+		if _, ok := a.redirects[id]; !ok {
+			return types.ErrRedirectNotFound
+		}
+		a.redirects[id] = redirect
+		return nil
+	}()
+	if err != nil {
+		return err
+	}
+	return a.write()
+}
+
 func (a *ProxyFSAdapter) RemoveRedirect(id uuid.UUID) error {
-	func() {
+	err := func() error {
 		a.redirectsMutex.Lock()
 		defer a.redirectsMutex.Unlock()
+		if _, ok := a.redirects[id]; !ok {
+			return types.ErrRedirectNotFound
+		}
 		delete(a.redirects, id)
+		return nil
 	}()
+	if err != nil {
+		return err
+	}
 	return a.write()
 }
 
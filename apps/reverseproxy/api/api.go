@@ -2,13 +2,15 @@ package api
 
 import (
 	"context"
+
+	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/reverseproxy"
 	"github.com/vertex-center/vertex/apps/reverseproxy/core/types"
 	"github.com/vertex-center/vertex/core/types/api"
 )
 
-func GetRedirects(ctx context.Context) ([]types.ProxyRedirect, *api.Error) {
-	var redirects []types.ProxyRedirect
+func GetRedirects(ctx context.Context) (types.ProxyRedirects, *api.Error) {
+	var redirects types.ProxyRedirects
 	var apiError api.Error
 	err := api.AppRequest(reverseproxy.AppRoute).
 		Path("./redirects").
@@ -18,12 +20,38 @@ func GetRedirects(ctx context.Context) ([]types.ProxyRedirect, *api.Error) {
 	return redirects, api.HandleError(err, apiError)
 }
 
-func AddRedirect(ctx context.Context, redirect types.ProxyRedirect) *api.Error {
+func GetRedirect(ctx context.Context, id string) (*types.ProxyRedirect, *api.Error) {
+	var redirect types.ProxyRedirect
+	var apiError api.Error
+	err := api.AppRequest(reverseproxy.AppRoute).
+		Pathf("./redirect/%s", id).
+		ToJSON(&redirect).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return &redirect, api.HandleError(err, apiError)
+}
+
+func AddRedirect(ctx context.Context, redirect types.ProxyRedirect) (uuid.UUID, *api.Error) {
+	var res struct {
+		ID uuid.UUID `json:"id"`
+	}
 	var apiError api.Error
 	err := api.AppRequest(reverseproxy.AppRoute).
 		Path("./redirect").
 		BodyJSON(&redirect).
 		Post().
+		ToJSON(&res).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return res.ID, api.HandleError(err, apiError)
+}
+
+func UpdateRedirect(ctx context.Context, id string, redirect types.ProxyRedirect) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(reverseproxy.AppRoute).
+		Pathf("./redirect/%s", id).
+		BodyJSON(&redirect).
+		Put().
 		ErrorJSON(&apiError).
 		Fetch(ctx)
 	return api.HandleError(err, apiError)
@@ -11,6 +11,7 @@ import (
 )
 
 const (
+	AppID    = "vx-reverse-proxy"
 	AppRoute = "/vx-reverse-proxy"
 )
 
@@ -29,14 +30,18 @@ func NewApp() *App {
 	return &App{}
 }
 
+func (a *App) ID() string {
+	return AppID
+}
+
 func (a *App) Initialize(app *apptypes.App) error {
 	a.App = app
 
 	proxyFSAdapter = adapter.NewProxyFSAdapter(nil)
 
-	proxyService = service.NewProxyService(proxyFSAdapter)
+	proxyService = service.NewProxyService(app.Context(), proxyFSAdapter)
 
-	a.proxy = NewProxyRouter(proxyService)
+	a.proxy = NewProxyRouter(app.Context(), proxyService)
 
 	go func() {
 		err := a.proxy.Start()
@@ -46,7 +51,7 @@ func (a *App) Initialize(app *apptypes.App) error {
 	}()
 
 	app.Register(apptypes.Meta{
-		ID:          "vx-reverse-proxy",
+		ID:          AppID,
 		Name:        "Vertex Reverse Proxy",
 		Description: "Redirect traffic to your containers.",
 		Icon:        "router",
@@ -55,7 +60,9 @@ func (a *App) Initialize(app *apptypes.App) error {
 	app.RegisterRoutes(AppRoute, func(r *router.Group) {
 		proxyHandler := handler.NewProxyHandler(proxyService)
 		r.GET("/redirects", proxyHandler.GetRedirects)
+		r.GET("/redirect/:id", proxyHandler.GetRedirect)
 		r.POST("/redirect", proxyHandler.AddRedirect)
+		r.PUT("/redirect/:id", proxyHandler.UpdateRedirect)
 		r.DELETE("/redirect/:id", proxyHandler.RemoveRedirect)
 	})
 
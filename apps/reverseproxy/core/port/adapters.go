@@ -7,7 +7,13 @@ import (
 
 type ProxyAdapter interface {
 	GetRedirects() types.ProxyRedirects
+	// GetRedirect returns types.ErrRedirectNotFound if the redirect doesn't
+	// exist.
+	GetRedirect(id uuid.UUID) (types.ProxyRedirect, error)
 	GetRedirectByHost(host string) *types.ProxyRedirect
 	AddRedirect(id uuid.UUID, redirect types.ProxyRedirect) error
+	// UpdateRedirect and RemoveRedirect return types.ErrRedirectNotFound if
+	// the redirect doesn't exist.
+	UpdateRedirect(id uuid.UUID, redirect types.ProxyRedirect) error
 	RemoveRedirect(id uuid.UUID) error
 }
@@ -7,7 +7,9 @@ import (
 type (
 	ProxyHandler interface {
 		GetRedirects(c *router.Context)
+		GetRedirect(c *router.Context)
 		AddRedirect(c *router.Context)
+		UpdateRedirect(c *router.Context)
 		RemoveRedirect(c *router.Context)
 	}
 )
@@ -8,8 +8,17 @@ import (
 type (
 	ProxyService interface {
 		GetRedirects() types.ProxyRedirects
+		GetRedirect(id uuid.UUID) (types.ProxyRedirect, error)
 		GetRedirectByHost(host string) *types.ProxyRedirect
-		AddRedirect(redirect types.ProxyRedirect) error
+
+		// AddRedirect validates and adds a redirect, and returns its id. It
+		// returns types.ErrRedirectSourceConflict if the source is already
+		// redirected.
+		AddRedirect(redirect types.ProxyRedirect) (uuid.UUID, error)
+
+		// UpdateRedirect validates and replaces a redirect. The redirect
+		// keeps being automatic if it was.
+		UpdateRedirect(id uuid.UUID, redirect types.ProxyRedirect) error
 		RemoveRedirect(id uuid.UUID) error
 	}
 )
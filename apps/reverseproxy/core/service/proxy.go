@@ -1,35 +1,102 @@
 package service
 
 import (
+	"fmt"
+	"sync"
+
 	"github.com/vertex-center/vertex/apps/reverseproxy/core/port"
 	"github.com/vertex-center/vertex/apps/reverseproxy/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
 
 	"github.com/google/uuid"
 )
 
 type ProxyService struct {
+	uuid         uuid.UUID
 	proxyAdapter port.ProxyAdapter
+
+	// mutex protects the checks of the existing redirects from the
+	// concurrent changes, like the automatic registration of the
+	// containers.
+	mutex sync.Mutex
 }
 
-func NewProxyService(proxyAdapter port.ProxyAdapter) port.ProxyService {
-	return &ProxyService{
+func NewProxyService(ctx *app.Context, proxyAdapter port.ProxyAdapter) port.ProxyService {
+	s := &ProxyService{
+		uuid:         uuid.New(),
 		proxyAdapter: proxyAdapter,
 	}
+	ctx.AddListener(s)
+	return s
 }
 
 func (s *ProxyService) GetRedirects() types.ProxyRedirects {
 	return s.proxyAdapter.GetRedirects()
 }
 
+func (s *ProxyService) GetRedirect(id uuid.UUID) (types.ProxyRedirect, error) {
+	return s.proxyAdapter.GetRedirect(id)
+}
+
 func (s *ProxyService) GetRedirectByHost(host string) *types.ProxyRedirect {
 	return s.proxyAdapter.GetRedirectByHost(host)
 }
 
-func (s *ProxyService) AddRedirect(redirect types.ProxyRedirect) error {
+func (s *ProxyService) AddRedirect(redirect types.ProxyRedirect) (uuid.UUID, error) {
+	err := redirect.Validate()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err = s.checkSource(uuid.Nil, redirect.Source)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
 	id := uuid.New()
-	return s.proxyAdapter.AddRedirect(id, redirect)
+	return id, s.proxyAdapter.AddRedirect(id, redirect)
+}
+
+func (s *ProxyService) UpdateRedirect(id uuid.UUID, redirect types.ProxyRedirect) error {
+	err := redirect.Validate()
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	current, err := s.proxyAdapter.GetRedirect(id)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkSource(id, redirect.Source)
+	if err != nil {
+		return err
+	}
+
+	redirect.Auto = current.Auto
+	return s.proxyAdapter.UpdateRedirect(id, redirect)
 }
 
 func (s *ProxyService) RemoveRedirect(id uuid.UUID) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	return s.proxyAdapter.RemoveRedirect(id)
 }
+
+// checkSource returns types.ErrRedirectSourceConflict if another redirect
+// than id already has the source.
+func (s *ProxyService) checkSource(id uuid.UUID, source string) error {
+	for otherID, other := range s.proxyAdapter.GetRedirects() {
+		if otherID != id && other.Source == source {
+			return fmt.Errorf("%w: %s", types.ErrRedirectSourceConflict, source)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/apps/reverseproxy/core/types"
+	"github.com/vertex-center/vertex/config"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+func (s *ProxyService) GetUUID() uuid.UUID {
+	return s.uuid
+}
+
+func (s *ProxyService) OnEvent(e interface{}) {
+	switch e := e.(type) {
+	case containerstypes.EventContainerStatusChange:
+		if e.Status == containerstypes.ContainerStatusRunning {
+			s.onContainerStart(&e.Container)
+		}
+	case containerstypes.EventContainerDeleted:
+		s.removeAutoRedirect(e.ContainerUUID)
+	}
+}
+
+// onContainerStart registers the redirect of the container if it opted in,
+// or removes its previous redirect if it opted out.
+func (s *ProxyService) onContainerStart(inst *containerstypes.Container) {
+	if !inst.ProxyAutoRegister {
+		s.removeAutoRedirect(inst.UUID)
+		return
+	}
+
+	baseDomain := config.Current.ProxyBaseDomain
+	if baseDomain == "" {
+		log.Warn("the container can't be registered in the reverse proxy without a base domain",
+			vlog.String("uuid", inst.UUID.String()),
+		)
+		return
+	}
+
+	port, ok := inst.WebHostPort()
+	if !ok {
+		log.Warn("the container has no web port to register in the reverse proxy",
+			vlog.String("uuid", inst.UUID.String()),
+		)
+		return
+	}
+
+	id := inst.UUID
+	redirect := types.ProxyRedirect{
+		Source:        inst.ProxyHostname(baseDomain),
+		Target:        fmt.Sprintf("http://%s:%s", config.Current.Host, port),
+		ContainerUUID: &id,
+		Auto:          true,
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for redirectID, r := range s.proxyAdapter.GetRedirects() {
+		isAuto := r.Auto && r.ContainerUUID != nil && *r.ContainerUUID == inst.UUID
+		if isAuto && r.Source == redirect.Source && r.Target == redirect.Target {
+			return
+		}
+		if isAuto {
+			// The display name or the port changed since the last start.
+			err := s.proxyAdapter.RemoveRedirect(redirectID)
+			if err != nil {
+				log.Error(err, vlog.String("uuid", inst.UUID.String()))
+				return
+			}
+		} else if r.Source == redirect.Source {
+			log.Warn("the hostname of the container is already registered in the reverse proxy",
+				vlog.String("uuid", inst.UUID.String()),
+				vlog.String("source", redirect.Source),
+			)
+			return
+		}
+	}
+
+	err := s.proxyAdapter.AddRedirect(uuid.New(), redirect)
+	if err != nil {
+		log.Error(err, vlog.String("uuid", inst.UUID.String()))
+		return
+	}
+	log.Info("container registered in the reverse proxy",
+		vlog.String("uuid", inst.UUID.String()),
+		vlog.String("source", redirect.Source),
+	)
+}
+
+func (s *ProxyService) removeAutoRedirect(containerUUID uuid.UUID) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for id, r := range s.proxyAdapter.GetRedirects() {
+		if !r.Auto || r.ContainerUUID == nil || *r.ContainerUUID != containerUUID {
+			continue
+		}
+		err := s.proxyAdapter.RemoveRedirect(id)
+		if err != nil {
+			log.Error(err, vlog.String("uuid", containerUUID.String()))
+		}
+	}
+}
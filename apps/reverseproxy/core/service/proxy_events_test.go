@@ -0,0 +1,147 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/apps/reverseproxy/core/port"
+	"github.com/vertex-center/vertex/apps/reverseproxy/core/types"
+	"github.com/vertex-center/vertex/config"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+)
+
+type ProxyEventsTestSuite struct {
+	suite.Suite
+
+	service *ProxyService
+	adapter *fakeProxyAdapter
+	inst    containerstypes.Container
+	config  config.Config
+}
+
+func TestProxyEventsTestSuite(t *testing.T) {
+	suite.Run(t, new(ProxyEventsTestSuite))
+}
+
+func (suite *ProxyEventsTestSuite) SetupTest() {
+	suite.config = config.Current
+	config.Current.Host = "192.168.1.10"
+	config.Current.ProxyBaseDomain = "example.com"
+
+	suite.adapter = &fakeProxyAdapter{redirects: types.ProxyRedirects{}}
+	suite.service = NewProxyService(app.NewContext(vtypes.NewVertexContext()), suite.adapter).(*ProxyService)
+	suite.inst = containerstypes.Container{
+		UUID: uuid.New(),
+		ContainerSettings: containerstypes.ContainerSettings{
+			DisplayName:       "Blog",
+			ProxyAutoRegister: true,
+		},
+		Env: containerstypes.ContainerEnvVariables{"PORT": "8080"},
+		Service: containerstypes.Service{
+			Env:  []containerstypes.ServiceEnv{{Type: "port", Name: "PORT", Default: "80"}},
+			URLs: []containerstypes.URL{{Port: "80", Kind: "client"}},
+		},
+	}
+}
+
+func (suite *ProxyEventsTestSuite) TearDownTest() {
+	config.Current = suite.config
+}
+
+func (suite *ProxyEventsTestSuite) start() {
+	suite.service.OnEvent(containerstypes.EventContainerStatusChange{
+		ContainerUUID: suite.inst.UUID,
+		Container:     suite.inst,
+		Status:        containerstypes.ContainerStatusRunning,
+	})
+}
+
+func (suite *ProxyEventsTestSuite) TestAutoRegister() {
+	suite.start()
+	suite.start()
+
+	redirect := suite.service.GetRedirectByHost("blog.example.com")
+	suite.Require().NotNil(redirect)
+	suite.Equal("http://192.168.1.10:8080", redirect.Target)
+	suite.True(redirect.Auto)
+	suite.Len(suite.adapter.redirects, 1)
+
+	// The redirect follows the changes of the port.
+	suite.inst.Env["PORT"] = "8081"
+	suite.start()
+	suite.Len(suite.adapter.redirects, 1)
+	suite.Equal("http://192.168.1.10:8081", suite.service.GetRedirectByHost("blog.example.com").Target)
+
+	suite.service.OnEvent(containerstypes.EventContainerDeleted{ContainerUUID: suite.inst.UUID})
+	suite.Empty(suite.adapter.redirects)
+}
+
+func (suite *ProxyEventsTestSuite) TestAutoRegisterDisabled() {
+	suite.start()
+	suite.inst.ProxyAutoRegister = false
+	suite.start()
+	suite.Empty(suite.adapter.redirects)
+}
+
+func (suite *ProxyEventsTestSuite) TestAutoRegisterKeepsManualRedirect() {
+	_, err := suite.service.AddRedirect(types.ProxyRedirect{
+		Source: "blog.example.com",
+		Target: "http://192.168.1.20:80",
+	})
+	suite.Require().NoError(err)
+
+	suite.start()
+	suite.Len(suite.adapter.redirects, 1)
+	suite.Equal("http://192.168.1.20:80", suite.service.GetRedirectByHost("blog.example.com").Target)
+}
+
+type fakeProxyAdapter struct {
+	port.ProxyAdapter
+	redirects types.ProxyRedirects
+}
+
+func (f *fakeProxyAdapter) GetRedirects() types.ProxyRedirects {
+	redirects := types.ProxyRedirects{}
+	for id, r := range f.redirects {
+		redirects[id] = r
+	}
+	return redirects
+}
+
+func (f *fakeProxyAdapter) GetRedirect(id uuid.UUID) (types.ProxyRedirect, error) {
+	r, ok := f.redirects[id]
+	if !ok {
+		return types.ProxyRedirect{}, types.ErrRedirectNotFound
+	}
+	return r, nil
+}
+
+func (f *fakeProxyAdapter) GetRedirectByHost(host string) *types.ProxyRedirect {
+	for _, r := range f.redirects {
+		if r.Source == host {
+			return &r
+		}
+	}
+	return nil
+}
+
+func (f *fakeProxyAdapter) AddRedirect(id uuid.UUID, redirect types.ProxyRedirect) error {
+	f.redirects[id] = redirect
+	return nil
+}
+
+func (f *fakeProxyAdapter) UpdateRedirect(id uuid.UUID, redirect types.ProxyRedirect) error {
+	if _, ok := f.redirects[id]; !ok {
+		return types.ErrRedirectNotFound
+	}
+	f.redirects[id] = redirect
+	return nil
+}
+
+func (f *fakeProxyAdapter) RemoveRedirect(id uuid.UUID) error {
+	delete(f.redirects, id)
+	return nil
+}
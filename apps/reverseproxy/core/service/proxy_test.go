@@ -0,0 +1,95 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/apps/reverseproxy/core/types"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+)
+
+type ProxyServiceTestSuite struct {
+	suite.Suite
+
+	service *ProxyService
+	adapter *fakeProxyAdapter
+}
+
+func TestProxyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ProxyServiceTestSuite))
+}
+
+func (suite *ProxyServiceTestSuite) SetupTest() {
+	suite.adapter = &fakeProxyAdapter{redirects: types.ProxyRedirects{}}
+	suite.service = NewProxyService(app.NewContext(vtypes.NewVertexContext()), suite.adapter).(*ProxyService)
+}
+
+func (suite *ProxyServiceTestSuite) TestAddRedirect() {
+	id, err := suite.service.AddRedirect(types.ProxyRedirect{
+		Source: "blog.example.com",
+		Target: "http://192.168.1.20:80",
+	})
+	suite.Require().NoError(err)
+
+	redirect, err := suite.service.GetRedirect(id)
+	suite.NoError(err)
+	suite.Equal("blog.example.com", redirect.Source)
+
+	_, err = suite.service.AddRedirect(types.ProxyRedirect{
+		Source: "blog.example.com",
+		Target: "http://192.168.1.30:80",
+	})
+	suite.ErrorIs(err, types.ErrRedirectSourceConflict)
+
+	_, err = suite.service.AddRedirect(types.ProxyRedirect{
+		Source: "wiki.example.com",
+		Target: "192.168.1.30",
+	})
+	suite.ErrorIs(err, types.ErrRedirectInvalid)
+	suite.Len(suite.adapter.redirects, 1)
+}
+
+func (suite *ProxyServiceTestSuite) TestUpdateRedirect() {
+	containerUUID := uuid.New()
+	id := uuid.New()
+	suite.adapter.redirects[id] = types.ProxyRedirect{
+		Source:        "blog.example.com",
+		Target:        "http://192.168.1.20:80",
+		ContainerUUID: &containerUUID,
+		Auto:          true,
+	}
+
+	// The redirect can keep its own source.
+	err := suite.service.UpdateRedirect(id, types.ProxyRedirect{
+		Source: "blog.example.com",
+		Target: "http://192.168.1.20:8080",
+	})
+	suite.Require().NoError(err)
+
+	redirect := suite.adapter.redirects[id]
+	suite.Equal("http://192.168.1.20:8080", redirect.Target)
+	suite.True(redirect.Auto)
+
+	err = suite.service.UpdateRedirect(uuid.New(), redirect)
+	suite.ErrorIs(err, types.ErrRedirectNotFound)
+}
+
+func (suite *ProxyServiceTestSuite) TestUpdateRedirectConflict() {
+	id := uuid.New()
+	suite.adapter.redirects[id] = types.ProxyRedirect{
+		Source: "blog.example.com",
+		Target: "http://192.168.1.20:80",
+	}
+	suite.adapter.redirects[uuid.New()] = types.ProxyRedirect{
+		Source: "wiki.example.com",
+		Target: "http://192.168.1.30:80",
+	}
+
+	err := suite.service.UpdateRedirect(id, types.ProxyRedirect{
+		Source: "wiki.example.com",
+		Target: "http://192.168.1.20:80",
+	})
+	suite.ErrorIs(err, types.ErrRedirectSourceConflict)
+}
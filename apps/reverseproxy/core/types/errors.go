@@ -5,6 +5,13 @@ import "github.com/vertex-center/vertex/pkg/router"
 const (
 	ErrCodeRedirectUuidMissing    router.ErrCode = "redirect_uuid_missing"
 	ErrCodeRedirectUuidInvalid    router.ErrCode = "redirect_uuid_invalid"
+	ErrCodeRedirectNotFound       router.ErrCode = "redirect_not_found"
+	ErrCodeRedirectInvalid        router.ErrCode = "redirect_invalid"
+	ErrCodeRedirectSourceConflict router.ErrCode = "redirect_source_conflict"
+	ErrCodeFailedToGetRedirect    router.ErrCode = "failed_to_get_redirect"
 	ErrCodeFailedToAddRedirect    router.ErrCode = "failed_to_add_redirect"
+	ErrCodeFailedToUpdateRedirect router.ErrCode = "failed_to_update_redirect"
 	ErrCodeFailedToRemoveRedirect router.ErrCode = "failed_to_remove_redirect"
+	ErrCodeHostNotRegistered      router.ErrCode = "host_not_registered"
+	ErrCodeUpstreamUnreachable    router.ErrCode = "upstream_unreachable"
 )
@@ -1,10 +1,51 @@
 package types
 
-import "github.com/google/uuid"
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRedirectNotFound       = errors.New("redirect not found")
+	ErrRedirectInvalid        = errors.New("the redirect is invalid")
+	ErrRedirectSourceConflict = errors.New("the source is already redirected")
+)
 
 type ProxyRedirects map[uuid.UUID]ProxyRedirect
 
 type ProxyRedirect struct {
 	Source string `json:"source"`
 	Target string `json:"target"`
+
+	// ContainerUUID is the container serving the target, if any. Its
+	// requests count as activity for its idle timeout.
+	ContainerUUID *uuid.UUID `json:"container_uuid,omitempty"`
+
+	// Auto is true if the redirect was registered automatically for the
+	// container, so it is also removed with it.
+	Auto bool `json:"auto,omitempty"`
+}
+
+// Validate checks that the source is a host, like blog.example.com or
+// blog.example.com:8080, and that the target is an http or https URL.
+func (r ProxyRedirect) Validate() error {
+	if r.Source == "" {
+		return fmt.Errorf("%w: the source is empty", ErrRedirectInvalid)
+	}
+	if strings.ContainsAny(r.Source, "/ ") {
+		return fmt.Errorf("%w: the source '%s' must be a host, without scheme nor path", ErrRedirectInvalid, r.Source)
+	}
+
+	target, err := url.Parse(r.Target)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRedirectInvalid, err)
+	}
+	if (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: the target '%s' must be an http or https URL", ErrRedirectInvalid, r.Target)
+	}
+	return nil
 }
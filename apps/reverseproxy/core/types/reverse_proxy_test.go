@@ -0,0 +1,36 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ReverseProxyTestSuite struct {
+	suite.Suite
+}
+
+func TestReverseProxyTestSuite(t *testing.T) {
+	suite.Run(t, new(ReverseProxyTestSuite))
+}
+
+func (suite *ReverseProxyTestSuite) TestValidate() {
+	valid := []ProxyRedirect{
+		{Source: "blog.example.com", Target: "http://192.168.1.10:8080"},
+		{Source: "blog.example.com:8080", Target: "https://blog.internal"},
+	}
+	for _, r := range valid {
+		suite.NoError(r.Validate(), r.Source)
+	}
+
+	invalid := []ProxyRedirect{
+		{Source: "", Target: "http://192.168.1.10:8080"},
+		{Source: "https://blog.example.com", Target: "http://192.168.1.10:8080"},
+		{Source: "blog.example.com/path", Target: "http://192.168.1.10:8080"},
+		{Source: "blog.example.com", Target: "192.168.1.10:8080"},
+		{Source: "blog.example.com", Target: "ftp://192.168.1.10"},
+	}
+	for _, r := range invalid {
+		suite.ErrorIs(r.Validate(), ErrRedirectInvalid, r.Source+" "+r.Target)
+	}
+}
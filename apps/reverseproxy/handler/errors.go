@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/vertex-center/vertex/apps/reverseproxy/core/types"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+func init() {
+	router.RegisterError(types.ErrRedirectNotFound, http.StatusNotFound, router.Error{
+		Code:          types.ErrCodeRedirectNotFound,
+		PublicMessage: "The redirect could not be found.",
+	})
+	router.RegisterError(types.ErrRedirectInvalid, http.StatusBadRequest, router.Error{
+		Code:          types.ErrCodeRedirectInvalid,
+		PublicMessage: "The redirect is invalid. The source must be a hostname and the target an http(s) URL.",
+	})
+	router.RegisterError(types.ErrRedirectSourceConflict, http.StatusConflict, router.Error{
+		Code:          types.ErrCodeRedirectSourceConflict,
+		PublicMessage: "Another redirect already uses this source.",
+	})
+}
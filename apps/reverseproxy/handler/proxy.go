@@ -20,14 +20,69 @@ func NewProxyHandler(proxyService port.ProxyService) port.ProxyHandler {
 	}
 }
 
+func (r *ProxyHandler) getParamID(c *router.Context) *uuid.UUID {
+	idString := c.Param("id")
+	if idString == "" {
+		c.BadRequest(router.Error{
+			Code:           types2.ErrCodeRedirectUuidMissing,
+			PublicMessage:  "The request is missing the redirect UUID.",
+			PrivateMessage: "Field 'id' is required.",
+		})
+		return nil
+	}
+
+	id, err := uuid.Parse(idString)
+	if err != nil {
+		c.BadRequest(router.Error{
+			Code:           types2.ErrCodeRedirectUuidInvalid,
+			PublicMessage:  "The redirect UUID is invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return nil
+	}
+
+	return &id
+}
+
 func (r *ProxyHandler) GetRedirects(c *router.Context) {
 	redirects := r.proxyService.GetRedirects()
 	c.JSON(redirects)
 }
 
+func (r *ProxyHandler) GetRedirect(c *router.Context) {
+	id := r.getParamID(c)
+	if id == nil {
+		return
+	}
+
+	redirect, err := r.proxyService.GetRedirect(*id)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types2.ErrCodeFailedToGetRedirect,
+			PublicMessage: fmt.Sprintf("Failed to retrieve redirect '%s'.", id),
+		})
+		return
+	}
+
+	c.JSON(redirect)
+}
+
 type AddRedirectBody struct {
-	Source string `json:"source"`
-	Target string `json:"target"`
+	Source        string     `json:"source"`
+	Target        string     `json:"target"`
+	ContainerUUID *uuid.UUID `json:"container_uuid,omitempty"`
+}
+
+func (b AddRedirectBody) redirect() types2.ProxyRedirect {
+	return types2.ProxyRedirect{
+		Source:        b.Source,
+		Target:        b.Target,
+		ContainerUUID: b.ContainerUUID,
+	}
+}
+
+type AddRedirectResponse struct {
+	ID uuid.UUID `json:"id"`
 }
 
 func (r *ProxyHandler) AddRedirect(c *router.Context) {
@@ -37,51 +92,57 @@ func (r *ProxyHandler) AddRedirect(c *router.Context) {
 		return
 	}
 
-	redirect := types2.ProxyRedirect{
-		Source: body.Source,
-		Target: body.Target,
-	}
+	redirect := body.redirect()
 
-	err = r.proxyService.AddRedirect(redirect)
+	id, err := r.proxyService.AddRedirect(redirect)
 	if err != nil {
-		c.Abort(router.Error{
-			Code:           types2.ErrCodeFailedToAddRedirect,
-			PublicMessage:  fmt.Sprintf("Failed to add redirect '%s' to '%s'.", redirect.Source, redirect.Target),
-			PrivateMessage: err.Error(),
+		c.Fail(err, router.Error{
+			Code:          types2.ErrCodeFailedToAddRedirect,
+			PublicMessage: fmt.Sprintf("Failed to add redirect '%s' to '%s'.", redirect.Source, redirect.Target),
 		})
 		return
 	}
 
-	c.OK()
+	c.JSON(AddRedirectResponse{ID: id})
 }
 
-func (r *ProxyHandler) RemoveRedirect(c *router.Context) {
-	idString := c.Param("id")
-	if idString == "" {
-		c.BadRequest(router.Error{
-			Code:           types2.ErrCodeRedirectUuidMissing,
-			PublicMessage:  "The request is missing the redirect UUID.",
-			PrivateMessage: "Field 'id' is required.",
-		})
+func (r *ProxyHandler) UpdateRedirect(c *router.Context) {
+	id := r.getParamID(c)
+	if id == nil {
 		return
 	}
 
-	id, err := uuid.Parse(idString)
+	var body AddRedirectBody
+	err := c.ParseBody(&body)
 	if err != nil {
-		c.BadRequest(router.Error{
-			Code:           types2.ErrCodeRedirectUuidInvalid,
-			PublicMessage:  "The redirect UUID is invalid.",
-			PrivateMessage: err.Error(),
+		return
+	}
+
+	redirect := body.redirect()
+
+	err = r.proxyService.UpdateRedirect(*id, redirect)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types2.ErrCodeFailedToUpdateRedirect,
+			PublicMessage: fmt.Sprintf("Failed to update redirect '%s'.", id),
 		})
 		return
 	}
 
-	err = r.proxyService.RemoveRedirect(id)
+	c.OK()
+}
+
+func (r *ProxyHandler) RemoveRedirect(c *router.Context) {
+	id := r.getParamID(c)
+	if id == nil {
+		return
+	}
+
+	err := r.proxyService.RemoveRedirect(*id)
 	if err != nil {
-		c.Abort(router.Error{
-			Code:           types2.ErrCodeFailedToRemoveRedirect,
-			PublicMessage:  fmt.Sprintf("Failed to remove redirect '%s'.", id),
-			PrivateMessage: err.Error(),
+		c.Fail(err, router.Error{
+			Code:          types2.ErrCodeFailedToRemoveRedirect,
+			PublicMessage: fmt.Sprintf("Failed to remove redirect '%s'.", id),
 		})
 		return
 	}
@@ -1,18 +1,22 @@
 package reverseproxy
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"html/template"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"time"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
 	"github.com/vertex-center/vertex/apps/reverseproxy/core/port"
+	"github.com/vertex-center/vertex/apps/reverseproxy/core/types"
 	"github.com/vertex-center/vertex/config"
+	apptypes "github.com/vertex-center/vertex/core/types/app"
 	"github.com/vertex-center/vertex/pkg/ginutils"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vertex/pkg/router"
@@ -22,20 +26,23 @@ import (
 type ProxyRouter struct {
 	*router.Router
 
+	ctx          *apptypes.Context
 	proxyService port.ProxyService
 }
 
-func NewProxyRouter(proxyService port.ProxyService) *ProxyRouter {
+func NewProxyRouter(ctx *apptypes.Context, proxyService port.ProxyService) *ProxyRouter {
 	gin.SetMode(gin.ReleaseMode)
 
 	r := &ProxyRouter{
 		Router:       router.New(),
+		ctx:          ctx,
 		proxyService: proxyService,
 	}
 
-	r.Use(cors.Default())
-	r.Use(ginutils.Logger("PROXY"))
-	r.Use(gin.Recovery())
+	r.Use(ginutils.CORS())
+	r.Use(ginutils.ErrorHandler())
+	r.Use(ginutils.Logger("PROXY", ginutils.LogFormat(config.Current.LogFormat)))
+	r.Use(ginutils.Recovery())
 
 	r.initAPIRoutes()
 
@@ -63,30 +70,106 @@ func (r *ProxyRouter) HandleProxy(c *router.Context) {
 
 	redirect := r.proxyService.GetRedirectByHost(host)
 	if redirect == nil {
-		log.Warn("this host is not registered in the reverse proxy",
-			vlog.String("host", host),
-		)
+		r.handleFallback(c, host)
 		return
 	}
 
+	if redirect.ContainerUUID != nil {
+		r.ctx.DispatchEvent(containerstypes.EventContainerActivity{
+			ContainerUUID: *redirect.ContainerUUID,
+		})
+	}
+
 	target, err := url.Parse(redirect.Target)
 	if err != nil {
 		log.Error(err)
+		c.AbortWithCode(http.StatusBadGateway, router.Error{
+			Code:          types.ErrCodeRedirectInvalid,
+			PublicMessage: fmt.Sprintf("The redirect of the host '%s' is invalid.", host),
+		})
 		return
 	}
 
+	r.proxyTo(c, target)
+}
+
+// handleFallback answers the requests to the hosts without a redirect. They
+// go to the fallback upstream, or get the fallback page, or a JSON error.
+func (r *ProxyRouter) handleFallback(c *router.Context, host string) {
+	log.Warn("this host is not registered in the reverse proxy",
+		vlog.String("host", host),
+	)
+
+	if config.Current.ProxyFallbackURL != "" {
+		target, err := url.Parse(config.Current.ProxyFallbackURL)
+		if err == nil {
+			r.proxyTo(c, target)
+			return
+		}
+		log.Error(err)
+	}
+
+	if config.Current.ProxyFallbackPage != "" {
+		page, err := renderFallbackPage(config.Current.ProxyFallbackPage, host)
+		if err == nil {
+			c.Data(http.StatusNotFound, "text/html; charset=utf-8", page)
+			return
+		}
+		log.Error(err)
+	}
+
+	c.NotFound(router.Error{
+		Code:          types.ErrCodeHostNotRegistered,
+		PublicMessage: fmt.Sprintf("The host '%s' is not registered in the reverse proxy.", host),
+	})
+}
+
+// renderFallbackPage renders the fallback page template for the host. The
+// page is read at each request, so it can be edited without a restart.
+func renderFallbackPage(path string, host string) ([]byte, error) {
+	tmpl, err := template.ParseFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var page bytes.Buffer
+	err = tmpl.Execute(&page, struct{ Host string }{Host: host})
+	if err != nil {
+		return nil, err
+	}
+	return page.Bytes(), nil
+}
+
+func (r *ProxyRouter) proxyTo(c *router.Context, target *url.URL) {
 	proxy := httputil.NewSingleHostReverseProxy(target)
 	proxy.ErrorHandler = func(w http.ResponseWriter, request *http.Request, err error) {
-		if err != nil && !errors.Is(err, context.Canceled) {
-			log.Error(err)
+		if errors.Is(err, context.Canceled) {
+			return
 		}
+		log.Error(err)
+		c.AbortWithCode(http.StatusBadGateway, router.Error{
+			Code:          types.ErrCodeUpstreamUnreachable,
+			PublicMessage: fmt.Sprintf("The upstream '%s' could not be reached.", target.Host),
+		})
 	}
 	proxy.Director = func(request *http.Request) {
-		request.Header = c.Request.Header
 		request.Host = target.Host
 		request.URL.Scheme = target.Scheme
 		request.URL.Host = target.Host
 		request.URL.Path = c.Param("path")
+		setForwardedHeaders(request, c.Request)
 	}
 	proxy.ServeHTTP(c.Writer, c.Request)
 }
+
+// setForwardedHeaders tells the upstream the original host and scheme of the
+// request. The client IP is appended to X-Forwarded-For by the
+// httputil.ReverseProxy itself, after the director.
+func setForwardedHeaders(out *http.Request, in *http.Request) {
+	out.Header.Set("X-Forwarded-Host", in.Host)
+	if in.TLS != nil {
+		out.Header.Set("X-Forwarded-Proto", "https")
+	} else {
+		out.Header.Set("X-Forwarded-Proto", "http")
+	}
+}
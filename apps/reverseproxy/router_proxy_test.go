@@ -0,0 +1,143 @@
+package reverseproxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/apps/reverseproxy/core/port"
+	"github.com/vertex-center/vertex/apps/reverseproxy/core/types"
+	"github.com/vertex-center/vertex/config"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	apptypes "github.com/vertex-center/vertex/core/types/app"
+)
+
+type ProxyRouterTestSuite struct {
+	suite.Suite
+
+	router *ProxyRouter
+	server *httptest.Server
+	config config.Config
+}
+
+func TestProxyRouterTestSuite(t *testing.T) {
+	suite.Run(t, new(ProxyRouterTestSuite))
+}
+
+func (suite *ProxyRouterTestSuite) SetupTest() {
+	suite.config = config.Current
+	ctx := apptypes.NewContext(vtypes.NewVertexContext())
+	suite.router = NewProxyRouter(ctx, &fakeProxyService{})
+	suite.server = httptest.NewServer(suite.router)
+}
+
+func (suite *ProxyRouterTestSuite) TearDownTest() {
+	suite.server.Close()
+	config.Current = suite.config
+}
+
+// request sends a request for the host unknown.example.com to the proxy,
+// and returns the status and the body of the response.
+func (suite *ProxyRouterTestSuite) request() (int, string) {
+	req, err := http.NewRequest(http.MethodGet, suite.server.URL+"/page", nil)
+	suite.Require().NoError(err)
+	req.Host = "unknown.example.com"
+
+	res, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	suite.Require().NoError(err)
+	return res.StatusCode, string(body)
+}
+
+func (suite *ProxyRouterTestSuite) TestFallbackNotFound() {
+	code, body := suite.request()
+
+	suite.Equal(http.StatusNotFound, code)
+	suite.Contains(body, string(types.ErrCodeHostNotRegistered))
+	suite.Contains(body, "unknown.example.com")
+}
+
+func (suite *ProxyRouterTestSuite) TestFallbackPage() {
+	p := path.Join(suite.T().TempDir(), "fallback.html")
+	err := os.WriteFile(p, []byte("<p>{{.Host}} is not configured</p>"), 0644)
+	suite.Require().NoError(err)
+	config.Current.ProxyFallbackPage = p
+
+	code, body := suite.request()
+
+	suite.Equal(http.StatusNotFound, code)
+	suite.Equal("<p>unknown.example.com is not configured</p>", body)
+}
+
+func (suite *ProxyRouterTestSuite) TestFallbackURL() {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("landing " + r.URL.Path))
+	}))
+	defer upstream.Close()
+	config.Current.ProxyFallbackURL = upstream.URL
+
+	code, body := suite.request()
+
+	suite.Equal(http.StatusOK, code)
+	suite.Equal("landing /page", body)
+}
+
+func (suite *ProxyRouterTestSuite) TestFallbackUnreachable() {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	upstream.Close()
+	config.Current.ProxyFallbackURL = upstream.URL
+
+	code, body := suite.request()
+
+	suite.Equal(http.StatusBadGateway, code)
+	suite.Contains(body, string(types.ErrCodeUpstreamUnreachable))
+}
+
+func (suite *ProxyRouterTestSuite) TestForwardedHeaders() {
+	var headers http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+	}))
+	defer upstream.Close()
+	config.Current.ProxyFallbackURL = upstream.URL
+
+	var in *http.Request
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		in = r
+		suite.router.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	req, err := http.NewRequest(http.MethodGet, proxy.URL+"/page", nil)
+	suite.Require().NoError(err)
+	req.Host = "unknown.example.com"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	res, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	defer res.Body.Close()
+
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal("198.51.100.1, 127.0.0.1", headers.Get("X-Forwarded-For"))
+	suite.Equal("unknown.example.com", headers.Get("X-Forwarded-Host"))
+	suite.Equal("http", headers.Get("X-Forwarded-Proto"))
+
+	// The headers of the incoming request are left untouched.
+	suite.Equal("198.51.100.1", in.Header.Get("X-Forwarded-For"))
+	suite.Empty(in.Header.Get("X-Forwarded-Host"))
+}
+
+type fakeProxyService struct {
+	port.ProxyService
+}
+
+func (f *fakeProxyService) GetRedirectByHost(host string) *types.ProxyRedirect {
+	return nil
+}
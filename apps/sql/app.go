@@ -9,6 +9,7 @@ import (
 )
 
 const (
+	AppID    = "vx-sql"
 	AppRoute = "/vx-sql"
 )
 
@@ -24,6 +25,10 @@ func NewApp() *App {
 	return &App{}
 }
 
+func (a *App) ID() string {
+	return AppID
+}
+
 func (a *App) Initialize(app *apptypes.App) error {
 	a.App = app
 
@@ -32,7 +37,7 @@ func (a *App) Initialize(app *apptypes.App) error {
 	sqlService = service.New(c)
 
 	app.Register(apptypes.Meta{
-		ID:          "vx-sql",
+		ID:          AppID,
 		Name:        "Vertex SQL",
 		Description: "Create and manage SQL databases.",
 		Icon:        "database",
@@ -0,0 +1,113 @@
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/vertex-center/vertex/apps/tunnels/core/port"
+	"github.com/vertex-center/vertex/apps/tunnels/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/storage"
+	"github.com/vertex-center/vlog"
+)
+
+var (
+	errCloudflaredSettingsNotFound       = errors.New("cloudflared.json doesn't exists or could not be found")
+	errCloudflaredSettingsFailedToRead   = errors.New("failed to read cloudflared.json")
+	errCloudflaredSettingsFailedToDecode = errors.New("failed to decode cloudflared.json")
+)
+
+type CloudflaredSettingsFSAdapter struct {
+	settings      types.CloudflaredSettings
+	settingsMutex sync.RWMutex
+
+	settingsPath string
+}
+
+type CloudflaredSettingsFSAdapterParams struct {
+	settingsPath string
+}
+
+func NewCloudflaredSettingsFSAdapter(params *CloudflaredSettingsFSAdapterParams) port.CloudflaredSettingsAdapter {
+	if params == nil {
+		params = &CloudflaredSettingsFSAdapterParams{}
+	}
+	if params.settingsPath == "" {
+		params.settingsPath = path.Join(storage.Path, "apps", "vx-tunnels")
+	}
+
+	err := os.MkdirAll(params.settingsPath, os.ModePerm)
+	if err != nil && !os.IsExist(err) {
+		log.Error(err,
+			vlog.String("message", "failed to create directory"),
+			vlog.String("path", params.settingsPath),
+		)
+		os.Exit(1)
+	}
+
+	adapter := &CloudflaredSettingsFSAdapter{
+		settingsPath: params.settingsPath,
+	}
+
+	err = adapter.read()
+	if errors.Is(err, errCloudflaredSettingsFailedToDecode) {
+		log.Error(err)
+	}
+
+	return adapter
+}
+
+func (a *CloudflaredSettingsFSAdapter) GetSettings() types.CloudflaredSettings {
+	a.settingsMutex.RLock()
+	defer a.settingsMutex.RUnlock()
+
+	return a.settings
+}
+
+func (a *CloudflaredSettingsFSAdapter) SetSettings(settings types.CloudflaredSettings) error {
+	a.settingsMutex.Lock()
+	a.settings = settings
+	a.settingsMutex.Unlock()
+
+	return a.write()
+}
+
+func (a *CloudflaredSettingsFSAdapter) read() error {
+	p := path.Join(a.settingsPath, "cloudflared.json")
+	file, err := os.ReadFile(p)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return errCloudflaredSettingsNotFound
+	} else if err != nil {
+		return fmt.Errorf("%w: %w", errCloudflaredSettingsFailedToRead, err)
+	}
+
+	a.settingsMutex.Lock()
+	defer a.settingsMutex.Unlock()
+
+	err = json.Unmarshal(file, &a.settings)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errCloudflaredSettingsFailedToDecode, err)
+	}
+
+	return nil
+}
+
+func (a *CloudflaredSettingsFSAdapter) write() error {
+	p := path.Join(a.settingsPath, "cloudflared.json")
+
+	a.settingsMutex.RLock()
+	defer a.settingsMutex.RUnlock()
+
+	bytes, err := json.MarshalIndent(a.settings, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	// The file holds the tunnel token, so it is only readable by Vertex.
+	return storage.WriteFileAtomic(p, bytes, 0600)
+}
@@ -0,0 +1,150 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	containersapi "github.com/vertex-center/vertex/apps/containers/api"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/apps/tunnels/core/port"
+	"github.com/vertex-center/vertex/apps/tunnels/core/types"
+	"github.com/vertex-center/vertex/config"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+const (
+	// cloudflaredServiceID is the service of the containers app running
+	// the cloudflared client.
+	cloudflaredServiceID = "cloudflared"
+
+	// cloudflaredCommand runs the tunnel of the TUNNEL_TOKEN variable, so
+	// that the token doesn't appear in the command of the container.
+	cloudflaredCommand = "tunnel --no-autoupdate run"
+)
+
+var errCloudflaredContainerMissing = errors.New("the tunnel has no cloudflared container")
+
+// TunnelCloudflaredAdapter opens the tunnels with Cloudflare Tunnel. Each
+// tunnel runs cloudflared in a container managed by the containers app. The
+// public hostname of the tunnel is routed to the container port from the
+// Cloudflare dashboard.
+type TunnelCloudflaredAdapter struct {
+	settingsAdapter port.CloudflaredSettingsAdapter
+}
+
+type TunnelCloudflaredAdapterParams struct {
+	SettingsAdapter port.CloudflaredSettingsAdapter
+}
+
+func NewTunnelCloudflaredAdapter(params TunnelCloudflaredAdapterParams) port.TunnelProviderAdapter {
+	return &TunnelCloudflaredAdapter{
+		settingsAdapter: params.SettingsAdapter,
+	}
+}
+
+func (a *TunnelCloudflaredAdapter) Open(ctx context.Context, id uuid.UUID, tunnel types.Tunnel) (types.Tunnel, error) {
+	token := a.settingsAdapter.GetSettings().Token
+	if token == "" {
+		return tunnel, types.ErrCloudflaredTokenMissing
+	}
+	if tunnel.Hostname == "" {
+		return tunnel, types.ErrTunnelHostnameMissing
+	}
+
+	// The container is configured with the install, so it is only started
+	// once, with the token.
+	displayName := fmt.Sprintf("Tunnel %s", tunnel.Hostname)
+	command := cloudflaredCommand
+	inst, apiError := containersapi.InstallServiceWith(ctx, cloudflaredServiceID, containerstypes.ServiceInstall{
+		DisplayName: &displayName,
+		Tags:        []string{"Vertex Tunnels", "Vertex Tunnels - Cloudflare"},
+		Command:     &command,
+		Environment: containerstypes.ContainerEnvVariables{
+			"TUNNEL_TOKEN": token,
+		},
+	})
+	if apiError != nil {
+		return tunnel, fmt.Errorf("failed to install cloudflared: %w", apiError.RouterError())
+	}
+
+	apiError = containersapi.StartContainer(ctx, inst.UUID)
+	if apiError != nil {
+		err := fmt.Errorf("failed to start cloudflared: %w", apiError.RouterError())
+		apiError = containersapi.DeleteContainer(ctx, inst.UUID)
+		if apiError != nil {
+			log.Error(apiError.RouterError(), vlog.String("uuid", inst.UUID.String()))
+		}
+		return tunnel, err
+	}
+
+	log.Info("cloudflared tunnel opened",
+		vlog.String("tunnel", id.String()),
+		vlog.String("hostname", tunnel.Hostname),
+		vlog.String("service", fmt.Sprintf("http://%s:%s", config.Current.Host, tunnel.Port)),
+	)
+
+	tunnel.ProviderContainerUUID = &inst.UUID
+	tunnel.PublicURL = "https://" + tunnel.Hostname
+	return tunnel, nil
+}
+
+func (a *TunnelCloudflaredAdapter) Close(ctx context.Context, id uuid.UUID, tunnel types.Tunnel) error {
+	if tunnel.ProviderContainerUUID == nil {
+		return nil
+	}
+
+	err := a.Stop(ctx, tunnel)
+	if err != nil {
+		return err
+	}
+
+	apiError := containersapi.DeleteContainer(ctx, *tunnel.ProviderContainerUUID)
+	if apiError != nil && apiError.HttpCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete cloudflared: %w", apiError.RouterError())
+	}
+	return nil
+}
+
+func (a *TunnelCloudflaredAdapter) Start(ctx context.Context, tunnel types.Tunnel) error {
+	if tunnel.ProviderContainerUUID == nil {
+		return errCloudflaredContainerMissing
+	}
+
+	apiError := containersapi.StartContainer(ctx, *tunnel.ProviderContainerUUID)
+	if apiError != nil {
+		return fmt.Errorf("failed to start cloudflared: %w", apiError.RouterError())
+	}
+	return nil
+}
+
+// Stop stops the cloudflared container. It does nothing if the container is
+// already stopped or was deleted.
+func (a *TunnelCloudflaredAdapter) Stop(ctx context.Context, tunnel types.Tunnel) error {
+	if tunnel.ProviderContainerUUID == nil {
+		return errCloudflaredContainerMissing
+	}
+
+	apiError := containersapi.StopContainer(ctx, *tunnel.ProviderContainerUUID)
+	if apiError != nil &&
+		apiError.HttpCode != http.StatusNotFound &&
+		apiError.Code != containerstypes.ErrCodeContainerNotRunning {
+		return fmt.Errorf("failed to stop cloudflared: %w", apiError.RouterError())
+	}
+	return nil
+}
+
+func (a *TunnelCloudflaredAdapter) Status(ctx context.Context, tunnel types.Tunnel) (types.TunnelStatus, error) {
+	if tunnel.ProviderContainerUUID == nil {
+		return types.TunnelStatus{}, errCloudflaredContainerMissing
+	}
+
+	inst, apiError := containersapi.GetContainer(ctx, *tunnel.ProviderContainerUUID)
+	if apiError != nil {
+		return types.TunnelStatus{}, fmt.Errorf("failed to get cloudflared: %w", apiError.RouterError())
+	}
+	return types.TunnelStatus{Status: string(inst.Status)}, nil
+}
@@ -0,0 +1,284 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/apps/containers"
+	"github.com/vertex-center/vertex/apps/containers/core/port"
+	containersservice "github.com/vertex-center/vertex/apps/containers/core/service"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	containershandler "github.com/vertex-center/vertex/apps/containers/handler"
+	"github.com/vertex-center/vertex/apps/tunnels/core/types"
+	"github.com/vertex-center/vertex/config"
+	vtypes "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/core/types/app"
+	"github.com/vertex-center/vertex/pkg/ginutils"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type TunnelCloudflaredAdapterTestSuite struct {
+	suite.Suite
+
+	adapter    *TunnelCloudflaredAdapter
+	containers port.ContainerService
+	runner     *fakeRunnerService
+	server     *httptest.Server
+	config     config.Config
+}
+
+func TestTunnelCloudflaredAdapterTestSuite(t *testing.T) {
+	suite.Run(t, new(TunnelCloudflaredAdapterTestSuite))
+}
+
+// SetupTest serves the routes of the containers app used by the adapter
+// with the real handlers and container service. Only the runner is faked.
+func (suite *TunnelCloudflaredAdapterTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	ctx := app.NewContext(vtypes.NewVertexContext())
+	settingsService := containersservice.NewContainerSettingsService(&memorySettingsAdapter{settings: map[uuid.UUID]containerstypes.ContainerSettings{}})
+	envService := containersservice.NewContainerEnvService(&memoryEnvAdapter{env: map[uuid.UUID]containerstypes.ContainerEnvVariables{}})
+	serviceService := containersservice.NewContainerServiceService(&memoryServiceAdapter{services: map[uuid.UUID]containerstypes.Service{}})
+	suite.runner = &fakeRunnerService{}
+	suite.containers = containersservice.NewContainerService(containersservice.ContainerServiceParams{
+		Ctx:                      ctx,
+		ContainerAdapter:         &memoryContainerAdapter{},
+		ContainerRunnerService:   suite.runner,
+		ContainerServiceService:  serviceService,
+		ContainerEnvService:      envService,
+		ContainerSettingsService: settingsService,
+	})
+
+	services := &fakeServiceService{service: containerstypes.Service{
+		ID:   cloudflaredServiceID,
+		Name: "Cloudflare Tunnel",
+		Env: []containerstypes.ServiceEnv{
+			{Type: "string", Name: "TUNNEL_TOKEN"},
+			{Type: "string", Name: "TUNNEL_LOGLEVEL", Default: "info"},
+		},
+	}}
+	serviceHandler := containershandler.NewServiceHandler(services, suite.containers)
+	containerHandler := containershandler.NewContainerHandler(containershandler.ContainerHandlerParams{
+		Ctx:                      ctx,
+		ContainerService:         suite.containers,
+		ContainerSettingsService: settingsService,
+		ContainerRunnerService:   suite.runner,
+		ContainerEnvService:      envService,
+		ContainerServiceService:  serviceService,
+		ServiceService:           services,
+	})
+
+	r := router.New()
+	r.Use(ginutils.ErrorHandler())
+	g := r.Group("/api/app" + containers.AppRoute)
+	g.POST("/service/:service_id/install", serviceHandler.Install)
+	container := g.Group("/container/:container_uuid")
+	container.DELETE("", containerHandler.Delete)
+	container.PATCH("", containerHandler.Patch)
+	container.PATCH("/environment", containerHandler.PatchEnvironment)
+	container.POST("/start", containerHandler.Start)
+	suite.server = httptest.NewServer(r)
+
+	host, p, err := net.SplitHostPort(suite.server.Listener.Addr().String())
+	suite.Require().NoError(err)
+	suite.config = config.Current
+	config.Current.Host = host
+	config.Current.Port = p
+
+	suite.adapter = NewTunnelCloudflaredAdapter(TunnelCloudflaredAdapterParams{
+		SettingsAdapter: &memoryCloudflaredSettingsAdapter{settings: types.CloudflaredSettings{Token: "token"}},
+	}).(*TunnelCloudflaredAdapter)
+}
+
+func (suite *TunnelCloudflaredAdapterTestSuite) TearDownTest() {
+	suite.server.Close()
+	config.Current = suite.config
+}
+
+func (suite *TunnelCloudflaredAdapterTestSuite) TestOpen() {
+	tunnel, err := suite.adapter.Open(context.Background(), uuid.New(), types.Tunnel{
+		Provider: "cloudflared",
+		Port:     "8080",
+		Hostname: "app.example.com",
+	})
+	suite.Require().NoError(err)
+	suite.Require().NotNil(tunnel.ProviderContainerUUID)
+	suite.Equal("https://app.example.com", tunnel.PublicURL)
+
+	// The container is configured before it is started, so it is started
+	// once with the token, and never recreated.
+	suite.Equal([]string{"install", "start"}, suite.runner.getCalls())
+
+	inst, err := suite.containers.Get(*tunnel.ProviderContainerUUID)
+	suite.Require().NoError(err)
+	suite.Equal("Tunnel app.example.com", inst.DisplayName)
+	suite.Equal([]string{"Vertex Tunnels", "Vertex Tunnels - Cloudflare"}, inst.Tags)
+	suite.Equal(cloudflaredCommand, *inst.Command)
+	suite.Equal(containerstypes.ContainerEnvVariables{
+		"TUNNEL_TOKEN":    "token",
+		"TUNNEL_LOGLEVEL": "info",
+	}, inst.Env)
+}
+
+func (suite *TunnelCloudflaredAdapterTestSuite) TestOpenStartFails() {
+	suite.runner.startErr = errors.New("docker unreachable")
+
+	_, err := suite.adapter.Open(context.Background(), uuid.New(), types.Tunnel{
+		Provider: "cloudflared",
+		Port:     "8080",
+		Hostname: "app.example.com",
+	})
+	suite.Error(err)
+
+	// The container that failed to start is deleted.
+	suite.Equal([]string{"install", "start", "delete"}, suite.runner.getCalls())
+	suite.Empty(suite.containers.GetAll())
+}
+
+// fakeRunnerService records the calls of the handlers. Recreating a
+// container fails, like when it is recreated while still starting.
+type fakeRunnerService struct {
+	port.ContainerRunnerService
+
+	mutex    sync.Mutex
+	calls    []string
+	startErr error
+}
+
+func (f *fakeRunnerService) call(name string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.calls = append(f.calls, name)
+}
+
+func (f *fakeRunnerService) getCalls() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.calls
+}
+
+func (f *fakeRunnerService) Install(uuid uuid.UUID, service containerstypes.Service) error {
+	f.call("install")
+	return nil
+}
+
+func (f *fakeRunnerService) Start(inst *containerstypes.Container) error {
+	f.call("start")
+	return f.startErr
+}
+
+func (f *fakeRunnerService) Delete(inst *containerstypes.Container) error {
+	f.call("delete")
+	return nil
+}
+
+func (f *fakeRunnerService) RecreateContainer(inst *containerstypes.Container) error {
+	f.call("recreate")
+	return errors.New("the container is still starting")
+}
+
+type fakeServiceService struct {
+	service containerstypes.Service
+}
+
+func (f *fakeServiceService) GetAll() []containerstypes.Service {
+	return []containerstypes.Service{f.service}
+}
+
+func (f *fakeServiceService) GetById(id string) (containerstypes.Service, error) {
+	if id != f.service.ID {
+		return containerstypes.Service{}, errors.New("service not found")
+	}
+	return f.service, nil
+}
+
+type memoryContainerAdapter struct{}
+
+func (a *memoryContainerAdapter) Create(uuid uuid.UUID) error { return nil }
+
+func (a *memoryContainerAdapter) Delete(uuid uuid.UUID) error { return nil }
+
+func (a *memoryContainerAdapter) GetAll() ([]uuid.UUID, error) { return nil, nil }
+
+type memoryServiceAdapter struct {
+	mutex    sync.Mutex
+	services map[uuid.UUID]containerstypes.Service
+}
+
+func (a *memoryServiceAdapter) Save(uuid uuid.UUID, service containerstypes.Service) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.services[uuid] = service
+	return nil
+}
+
+func (a *memoryServiceAdapter) Load(uuid uuid.UUID) (containerstypes.Service, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.services[uuid], nil
+}
+
+func (a *memoryServiceAdapter) LoadRaw(uuid uuid.UUID) (interface{}, error) {
+	return a.Load(uuid)
+}
+
+type memorySettingsAdapter struct {
+	mutex    sync.Mutex
+	settings map[uuid.UUID]containerstypes.ContainerSettings
+}
+
+func (a *memorySettingsAdapter) Save(uuid uuid.UUID, settings containerstypes.ContainerSettings) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.settings[uuid] = settings
+	return nil
+}
+
+func (a *memorySettingsAdapter) Load(uuid uuid.UUID) (containerstypes.ContainerSettings, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.settings[uuid], nil
+}
+
+type memoryEnvAdapter struct {
+	mutex sync.Mutex
+	env   map[uuid.UUID]containerstypes.ContainerEnvVariables
+}
+
+func (a *memoryEnvAdapter) Save(uuid uuid.UUID, env containerstypes.ContainerEnvVariables) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.env[uuid] = env
+	return nil
+}
+
+func (a *memoryEnvAdapter) Load(uuid uuid.UUID) (containerstypes.ContainerEnvVariables, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	env := containerstypes.ContainerEnvVariables{}
+	for name, value := range a.env[uuid] {
+		env[name] = value
+	}
+	return env, nil
+}
+
+type memoryCloudflaredSettingsAdapter struct {
+	settings types.CloudflaredSettings
+}
+
+func (a *memoryCloudflaredSettingsAdapter) GetSettings() types.CloudflaredSettings {
+	return a.settings
+}
+
+func (a *memoryCloudflaredSettingsAdapter) SetSettings(settings types.CloudflaredSettings) error {
+	a.settings = settings
+	return nil
+}
@@ -0,0 +1,148 @@
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/tunnels/core/port"
+	"github.com/vertex-center/vertex/apps/tunnels/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/storage"
+	"github.com/vertex-center/vlog"
+)
+
+var (
+	errTunnelsNotFound       = errors.New("tunnels.json doesn't exists or could not be found")
+	errTunnelsFailedToRead   = errors.New("failed to read tunnels.json")
+	errTunnelsFailedToDecode = errors.New("failed to decode tunnels.json")
+)
+
+type TunnelsFSAdapter struct {
+	tunnels      types.Tunnels
+	tunnelsMutex sync.RWMutex
+
+	tunnelsPath string
+}
+
+type TunnelsFSAdapterParams struct {
+	tunnelsPath string
+}
+
+func NewTunnelsFSAdapter(params *TunnelsFSAdapterParams) port.TunnelsAdapter {
+	if params == nil {
+		params = &TunnelsFSAdapterParams{}
+	}
+	if params.tunnelsPath == "" {
+		params.tunnelsPath = path.Join(storage.Path, "apps", "vx-tunnels")
+	}
+
+	err := os.MkdirAll(params.tunnelsPath, os.ModePerm)
+	if err != nil && !os.IsExist(err) {
+		log.Error(err,
+			vlog.String("message", "failed to create directory"),
+			vlog.String("path", params.tunnelsPath),
+		)
+		os.Exit(1)
+	}
+
+	adapter := &TunnelsFSAdapter{
+		tunnels:      types.Tunnels{},
+		tunnelsMutex: sync.RWMutex{},
+
+		tunnelsPath: params.tunnelsPath,
+	}
+
+	err = adapter.read()
+	if errors.Is(err, errTunnelsFailedToDecode) {
+		log.Error(err)
+	}
+
+	return adapter
+}
+
+func (a *TunnelsFSAdapter) GetTunnels() types.Tunnels {
+	a.tunnelsMutex.RLock()
+	defer a.tunnelsMutex.RUnlock()
+
+	tunnels := types.Tunnels{}
+	for id, tunnel := range a.tunnels {
+		tunnels[id] = tunnel
+	}
+	return tunnels
+}
+
+func (a *TunnelsFSAdapter) GetTunnel(id uuid.UUID) (types.Tunnel, error) {
+	a.tunnelsMutex.RLock()
+	defer a.tunnelsMutex.RUnlock()
+
+	tunnel, ok := a.tunnels[id]
+	if !ok {
+		return types.Tunnel{}, types.ErrTunnelNotFound
+	}
+	return tunnel, nil
+}
+
+func (a *TunnelsFSAdapter) SetTunnel(id uuid.UUID, tunnel types.Tunnel) error {
+	func() {
+		a.tunnelsMutex.Lock()
+		defer a.tunnelsMutex.Unlock()
+		a.tunnels[id] = tunnel
+	}()
+	return a.write()
+}
+
+func (a *TunnelsFSAdapter) RemoveTunnel(id uuid.UUID) error {
+	err := func() error {
+		a.tunnelsMutex.Lock()
+		defer a.tunnelsMutex.Unlock()
+		if _, ok := a.tunnels[id]; !ok {
+			return types.ErrTunnelNotFound
+		}
+		delete(a.tunnels, id)
+		return nil
+	}()
+	if err != nil {
+		return err
+	}
+	return a.write()
+}
+
+func (a *TunnelsFSAdapter) read() error {
+	p := path.Join(a.tunnelsPath, "tunnels.json")
+	file, err := os.ReadFile(p)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return errTunnelsNotFound
+	} else if err != nil {
+		return fmt.Errorf("%w: %w", errTunnelsFailedToRead, err)
+	}
+
+	a.tunnelsMutex.Lock()
+	defer a.tunnelsMutex.Unlock()
+
+	err = json.Unmarshal(file, &a.tunnels)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errTunnelsFailedToDecode, err)
+	}
+
+	return nil
+}
+
+func (a *TunnelsFSAdapter) write() error {
+	p := path.Join(a.tunnelsPath, "tunnels.json")
+
+	a.tunnelsMutex.RLock()
+	defer a.tunnelsMutex.RUnlock()
+
+	bytes, err := json.MarshalIndent(a.tunnels, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return storage.WriteFileAtomic(p, bytes, 0644)
+}
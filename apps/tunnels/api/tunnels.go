@@ -1,7 +1,11 @@
 package api
 
 import (
+	"context"
+
+	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/tunnels"
+	"github.com/vertex-center/vertex/apps/tunnels/core/types"
 	"github.com/vertex-center/vertex/core/types/api"
 	"github.com/vertex-center/vertex/pkg/router"
 )
@@ -15,3 +19,95 @@ func InstallTunnel(c *router.Context, provider string) *api.Error {
 		Fetch(c)
 	return api.HandleError(err, apiError)
 }
+
+func GetTunnels(ctx context.Context) (types.Tunnels, *api.Error) {
+	var res types.Tunnels
+	var apiError api.Error
+	err := api.AppRequest(tunnels.AppRoute).
+		Path("./tunnels").
+		ToJSON(&res).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return res, api.HandleError(err, apiError)
+}
+
+// CreateTunnel opens a tunnel, and returns its id and the tunnel with its
+// public URL.
+func CreateTunnel(ctx context.Context, tunnel types.Tunnel) (uuid.UUID, *types.Tunnel, *api.Error) {
+	var res struct {
+		ID uuid.UUID `json:"id"`
+		types.Tunnel
+	}
+	var apiError api.Error
+	err := api.AppRequest(tunnels.AppRoute).
+		Path("./tunnel").
+		BodyJSON(&tunnel).
+		Post().
+		ToJSON(&res).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return res.ID, &res.Tunnel, api.HandleError(err, apiError)
+}
+
+func DeleteTunnel(ctx context.Context, id uuid.UUID) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(tunnels.AppRoute).
+		Pathf("./tunnel/%s", id).
+		Delete().
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
+
+func StartTunnel(ctx context.Context, id uuid.UUID) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(tunnels.AppRoute).
+		Pathf("./tunnel/%s/start", id).
+		Post().
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
+
+func StopTunnel(ctx context.Context, id uuid.UUID) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(tunnels.AppRoute).
+		Pathf("./tunnel/%s/stop", id).
+		Post().
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
+
+func GetTunnelStatus(ctx context.Context, id uuid.UUID) (*types.TunnelStatus, *api.Error) {
+	var status types.TunnelStatus
+	var apiError api.Error
+	err := api.AppRequest(tunnels.AppRoute).
+		Pathf("./tunnel/%s/status", id).
+		ToJSON(&status).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return &status, api.HandleError(err, apiError)
+}
+
+func GetCloudflaredSettings(ctx context.Context) (*types.CloudflaredSettings, *api.Error) {
+	var settings types.CloudflaredSettings
+	var apiError api.Error
+	err := api.AppRequest(tunnels.AppRoute).
+		Path("./provider/cloudflared/settings").
+		ToJSON(&settings).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return &settings, api.HandleError(err, apiError)
+}
+
+func PatchCloudflaredSettings(ctx context.Context, settings types.CloudflaredSettings) *api.Error {
+	var apiError api.Error
+	err := api.AppRequest(tunnels.AppRoute).
+		Path("./provider/cloudflared/settings").
+		Patch().
+		BodyJSON(&settings).
+		ErrorJSON(&apiError).
+		Fetch(ctx)
+	return api.HandleError(err, apiError)
+}
@@ -1,15 +1,27 @@
 package tunnels
 
 import (
+	"github.com/vertex-center/vertex/apps/tunnels/adapter"
+	"github.com/vertex-center/vertex/apps/tunnels/core/port"
+	"github.com/vertex-center/vertex/apps/tunnels/core/service"
 	"github.com/vertex-center/vertex/apps/tunnels/handler"
 	apptypes "github.com/vertex-center/vertex/core/types/app"
 	"github.com/vertex-center/vertex/pkg/router"
 )
 
 const (
+	AppID    = "vx-tunnels"
 	AppRoute = "/vx-tunnels"
 )
 
+var (
+	tunnelsFSAdapter             port.TunnelsAdapter
+	cloudflaredSettingsFSAdapter port.CloudflaredSettingsAdapter
+	cloudflaredAdapter           port.TunnelProviderAdapter
+
+	tunnelsService port.TunnelsService
+)
+
 type App struct {
 	*apptypes.App
 }
@@ -18,19 +30,47 @@ func NewApp() *App {
 	return &App{}
 }
 
+func (a *App) ID() string {
+	return AppID
+}
+
 func (a *App) Initialize(app *apptypes.App) error {
 	a.App = app
 
+	tunnelsFSAdapter = adapter.NewTunnelsFSAdapter(nil)
+	cloudflaredSettingsFSAdapter = adapter.NewCloudflaredSettingsFSAdapter(nil)
+	cloudflaredAdapter = adapter.NewTunnelCloudflaredAdapter(adapter.TunnelCloudflaredAdapterParams{
+		SettingsAdapter: cloudflaredSettingsFSAdapter,
+	})
+
+	tunnelsService = service.NewTunnelsService(service.TunnelsServiceParams{
+		TunnelsAdapter:             tunnelsFSAdapter,
+		CloudflaredSettingsAdapter: cloudflaredSettingsFSAdapter,
+		Providers: map[string]port.TunnelProviderAdapter{
+			"cloudflared": cloudflaredAdapter,
+		},
+	})
+
 	app.Register(apptypes.Meta{
-		ID:          "vx-tunnels",
+		ID:          AppID,
 		Name:        "Vertex Tunnels",
 		Description: "Create and manage tunnels.",
 		Icon:        "subway",
 	})
 
 	app.RegisterRoutes(AppRoute, func(r *router.Group) {
-		providerHandler := handler.NewProviderHandler()
+		providerHandler := handler.NewProviderHandler(tunnelsService)
 		r.POST("/provider/:provider/install", providerHandler.Install)
+		r.GET("/provider/:provider/settings", providerHandler.GetSettings)
+		r.PATCH("/provider/:provider/settings", providerHandler.PatchSettings)
+
+		tunnelsHandler := handler.NewTunnelsHandler(tunnelsService)
+		r.GET("/tunnels", tunnelsHandler.Get)
+		r.POST("/tunnel", tunnelsHandler.Create)
+		r.DELETE("/tunnel/:id", tunnelsHandler.Delete)
+		r.POST("/tunnel/:id/start", tunnelsHandler.Start)
+		r.POST("/tunnel/:id/stop", tunnelsHandler.Stop)
+		r.GET("/tunnel/:id/status", tunnelsHandler.Status)
 	})
 
 	return nil
@@ -0,0 +1,41 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/apps/tunnels/core/types"
+)
+
+type (
+	TunnelsAdapter interface {
+		GetTunnels() types.Tunnels
+		// GetTunnel returns the tunnel with the given id, or ErrTunnelNotFound.
+		GetTunnel(id uuid.UUID) (types.Tunnel, error)
+		SetTunnel(id uuid.UUID, tunnel types.Tunnel) error
+		// RemoveTunnel removes the tunnel with the given id, or returns ErrTunnelNotFound.
+		RemoveTunnel(id uuid.UUID) error
+	}
+
+	TunnelProviderAdapter interface {
+		// Open starts the tunnel, and returns it with its public URL.
+		Open(ctx context.Context, id uuid.UUID, tunnel types.Tunnel) (types.Tunnel, error)
+
+		// Close stops the tunnel, and removes what the provider created for it.
+		Close(ctx context.Context, id uuid.UUID, tunnel types.Tunnel) error
+
+		// Start starts the client of an opened tunnel.
+		Start(ctx context.Context, tunnel types.Tunnel) error
+
+		// Stop stops the client of an opened tunnel, without closing it.
+		Stop(ctx context.Context, tunnel types.Tunnel) error
+
+		// Status returns the status of the client of the tunnel.
+		Status(ctx context.Context, tunnel types.Tunnel) (types.TunnelStatus, error)
+	}
+
+	CloudflaredSettingsAdapter interface {
+		GetSettings() types.CloudflaredSettings
+		SetSettings(settings types.CloudflaredSettings) error
+	}
+)
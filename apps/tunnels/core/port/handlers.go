@@ -2,6 +2,19 @@ package port
 
 import "github.com/vertex-center/vertex/pkg/router"
 
-type ProviderHandler interface {
-	Install(c *router.Context)
-}
+type (
+	ProviderHandler interface {
+		Install(c *router.Context)
+		GetSettings(c *router.Context)
+		PatchSettings(c *router.Context)
+	}
+
+	TunnelsHandler interface {
+		Get(c *router.Context)
+		Create(c *router.Context)
+		Delete(c *router.Context)
+		Start(c *router.Context)
+		Stop(c *router.Context)
+		Status(c *router.Context)
+	}
+)
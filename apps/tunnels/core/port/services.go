@@ -0,0 +1,25 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/apps/tunnels/core/types"
+)
+
+type (
+	TunnelsService interface {
+		GetTunnels() types.Tunnels
+		// Create opens a tunnel to a port of the container, and returns its id
+		// and the tunnel with its public URL.
+		Create(ctx context.Context, inst *containerstypes.Container, tunnel types.Tunnel) (uuid.UUID, types.Tunnel, error)
+		Delete(ctx context.Context, id uuid.UUID) error
+		Start(ctx context.Context, id uuid.UUID) error
+		Stop(ctx context.Context, id uuid.UUID) error
+		Status(ctx context.Context, id uuid.UUID) (types.TunnelStatus, error)
+
+		GetCloudflaredSettings() types.CloudflaredSettings
+		SetCloudflaredSettings(settings types.CloudflaredSettings) error
+	}
+)
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/apps/tunnels/core/port"
+	"github.com/vertex-center/vertex/apps/tunnels/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+type TunnelsService struct {
+	adapter                    port.TunnelsAdapter
+	cloudflaredSettingsAdapter port.CloudflaredSettingsAdapter
+	providers                  map[string]port.TunnelProviderAdapter
+}
+
+type TunnelsServiceParams struct {
+	TunnelsAdapter             port.TunnelsAdapter
+	CloudflaredSettingsAdapter port.CloudflaredSettingsAdapter
+	// Providers are the tunnel providers, by name.
+	Providers map[string]port.TunnelProviderAdapter
+}
+
+func NewTunnelsService(params TunnelsServiceParams) port.TunnelsService {
+	return &TunnelsService{
+		adapter:                    params.TunnelsAdapter,
+		cloudflaredSettingsAdapter: params.CloudflaredSettingsAdapter,
+		providers:                  params.Providers,
+	}
+}
+
+func (s *TunnelsService) GetTunnels() types.Tunnels {
+	return s.adapter.GetTunnels()
+}
+
+func (s *TunnelsService) Create(ctx context.Context, inst *containerstypes.Container, tunnel types.Tunnel) (uuid.UUID, types.Tunnel, error) {
+	provider, ok := s.providers[tunnel.Provider]
+	if !ok {
+		return uuid.Nil, types.Tunnel{}, fmt.Errorf("%w: %s", types.ErrProviderNotFound, tunnel.Provider)
+	}
+
+	tunnel.ContainerUUID = inst.UUID
+	tunnel.PublicURL = ""
+	if tunnel.Port == "" {
+		tunnel.Port, ok = inst.WebHostPort()
+		if !ok {
+			return uuid.Nil, types.Tunnel{}, types.ErrTunnelPortMissing
+		}
+	}
+	port, err := strconv.Atoi(tunnel.Port)
+	if err != nil || port < 1 || port > 65535 {
+		return uuid.Nil, types.Tunnel{}, fmt.Errorf("%w: %s", types.ErrTunnelPortInvalid, tunnel.Port)
+	}
+
+	id := uuid.New()
+	tunnel, err = provider.Open(ctx, id, tunnel)
+	if err != nil {
+		return uuid.Nil, types.Tunnel{}, err
+	}
+
+	err = s.adapter.SetTunnel(id, tunnel)
+	if err != nil {
+		closeErr := provider.Close(ctx, id, tunnel)
+		if closeErr != nil {
+			log.Error(closeErr, vlog.String("tunnel", id.String()))
+		}
+		return uuid.Nil, types.Tunnel{}, err
+	}
+	return id, tunnel, nil
+}
+
+func (s *TunnelsService) Delete(ctx context.Context, id uuid.UUID) error {
+	tunnel, err := s.adapter.GetTunnel(id)
+	if err != nil {
+		return err
+	}
+
+	// The tunnel is removed even if its provider is no longer available.
+	provider, ok := s.providers[tunnel.Provider]
+	if ok {
+		err = provider.Close(ctx, id, tunnel)
+		if err != nil {
+			return err
+		}
+	}
+	return s.adapter.RemoveTunnel(id)
+}
+
+func (s *TunnelsService) Start(ctx context.Context, id uuid.UUID) error {
+	tunnel, provider, err := s.getTunnel(id)
+	if err != nil {
+		return err
+	}
+	return provider.Start(ctx, tunnel)
+}
+
+func (s *TunnelsService) Stop(ctx context.Context, id uuid.UUID) error {
+	tunnel, provider, err := s.getTunnel(id)
+	if err != nil {
+		return err
+	}
+	return provider.Stop(ctx, tunnel)
+}
+
+func (s *TunnelsService) Status(ctx context.Context, id uuid.UUID) (types.TunnelStatus, error) {
+	tunnel, provider, err := s.getTunnel(id)
+	if err != nil {
+		return types.TunnelStatus{}, err
+	}
+	return provider.Status(ctx, tunnel)
+}
+
+// getTunnel returns the tunnel with the given id, and its provider.
+func (s *TunnelsService) getTunnel(id uuid.UUID) (types.Tunnel, port.TunnelProviderAdapter, error) {
+	tunnel, err := s.adapter.GetTunnel(id)
+	if err != nil {
+		return types.Tunnel{}, nil, err
+	}
+
+	provider, ok := s.providers[tunnel.Provider]
+	if !ok {
+		return types.Tunnel{}, nil, fmt.Errorf("%w: %s", types.ErrProviderNotFound, tunnel.Provider)
+	}
+	return tunnel, provider, nil
+}
+
+func (s *TunnelsService) GetCloudflaredSettings() types.CloudflaredSettings {
+	return s.cloudflaredSettingsAdapter.GetSettings()
+}
+
+// SetCloudflaredSettings saves the cloudflared settings. A redacted token,
+// as returned by the API, keeps the current token. The opened tunnels keep
+// the token they were opened with.
+func (s *TunnelsService) SetCloudflaredSettings(settings types.CloudflaredSettings) error {
+	if settings.Token == types.RedactedValue {
+		settings.Token = s.cloudflaredSettingsAdapter.GetSettings().Token
+	}
+	return s.cloudflaredSettingsAdapter.SetSettings(settings)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
+	"github.com/vertex-center/vertex/apps/tunnels/core/port"
+	"github.com/vertex-center/vertex/apps/tunnels/core/types"
+)
+
+type TunnelsServiceTestSuite struct {
+	suite.Suite
+
+	service  port.TunnelsService
+	adapter  *fakeTunnelsAdapter
+	provider *fakeTunnelProvider
+	settings *fakeCloudflaredSettingsAdapter
+	inst     containerstypes.Container
+}
+
+func TestTunnelsServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TunnelsServiceTestSuite))
+}
+
+func (suite *TunnelsServiceTestSuite) SetupTest() {
+	suite.adapter = &fakeTunnelsAdapter{tunnels: types.Tunnels{}}
+	suite.provider = &fakeTunnelProvider{open: map[uuid.UUID]bool{}}
+	suite.settings = &fakeCloudflaredSettingsAdapter{}
+	suite.service = NewTunnelsService(TunnelsServiceParams{
+		TunnelsAdapter:             suite.adapter,
+		CloudflaredSettingsAdapter: suite.settings,
+		Providers:                  map[string]port.TunnelProviderAdapter{"fake": suite.provider},
+	})
+	suite.inst = containerstypes.Container{
+		UUID: uuid.New(),
+		Env:  containerstypes.ContainerEnvVariables{"PORT": "8080"},
+		Service: containerstypes.Service{
+			Env:  []containerstypes.ServiceEnv{{Type: "port", Name: "PORT", Default: "80"}},
+			URLs: []containerstypes.URL{{Port: "80", Kind: "client"}},
+		},
+	}
+}
+
+func (suite *TunnelsServiceTestSuite) TestCreate() {
+	id, tunnel, err := suite.service.Create(context.Background(), &suite.inst, types.Tunnel{Provider: "fake"})
+	suite.Require().NoError(err)
+
+	// The port defaults to the web port of the container.
+	suite.Equal("8080", tunnel.Port)
+	suite.Equal(suite.inst.UUID, tunnel.ContainerUUID)
+	suite.Equal("https://fake.example.com", tunnel.PublicURL)
+	suite.Equal(tunnel, suite.adapter.tunnels[id])
+	suite.True(suite.provider.open[id])
+
+	err = suite.service.Delete(context.Background(), id)
+	suite.NoError(err)
+	suite.Empty(suite.adapter.tunnels)
+	suite.False(suite.provider.open[id])
+}
+
+func (suite *TunnelsServiceTestSuite) TestCreateInvalid() {
+	_, _, err := suite.service.Create(context.Background(), &suite.inst, types.Tunnel{Provider: "ngrok"})
+	suite.ErrorIs(err, types.ErrProviderNotFound)
+
+	_, _, err = suite.service.Create(context.Background(), &suite.inst, types.Tunnel{Provider: "fake", Port: "99999"})
+	suite.ErrorIs(err, types.ErrTunnelPortInvalid)
+
+	suite.inst.Service.URLs = nil
+	_, _, err = suite.service.Create(context.Background(), &suite.inst, types.Tunnel{Provider: "fake"})
+	suite.ErrorIs(err, types.ErrTunnelPortMissing)
+
+	suite.Empty(suite.adapter.tunnels)
+}
+
+func (suite *TunnelsServiceTestSuite) TestCreateClosesUnsavedTunnel() {
+	suite.adapter.err = errors.New("disk full")
+
+	_, _, err := suite.service.Create(context.Background(), &suite.inst, types.Tunnel{Provider: "fake"})
+	suite.Error(err)
+	suite.Empty(suite.provider.open)
+}
+
+func (suite *TunnelsServiceTestSuite) TestDeleteNotFound() {
+	err := suite.service.Delete(context.Background(), uuid.New())
+	suite.ErrorIs(err, types.ErrTunnelNotFound)
+}
+
+func (suite *TunnelsServiceTestSuite) TestSetCloudflaredSettings() {
+	err := suite.service.SetCloudflaredSettings(types.CloudflaredSettings{Token: "secret"})
+	suite.Require().NoError(err)
+	suite.Equal(types.RedactedValue, suite.service.GetCloudflaredSettings().Redacted().Token)
+
+	// The redacted token sent back by the clients keeps the current token.
+	err = suite.service.SetCloudflaredSettings(types.CloudflaredSettings{Token: types.RedactedValue})
+	suite.Require().NoError(err)
+	suite.Equal("secret", suite.service.GetCloudflaredSettings().Token)
+}
+
+type fakeTunnelsAdapter struct {
+	port.TunnelsAdapter
+	tunnels types.Tunnels
+	err     error
+}
+
+func (f *fakeTunnelsAdapter) GetTunnel(id uuid.UUID) (types.Tunnel, error) {
+	tunnel, ok := f.tunnels[id]
+	if !ok {
+		return types.Tunnel{}, types.ErrTunnelNotFound
+	}
+	return tunnel, nil
+}
+
+func (f *fakeTunnelsAdapter) SetTunnel(id uuid.UUID, tunnel types.Tunnel) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.tunnels[id] = tunnel
+	return nil
+}
+
+func (f *fakeTunnelsAdapter) RemoveTunnel(id uuid.UUID) error {
+	delete(f.tunnels, id)
+	return nil
+}
+
+type fakeTunnelProvider struct {
+	port.TunnelProviderAdapter
+	open map[uuid.UUID]bool
+}
+
+func (f *fakeTunnelProvider) Open(ctx context.Context, id uuid.UUID, tunnel types.Tunnel) (types.Tunnel, error) {
+	f.open[id] = true
+	tunnel.PublicURL = "https://fake.example.com"
+	return tunnel, nil
+}
+
+func (f *fakeTunnelProvider) Close(ctx context.Context, id uuid.UUID, tunnel types.Tunnel) error {
+	delete(f.open, id)
+	return nil
+}
+
+type fakeCloudflaredSettingsAdapter struct {
+	settings types.CloudflaredSettings
+}
+
+func (f *fakeCloudflaredSettingsAdapter) GetSettings() types.CloudflaredSettings {
+	return f.settings
+}
+
+func (f *fakeCloudflaredSettingsAdapter) SetSettings(settings types.CloudflaredSettings) error {
+	f.settings = settings
+	return nil
+}
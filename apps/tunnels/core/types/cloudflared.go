@@ -0,0 +1,26 @@
+package types
+
+import "errors"
+
+var (
+	ErrCloudflaredTokenMissing = errors.New("the cloudflared tunnel token is missing")
+	ErrTunnelHostnameMissing   = errors.New("the tunnel hostname is missing")
+)
+
+// RedactedValue replaces the secrets in the settings returned by the API.
+const RedactedValue = "********"
+
+type CloudflaredSettings struct {
+	// Token is the token of the Cloudflare Tunnel, given by the Cloudflare
+	// dashboard when the tunnel is created.
+	Token string `json:"token,omitempty"`
+}
+
+// Redacted returns a copy of the settings where the token is replaced by
+// RedactedValue.
+func (s CloudflaredSettings) Redacted() CloudflaredSettings {
+	if s.Token != "" {
+		s.Token = RedactedValue
+	}
+	return s
+}
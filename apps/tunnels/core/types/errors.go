@@ -0,0 +1,19 @@
+package types
+
+import "github.com/vertex-center/vertex/pkg/router"
+
+const (
+	ErrCodeProviderNotFound        router.ErrCode = "provider_not_found"
+	ErrCodeTunnelUuidMissing       router.ErrCode = "tunnel_uuid_missing"
+	ErrCodeTunnelUuidInvalid       router.ErrCode = "tunnel_uuid_invalid"
+	ErrCodeTunnelNotFound          router.ErrCode = "tunnel_not_found"
+	ErrCodeTunnelInvalid           router.ErrCode = "tunnel_invalid"
+	ErrCodeFailedToCreateTunnel    router.ErrCode = "failed_to_create_tunnel"
+	ErrCodeFailedToDeleteTunnel    router.ErrCode = "failed_to_delete_tunnel"
+	ErrCodeFailedToStartTunnel     router.ErrCode = "failed_to_start_tunnel"
+	ErrCodeFailedToStopTunnel      router.ErrCode = "failed_to_stop_tunnel"
+	ErrCodeFailedToGetTunnelStatus router.ErrCode = "failed_to_get_tunnel_status"
+
+	ErrCodeCloudflaredTokenMissing        router.ErrCode = "cloudflared_token_missing"
+	ErrCodeFailedToSetCloudflaredSettings router.ErrCode = "failed_to_set_cloudflared_settings"
+)
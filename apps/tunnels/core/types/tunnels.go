@@ -0,0 +1,44 @@
+package types
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTunnelNotFound    = errors.New("tunnel not found")
+	ErrProviderNotFound  = errors.New("tunnel provider not found")
+	ErrTunnelPortMissing = errors.New("the tunnel port is missing and the container has no web port")
+	ErrTunnelPortInvalid = errors.New("the tunnel port is invalid")
+)
+
+type Tunnels map[uuid.UUID]Tunnel
+
+type Tunnel struct {
+	// Provider is the tunnel provider, like cloudflared.
+	Provider string `json:"provider"`
+
+	// ContainerUUID is the container exposed by the tunnel.
+	ContainerUUID uuid.UUID `json:"container_uuid"`
+
+	// Port is the host port of the container exposed by the tunnel.
+	Port string `json:"port"`
+
+	// Hostname is the public hostname of the tunnel, for the providers that
+	// route a hostname chosen by the user, like cloudflared.
+	Hostname string `json:"hostname,omitempty"`
+
+	// PublicURL is the URL of the tunnel, given by the provider.
+	PublicURL string `json:"public_url,omitempty"`
+
+	// ProviderContainerUUID is the container running the client of the
+	// provider, if the provider runs one.
+	ProviderContainerUUID *uuid.UUID `json:"provider_container_uuid,omitempty"`
+}
+
+type TunnelStatus struct {
+	// Status is the status of the tunnel client, like the status of its
+	// container.
+	Status string `json:"status"`
+}
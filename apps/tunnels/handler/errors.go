@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/vertex-center/vertex/apps/tunnels/core/types"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+func init() {
+	router.RegisterError(types.ErrTunnelNotFound, http.StatusNotFound, router.Error{
+		Code:          types.ErrCodeTunnelNotFound,
+		PublicMessage: "The tunnel could not be found.",
+	})
+	router.RegisterError(types.ErrProviderNotFound, http.StatusNotFound, router.Error{
+		Code:          types.ErrCodeProviderNotFound,
+		PublicMessage: "The tunnel provider is not supported.",
+	})
+	router.RegisterError(types.ErrTunnelPortMissing, http.StatusBadRequest, router.Error{
+		Code:          types.ErrCodeTunnelInvalid,
+		PublicMessage: "The container has no web port. Choose the port to expose.",
+	})
+	router.RegisterError(types.ErrTunnelPortInvalid, http.StatusBadRequest, router.Error{
+		Code:          types.ErrCodeTunnelInvalid,
+		PublicMessage: "The port of the tunnel is invalid.",
+	})
+	router.RegisterError(types.ErrTunnelHostnameMissing, http.StatusBadRequest, router.Error{
+		Code:          types.ErrCodeTunnelInvalid,
+		PublicMessage: "The tunnel needs the public hostname routed to it by the provider.",
+	})
+	router.RegisterError(types.ErrCloudflaredTokenMissing, http.StatusConflict, router.Error{
+		Code:          types.ErrCodeCloudflaredTokenMissing,
+		PublicMessage: "The Cloudflare Tunnel token is missing. Set it in the cloudflared settings first.",
+	})
+}
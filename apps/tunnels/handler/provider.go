@@ -1,21 +1,24 @@
 package handler
 
 import (
-	"errors"
 	"fmt"
 
 	containerstypes "github.com/vertex-center/vertex/apps/containers/core/types"
-	"github.com/vertex-center/vertex/apps/monitoring/core/types"
 	"github.com/vertex-center/vertex/apps/tunnels/core/port"
+	"github.com/vertex-center/vertex/apps/tunnels/core/types"
 
 	containersapi "github.com/vertex-center/vertex/apps/containers/api"
 	"github.com/vertex-center/vertex/pkg/router"
 )
 
-type ProviderHandler struct{}
+type ProviderHandler struct {
+	tunnelsService port.TunnelsService
+}
 
-func NewProviderHandler() port.ProviderHandler {
-	return &ProviderHandler{}
+func NewProviderHandler(tunnelsService port.TunnelsService) port.ProviderHandler {
+	return &ProviderHandler{
+		tunnelsService: tunnelsService,
+	}
 }
 
 func (r *ProviderHandler) Install(c *router.Context) {
@@ -47,15 +50,49 @@ func (r *ProviderHandler) Install(c *router.Context) {
 	c.OK()
 }
 
+// GetSettings returns the settings of the provider, without its secrets.
+func (r *ProviderHandler) GetSettings(c *router.Context) {
+	_, err := getTunnelProvider(c)
+	if err != nil {
+		return
+	}
+
+	c.JSON(r.tunnelsService.GetCloudflaredSettings().Redacted())
+}
+
+func (r *ProviderHandler) PatchSettings(c *router.Context) {
+	_, err := getTunnelProvider(c)
+	if err != nil {
+		return
+	}
+
+	var settings types.CloudflaredSettings
+	err = c.ParseBody(&settings)
+	if err != nil {
+		return
+	}
+
+	err = r.tunnelsService.SetCloudflaredSettings(settings)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types.ErrCodeFailedToSetCloudflaredSettings,
+			PublicMessage: "Failed to save the cloudflared settings.",
+		})
+		return
+	}
+
+	c.OK()
+}
+
 func getTunnelProvider(c *router.Context) (string, error) {
 	provider := c.Param("provider")
 	if provider != "cloudflared" {
 		c.NotFound(router.Error{
-			Code:           types.ErrCodeCollectorNotFound,
+			Code:           types.ErrCodeProviderNotFound,
 			PublicMessage:  fmt.Sprintf("Provider not found: %s.", provider),
 			PrivateMessage: "The provider is not supported. It should be 'cloudflared'.",
 		})
-		return "", errors.New("collector not found")
+		return "", types.ErrProviderNotFound
 	}
 	return provider, nil
 }
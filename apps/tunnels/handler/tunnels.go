@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	containersapi "github.com/vertex-center/vertex/apps/containers/api"
+	"github.com/vertex-center/vertex/apps/tunnels/core/port"
+	"github.com/vertex-center/vertex/apps/tunnels/core/types"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type TunnelsHandler struct {
+	tunnelsService port.TunnelsService
+}
+
+func NewTunnelsHandler(tunnelsService port.TunnelsService) port.TunnelsHandler {
+	return &TunnelsHandler{
+		tunnelsService: tunnelsService,
+	}
+}
+
+func getTunnelID(c *router.Context) (uuid.UUID, error) {
+	idString := c.Param("id")
+	if idString == "" {
+		c.BadRequest(router.Error{
+			Code:           types.ErrCodeTunnelUuidMissing,
+			PublicMessage:  "The request is missing the tunnel UUID.",
+			PrivateMessage: "Field 'id' is required.",
+		})
+		return uuid.UUID{}, errors.New("tunnel uuid missing")
+	}
+
+	id, err := uuid.Parse(idString)
+	if err != nil {
+		c.BadRequest(router.Error{
+			Code:           types.ErrCodeTunnelUuidInvalid,
+			PublicMessage:  "The tunnel UUID is invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return uuid.UUID{}, err
+	}
+
+	return id, nil
+}
+
+func (r *TunnelsHandler) Get(c *router.Context) {
+	c.JSON(r.tunnelsService.GetTunnels())
+}
+
+type CreateTunnelResponse struct {
+	ID uuid.UUID `json:"id"`
+	types.Tunnel
+}
+
+func (r *TunnelsHandler) Create(c *router.Context) {
+	var tunnel types.Tunnel
+	err := c.ParseBody(&tunnel)
+	if err != nil {
+		return
+	}
+
+	inst, apiError := containersapi.GetContainer(c, tunnel.ContainerUUID)
+	if apiError != nil {
+		c.AbortWithCode(apiError.HttpCode, apiError.RouterError())
+		return
+	}
+
+	id, tunnel, err := r.tunnelsService.Create(c, inst, tunnel)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types.ErrCodeFailedToCreateTunnel,
+			PublicMessage: fmt.Sprintf("Failed to create a tunnel to the container '%s'.", inst.DisplayName),
+		})
+		return
+	}
+
+	c.JSON(CreateTunnelResponse{
+		ID:     id,
+		Tunnel: tunnel,
+	})
+}
+
+func (r *TunnelsHandler) Delete(c *router.Context) {
+	id, err := getTunnelID(c)
+	if err != nil {
+		return
+	}
+
+	err = r.tunnelsService.Delete(c, id)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types.ErrCodeFailedToDeleteTunnel,
+			PublicMessage: fmt.Sprintf("Failed to delete tunnel '%s'.", id),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+func (r *TunnelsHandler) Start(c *router.Context) {
+	id, err := getTunnelID(c)
+	if err != nil {
+		return
+	}
+
+	err = r.tunnelsService.Start(c, id)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types.ErrCodeFailedToStartTunnel,
+			PublicMessage: fmt.Sprintf("Failed to start tunnel '%s'.", id),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+func (r *TunnelsHandler) Stop(c *router.Context) {
+	id, err := getTunnelID(c)
+	if err != nil {
+		return
+	}
+
+	err = r.tunnelsService.Stop(c, id)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types.ErrCodeFailedToStopTunnel,
+			PublicMessage: fmt.Sprintf("Failed to stop tunnel '%s'.", id),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+func (r *TunnelsHandler) Status(c *router.Context) {
+	id, err := getTunnelID(c)
+	if err != nil {
+		return
+	}
+
+	status, err := r.tunnelsService.Status(c, id)
+	if err != nil {
+		c.Fail(err, router.Error{
+			Code:          types.ErrCodeFailedToGetTunnelStatus,
+			PublicMessage: fmt.Sprintf("Failed to get the status of tunnel '%s'.", id),
+		})
+		return
+	}
+
+	c.JSON(status)
+}
@@ -2,8 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
-	"fmt"
 	"github.com/gin-gonic/gin"
 	adapter2 "github.com/vertex-center/vertex/adapter"
 	"github.com/vertex-center/vertex/core/port"
@@ -12,6 +12,8 @@ import (
 	"github.com/vertex-center/vertex/pkg/ginutils"
 	"github.com/vertex-center/vertex/pkg/router"
 	"github.com/vertex-center/vlog"
+	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -27,6 +29,10 @@ import (
 var (
 	r *router.Router
 
+	// configPath is the configuration file given to the kernel, also
+	// loaded by Vertex.
+	configPath string
+
 	dockerCliAdapter port.DockerAdapter
 	sshAdapter       port.SshAdapter
 
@@ -53,8 +59,8 @@ func main() {
 	gin.SetMode(gin.ReleaseMode)
 	r = router.New()
 	r.Use(ginutils.ErrorHandler())
-	r.Use(ginutils.Logger("KERNEL"))
-	r.Use(gin.Recovery())
+	r.Use(ginutils.Logger("KERNEL", ginutils.LogFormat(config.KernelCurrent.LogFormat)))
+	r.Use(ginutils.Recovery())
 
 	initAdapters()
 	initServices()
@@ -69,12 +75,39 @@ func main() {
 	var vertex *exec.Cmd
 	go func() {
 		var err error
-		vertex, err = runVertex([]string{
+		// The secrets are passed in the environment, as the command line
+		// of a process can be read by any user.
+		var env []string
+		if config.KernelCurrent.DockerHubToken != "" {
+			env = append(env, config.EnvPrefix+"DOCKER_HUB_TOKEN="+config.KernelCurrent.DockerHubToken)
+		}
+
+		vertex, err = runVertex(env, []string{
+			"-config", configPath,
 			"-host", config.KernelCurrent.Host,
+			"-host-kernel", config.KernelCurrent.HostKernel,
 			"-port", config.KernelCurrent.Port,
 			"-port-kernel", config.KernelCurrent.PortKernel,
 			"-port-proxy", config.KernelCurrent.PortProxy,
 			"-port-prometheus", config.KernelCurrent.PortPrometheus,
+			"-proxy-base-domain", config.KernelCurrent.ProxyBaseDomain,
+			"-proxy-fallback-url", config.KernelCurrent.ProxyFallbackURL,
+			"-proxy-fallback-page", config.KernelCurrent.ProxyFallbackPage,
+			"-apps", config.KernelCurrent.Apps,
+			"-connectivity-check", config.KernelCurrent.ConnectivityCheck,
+			"-baselines-url", config.KernelCurrent.BaselinesURL,
+			"-git-url", config.KernelCurrent.GitURL,
+			"-log-format", config.KernelCurrent.LogFormat,
+			"-log-level", config.KernelCurrent.LogLevel,
+			"-log-file", config.KernelCurrent.LogFile,
+			"-log-max-size", config.KernelCurrent.LogMaxSize,
+			"-log-max-age", config.KernelCurrent.LogMaxAge,
+			"-public-about", config.KernelCurrent.PublicAbout,
+			"-registry-cache-ttl", config.KernelCurrent.RegistryCacheTTL,
+			"-max-concurrent-builds", config.KernelCurrent.MaxConcurrentBuilds,
+			"-max-body-size", config.KernelCurrent.MaxBodySize,
+			"-request-timeout", config.KernelCurrent.RequestTimeout,
+			"-docker-hub-username", config.KernelCurrent.DockerHubUsername,
 		}...)
 		if err != nil {
 			log.Error(err)
@@ -93,6 +126,7 @@ func main() {
 
 	<-shutdownChan
 	log.Info("Shutting down...")
+	forceQuitOnSignal()
 	if vertex != nil && vertex.Process != nil {
 		_ = vertex.Process.Signal(os.Interrupt)
 		_, _ = vertex.Process.Wait()
@@ -100,6 +134,18 @@ func main() {
 	stopRouter()
 }
 
+// forceQuitOnSignal exits immediately if another signal is received while
+// shutting down, in case the graceful shutdown hangs.
+func forceQuitOnSignal() {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-c
+		log.Warn("second shutdown signal received, forcing exit")
+		os.Exit(1)
+	}()
+}
+
 func ensureRoot() {
 	if os.Getuid() != 0 {
 		log.Warn("vertex-kernel must be run as root to work properly")
@@ -111,22 +157,20 @@ func parseArgs() {
 		flagUsername = flag.String("user", "", "username of the unprivileged user")
 		flagUID      = flag.Uint("uid", 0, "uid of the unprivileged user")
 		flagGID      = flag.Uint("gid", 0, "gid of the unprivileged user")
-
-		flagHost = flag.String("host", config.Current.Host, "The Vertex access url")
-
-		flagPort           = flag.String("port", config.Current.Port, "The Vertex port")
-		flagPortKernel     = flag.String("port-kernel", config.Current.PortKernel, "The Vertex Kernel port")
-		flagPortProxy      = flag.String("port-proxy", config.Current.PortProxy, "The Vertex Proxy port")
-		flagPortPrometheus = flag.String("port-prometheus", config.Current.PortPrometheus, "The Prometheus port")
 	)
 
+	configFlags := config.KernelCurrent.RegisterFlags(flag.CommandLine)
+
 	flag.Parse()
 
-	config.KernelCurrent.Host = *flagHost
-	config.KernelCurrent.Port = *flagPort
-	config.KernelCurrent.PortKernel = *flagPortKernel
-	config.KernelCurrent.PortProxy = *flagPortProxy
-	config.KernelCurrent.PortPrometheus = *flagPortPrometheus
+	err := configFlags.Apply(&config.KernelCurrent.Config)
+	if err != nil {
+		log.Error(err)
+		os.Exit(1)
+	}
+	configPath = configFlags.Path()
+
+	log.SetLevel(config.KernelCurrent.MinLogLevel())
 
 	if *flagUsername != "" {
 		u, err := user.Lookup(*flagUsername)
@@ -183,15 +227,19 @@ func initRoutes() {
 
 	dockerHandler := handler.NewDockerKernelHandler(dockerService)
 	docker := api.Group("/docker")
+	docker.GET("/ping", dockerHandler.Ping)
 	docker.GET("/containers", dockerHandler.GetContainers)
 	docker.POST("/container", dockerHandler.CreateContainer)
 	docker.DELETE("/container/:id", dockerHandler.DeleteContainer)
 	docker.POST("/container/:id/start", dockerHandler.StartContainer)
 	docker.POST("/container/:id/stop", dockerHandler.StopContainer)
+	docker.POST("/container/:id/pause", dockerHandler.PauseContainer)
+	docker.POST("/container/:id/unpause", dockerHandler.UnpauseContainer)
 	docker.GET("/container/:id/info", dockerHandler.InfoContainer)
 	docker.GET("/container/:id/logs/stdout", dockerHandler.LogsStdoutContainer)
 	docker.GET("/container/:id/logs/stderr", dockerHandler.LogsStderrContainer)
 	docker.GET("/container/:id/wait/:cond", dockerHandler.WaitContainer)
+	docker.POST("/container/:id/copy", dockerHandler.CopyToContainer)
 	docker.GET("/image/:id/info", dockerHandler.InfoImage)
 	docker.POST("/image/pull", dockerHandler.PullImage)
 	docker.POST("/image/build", dockerHandler.BuildImage)
@@ -205,10 +253,12 @@ func initRoutes() {
 
 func startRouter() {
 	log.Info("vertex-kernel started", vlog.String("url", config.KernelCurrent.KernelURL()))
-	addr := fmt.Sprintf(":%s", config.KernelCurrent.PortKernel)
+	addr := net.JoinHostPort(config.KernelCurrent.HostKernel, config.KernelCurrent.PortKernel)
 
 	err := r.Start(addr)
-	if err != nil {
+	if errors.Is(err, http.ErrServerClosed) {
+		log.Info("vertex-kernel closed")
+	} else if err != nil {
 		log.Error(err)
 		os.Exit(1)
 	}
@@ -220,6 +270,5 @@ func stopRouter() {
 	err := r.Stop(ctx)
 	if err != nil {
 		log.Error(err)
-		os.Exit(1)
 	}
 }
@@ -12,7 +12,9 @@ import (
 	"github.com/vertex-center/vlog"
 )
 
-func runVertex(args ...string) (*exec.Cmd, error) {
+// runVertex starts Vertex with the args, and the env added to the
+// environment of the kernel.
+func runVertex(env []string, args ...string) (*exec.Cmd, error) {
 	uid, gid := config.KernelCurrent.Uid, config.KernelCurrent.Gid
 
 	log.Info("running vertex",
@@ -23,6 +25,7 @@ func runVertex(args ...string) (*exec.Cmd, error) {
 	cmd := exec.Command("./vertex", args...)
 	cmd.SysProcAttr = &syscall.SysProcAttr{}
 	cmd.SysProcAttr.Credential = &syscall.Credential{Uid: uid, Gid: gid}
+	cmd.Env = append(os.Environ(), env...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
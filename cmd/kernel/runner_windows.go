@@ -7,8 +7,11 @@ import (
 	"os/exec"
 )
 
-func runVertex(args ...string) (*exec.Cmd, error) {
+// runVertex starts Vertex with the args, and the env added to the
+// environment of the kernel.
+func runVertex(env []string, args ...string) (*exec.Cmd, error) {
 	cmd := exec.Command("vertex.exe", args...)
+	cmd.Env = append(os.Environ(), env...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/static"
 	"github.com/gin-gonic/gin"
 	adapter2 "github.com/vertex-center/vertex/adapter"
@@ -29,6 +28,7 @@ import (
 	"os/signal"
 	"path"
 	"runtime"
+	"syscall"
 	"time"
 
 	"github.com/vertex-center/vertex/config"
@@ -48,20 +48,22 @@ var (
 	r   *router.Router
 	ctx *types.VertexContext
 
-	settingsFSAdapter   port.SettingsAdapter
-	sshKernelApiAdapter port.SshAdapter
-	baselinesApiAdapter port.BaselinesAdapter
+	settingsFSAdapter      port.SettingsAdapter
+	sshKernelApiAdapter    port.SshAdapter
+	baselinesApiAdapter    port.BaselinesAdapter
+	dockerKernelApiAdapter port.DockerKernelAdapter
 
 	appsService          port.AppsService
 	notificationsService service.NotificationsService
 	hardwareService      port.HardwareService
+	readinessService     port.ReadinessService
 	settingsService      port.SettingsService
 	sshService           port.SshService
 	updateService        port.UpdateService
 )
 
 func main() {
-	defer log.Default.Close()
+	defer log.Close()
 
 	log.Info("Vertex starting...")
 
@@ -77,10 +79,13 @@ func main() {
 	gin.SetMode(gin.ReleaseMode)
 	ctx = types.NewVertexContext()
 	r = router.New()
-	r.Use(cors.Default())
+	r.Use(ginutils.CORS())
 	r.Use(ginutils.ErrorHandler())
-	r.Use(ginutils.Logger("MAIN"))
-	r.Use(gin.Recovery())
+	r.Use(ginutils.Logger("MAIN", ginutils.LogFormat(config.Current.LogFormat)))
+	r.Use(ginutils.Recovery())
+	r.Use(ginutils.Compress(ginutils.DefaultCompressConfig()))
+	r.Use(ginutils.MaxBodySize(config.Current.MaxBodyBytes()))
+	r.Use(ginutils.Timeout(config.Current.RequestTimeoutDuration()))
 
 	about := types.About{
 		Version: version,
@@ -95,10 +100,11 @@ func main() {
 	initRoutes(about)
 	handleSignals()
 
-	err = net.Wait("google.com:80")
-	if err != nil {
-		log.Error(err)
-		os.Exit(1)
+	if config.Current.ConnectivityCheck != "" {
+		err = net.Wait(config.Current.ConnectivityCheck)
+		if err != nil {
+			log.Warn("no internet connection, starting in offline mode", vlog.String("reason", err.Error()))
+		}
 	}
 
 	ctx.DispatchEvent(types.EventServerStart{
@@ -117,29 +123,35 @@ func main() {
 
 func handleSignals() {
 	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-c
 		log.Info("shutdown signal sent")
+		forceQuitOnSignal()
 		stopRouter()
 		os.Exit(0)
 	}()
 }
 
+// forceQuitOnSignal exits immediately if another signal is received while
+// shutting down, in case the graceful shutdown hangs.
+func forceQuitOnSignal() {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-c
+		log.Warn("second shutdown signal received, forcing exit")
+		os.Exit(1)
+	}()
+}
+
 func parseArgs() {
 	flagVersion := flag.Bool("version", false, "Print vertex version")
 	flagV := flag.Bool("v", false, "Print vertex version")
 	flagDate := flag.Bool("date", false, "Print the release date")
 	flagCommit := flag.Bool("commit", false, "Print the commit hash")
 
-	var (
-		flagHost = flag.String("host", config.Current.Host, "The Vertex access url")
-
-		flagPort           = flag.String("port", config.Current.Port, "The Vertex port")
-		flagPortKernel     = flag.String("port-kernel", config.Current.PortKernel, "The Vertex Kernel port")
-		flagPortProxy      = flag.String("port-proxy", config.Current.PortProxy, "The Vertex Proxy port")
-		flagPortPrometheus = flag.String("port-prometheus", config.Current.PortPrometheus, "The Prometheus port")
-	)
+	configFlags := config.Current.RegisterFlags(flag.CommandLine)
 
 	flag.Parse()
 
@@ -155,11 +167,26 @@ func parseArgs() {
 		fmt.Println(commit)
 		os.Exit(0)
 	}
-	config.Current.Host = *flagHost
-	config.Current.Port = *flagPort
-	config.Current.PortKernel = *flagPortKernel
-	config.Current.PortProxy = *flagPortProxy
-	config.Current.PortPrometheus = *flagPortPrometheus
+
+	err := configFlags.Apply(&config.Current)
+	if err != nil {
+		log.Error(err)
+		os.Exit(1)
+	}
+
+	log.SetLevel(config.Current.MinLogLevel())
+
+	if config.Current.LogFile != "" {
+		err = log.UseFile(log.FileOptions{
+			Path:    config.Current.LogFile,
+			MaxSize: config.Current.LogMaxSizeBytes(),
+			MaxAge:  config.Current.LogMaxAgeDuration(),
+		})
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+	}
 }
 
 func checkNotRoot() {
@@ -172,9 +199,13 @@ func initAdapters() {
 	settingsFSAdapter = adapter2.NewSettingsFSAdapter(nil)
 	sshKernelApiAdapter = adapter2.NewSshKernelApiAdapter()
 	baselinesApiAdapter = adapter2.NewBaselinesApiAdapter()
+	dockerKernelApiAdapter = adapter2.NewDockerKernelApiAdapter()
 }
 
 func initServices(about types.About) {
+	// The readiness service must listen to the events from the start.
+	readinessService = service.NewReadinessService(ctx, dockerKernelApiAdapter)
+
 	// Update service must be initialized before all other services, because it
 	// is responsible for downloading dependencies for other services.
 	updateService = service.NewUpdateService(ctx, baselinesApiAdapter, []types.Updater{
@@ -192,7 +223,7 @@ func initServices(about types.About) {
 		},
 	)
 	notificationsService = service.NewNotificationsService(ctx, settingsFSAdapter)
-	settingsService = service.NewSettingsService(settingsFSAdapter)
+	settingsService = service.NewSettingsService(ctx, settingsFSAdapter)
 	//services.NewSetupService(r.ctx)
 	hardwareService = service.NewHardwareService()
 	sshService = service.NewSshService(sshKernelApiAdapter)
@@ -207,15 +238,26 @@ func initRoutes(about types.About) {
 	})
 
 	api := r.Group("/api")
+	if config.Current.RedactAbout() {
+		about = about.Redacted()
+	}
 	api.GET("/about", func(c *router.Context) {
 		c.JSON(about)
 	})
 
+	healthHandler := handler.NewHealthHandler(readinessService)
+	api.GET("/health", healthHandler.Get)
+
 	if config.Current.Debug() {
 		api.POST("/hard-reset", func(c *router.Context) {
 			ctx.DispatchEvent(types.EventServerHardReset{})
 			c.OK()
 		})
+
+		configHandler := handler.NewConfigHandler()
+		api.GET("/config", configHandler.Get)
+
+		ginutils.Debug(r.Engine)
 	}
 
 	appsHandler := handler.NewAppsHandler(appsService)
@@ -230,11 +272,18 @@ func initRoutes(about types.About) {
 	update := api.Group("/update")
 	update.GET("", updateHandler.Get)
 	update.POST("", updateHandler.Install)
+	update.GET("/channels", updateHandler.GetChannels)
+	update.GET("/updaters/:updater_id/versions", updateHandler.GetVersions)
+	update.POST("/updaters/:updater_id/install", updateHandler.InstallVersion)
 
 	settingsHandler := handler.NewSettingsHandler(settingsService)
 	settings := api.Group("/settings")
 	settings.GET("", settingsHandler.Get)
 	settings.PATCH("", settingsHandler.Patch)
+	settings.GET("/export", settingsHandler.Export)
+	settings.POST("/import", settingsHandler.Import)
+	settings.GET("/custom/:namespace/:key", settingsHandler.GetCustom)
+	settings.PUT("/custom/:namespace/:key", settingsHandler.SetCustom)
 
 	sshHandler := handler.NewSshHandler(sshService)
 	ssh := api.Group("/security/ssh")
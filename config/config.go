@@ -4,10 +4,16 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/docker/go-units"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vertex/pkg/net"
 	"github.com/vertex-center/vertex/pkg/storage"
+	"github.com/vertex-center/vlog"
 )
 
 const urlFormat = "http://%s:%s"
@@ -24,12 +30,99 @@ const (
 type Config struct {
 	mode Mode
 
-	Host string `json:"host"`
+	Host string `json:"host" yaml:"host"`
 
-	Port           string `json:"port"`
-	PortKernel     string `json:"port_kernel"`
-	PortProxy      string `json:"port_proxy"`
-	PortPrometheus string `json:"port_prometheus"`
+	// HostKernel is the address the Vertex Kernel listens on. The kernel
+	// runs as root, so it only listens on localhost by default.
+	HostKernel string `json:"host_kernel" yaml:"host_kernel"`
+
+	Port           string `json:"port" yaml:"port"`
+	PortKernel     string `json:"port_kernel" yaml:"port_kernel"`
+	PortProxy      string `json:"port_proxy" yaml:"port_proxy"`
+	PortPrometheus string `json:"port_prometheus" yaml:"port_prometheus"`
+
+	// ProxyBaseDomain is the domain under which the containers are
+	// registered in the reverse proxy, like example.com for
+	// my-blog.example.com. An empty value disables the auto-registration.
+	ProxyBaseDomain string `json:"proxy_base_domain" yaml:"proxy_base_domain"`
+
+	// ProxyFallbackURL is the upstream where the reverse proxy sends the
+	// requests to the hosts without a redirect. If empty, ProxyFallbackPage
+	// is served instead.
+	ProxyFallbackURL string `json:"proxy_fallback_url" yaml:"proxy_fallback_url"`
+
+	// ProxyFallbackPage is the path of an HTML page served with a 404 to the
+	// hosts without a redirect. The page is a Go template, where {{.Host}}
+	// is the requested host. If empty, a JSON error is returned.
+	ProxyFallbackPage string `json:"proxy_fallback_page" yaml:"proxy_fallback_page"`
+
+	// ConnectivityCheck is the address pinged to check the internet
+	// connection at startup. An empty value disables the check.
+	ConnectivityCheck string `json:"connectivity_check" yaml:"connectivity_check"`
+
+	// BaselinesURL is the URL where the baselines of the dependencies are
+	// downloaded from. It can be set to a mirror.
+	BaselinesURL string `json:"baselines_url" yaml:"baselines_url"`
+
+	// GitURL is the URL of the Git host where the dependency repositories
+	// are cloned from. It can be set to a mirror.
+	GitURL string `json:"git_url" yaml:"git_url"`
+
+	// LogFormat is the format of the access logs of the routers, either
+	// "text" or "json".
+	LogFormat string `json:"log_format" yaml:"log_format"`
+
+	// LogLevel is the minimum level of the logs, either "debug", "info",
+	// "warn" or "error".
+	LogLevel string `json:"log_level" yaml:"log_level"`
+
+	// LogFile is the path of the file where Vertex writes its logs, rotated
+	// each day and after LogMaxSize. An empty value keeps the daily log
+	// files in live/logs.
+	LogFile string `json:"log_file" yaml:"log_file"`
+
+	// LogMaxSize is the size after which the LogFile is rotated, like 10MB.
+	// 0 only rotates it each day.
+	LogMaxSize string `json:"log_max_size" yaml:"log_max_size"`
+
+	// LogMaxAge is how long the rotated log files are kept, as a duration
+	// like 168h. 0 keeps them forever.
+	LogMaxAge string `json:"log_max_age" yaml:"log_max_age"`
+
+	// PublicAbout is the build information returned by /about, either
+	// "full" or "version". The version level hides the commit, the OS and
+	// the architecture from the internet-facing deployments.
+	PublicAbout string `json:"public_about" yaml:"public_about"`
+
+	// RegistryCacheTTL is how long the tags and the digests fetched from
+	// the image registries are cached, as a duration like 10m. 0 disables
+	// the cache.
+	RegistryCacheTTL string `json:"registry_cache_ttl" yaml:"registry_cache_ttl"`
+
+	// MaxConcurrentBuilds is how many images can be built or pulled at the
+	// same time by the containers. The others are queued. 0 disables the
+	// limit.
+	MaxConcurrentBuilds string `json:"max_concurrent_builds" yaml:"max_concurrent_builds"`
+
+	// DockerHubUsername and DockerHubToken authenticate the pulls and the
+	// registry queries of the Docker Hub images, which get a higher rate
+	// limit than the anonymous ones. The other registries are not affected.
+	DockerHubUsername string `json:"docker_hub_username" yaml:"docker_hub_username"`
+	DockerHubToken    string `json:"docker_hub_token" yaml:"docker_hub_token" secret:"true"`
+
+	// MaxBodySize is the maximum size of the request bodies of the API, like
+	// 1MB. The larger requests are rejected with a 413. 0 disables the limit.
+	MaxBodySize string `json:"max_body_size" yaml:"max_body_size"`
+
+	// RequestTimeout is how long the requests to the API can take, as a
+	// duration like 1m. The streaming requests are not limited. 0 disables
+	// the timeout.
+	RequestTimeout string `json:"request_timeout" yaml:"request_timeout"`
+
+	// Apps is the comma-separated list of the IDs of the apps to enable,
+	// like "vx-containers,vx-reverse-proxy". An empty value enables all the
+	// apps. The other apps rely on vx-containers.
+	Apps string `json:"apps" yaml:"apps"`
 }
 
 func New() Config {
@@ -42,17 +135,34 @@ func New() Config {
 	c := Config{
 		mode: ProductionMode,
 
-		Host: host,
+		Host:       host,
+		HostKernel: "127.0.0.1",
 
 		Port:           "6130",
 		PortKernel:     "6131",
 		PortProxy:      "80",
 		PortPrometheus: "2112",
+
+		ConnectivityCheck: "google.com:80",
+		BaselinesURL:      "https://bl.vx.quentinguidee.dev/",
+		GitURL:            "https://github.com",
+
+		LogFormat:        "text",
+		LogLevel:         "info",
+		LogMaxSize:       "10MB",
+		LogMaxAge:        "168h",
+		PublicAbout:      "full",
+		RegistryCacheTTL: "10m",
+		MaxBodySize:      "1MB",
+		RequestTimeout:   "1m",
+
+		MaxConcurrentBuilds: "2",
 	}
 
 	if os.Getenv("DEBUG") == "1" {
 		log.Warn("debug mode enabled. proceed with caution!")
 		c.mode = DebugMode
+		c.LogLevel = "debug"
 	}
 
 	return c
@@ -63,7 +173,7 @@ func (c Config) VertexURL() string {
 }
 
 func (c Config) KernelURL() string {
-	return fmt.Sprintf(urlFormat, c.Host, c.PortKernel)
+	return fmt.Sprintf(urlFormat, c.HostKernel, c.PortKernel)
 }
 
 func (c Config) ProxyURL() string {
@@ -74,7 +184,120 @@ func (c Config) Debug() bool {
 	return c.mode == DebugMode
 }
 
+// RedactAbout returns true if /about must only return the version.
+func (c Config) RedactAbout() bool {
+	return c.PublicAbout == "version"
+}
+
+// RegistryCacheDuration returns the parsed RegistryCacheTTL. An invalid value
+// falls back to 10 minutes.
+func (c Config) RegistryCacheDuration() time.Duration {
+	d, err := time.ParseDuration(c.RegistryCacheTTL)
+	if err != nil {
+		log.Warn("invalid registry cache ttl, using 10m", vlog.String("ttl", c.RegistryCacheTTL))
+		return 10 * time.Minute
+	}
+	return d
+}
+
+// MaxConcurrentBuildsCount returns the parsed MaxConcurrentBuilds. An invalid
+// value falls back to 2.
+func (c Config) MaxConcurrentBuildsCount() int {
+	n, err := strconv.Atoi(c.MaxConcurrentBuilds)
+	if err != nil || n < 0 {
+		log.Warn("invalid max concurrent builds, using 2", vlog.String("builds", c.MaxConcurrentBuilds))
+		return 2
+	}
+	return n
+}
+
+// MinLogLevel returns the parsed LogLevel. An invalid value falls back to
+// the info level.
+func (c Config) MinLogLevel() log.Level {
+	level, err := log.ParseLevel(c.LogLevel)
+	if err != nil {
+		log.Warn("invalid log level, using info", vlog.String("level", c.LogLevel))
+		return log.LevelInfo
+	}
+	return level
+}
+
+// LogMaxSizeBytes returns the parsed LogMaxSize. An invalid value falls back
+// to 10MB.
+func (c Config) LogMaxSizeBytes() int64 {
+	size, err := units.RAMInBytes(c.LogMaxSize)
+	if err != nil {
+		log.Warn("invalid log max size, using 10MB", vlog.String("size", c.LogMaxSize))
+		return 10 * units.MiB
+	}
+	return size
+}
+
+// LogMaxAgeDuration returns the parsed LogMaxAge. An invalid value falls
+// back to 7 days.
+func (c Config) LogMaxAgeDuration() time.Duration {
+	d, err := time.ParseDuration(c.LogMaxAge)
+	if err != nil {
+		log.Warn("invalid log max age, using 168h", vlog.String("age", c.LogMaxAge))
+		return 7 * 24 * time.Hour
+	}
+	return d
+}
+
+// RequestTimeoutDuration returns the parsed RequestTimeout. An invalid value
+// falls back to 1 minute.
+func (c Config) RequestTimeoutDuration() time.Duration {
+	d, err := time.ParseDuration(c.RequestTimeout)
+	if err != nil {
+		log.Warn("invalid request timeout, using 1m", vlog.String("timeout", c.RequestTimeout))
+		return time.Minute
+	}
+	return d
+}
+
+// MaxBodyBytes returns the parsed MaxBodySize. An invalid value falls back
+// to 1MB.
+func (c Config) MaxBodyBytes() int64 {
+	size, err := units.RAMInBytes(c.MaxBodySize)
+	if err != nil {
+		log.Warn("invalid max body size, using 1MB", vlog.String("size", c.MaxBodySize))
+		return units.MiB
+	}
+	return size
+}
+
+// AppEnabled returns true if the app with the given ID is enabled.
+func (c Config) AppEnabled(id string) bool {
+	if strings.TrimSpace(c.Apps) == "" {
+		return true
+	}
+	for _, app := range strings.Split(c.Apps, ",") {
+		if strings.TrimSpace(app) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// HasDockerHubAuth returns true if the Docker Hub credentials are set.
+func (c Config) HasDockerHubAuth() bool {
+	return c.DockerHubUsername != "" && c.DockerHubToken != ""
+}
+
 func (c Config) Apply() error {
 	configJsContent := fmt.Sprintf("window.apiURL = \"%s\";", c.VertexURL())
 	return os.WriteFile(path.Join(storage.Path, "client", "dist", "config.js"), []byte(configJsContent), os.ModePerm)
 }
+
+// Redacted returns a copy of the configuration that can be shown to the
+// user. The fields tagged with `secret:"true"` are emptied.
+func (c Config) Redacted() Config {
+	v := reflect.ValueOf(&c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("secret") == "true" && v.Field(i).CanSet() {
+			v.Field(i).Set(reflect.Zero(t.Field(i).Type))
+		}
+	}
+	return c
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// EnvPrefix is the prefix of the environment variables that override the
+// configuration, like VERTEX_PORT or VERTEX_PORT_PROXY.
+const EnvPrefix = "VERTEX_"
+
+// LoadEnv overrides the configuration with the environment variables that
+// are set.
+func (c *Config) LoadEnv() {
+	for name, field := range c.fields() {
+		value, ok := os.LookupEnv(envName(name))
+		if ok {
+			*field = value
+		}
+	}
+}
+
+// envName returns the environment variable of a field, from its flag name.
+func envName(name string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigPathEnv is the environment variable that can be used instead of the
+// -config flag to set the path of the configuration file.
+const ConfigPathEnv = "VERTEX_CONFIG"
+
+// LoadFile overrides the configuration with the values of a JSON or YAML
+// file. The fields missing from the file are left unchanged.
+func (c *Config) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch filepath.Ext(path) {
+	case ".json":
+		err = json.Unmarshal(data, c)
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(data, c)
+	default:
+		return fmt.Errorf("unsupported config file format: %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"flag"
+	"os"
+)
+
+// Flags are the command line flags that override the configuration.
+type Flags struct {
+	fs     *flag.FlagSet
+	path   *string
+	values map[string]*string
+}
+
+// RegisterFlags adds the configuration flags to fs. The flags take
+// precedence over the environment variables, then the configuration file,
+// then the defaults.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{
+		fs:     fs,
+		path:   fs.String("config", "", "The path of the JSON or YAML configuration file"),
+		values: map[string]*string{},
+	}
+
+	usages := map[string]string{
+		"host":            "The Vertex access url",
+		"host-kernel":     "The Vertex Kernel bind address",
+		"port":            "The Vertex port",
+		"port-kernel":     "The Vertex Kernel port",
+		"port-proxy":      "The Vertex Proxy port",
+		"port-prometheus": "The Prometheus port",
+
+		"proxy-base-domain":   "The domain of the containers registered in the reverse proxy, or empty to disable",
+		"proxy-fallback-url":  "The upstream of the hosts without a redirect in the reverse proxy",
+		"proxy-fallback-page": "The HTML page served to the hosts without a redirect in the reverse proxy",
+		"apps":                "The comma-separated IDs of the apps to enable, or empty to enable all",
+
+		"connectivity-check": "The address pinged to check the internet connection, or empty to disable",
+		"baselines-url":      "The URL of the dependency baselines, or of a mirror",
+		"git-url":            "The URL of the Git host of the dependencies, or of a mirror",
+
+		"log-format":         "The format of the access logs, text or json",
+		"log-level":          "The minimum level of the logs, debug, info, warn or error",
+		"log-file":           "The file where the logs are written and rotated, or empty for the daily files",
+		"log-max-size":       "The size after which the log file is rotated, like 10MB, or 0 to rotate daily only",
+		"log-max-age":        "How long the rotated log files are kept, like 168h, or 0 to keep them",
+		"public-about":       "The build information shown by /about, full or version",
+		"registry-cache-ttl": "How long the image tags and digests are cached, like 10m, or 0 to disable",
+		"max-body-size":      "The maximum size of the request bodies of the API, like 1MB, or 0 to disable",
+		"request-timeout":    "How long the requests to the API can take, like 1m, or 0 to disable",
+
+		"max-concurrent-builds": "How many images can be built or pulled at the same time, or 0 for no limit",
+
+		"docker-hub-username": "The Docker Hub username used to pull the Docker Hub images",
+		"docker-hub-token":    "The Docker Hub access token used to pull the Docker Hub images",
+	}
+
+	for name, field := range c.fields() {
+		usage := usages[name] + " (env " + envName(name) + ")"
+		f.values[name] = fs.String(name, *field, usage)
+	}
+	return f
+}
+
+// Path returns the path of the configuration file, from the -config flag
+// or the VERTEX_CONFIG environment variable. It is empty if there is none.
+func (f *Flags) Path() string {
+	if *f.path != "" {
+		return *f.path
+	}
+	return os.Getenv(ConfigPathEnv)
+}
+
+// Apply loads the configuration file, from the -config flag or the
+// VERTEX_CONFIG environment variable, the environment variables, and then
+// the flags that were set. It must be called after fs is parsed.
+func (f *Flags) Apply(c *Config) error {
+	path := f.Path()
+	if path != "" {
+		err := c.LoadFile(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.LoadEnv()
+
+	fields := c.fields()
+	f.fs.Visit(func(fl *flag.Flag) {
+		if field, ok := fields[fl.Name]; ok {
+			*field = *f.values[fl.Name]
+		}
+	})
+	return nil
+}
+
+// fields returns the configurable fields of the configuration, by flag name.
+func (c *Config) fields() map[string]*string {
+	return map[string]*string{
+		"host":            &c.Host,
+		"host-kernel":     &c.HostKernel,
+		"port":            &c.Port,
+		"port-kernel":     &c.PortKernel,
+		"port-proxy":      &c.PortProxy,
+		"port-prometheus": &c.PortPrometheus,
+
+		"proxy-base-domain":   &c.ProxyBaseDomain,
+		"proxy-fallback-url":  &c.ProxyFallbackURL,
+		"proxy-fallback-page": &c.ProxyFallbackPage,
+		"apps":                &c.Apps,
+
+		"connectivity-check": &c.ConnectivityCheck,
+		"baselines-url":      &c.BaselinesURL,
+		"git-url":            &c.GitURL,
+
+		"log-format":         &c.LogFormat,
+		"log-level":          &c.LogLevel,
+		"log-file":           &c.LogFile,
+		"log-max-size":       &c.LogMaxSize,
+		"log-max-age":        &c.LogMaxAge,
+		"public-about":       &c.PublicAbout,
+		"registry-cache-ttl": &c.RegistryCacheTTL,
+		"max-body-size":      &c.MaxBodySize,
+		"request-timeout":    &c.RequestTimeout,
+
+		"max-concurrent-builds": &c.MaxConcurrentBuilds,
+
+		"docker-hub-username": &c.DockerHubUsername,
+		"docker-hub-token":    &c.DockerHubToken,
+	}
+}
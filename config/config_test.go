@@ -1,9 +1,14 @@
 package config
 
 import (
+	"flag"
+	"os"
+	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/pkg/log"
 )
 
 type ConfigTestSuite struct {
@@ -25,4 +30,159 @@ func (suite *ConfigTestSuite) TestNewDebug() {
 	cfg := New()
 
 	suite.Equal(DebugMode, cfg.mode)
+	suite.Equal(log.LevelDebug, cfg.MinLogLevel())
+}
+
+func (suite *ConfigTestSuite) TestAppEnabled() {
+	cfg := New()
+	suite.True(cfg.AppEnabled("vx-sql"))
+
+	cfg.Apps = "vx-containers, vx-reverse-proxy"
+	suite.True(cfg.AppEnabled("vx-containers"))
+	suite.True(cfg.AppEnabled("vx-reverse-proxy"))
+	suite.False(cfg.AppEnabled("vx-sql"))
+}
+
+func (suite *ConfigTestSuite) TestMinLogLevel() {
+	cfg := New()
+	suite.Equal(log.LevelInfo, cfg.MinLogLevel())
+
+	cfg.LogLevel = "warn"
+	suite.Equal(log.LevelWarn, cfg.MinLogLevel())
+
+	cfg.LogLevel = "invalid"
+	suite.Equal(log.LevelInfo, cfg.MinLogLevel())
+}
+
+func (suite *ConfigTestSuite) TestLogRotation() {
+	cfg := New()
+	suite.Equal(int64(10*1024*1024), cfg.LogMaxSizeBytes())
+	suite.Equal(7*24*time.Hour, cfg.LogMaxAgeDuration())
+
+	cfg.LogMaxSize = "invalid"
+	cfg.LogMaxAge = "invalid"
+	suite.Equal(int64(10*1024*1024), cfg.LogMaxSizeBytes())
+	suite.Equal(7*24*time.Hour, cfg.LogMaxAgeDuration())
+}
+
+func (suite *ConfigTestSuite) TestRequestTimeoutDuration() {
+	cfg := New()
+	suite.Equal(time.Minute, cfg.RequestTimeoutDuration())
+
+	cfg.RequestTimeout = "0"
+	suite.Equal(time.Duration(0), cfg.RequestTimeoutDuration())
+
+	cfg.RequestTimeout = "invalid"
+	suite.Equal(time.Minute, cfg.RequestTimeoutDuration())
+}
+
+func (suite *ConfigTestSuite) TestMaxConcurrentBuildsCount() {
+	cfg := New()
+	suite.Equal(2, cfg.MaxConcurrentBuildsCount())
+
+	cfg.MaxConcurrentBuilds = "0"
+	suite.Equal(0, cfg.MaxConcurrentBuildsCount())
+
+	cfg.MaxConcurrentBuilds = "-1"
+	suite.Equal(2, cfg.MaxConcurrentBuildsCount())
+
+	cfg.MaxConcurrentBuilds = "invalid"
+	suite.Equal(2, cfg.MaxConcurrentBuildsCount())
+}
+
+func (suite *ConfigTestSuite) TestMaxBodyBytes() {
+	cfg := New()
+	suite.Equal(int64(1024*1024), cfg.MaxBodyBytes())
+
+	cfg.MaxBodySize = "0"
+	suite.Equal(int64(0), cfg.MaxBodyBytes())
+
+	cfg.MaxBodySize = "invalid"
+	suite.Equal(int64(1024*1024), cfg.MaxBodyBytes())
+}
+
+func (suite *ConfigTestSuite) TestKernelURL() {
+	cfg := New()
+	suite.Equal("http://127.0.0.1:6131", cfg.KernelURL())
+
+	cfg.HostKernel = "192.168.1.10"
+	cfg.PortKernel = "7000"
+	suite.Equal("http://192.168.1.10:7000", cfg.KernelURL())
+}
+
+func (suite *ConfigTestSuite) TestLoadFile() {
+	dir := suite.T().TempDir()
+
+	jsonPath := path.Join(dir, "config.json")
+	err := os.WriteFile(jsonPath, []byte(`{"port": "7000"}`), 0644)
+	suite.Require().NoError(err)
+
+	yamlPath := path.Join(dir, "config.yml")
+	err = os.WriteFile(yamlPath, []byte("port_proxy: \"8080\"\n"), 0644)
+	suite.Require().NoError(err)
+
+	cfg := New()
+	suite.Require().NoError(cfg.LoadFile(jsonPath))
+	suite.Require().NoError(cfg.LoadFile(yamlPath))
+	suite.Equal("7000", cfg.Port)
+	suite.Equal("8080", cfg.PortProxy)
+	suite.Equal("6131", cfg.PortKernel)
+
+	suite.Error(cfg.LoadFile(path.Join(dir, "config.toml")))
+}
+
+func (suite *ConfigTestSuite) TestFlagsPrecedence() {
+	configPath := path.Join(suite.T().TempDir(), "config.json")
+	err := os.WriteFile(configPath, []byte(`{"port": "7000", "port_proxy": "8080"}`), 0644)
+	suite.Require().NoError(err)
+
+	cfg := New()
+	fs := flag.NewFlagSet("vertex", flag.ContinueOnError)
+	flags := cfg.RegisterFlags(fs)
+
+	err = fs.Parse([]string{"-config", configPath, "-port", "7001"})
+	suite.Require().NoError(err)
+	suite.Require().NoError(flags.Apply(&cfg))
+	suite.Equal(configPath, flags.Path())
+
+	suite.Equal("7001", cfg.Port)
+	suite.Equal("8080", cfg.PortProxy)
+	suite.Equal("6131", cfg.PortKernel)
+}
+
+func (suite *ConfigTestSuite) TestEnvPrecedence() {
+	configPath := path.Join(suite.T().TempDir(), "config.json")
+	err := os.WriteFile(configPath, []byte(`{"port": "7000", "port_proxy": "8080"}`), 0644)
+	suite.Require().NoError(err)
+
+	suite.T().Setenv("VERTEX_CONFIG", configPath)
+	suite.T().Setenv("VERTEX_PORT_PROXY", "8081")
+	suite.T().Setenv("VERTEX_PORT_KERNEL", "7002")
+
+	cfg := New()
+	fs := flag.NewFlagSet("vertex", flag.ContinueOnError)
+	flags := cfg.RegisterFlags(fs)
+
+	err = fs.Parse([]string{"-port-kernel", "7003"})
+	suite.Require().NoError(err)
+	suite.Require().NoError(flags.Apply(&cfg))
+	suite.Equal(configPath, flags.Path())
+
+	suite.Equal("7000", cfg.Port)
+	suite.Equal("8081", cfg.PortProxy)
+	suite.Equal("7003", cfg.PortKernel)
+}
+
+func (suite *ConfigTestSuite) TestRedacted() {
+	cfg := New()
+	cfg.Port = "7000"
+
+	redacted := cfg.Redacted()
+	suite.Equal(cfg, redacted)
+
+	cfg.DockerHubUsername = "user"
+	cfg.DockerHubToken = "token"
+	redacted = cfg.Redacted()
+	suite.Equal("user", redacted.DockerHubUsername)
+	suite.Empty(redacted.DockerHubToken)
 }
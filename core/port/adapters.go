@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"encoding/json"
 	types2 "github.com/docker/docker/api/types"
 	"github.com/vertex-center/vertex/core/types"
 	"io"
@@ -15,26 +16,43 @@ type (
 	}
 
 	DockerAdapter interface {
-		ListContainers() ([]types.Container, error)
+		Ping() error
+		ListContainers(options types.ListContainersOptions) ([]types.Container, error)
 		DeleteContainer(id string) error
 		CreateContainer(options types.CreateContainerOptions) (types.CreateContainerResponse, error)
 		StartContainer(id string) error
 		StopContainer(id string) error
+		PauseContainer(id string) error
+		UnpauseContainer(id string) error
 		InfoContainer(id string) (types.InfoContainerResponse, error)
-		LogsStdoutContainer(id string) (io.ReadCloser, error)
-		LogsStderrContainer(id string) (io.ReadCloser, error)
+		LogsStdoutContainer(id string, options types.LogsContainerOptions) (io.ReadCloser, error)
+		LogsStderrContainer(id string, options types.LogsContainerOptions) (io.ReadCloser, error)
 		WaitContainer(id string, cond types.WaitContainerCondition) error
+		CopyToContainer(id string, dir string, archive io.Reader) error
 		InfoImage(id string) (types.InfoImageResponse, error)
-		PullImage(options types.PullImageOptions) (io.ReadCloser, error)
-		BuildImage(options types.BuildImageOptions) (types2.ImageBuildResponse, error)
+		PullImage(ctx context.Context, options types.PullImageOptions) (io.ReadCloser, error)
+		BuildImage(ctx context.Context, options types.BuildImageOptions) (types2.ImageBuildResponse, error)
+	}
+
+	DockerKernelAdapter interface {
+		// Ping checks that the kernel can reach the Docker daemon.
+		Ping() error
 	}
 
 	SettingsAdapter interface {
 		GetSettings() types.Settings
+		// SetSettings replaces all the settings at once.
+		SetSettings(settings types.Settings) error
 		GetNotificationsWebhook() *string
 		SetNotificationsWebhook(webhook string) error
 		GetChannel() *types.SettingsUpdatesChannel
 		SetChannel(channel types.SettingsUpdatesChannel) error
+		// GetCustom returns the raw value of a custom setting, or
+		// ErrCustomSettingNotFound if it is not set.
+		GetCustom(namespace string, key string) (json.RawMessage, error)
+		// SetCustom sets the raw value of a custom setting. A nil
+		// value removes it.
+		SetCustom(namespace string, key string, value json.RawMessage) error
 	}
 
 	SshAdapter interface {
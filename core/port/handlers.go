@@ -8,6 +8,16 @@ type (
 		Get(c *router.Context)
 	}
 
+	ConfigHandler interface {
+		// Get handles the retrieval of the effective configuration.
+		Get(c *router.Context)
+	}
+
+	HealthHandler interface {
+		// Get handles the retrieval of the readiness of Vertex.
+		Get(c *router.Context)
+	}
+
 	HardwareHandler interface {
 		// Get handles the retrieval of the current hardware.
 		Get(c *router.Context)
@@ -18,6 +28,12 @@ type (
 		Get(c *router.Context)
 		// Install handles the installation of the update.
 		Install(c *router.Context)
+		// GetChannels handles the retrieval of the versions available on each update channel.
+		GetChannels(c *router.Context)
+		// GetVersions handles the retrieval of the versions available for an updater.
+		GetVersions(c *router.Context)
+		// InstallVersion handles the installation of a specific version of an updater.
+		InstallVersion(c *router.Context)
 	}
 
 	SettingsHandler interface {
@@ -25,6 +41,14 @@ type (
 		Get(c *router.Context)
 		// Patch handles the update of all settings.
 		Patch(c *router.Context)
+		// Export handles the download of all settings as a JSON file.
+		Export(c *router.Context)
+		// Import handles the replacement of all settings by an export.
+		Import(c *router.Context)
+		// GetCustom handles the retrieval of a custom setting of an app.
+		GetCustom(c *router.Context)
+		// SetCustom handles the update of a custom setting of an app.
+		SetCustom(c *router.Context)
 	}
 
 	SshHandler interface {
@@ -37,6 +61,8 @@ type (
 	}
 
 	DockerKernelHandler interface {
+		// Ping handles the check of the connection to the Docker daemon.
+		Ping(c *router.Context)
 		// GetContainers handles the retrieval of all Docker containers.
 		GetContainers(c *router.Context)
 		// CreateContainer handles the creation of a Docker container.
@@ -47,6 +73,10 @@ type (
 		StartContainer(c *router.Context)
 		// StopContainer handles the stopping of a Docker container.
 		StopContainer(c *router.Context)
+		// PauseContainer handles the pausing of a Docker container.
+		PauseContainer(c *router.Context)
+		// UnpauseContainer handles the unpausing of a Docker container.
+		UnpauseContainer(c *router.Context)
 		// InfoContainer handles the retrieval of information about a Docker container.
 		InfoContainer(c *router.Context)
 		// LogsStdoutContainer handles the retrieval of the stdout logs of a Docker container.
@@ -55,6 +85,8 @@ type (
 		LogsStderrContainer(c *router.Context)
 		// WaitContainer handles the waiting for a Docker container to reach a certain condition.
 		WaitContainer(c *router.Context)
+		// CopyToContainer handles the copy of a tar archive into a Docker container.
+		CopyToContainer(c *router.Context)
 		// InfoImage handles the retrieval of information about a Docker image.
 		InfoImage(c *router.Context)
 		// PullImage handles the pulling of a Docker image.
@@ -1,6 +1,8 @@
 package port
 
 import (
+	"context"
+
 	dockertypes "github.com/docker/docker/api/types"
 	"github.com/vertex-center/vertex/core/types"
 	"github.com/vertex-center/vertex/core/types/app"
@@ -9,35 +11,55 @@ import (
 
 type (
 	AppsService interface {
-		All() []app.Meta
+		// All returns the status of every app, including the disabled apps
+		// and the apps that failed to initialize.
+		All() []app.Status
 	}
 
 	DockerService interface {
-		ListContainers() ([]types.Container, error)
+		Ping() error
+		ListContainers(options types.ListContainersOptions) ([]types.Container, error)
 		DeleteContainer(id string) error
 		CreateContainer(options types.CreateContainerOptions) (types.CreateContainerResponse, error)
 		StartContainer(id string) error
 		StopContainer(id string) error
+		PauseContainer(id string) error
+		UnpauseContainer(id string) error
 		InfoContainer(id string) (types.InfoContainerResponse, error)
-		LogsStdoutContainer(id string) (io.ReadCloser, error)
-		LogsStderrContainer(id string) (io.ReadCloser, error)
+		LogsStdoutContainer(id string, options types.LogsContainerOptions) (io.ReadCloser, error)
+		LogsStderrContainer(id string, options types.LogsContainerOptions) (io.ReadCloser, error)
 		WaitContainer(id string, cond types.WaitContainerCondition) error
+		CopyToContainer(id string, dir string, archive io.Reader) error
 		InfoImage(id string) (types.InfoImageResponse, error)
-		PullImage(options types.PullImageOptions) (io.ReadCloser, error)
-		BuildImage(options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error)
+		PullImage(ctx context.Context, options types.PullImageOptions) (io.ReadCloser, error)
+		BuildImage(ctx context.Context, options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error)
 	}
 
 	HardwareService interface {
 		Get() types.Hardware
 	}
 
+	ReadinessService interface {
+		// Get returns whether Vertex is ready to serve the client, and
+		// the state of each check.
+		Get() types.Readiness
+	}
+
 	SettingsService interface {
 		Get() types.Settings
 		Update(settings types.Settings) error
+		// Import replaces all the settings with the given ones, after
+		// checking them. Returns ErrInvalidSettings if they are invalid.
+		Import(settings types.Settings) error
 		GetNotificationsWebhook() *string
 		SetNotificationsWebhook(webhook string) error
 		GetChannel() types.SettingsUpdatesChannel
 		SetChannel(channel types.SettingsUpdatesChannel) error
+		// GetCustom decodes a custom setting of an app in value. It returns
+		// ErrCustomSettingNotFound if it is not set.
+		GetCustom(namespace string, key string, value any) error
+		// SetCustom encodes value in a custom setting of an app.
+		SetCustom(namespace string, key string, value any) error
 	}
 
 	SshService interface {
@@ -49,5 +71,8 @@ type (
 	UpdateService interface {
 		GetUpdate(channel types.SettingsUpdatesChannel) (*types.Update, error)
 		InstallLatest(channel types.SettingsUpdatesChannel) error
+		GetChannels() ([]types.UpdaterVersions, error)
+		GetVersions(updaterID string) ([]string, error)
+		InstallVersion(updaterID string, version string) error
 	}
 )
@@ -2,14 +2,19 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/config"
 	"github.com/vertex-center/vertex/core/port"
 	"github.com/vertex-center/vertex/core/types"
 	"github.com/vertex-center/vertex/core/types/app"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vertex/pkg/router"
 	"github.com/vertex-center/vlog"
+	"golang.org/x/exp/slices"
 )
 
 type AppsService struct {
@@ -18,6 +23,9 @@ type AppsService struct {
 	apps     []app.Interface
 	registry *app.AppsRegistry
 	router   *router.Router
+
+	statuses      []app.Status
+	statusesMutex sync.RWMutex
 }
 
 func NewAppsService(ctx *types.VertexContext, router *router.Router, apps []app.Interface) port.AppsService {
@@ -39,48 +47,83 @@ func (s *AppsService) GetUUID() uuid.UUID {
 func (s *AppsService) OnEvent(e interface{}) {
 	switch e.(type) {
 	case types.EventServerStart:
-		s.StartApps()
+		err := s.StartApps()
+		if err != nil {
+			log.Warn("some apps failed to initialize", vlog.String("error", err.Error()))
+		}
 	case types.EventServerStop:
 		s.StopApps()
 	}
 }
 
-func (s *AppsService) StartApps() {
+// StartApps initializes the enabled apps. An app that fails to initialize
+// doesn't stop the others. The errors are returned together, each with the
+// ID of its app, and are listed by All.
+func (s *AppsService) StartApps() error {
 	log.Info("starting apps")
 
-	for i := range s.apps {
-		err := s.startApp(s.apps[i])
-		if err != nil {
-			log.Error(errors.New("failed to initialize app"), vlog.String("error", err.Error()))
+	var errs []error
+	for _, impl := range s.apps {
+		status := s.startApp(impl)
+		if status.Error != "" {
+			errs = append(errs, fmt.Errorf("%s: %s", status.ID, status.Error))
 		}
-	}
 
-	for _, a := range s.registry.Apps() {
-		for route, handle := range a.HttpHandlers() {
-			handle(s.router.Group("/api/app" + route))
-		}
+		s.statusesMutex.Lock()
+		s.statuses = append(s.statuses, status)
+		s.statusesMutex.Unlock()
 	}
+	return errors.Join(errs...)
 }
 
-func (s *AppsService) startApp(impl app.Interface) error {
+// startApp initializes the app if it is enabled, and mounts its routes.
+func (s *AppsService) startApp(impl app.Interface) app.Status {
+	status := app.Status{
+		Meta: app.Meta{ID: impl.ID()},
+	}
+
+	if !config.Current.AppEnabled(impl.ID()) {
+		log.Info("app disabled", vlog.String("id", impl.ID()))
+		return status
+	}
+	status.Enabled = true
+
 	a := app.New(s.ctx)
 	err := s.registry.RegisterApp(a, impl)
+	if a.ID() != "" {
+		status.Meta = a.Meta()
+	}
 	if err != nil {
-		log.Error(errors.New("failed to initialize app"), vlog.String("error", err.Error()))
-		return err
+		log.Error(errors.New("failed to initialize app"),
+			vlog.String("id", impl.ID()),
+			vlog.String("error", err.Error()),
+		)
+		status.Error = err.Error()
+		return status
 	}
+
+	for route, handle := range a.HttpHandlers() {
+		handle(s.router.Group("/api/app" + route))
+		status.Routes = append(status.Routes, "/api/app"+route)
+	}
+	sort.Strings(status.Routes)
+
 	log.Info("app initialized", vlog.String("name", a.Name()))
-	return nil
+	return status
 }
 
+// StopApps uninitializes the apps, in the reverse order of their
+// initialization.
 func (s *AppsService) StopApps() {
-	s.registry.Close()
+	err := s.registry.Close()
+	if err != nil {
+		log.Error(errors.New("failed to uninitialize apps"), vlog.String("error", err.Error()))
+	}
 }
 
-func (s *AppsService) All() []app.Meta {
-	var apps []app.Meta
-	for _, a := range s.registry.Apps() {
-		apps = append(apps, a.App.Meta())
-	}
-	return apps
+func (s *AppsService) All() []app.Status {
+	s.statusesMutex.RLock()
+	defer s.statusesMutex.RUnlock()
+
+	return slices.Clone(s.statuses)
 }
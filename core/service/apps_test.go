@@ -1,9 +1,13 @@
 package service
 
 import (
+	"errors"
+
+	"github.com/vertex-center/vertex/config"
 	"github.com/vertex-center/vertex/core/types"
 	"github.com/vertex-center/vertex/core/types/app"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -22,7 +26,7 @@ func TestAppsServiceTestSuite(t *testing.T) {
 
 func (suite *AppsServiceTestSuite) SetupTest() {
 	ctx := types.NewVertexContext()
-	suite.app = &MockApp{}
+	suite.app = &MockApp{id: "vx-test"}
 	suite.service = NewAppsService(ctx, router.New(), []app.Interface{
 		suite.app,
 	}).(*AppsService)
@@ -32,12 +36,95 @@ func (suite *AppsServiceTestSuite) TestStartApps() {
 	a := app.New(suite.service.ctx)
 
 	suite.app.On("Initialize", a).Return(nil)
-	suite.service.StartApps()
+	err := suite.service.StartApps()
+	suite.NoError(err)
 	suite.app.AssertExpectations(suite.T())
 }
 
+func (suite *AppsServiceTestSuite) TestStartAppsDisabled() {
+	cfg := config.Current
+	defer func() { config.Current = cfg }()
+	config.Current.Apps = "vx-containers"
+
+	err := suite.service.StartApps()
+	suite.NoError(err)
+	suite.app.AssertNotCalled(suite.T(), "Initialize", mock.Anything)
+	suite.Equal([]app.Status{{
+		Meta:    app.Meta{ID: "vx-test"},
+		Enabled: false,
+	}}, suite.service.All())
+}
+
+func (suite *AppsServiceTestSuite) TestStartAppsError() {
+	other := &MockApp{id: "vx-other"}
+	suite.service.apps = append(suite.service.apps, other)
+
+	suite.app.On("Initialize", mock.Anything).Return(errors.New("port already in use"))
+	other.On("Initialize", mock.Anything).Return(nil)
+
+	err := suite.service.StartApps()
+	suite.EqualError(err, "vx-test: port already in use")
+
+	// The failing app doesn't stop the next one.
+	other.AssertExpectations(suite.T())
+
+	statuses := suite.service.All()
+	suite.Require().Len(statuses, 2)
+	suite.Equal("vx-test", statuses[0].ID)
+	suite.True(statuses[0].Enabled)
+	suite.Equal("port already in use", statuses[0].Error)
+	suite.Empty(statuses[0].Routes)
+	suite.Equal("vx-other", statuses[1].ID)
+	suite.Empty(statuses[1].Error)
+}
+
+func (suite *AppsServiceTestSuite) TestStopApps() {
+	timeout := app.UninitializeTimeout
+	defer func() { app.UninitializeTimeout = timeout }()
+	app.UninitializeTimeout = 50 * time.Millisecond
+
+	var stopped []string
+	first := &MockClosableApp{MockApp: MockApp{id: "vx-first"}, stopped: &stopped}
+	second := &MockClosableApp{MockApp: MockApp{id: "vx-second"}, stopped: &stopped}
+	stuck := &MockClosableApp{MockApp: MockApp{id: "vx-stuck"}, stopped: &stopped, stuck: true}
+	suite.service.apps = []app.Interface{first, second, stuck}
+	for _, a := range []*MockClosableApp{first, second, stuck} {
+		a.On("Initialize", mock.Anything).Return(nil)
+	}
+
+	err := suite.service.StartApps()
+	suite.Require().NoError(err)
+
+	err = suite.service.registry.Close()
+	suite.ErrorIs(err, app.ErrUninitializeTimeout)
+	suite.ErrorContains(err, "vx-stuck")
+
+	// The apps stop in the reverse order of their initialization.
+	suite.Equal([]string{"vx-second", "vx-first"}, stopped)
+}
+
+type MockClosableApp struct {
+	MockApp
+	stopped *[]string
+	stuck   bool
+}
+
+func (m *MockClosableApp) Uninitialize() error {
+	if m.stuck {
+		time.Sleep(time.Second)
+		return nil
+	}
+	*m.stopped = append(*m.stopped, m.id)
+	return nil
+}
+
 type MockApp struct {
 	mock.Mock
+	id string
+}
+
+func (m *MockApp) ID() string {
+	return m.id
 }
 
 func (m *MockApp) Initialize(app *app.App) error {
@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"fmt"
 	"github.com/vertex-center/vertex/core/port"
 	"github.com/vertex-center/vertex/core/types"
 	"io"
@@ -20,8 +22,12 @@ func NewDockerKernelService(dockerAdapter port.DockerAdapter) port.DockerService
 	}
 }
 
-func (s DockerKernelService) ListContainers() ([]types.Container, error) {
-	return s.dockerAdapter.ListContainers()
+func (s DockerKernelService) Ping() error {
+	return s.dockerAdapter.Ping()
+}
+
+func (s DockerKernelService) ListContainers(options types.ListContainersOptions) ([]types.Container, error) {
+	return s.dockerAdapter.ListContainers(options)
 }
 
 func (s DockerKernelService) DeleteContainer(id string) error {
@@ -29,6 +35,17 @@ func (s DockerKernelService) DeleteContainer(id string) error {
 }
 
 func (s DockerKernelService) CreateContainer(options types.CreateContainerOptions) (types.CreateContainerResponse, error) {
+	err := types.ValidateSysctls(options.Sysctls)
+	if err != nil {
+		return types.CreateContainerResponse{}, err
+	}
+	err = types.ValidateExtraHosts(options.ExtraHosts)
+	if err != nil {
+		return types.CreateContainerResponse{}, err
+	}
+	if options.ShmSize < 0 {
+		return types.CreateContainerResponse{}, fmt.Errorf("%w: %d must be positive", types.ErrInvalidShmSize, options.ShmSize)
+	}
 	return s.dockerAdapter.CreateContainer(options)
 }
 
@@ -40,32 +57,44 @@ func (s DockerKernelService) StopContainer(id string) error {
 	return s.dockerAdapter.StopContainer(id)
 }
 
+func (s DockerKernelService) PauseContainer(id string) error {
+	return s.dockerAdapter.PauseContainer(id)
+}
+
+func (s DockerKernelService) UnpauseContainer(id string) error {
+	return s.dockerAdapter.UnpauseContainer(id)
+}
+
 func (s DockerKernelService) InfoContainer(id string) (types.InfoContainerResponse, error) {
 	return s.dockerAdapter.InfoContainer(id)
 }
 
-func (s DockerKernelService) LogsStdoutContainer(id string) (io.ReadCloser, error) {
-	return s.dockerAdapter.LogsStdoutContainer(id)
+func (s DockerKernelService) LogsStdoutContainer(id string, options types.LogsContainerOptions) (io.ReadCloser, error) {
+	return s.dockerAdapter.LogsStdoutContainer(id, options)
 }
 
-func (s DockerKernelService) LogsStderrContainer(id string) (io.ReadCloser, error) {
-	return s.dockerAdapter.LogsStderrContainer(id)
+func (s DockerKernelService) LogsStderrContainer(id string, options types.LogsContainerOptions) (io.ReadCloser, error) {
+	return s.dockerAdapter.LogsStderrContainer(id, options)
 }
 
 func (s DockerKernelService) WaitContainer(id string, cond types.WaitContainerCondition) error {
 	return s.dockerAdapter.WaitContainer(id, cond)
 }
 
+func (s DockerKernelService) CopyToContainer(id string, dir string, archive io.Reader) error {
+	return s.dockerAdapter.CopyToContainer(id, dir, archive)
+}
+
 func (s DockerKernelService) InfoImage(id string) (types.InfoImageResponse, error) {
 	return s.dockerAdapter.InfoImage(id)
 }
 
-func (s DockerKernelService) PullImage(options types.PullImageOptions) (io.ReadCloser, error) {
+func (s DockerKernelService) PullImage(ctx context.Context, options types.PullImageOptions) (io.ReadCloser, error) {
 	log.Info("pulling image", vlog.String("image", options.Image))
-	return s.dockerAdapter.PullImage(options)
+	return s.dockerAdapter.PullImage(ctx, options)
 }
 
-func (s DockerKernelService) BuildImage(options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error) {
+func (s DockerKernelService) BuildImage(ctx context.Context, options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error) {
 	log.Info("building image", vlog.String("dockerfile", options.Dockerfile))
-	return s.dockerAdapter.BuildImage(options)
+	return s.dockerAdapter.BuildImage(ctx, options)
 }
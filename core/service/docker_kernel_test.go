@@ -1,8 +1,10 @@
 package service
 
 import (
+	"context"
 	"github.com/vertex-center/vertex/core/types"
 	"io"
+	"strings"
 	"testing"
 
 	dockertypes "github.com/docker/docker/api/types"
@@ -27,10 +29,19 @@ func (suite *DockerKernelServiceTestSuite) SetupSuite() {
 	suite.service = NewDockerKernelService(&suite.adapter).(*DockerKernelService)
 }
 
+func (suite *DockerKernelServiceTestSuite) TestPing() {
+	suite.adapter.On("Ping").Return(nil)
+
+	err := suite.service.Ping()
+
+	suite.NoError(err)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
 func (suite *DockerKernelServiceTestSuite) TestListContainers() {
-	suite.adapter.On("ListContainers").Return([]types.Container{}, nil)
+	suite.adapter.On("ListContainers", mock.Anything).Return([]types.Container{}, nil)
 
-	containers, err := suite.service.ListContainers()
+	containers, err := suite.service.ListContainers(types.ListContainersOptions{})
 
 	suite.NoError(err)
 	suite.Equal([]types.Container{}, containers)
@@ -56,6 +67,14 @@ func (suite *DockerKernelServiceTestSuite) TestCreateContainer() {
 	suite.adapter.AssertExpectations(suite.T())
 }
 
+func (suite *DockerKernelServiceTestSuite) TestCreateContainerSysctlNotAllowed() {
+	_, err := suite.service.CreateContainer(types.CreateContainerOptions{
+		Sysctls: map[string]string{"vm.swappiness": "10"},
+	})
+
+	suite.ErrorIs(err, types.ErrSysctlNotAllowed)
+}
+
 func (suite *DockerKernelServiceTestSuite) TestStartContainer() {
 	suite.adapter.On("StartContainer", mock.Anything).Return(nil)
 
@@ -74,6 +93,24 @@ func (suite *DockerKernelServiceTestSuite) TestStopContainer() {
 	suite.adapter.AssertExpectations(suite.T())
 }
 
+func (suite *DockerKernelServiceTestSuite) TestPauseContainer() {
+	suite.adapter.On("PauseContainer", mock.Anything).Return(nil)
+
+	err := suite.service.PauseContainer("")
+
+	suite.NoError(err)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+func (suite *DockerKernelServiceTestSuite) TestUnpauseContainer() {
+	suite.adapter.On("UnpauseContainer", mock.Anything).Return(nil)
+
+	err := suite.service.UnpauseContainer("")
+
+	suite.NoError(err)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
 func (suite *DockerKernelServiceTestSuite) TestInfoContainer() {
 	suite.adapter.On("InfoContainer", mock.Anything).Return(types.InfoContainerResponse{}, nil)
 
@@ -85,9 +122,9 @@ func (suite *DockerKernelServiceTestSuite) TestInfoContainer() {
 }
 
 func (suite *DockerKernelServiceTestSuite) TestLogsStdoutContainer() {
-	suite.adapter.On("LogsStdoutContainer", mock.Anything).Return(nil, nil)
+	suite.adapter.On("LogsStdoutContainer", mock.Anything, types.LogsContainerOptions{Tail: "200"}).Return(nil, nil)
 
-	stdout, err := suite.service.LogsStdoutContainer("")
+	stdout, err := suite.service.LogsStdoutContainer("", types.LogsContainerOptions{Tail: "200"})
 
 	suite.NoError(err)
 	suite.Nil(stdout)
@@ -95,9 +132,9 @@ func (suite *DockerKernelServiceTestSuite) TestLogsStdoutContainer() {
 }
 
 func (suite *DockerKernelServiceTestSuite) TestLogsStderrContainer() {
-	suite.adapter.On("LogsStderrContainer", mock.Anything).Return(nil, nil)
+	suite.adapter.On("LogsStderrContainer", mock.Anything, types.LogsContainerOptions{Tail: "200"}).Return(nil, nil)
 
-	stderr, err := suite.service.LogsStderrContainer("")
+	stderr, err := suite.service.LogsStderrContainer("", types.LogsContainerOptions{Tail: "200"})
 
 	suite.NoError(err)
 	suite.Nil(stderr)
@@ -113,6 +150,15 @@ func (suite *DockerKernelServiceTestSuite) TestWaitContainer() {
 	suite.adapter.AssertExpectations(suite.T())
 }
 
+func (suite *DockerKernelServiceTestSuite) TestCopyToContainer() {
+	suite.adapter.On("CopyToContainer", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	err := suite.service.CopyToContainer("", "/etc", strings.NewReader(""))
+
+	suite.NoError(err)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
 func (suite *DockerKernelServiceTestSuite) TestInfoImage() {
 	suite.adapter.On("InfoImage", mock.Anything).Return(types.InfoImageResponse{}, nil)
 
@@ -124,9 +170,9 @@ func (suite *DockerKernelServiceTestSuite) TestInfoImage() {
 }
 
 func (suite *DockerKernelServiceTestSuite) TestPullImage() {
-	suite.adapter.On("PullImage", mock.Anything).Return(nil, nil)
+	suite.adapter.On("PullImage", mock.Anything, mock.Anything).Return(nil, nil)
 
-	image, err := suite.service.PullImage(types.PullImageOptions{})
+	image, err := suite.service.PullImage(context.Background(), types.PullImageOptions{})
 
 	suite.NoError(err)
 	suite.Nil(image)
@@ -134,9 +180,9 @@ func (suite *DockerKernelServiceTestSuite) TestPullImage() {
 }
 
 func (suite *DockerKernelServiceTestSuite) TestBuildImage() {
-	suite.adapter.On("BuildImage", mock.Anything).Return(dockertypes.ImageBuildResponse{}, nil)
+	suite.adapter.On("BuildImage", mock.Anything, mock.Anything).Return(dockertypes.ImageBuildResponse{}, nil)
 
-	image, err := suite.service.BuildImage(types.BuildImageOptions{})
+	image, err := suite.service.BuildImage(context.Background(), types.BuildImageOptions{})
 
 	suite.NoError(err)
 	suite.Equal(dockertypes.ImageBuildResponse{}, image)
@@ -147,8 +193,13 @@ type MockDockerAdapter struct {
 	mock.Mock
 }
 
-func (m *MockDockerAdapter) ListContainers() ([]types.Container, error) {
+func (m *MockDockerAdapter) Ping() error {
 	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockDockerAdapter) ListContainers(options types.ListContainersOptions) ([]types.Container, error) {
+	args := m.Called(options)
 	return args.Get(0).([]types.Container), args.Error(1)
 }
 
@@ -172,18 +223,28 @@ func (m *MockDockerAdapter) StopContainer(id string) error {
 	return args.Error(0)
 }
 
+func (m *MockDockerAdapter) PauseContainer(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockDockerAdapter) UnpauseContainer(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 func (m *MockDockerAdapter) InfoContainer(id string) (types.InfoContainerResponse, error) {
 	args := m.Called(id)
 	return args.Get(0).(types.InfoContainerResponse), args.Error(1)
 }
 
-func (m *MockDockerAdapter) LogsStdoutContainer(id string) (io.ReadCloser, error) {
-	args := m.Called(id)
+func (m *MockDockerAdapter) LogsStdoutContainer(id string, options types.LogsContainerOptions) (io.ReadCloser, error) {
+	args := m.Called(id, options)
 	return nil, args.Error(1)
 }
 
-func (m *MockDockerAdapter) LogsStderrContainer(id string) (io.ReadCloser, error) {
-	args := m.Called(id)
+func (m *MockDockerAdapter) LogsStderrContainer(id string, options types.LogsContainerOptions) (io.ReadCloser, error) {
+	args := m.Called(id, options)
 	return nil, args.Error(1)
 }
 
@@ -192,17 +253,22 @@ func (m *MockDockerAdapter) WaitContainer(id string, cond types.WaitContainerCon
 	return args.Error(0)
 }
 
+func (m *MockDockerAdapter) CopyToContainer(id string, dir string, archive io.Reader) error {
+	args := m.Called(id, dir, archive)
+	return args.Error(0)
+}
+
 func (m *MockDockerAdapter) InfoImage(id string) (types.InfoImageResponse, error) {
 	args := m.Called(id)
 	return args.Get(0).(types.InfoImageResponse), args.Error(1)
 }
 
-func (m *MockDockerAdapter) PullImage(options types.PullImageOptions) (io.ReadCloser, error) {
-	args := m.Called(options)
+func (m *MockDockerAdapter) PullImage(ctx context.Context, options types.PullImageOptions) (io.ReadCloser, error) {
+	args := m.Called(ctx, options)
 	return nil, args.Error(1)
 }
 
-func (m *MockDockerAdapter) BuildImage(options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error) {
-	args := m.Called(options)
+func (m *MockDockerAdapter) BuildImage(ctx context.Context, options types.BuildImageOptions) (dockertypes.ImageBuildResponse, error) {
+	args := m.Called(ctx, options)
 	return args.Get(0).(dockertypes.ImageBuildResponse), args.Error(1)
 }
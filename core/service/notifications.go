@@ -1,12 +1,15 @@
 package service
 
 import (
+	"sync"
+
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/webhook"
 	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/apps/containers/core/types"
 	"github.com/vertex-center/vertex/core/port"
 	types2 "github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
 )
 
 // TODO: Move webhooks use to a Discord adapter
@@ -15,7 +18,11 @@ type NotificationsService struct {
 	uuid            uuid.UUID
 	ctx             *types2.VertexContext
 	settingsAdapter port.SettingsAdapter
-	client          webhook.Client
+
+	// client is nil if no webhook is set. It is replaced when the
+	// settings change.
+	client      webhook.Client
+	clientMutex sync.RWMutex
 }
 
 func NewNotificationsService(ctx *types2.VertexContext, settingsAdapter port.SettingsAdapter) NotificationsService {
@@ -27,19 +34,26 @@ func NewNotificationsService(ctx *types2.VertexContext, settingsAdapter port.Set
 }
 
 func (s *NotificationsService) StartWebhook() error {
-	webhookURL := s.settingsAdapter.GetNotificationsWebhook()
-	if webhookURL == nil {
-		return nil
-	}
+	// The service listens even without webhook, to load it when it is set.
+	s.ctx.AddListener(s)
+	return s.loadWebhook()
+}
 
-	var err error
-	s.client, err = webhook.NewWithURL(*webhookURL)
-	if err != nil {
-		return err
+// loadWebhook creates the client of the webhook set in the settings.
+func (s *NotificationsService) loadWebhook() error {
+	var client webhook.Client
+	webhookURL := s.settingsAdapter.GetNotificationsWebhook()
+	if webhookURL != nil && *webhookURL != "" {
+		var err error
+		client, err = webhook.NewWithURL(*webhookURL)
+		if err != nil {
+			return err
+		}
 	}
 
-	s.ctx.AddListener(s)
-
+	s.clientMutex.Lock()
+	s.client = client
+	s.clientMutex.Unlock()
 	return nil
 }
 
@@ -53,6 +67,11 @@ func (s *NotificationsService) GetUUID() uuid.UUID {
 
 func (s *NotificationsService) OnEvent(e interface{}) {
 	switch e := e.(type) {
+	case types2.EventSettingsChanged:
+		err := s.loadWebhook()
+		if err != nil {
+			log.Error(err)
+		}
 	case types.EventContainerStatusChange:
 		if e.Status == types.ContainerStatusOff || e.Status == types.ContainerStatusError || e.Status == types.ContainerStatusRunning {
 			s.sendStatus(e.Name, e.Status)
@@ -60,7 +79,7 @@ func (s *NotificationsService) OnEvent(e interface{}) {
 	}
 }
 
-func (s *NotificationsService) sendStatus(name string, status string) {
+func (s *NotificationsService) sendStatus(name string, status types.ContainerStatus) {
 	var color int
 
 	switch status {
@@ -78,7 +97,14 @@ func (s *NotificationsService) sendStatus(name string, status string) {
 		SetColor(color).
 		Build()
 
-	_, err := s.client.CreateEmbeds([]discord.Embed{embed})
+	s.clientMutex.RLock()
+	client := s.client
+	s.clientMutex.RUnlock()
+	if client == nil {
+		return
+	}
+
+	_, err := client.CreateEmbeds([]discord.Embed{embed})
 	if err != nil {
 		return
 	}
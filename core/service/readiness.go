@@ -0,0 +1,100 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/config"
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+// ReadinessService tracks whether Vertex finished starting, so the client
+// is not served before the adapters and the services are ready.
+type ReadinessService struct {
+	uuid          uuid.UUID
+	ctx           *types.VertexContext
+	dockerAdapter port.DockerKernelAdapter
+
+	checks      map[string]bool
+	checksMutex sync.RWMutex
+
+	// pingDelay is the delay between two pings of Docker.
+	pingDelay time.Duration
+}
+
+func NewReadinessService(ctx *types.VertexContext, dockerAdapter port.DockerKernelAdapter) port.ReadinessService {
+	s := &ReadinessService{
+		uuid:          uuid.New(),
+		ctx:           ctx,
+		dockerAdapter: dockerAdapter,
+		checks: map[string]bool{
+			types.ReadinessCheckDocker: false,
+		},
+		pingDelay: time.Second,
+	}
+	// The containers are only loaded if their app is enabled.
+	if config.Current.AppEnabled(types.ReadinessCheckContainers) {
+		s.checks[types.ReadinessCheckContainers] = false
+	}
+	s.ctx.AddListener(s)
+	return s
+}
+
+func (s *ReadinessService) Get() types.Readiness {
+	s.checksMutex.RLock()
+	defer s.checksMutex.RUnlock()
+
+	readiness := types.Readiness{
+		Ready:  true,
+		Checks: make(map[string]bool, len(s.checks)),
+	}
+	for name, ok := range s.checks {
+		readiness.Checks[name] = ok
+		readiness.Ready = readiness.Ready && ok
+	}
+	return readiness
+}
+
+func (s *ReadinessService) OnEvent(e interface{}) {
+	switch e := e.(type) {
+	case types.EventServerStart:
+		go s.waitDocker()
+	case types.EventAppReady:
+		s.pass(e.AppID)
+	}
+}
+
+func (s *ReadinessService) GetUUID() uuid.UUID {
+	return s.uuid
+}
+
+// waitDocker pings Docker until it is reachable.
+func (s *ReadinessService) waitDocker() {
+	for attempt := 1; ; attempt++ {
+		err := s.dockerAdapter.Ping()
+		if err == nil {
+			s.pass(types.ReadinessCheckDocker)
+			return
+		}
+		if attempt == 1 {
+			log.Warn("docker is not reachable yet", vlog.String("reason", err.Error()))
+		}
+		time.Sleep(s.pingDelay)
+	}
+}
+
+// pass marks a check as passed. The checks that are not awaited are ignored.
+func (s *ReadinessService) pass(name string) {
+	s.checksMutex.Lock()
+	defer s.checksMutex.Unlock()
+
+	if _, ok := s.checks[name]; !ok {
+		return
+	}
+	s.checks[name] = true
+	log.Info("readiness check passed", vlog.String("check", name))
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/config"
+	"github.com/vertex-center/vertex/core/types"
+)
+
+type ReadinessServiceTestSuite struct {
+	suite.Suite
+
+	ctx     *types.VertexContext
+	service *ReadinessService
+	adapter MockDockerKernelAdapter
+}
+
+func TestReadinessServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ReadinessServiceTestSuite))
+}
+
+func (suite *ReadinessServiceTestSuite) SetupTest() {
+	suite.ctx = types.NewVertexContext()
+	suite.adapter = MockDockerKernelAdapter{}
+	suite.service = NewReadinessService(suite.ctx, &suite.adapter).(*ReadinessService)
+	suite.service.pingDelay = time.Millisecond
+}
+
+func (suite *ReadinessServiceTestSuite) TestReady() {
+	suite.adapter.On("Ping").Return(errors.New("connection refused")).Twice()
+	suite.adapter.On("Ping").Return(nil).Once()
+
+	suite.False(suite.service.Get().Ready)
+
+	suite.ctx.DispatchEvent(types.EventServerStart{})
+	suite.Eventually(func() bool {
+		return suite.service.Get().Checks[types.ReadinessCheckDocker]
+	}, time.Second, time.Millisecond)
+	suite.False(suite.service.Get().Ready)
+
+	suite.ctx.DispatchEvent(types.EventAppReady{AppID: types.ReadinessCheckContainers})
+	suite.Equal(types.Readiness{
+		Ready: true,
+		Checks: map[string]bool{
+			types.ReadinessCheckDocker:     true,
+			types.ReadinessCheckContainers: true,
+		},
+	}, suite.service.Get())
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+func (suite *ReadinessServiceTestSuite) TestContainersAppDisabled() {
+	defer func(apps string) { config.Current.Apps = apps }(config.Current.Apps)
+	config.Current.Apps = "vx-sql"
+
+	suite.ctx = types.NewVertexContext()
+	suite.service = NewReadinessService(suite.ctx, &suite.adapter).(*ReadinessService)
+	suite.adapter.On("Ping").Return(nil).Once()
+
+	suite.ctx.DispatchEvent(types.EventServerStart{})
+	suite.Eventually(func() bool {
+		return suite.service.Get().Ready
+	}, time.Second, time.Millisecond)
+	suite.NotContains(suite.service.Get().Checks, types.ReadinessCheckContainers)
+}
+
+func (suite *ReadinessServiceTestSuite) TestIgnoresOtherApps() {
+	suite.ctx.DispatchEvent(types.EventAppReady{AppID: "vx-unknown"})
+	suite.NotContains(suite.service.Get().Checks, "vx-unknown")
+}
+
+type MockDockerKernelAdapter struct {
+	mock.Mock
+}
+
+func (m *MockDockerKernelAdapter) Ping() error {
+	args := m.Called()
+	return args.Error(0)
+}
@@ -1,16 +1,20 @@
 package service
 
 import (
+	"encoding/json"
+
 	"github.com/vertex-center/vertex/core/port"
 	"github.com/vertex-center/vertex/core/types"
 )
 
 type SettingsService struct {
+	ctx             *types.VertexContext
 	settingsAdapter port.SettingsAdapter
 }
 
-func NewSettingsService(settingsAdapter port.SettingsAdapter) port.SettingsService {
+func NewSettingsService(ctx *types.VertexContext, settingsAdapter port.SettingsAdapter) port.SettingsService {
 	return &SettingsService{
+		ctx:             ctx,
 		settingsAdapter: settingsAdapter,
 	}
 }
@@ -40,6 +44,29 @@ func (s *SettingsService) Update(settings types.Settings) error {
 		}
 	}
 
+	for namespace, values := range settings.Custom {
+		for key, value := range values {
+			err := s.settingsAdapter.SetCustom(namespace, key, value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	s.dispatchChanged()
+	return nil
+}
+
+func (s *SettingsService) Import(settings types.Settings) error {
+	err := settings.Validate()
+	if err != nil {
+		return err
+	}
+	err = s.settingsAdapter.SetSettings(settings)
+	if err != nil {
+		return err
+	}
+	s.dispatchChanged()
 	return nil
 }
 
@@ -48,7 +75,12 @@ func (s *SettingsService) GetNotificationsWebhook() *string {
 }
 
 func (s *SettingsService) SetNotificationsWebhook(webhook string) error {
-	return s.settingsAdapter.SetNotificationsWebhook(webhook)
+	err := s.settingsAdapter.SetNotificationsWebhook(webhook)
+	if err != nil {
+		return err
+	}
+	s.dispatchChanged()
+	return nil
 }
 
 func (s *SettingsService) GetChannel() types.SettingsUpdatesChannel {
@@ -60,5 +92,37 @@ func (s *SettingsService) GetChannel() types.SettingsUpdatesChannel {
 }
 
 func (s *SettingsService) SetChannel(channel types.SettingsUpdatesChannel) error {
-	return s.settingsAdapter.SetChannel(channel)
+	err := s.settingsAdapter.SetChannel(channel)
+	if err != nil {
+		return err
+	}
+	s.dispatchChanged()
+	return nil
+}
+
+func (s *SettingsService) GetCustom(namespace string, key string, value any) error {
+	raw, err := s.settingsAdapter.GetCustom(namespace, key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, value)
+}
+
+func (s *SettingsService) SetCustom(namespace string, key string, value any) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	err = s.settingsAdapter.SetCustom(namespace, key, raw)
+	if err != nil {
+		return err
+	}
+	s.dispatchChanged()
+	return nil
+}
+
+func (s *SettingsService) dispatchChanged() {
+	s.ctx.DispatchEvent(types.EventSettingsChanged{
+		Settings: s.settingsAdapter.GetSettings(),
+	})
 }
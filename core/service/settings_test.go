@@ -0,0 +1,105 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/core/types"
+)
+
+type SettingsServiceTestSuite struct {
+	suite.Suite
+
+	ctx     *types.VertexContext
+	service *SettingsService
+	adapter MockSettingsAdapter
+	events  []types.EventSettingsChanged
+}
+
+func TestSettingsServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SettingsServiceTestSuite))
+}
+
+func (suite *SettingsServiceTestSuite) SetupTest() {
+	suite.ctx = types.NewVertexContext()
+	suite.adapter = MockSettingsAdapter{}
+	suite.service = NewSettingsService(suite.ctx, &suite.adapter).(*SettingsService)
+	suite.events = nil
+	suite.ctx.AddListener(types.NewTempListener(func(e interface{}) {
+		if e, ok := e.(types.EventSettingsChanged); ok {
+			suite.events = append(suite.events, e)
+		}
+	}))
+}
+
+func (suite *SettingsServiceTestSuite) TestUpdateDispatchesEvent() {
+	channel := types.SettingsUpdatesChannelBeta
+	settings := types.Settings{
+		Updates: &types.SettingsUpdates{Channel: &channel},
+	}
+	suite.adapter.On("SetChannel", channel).Return(nil)
+	suite.adapter.On("GetSettings").Return(settings)
+
+	err := suite.service.Update(settings)
+
+	suite.NoError(err)
+	suite.Equal([]types.EventSettingsChanged{{Settings: settings}}, suite.events)
+	suite.adapter.AssertExpectations(suite.T())
+}
+
+func (suite *SettingsServiceTestSuite) TestImportInvalid() {
+	channel := types.SettingsUpdatesChannel("nightly")
+
+	err := suite.service.Import(types.Settings{
+		Updates: &types.SettingsUpdates{Channel: &channel},
+	})
+
+	suite.ErrorIs(err, types.ErrInvalidSettings)
+	suite.Empty(suite.events)
+}
+
+type MockSettingsAdapter struct {
+	mock.Mock
+}
+
+func (m *MockSettingsAdapter) GetSettings() types.Settings {
+	args := m.Called()
+	return args.Get(0).(types.Settings)
+}
+
+func (m *MockSettingsAdapter) SetSettings(settings types.Settings) error {
+	args := m.Called(settings)
+	return args.Error(0)
+}
+
+func (m *MockSettingsAdapter) GetNotificationsWebhook() *string {
+	args := m.Called()
+	return args.Get(0).(*string)
+}
+
+func (m *MockSettingsAdapter) SetNotificationsWebhook(webhook string) error {
+	args := m.Called(webhook)
+	return args.Error(0)
+}
+
+func (m *MockSettingsAdapter) GetChannel() *types.SettingsUpdatesChannel {
+	args := m.Called()
+	return args.Get(0).(*types.SettingsUpdatesChannel)
+}
+
+func (m *MockSettingsAdapter) SetChannel(channel types.SettingsUpdatesChannel) error {
+	args := m.Called(channel)
+	return args.Error(0)
+}
+
+func (m *MockSettingsAdapter) GetCustom(namespace string, key string) (json.RawMessage, error) {
+	args := m.Called(namespace, key)
+	return args.Get(0).(json.RawMessage), args.Error(1)
+}
+
+func (m *MockSettingsAdapter) SetCustom(namespace string, key string, value json.RawMessage) error {
+	args := m.Called(namespace, key, value)
+	return args.Error(0)
+}
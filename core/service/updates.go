@@ -10,8 +10,16 @@ import (
 	"github.com/vertex-center/vertex/core/types"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vlog"
+	"golang.org/x/exp/slices"
 	"os"
 	"sync/atomic"
+	"time"
+)
+
+const (
+	// setupAttempts is the number of times the missing dependencies are
+	// fetched before giving up.
+	setupAttempts = 5
 )
 
 type UpdateService struct {
@@ -20,6 +28,8 @@ type UpdateService struct {
 	adapter  port.BaselinesAdapter
 	updaters []types.Updater // updaters containers update logic for each dependency.
 	updating atomic.Bool     // updating is true if an update is currently in progress.
+
+	retryDelay time.Duration // retryDelay is the delay before the first retry, doubled after each attempt.
 }
 
 func NewUpdateService(ctx *types.VertexContext, adapter port.BaselinesAdapter, updaters []types.Updater) port.UpdateService {
@@ -28,6 +38,8 @@ func NewUpdateService(ctx *types.VertexContext, adapter port.BaselinesAdapter, u
 		ctx:      ctx,
 		adapter:  adapter,
 		updaters: updaters,
+
+		retryDelay: 2 * time.Second,
 	}
 	s.ctx.AddListener(s)
 	return s
@@ -70,6 +82,41 @@ func (s *UpdateService) GetUpdate(channel types.SettingsUpdatesChannel) (*types.
 	return &update, nil
 }
 
+// GetChannels returns the current version of each dependency, and the latest
+// version available on each update channel.
+func (s *UpdateService) GetChannels() ([]types.UpdaterVersions, error) {
+	baselines := map[types.SettingsUpdatesChannel]types.Baseline{}
+	for _, channel := range types.SettingsUpdatesChannels {
+		latest, err := s.adapter.GetLatest(context.Background(), channel)
+		if err != nil {
+			return nil, err
+		}
+		baselines[channel] = latest
+	}
+
+	versions := []types.UpdaterVersions{}
+	for _, updater := range s.updaters {
+		currentVersion, err := updater.CurrentVersion()
+		if err != nil {
+			return nil, err
+		}
+
+		v := types.UpdaterVersions{
+			ID:             updater.ID(),
+			CurrentVersion: currentVersion,
+			Latest:         map[types.SettingsUpdatesChannel]string{},
+		}
+		for channel, baseline := range baselines {
+			v.Latest[channel], err = baseline.GetVersionByID(updater.ID())
+			if err != nil {
+				return nil, fmt.Errorf("'%w' when accessing '%s'", err, updater.ID())
+			}
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
 func (s *UpdateService) InstallLatest(channel types.SettingsUpdatesChannel) error {
 	if !s.updating.CompareAndSwap(false, true) {
 		return types.ErrAlreadyUpdating
@@ -97,6 +144,63 @@ func (s *UpdateService) InstallLatest(channel types.SettingsUpdatesChannel) erro
 	return nil
 }
 
+// GetVersions returns the versions that can be installed with the updater.
+func (s *UpdateService) GetVersions(updaterID string) ([]string, error) {
+	updater, err := s.getUpdater(updaterID)
+	if err != nil {
+		return nil, err
+	}
+
+	lister, ok := updater.(types.VersionsLister)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", types.ErrVersionsNotListable, updaterID)
+	}
+	return lister.Versions()
+}
+
+// InstallVersion installs a specific version of a dependency, for example to
+// go back to an older version after a regression.
+func (s *UpdateService) InstallVersion(updaterID string, version string) error {
+	updater, err := s.getUpdater(updaterID)
+	if err != nil {
+		return err
+	}
+
+	if lister, ok := updater.(types.VersionsLister); ok {
+		versions, err := lister.Versions()
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(versions, version) {
+			return fmt.Errorf("%w: %s %s", types.ErrUpdateVersionNotFound, updaterID, version)
+		}
+	}
+
+	if !s.updating.CompareAndSwap(false, true) {
+		return types.ErrAlreadyUpdating
+	}
+	defer s.updating.Store(false)
+
+	log.Info("installing a specific version", vlog.String("id", updaterID), vlog.String("version", version))
+
+	err = updater.Install(version)
+	if err != nil {
+		return err
+	}
+
+	s.ctx.DispatchEvent(types.EventVertexUpdated{})
+	return nil
+}
+
+func (s *UpdateService) getUpdater(id string) (types.Updater, error) {
+	for _, updater := range s.updaters {
+		if updater.ID() == id {
+			return updater, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", types.ErrUpdaterNotFound, id)
+}
+
 func (s *UpdateService) firstSetup() error {
 	var missingDeps []types.Updater
 	for _, updater := range s.updaters {
@@ -112,7 +216,12 @@ func (s *UpdateService) firstSetup() error {
 
 	log.Info("installing missing dependencies", vlog.Any("count", len(missingDeps)))
 
-	latest, err := s.adapter.GetLatest(context.Background(), types.SettingsUpdatesChannelStable)
+	var latest types.Baseline
+	err := s.retry("fetch the latest baseline", func() error {
+		var err error
+		latest, err = s.adapter.GetLatest(context.Background(), types.SettingsUpdatesChannelStable)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -123,7 +232,9 @@ func (s *UpdateService) firstSetup() error {
 			return err
 		}
 
-		err = updater.Install(version)
+		err = s.retry(fmt.Sprintf("install %s %s", updater.ID(), version), func() error {
+			return updater.Install(version)
+		})
 		if err != nil {
 			return err
 		}
@@ -132,13 +243,39 @@ func (s *UpdateService) firstSetup() error {
 	return nil
 }
 
+// retry runs f until it succeeds, at most setupAttempts times, with an
+// exponential backoff between the attempts.
+func (s *UpdateService) retry(action string, f func() error) error {
+	delay := s.retryDelay
+
+	var err error
+	for attempt := 1; attempt <= setupAttempts; attempt++ {
+		err = f()
+		if err == nil {
+			return nil
+		}
+		if attempt == setupAttempts {
+			break
+		}
+
+		log.Warn("failed to "+action+", retrying",
+			vlog.Int("attempt", attempt),
+			vlog.String("delay", delay.String()),
+			vlog.String("reason", err.Error()),
+		)
+		time.Sleep(delay)
+		delay *= 2
+	}
+	return fmt.Errorf("failed to %s after %d attempts: %w", action, setupAttempts, err)
+}
+
 func (s *UpdateService) OnEvent(e interface{}) {
 	switch e.(type) {
 	case types.EventServerStart:
 		err := s.firstSetup()
 		if err != nil {
 			log.Error(err)
-			err = errors.New("failed to fetch latest baseline. panic because vertex cannot run without missing dependencies")
+			err = errors.New("failed to install the missing dependencies. vertex cannot run without them. check the internet connection, or set a mirror with baselines_url and git_url in the configuration")
 			log.Error(err)
 			os.Exit(1)
 		}
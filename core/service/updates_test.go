@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	types2 "github.com/vertex-center/vertex/core/types"
@@ -84,6 +85,86 @@ func (suite *UpdateServiceTestSuite) TestGetUpdateBeta() {
 	suite.Equal(suite.betaBaseline, update.Baseline)
 }
 
+func (suite *UpdateServiceTestSuite) TestGetChannels() {
+	suite.updaterA.On("CurrentVersion").Return("v0.12.1", nil)
+	suite.updaterB.On("CurrentVersion").Return("v0.12.0", nil)
+
+	versions, err := suite.service.GetChannels()
+	suite.NoError(err)
+	suite.Equal([]types2.UpdaterVersions{
+		{
+			ID:             "vertex",
+			CurrentVersion: "v0.12.1",
+			Latest: map[types2.SettingsUpdatesChannel]string{
+				types2.SettingsUpdatesChannelStable: "v0.12.1",
+				types2.SettingsUpdatesChannelBeta:   "v0.13.5-beta",
+			},
+		},
+		{
+			ID:             "vertex_client",
+			CurrentVersion: "v0.12.0",
+			Latest: map[types2.SettingsUpdatesChannel]string{
+				types2.SettingsUpdatesChannelStable: "v0.12.0",
+				types2.SettingsUpdatesChannelBeta:   "v0.13.3-beta",
+			},
+		},
+	}, versions)
+}
+
+func (suite *UpdateServiceTestSuite) TestFirstSetupRetries() {
+	adapter := &MockBaselineAdapter{}
+	adapter.On("GetLatest", context.Background(), types2.SettingsUpdatesChannelStable).Return(types2.Baseline{}, errors.New("network unreachable")).Once()
+	adapter.On("GetLatest", context.Background(), types2.SettingsUpdatesChannelStable).Return(suite.latestBaseline, nil).Once()
+
+	suite.updaterA.On("IsInstalled").Return(false)
+	suite.updaterA.On("Install", "v0.12.1").Return(errors.New("connection reset")).Once()
+	suite.updaterA.On("Install", "v0.12.1").Return(nil).Once()
+	suite.updaterB.On("IsInstalled").Return(true)
+
+	suite.service.adapter = adapter
+	suite.service.retryDelay = 0
+
+	err := suite.service.firstSetup()
+	suite.NoError(err)
+	adapter.AssertNumberOfCalls(suite.T(), "GetLatest", 2)
+	suite.updaterA.AssertNumberOfCalls(suite.T(), "Install", 2)
+}
+
+func (suite *UpdateServiceTestSuite) TestFirstSetupGivesUp() {
+	adapter := &MockBaselineAdapter{}
+	adapter.On("GetLatest", context.Background(), types2.SettingsUpdatesChannelStable).Return(types2.Baseline{}, errors.New("network unreachable"))
+
+	suite.updaterA.On("IsInstalled").Return(false)
+	suite.updaterB.On("IsInstalled").Return(true)
+
+	suite.service.adapter = adapter
+	suite.service.retryDelay = 0
+
+	err := suite.service.firstSetup()
+	suite.Error(err)
+	adapter.AssertNumberOfCalls(suite.T(), "GetLatest", setupAttempts)
+}
+
+func (suite *UpdateServiceTestSuite) TestInstallVersion() {
+	suite.updaterB.On("Install", "v0.11.0").Return(nil)
+
+	err := suite.service.InstallVersion("vertex_client", "v0.11.0")
+	suite.NoError(err)
+	suite.updaterB.AssertCalled(suite.T(), "Install", "v0.11.0")
+
+	err = suite.service.InstallVersion("unknown", "v0.11.0")
+	suite.ErrorIs(err, types2.ErrUpdaterNotFound)
+
+	suite.service.updating.Store(true)
+	err = suite.service.InstallVersion("vertex_client", "v0.11.0")
+	suite.ErrorIs(err, types2.ErrAlreadyUpdating)
+}
+
+func (suite *UpdateServiceTestSuite) TestGetVersionsNotListable() {
+	_, err := suite.service.GetVersions("vertex")
+	suite.ErrorIs(err, types2.ErrVersionsNotListable)
+}
+
 type MockBaselineAdapter struct {
 	mock.Mock
 }
@@ -2,9 +2,18 @@ package types
 
 type About struct {
 	Version string `json:"version"`
-	Commit  string `json:"commit"`
+	Commit  string `json:"commit,omitempty"`
 	Date    string `json:"date"`
 
-	OS   string `json:"os"`
-	Arch string `json:"arch"`
+	OS   string `json:"os,omitempty"`
+	Arch string `json:"arch,omitempty"`
+}
+
+// Redacted returns the information that can be shown publicly without
+// helping to target the exact build or system of the server.
+func (a About) Redacted() About {
+	return About{
+		Version: a.Version,
+		Date:    a.Date,
+	}
 }
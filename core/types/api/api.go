@@ -13,14 +13,16 @@ import (
 
 type Error struct {
 	HttpCode int
-	Code     router.ErrCode `json:"code"`
-	Message  string         `json:"message"`
+	Code     router.ErrCode      `json:"code"`
+	Message  string              `json:"message"`
+	Details  []router.FieldError `json:"details,omitempty"`
 }
 
 func (e *Error) RouterError() router.Error {
 	return router.Error{
 		Code:          e.Code,
 		PublicMessage: e.Message,
+		Details:       e.Details,
 	}
 }
 
@@ -11,20 +11,33 @@ const (
 	ErrAlreadyUpdating            router.ErrCode = "already_updating"
 	ErrFailedToFetchLatestVersion router.ErrCode = "failed_to_fetch_latest_version"
 	ErrFailedToGetUpdates         router.ErrCode = "failed_to_get_updates"
+	ErrUpdaterNotFound            router.ErrCode = "updater_not_found"
+	ErrVersionsNotListable        router.ErrCode = "versions_not_listable"
+	ErrUpdateVersionNotFound      router.ErrCode = "update_version_not_found"
+	ErrUpdateVersionMissing       router.ErrCode = "update_version_missing"
+	ErrFailedToGetVersions        router.ErrCode = "failed_to_get_versions"
 
 	ErrFailedToListContainers    router.ErrCode = "failed_to_list_containers"
 	ErrFailedToDeleteContainer   router.ErrCode = "failed_to_delete_container"
 	ErrFailedToCreateContainer   router.ErrCode = "failed_to_create_container"
 	ErrFailedToStartContainer    router.ErrCode = "failed_to_start_container"
 	ErrFailedToStopContainer     router.ErrCode = "failed_to_stop_container"
+	ErrFailedToPauseContainer    router.ErrCode = "failed_to_pause_container"
+	ErrFailedToUnpauseContainer  router.ErrCode = "failed_to_unpause_container"
 	ErrFailedToRecreateContainer router.ErrCode = "failed_to_recreate_container"
 	ErrFailedToGetContainerLogs  router.ErrCode = "failed_to_get_container_logs"
+	ErrInvalidLogsTail           router.ErrCode = "invalid_logs_tail"
 	ErrFailedToWaitContainer     router.ErrCode = "failed_to_wait_container"
+	ErrFailedToCopyToContainer   router.ErrCode = "failed_to_copy_to_container"
 	ErrFailedToGetContainerInfo  router.ErrCode = "failed_to_get_container_info"
 	ErrFailedToGetImageInfo      router.ErrCode = "failed_to_get_image_info"
 	ErrFailedToPullImage         router.ErrCode = "failed_to_pull_image"
 	ErrFailedToBuildImage        router.ErrCode = "failed_to_build_image"
 	ErrContainerNotFound         router.ErrCode = "container_not_found"
+	ErrDockerUnavailable         router.ErrCode = "docker_unavailable"
+	ErrSysctlNotAllowed          router.ErrCode = "sysctl_not_allowed"
+	ErrInvalidExtraHost          router.ErrCode = "invalid_extra_host"
+	ErrInvalidShmSize            router.ErrCode = "invalid_shm_size"
 
 	ErrFailedToGetSSHKeys   router.ErrCode = "failed_to_get_ssh_keys"
 	ErrFailedToAddSSHKey    router.ErrCode = "failed_to_add_ssh_key"
@@ -32,5 +45,10 @@ const (
 	ErrInvalidPublicKey     router.ErrCode = "invalid_public_key"
 	ErrInvalidFingerprint   router.ErrCode = "invalid_fingerprint"
 
-	ErrFailedToPatchSettings router.ErrCode = "failed_to_patch_settings"
+	ErrFailedToPatchSettings    router.ErrCode = "failed_to_patch_settings"
+	ErrInvalidSettings          router.ErrCode = "invalid_settings"
+	ErrFailedToImportSettings   router.ErrCode = "failed_to_import_settings"
+	ErrCustomSettingNotFound    router.ErrCode = "custom_setting_not_found"
+	ErrFailedToGetCustomSetting router.ErrCode = "failed_to_get_custom_setting"
+	ErrFailedToSetCustomSetting router.ErrCode = "failed_to_set_custom_setting"
 )
@@ -6,6 +6,7 @@ import (
 	"github.com/vertex-center/vertex/core/types"
 
 	"github.com/gin-contrib/sse"
+	"github.com/vertex-center/vertex/pkg/ginutils"
 	"github.com/vertex-center/vertex/pkg/router"
 )
 
@@ -23,6 +24,22 @@ type Meta struct {
 	Icon string `json:"icon"`
 }
 
+// Status is the state of an app, as listed by /apps.
+type Status struct {
+	Meta
+
+	// Enabled is false if the app is disabled in the configuration. The
+	// disabled apps are not initialized, so only their ID is known.
+	Enabled bool `json:"enabled"`
+
+	// Routes are the routes mounted by the app, like /api/app/vx-sql.
+	Routes []string `json:"routes,omitempty"`
+
+	// Error is the error returned by the initialization of the app. The
+	// apps that failed to initialize mount no routes.
+	Error string `json:"error,omitempty"`
+}
+
 type App struct {
 	meta         Meta
 	ctx          *Context
@@ -73,9 +90,15 @@ func (app *App) Context() *Context {
 }
 
 type Interface interface {
+	// ID returns the ID of the app. It is called before Initialize, to skip
+	// the apps disabled in the configuration.
+	ID() string
 	Initialize(app *App) error
 }
 
+// Uninitializable is implemented by the apps that hold resources, like
+// servers or background goroutines. Uninitialize is called when Vertex
+// stops, to release them.
 type Uninitializable interface {
 	Uninitialize() error
 }
@@ -86,7 +109,10 @@ type Service interface {
 	OnEvent(e interface{})
 }
 
+// HeadersSSE prepares the response for the server-sent events, and lifts the
+// request timeout.
 func HeadersSSE(c *router.Context) {
+	ginutils.DisableTimeout(c.Context)
 	c.Writer.Header().Set("Content-Type", sse.ContentType)
 	c.Writer.Header().Set("Cache-Control", "no-cache")
 	c.Writer.Header().Set("Connection", "keep-alive")
@@ -1,14 +1,23 @@
 package app
 
 import (
-	"github.com/vertex-center/vertex/core/types"
+	"errors"
+	"fmt"
 	"sync"
+	"time"
+
+	"github.com/vertex-center/vertex/core/types"
 
 	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vlog"
 )
 
+// UninitializeTimeout is how long an app has to stop when Vertex stops.
+var UninitializeTimeout = 10 * time.Second
+
+var ErrUninitializeTimeout = errors.New("the app didn't stop in time")
+
 type AppRegistry struct {
 	Interface
 	*App
@@ -20,6 +29,9 @@ type AppsRegistry struct {
 
 	apps      map[string]AppRegistry
 	appsMutex *sync.RWMutex
+
+	// order is the IDs of the apps, in the order of their registration.
+	order []string
 }
 
 func NewAppsRegistry(ctx *types.VertexContext) *AppsRegistry {
@@ -39,22 +51,50 @@ func (registry *AppsRegistry) RegisterApp(app *App, impl Interface) error {
 	if err != nil {
 		return err
 	}
-	registry.apps[app.ID()] = AppRegistry{
+	registry.apps[impl.ID()] = AppRegistry{
 		Interface: impl,
 		App:       app,
 	}
+	registry.order = append(registry.order, impl.ID())
 	return nil
 }
 
-func (registry *AppsRegistry) Close() {
-	for id, app := range registry.apps {
-		if a, ok := app.Interface.(Uninitializable); ok {
-			log.Info("uninitializing app", vlog.String("id", id))
-			err := a.Uninitialize()
-			if err != nil {
-				log.Error(err)
-			}
+// Close uninitializes the apps in the reverse order of their registration,
+// so that an app stops before the apps registered before it. An app that
+// doesn't stop within UninitializeTimeout is left behind. The errors are
+// returned together, each with the ID of its app.
+func (registry *AppsRegistry) Close() error {
+	registry.appsMutex.RLock()
+	defer registry.appsMutex.RUnlock()
+
+	var errs []error
+	for i := len(registry.order) - 1; i >= 0; i-- {
+		id := registry.order[i]
+		a, ok := registry.apps[id].Interface.(Uninitializable)
+		if !ok {
+			continue
 		}
+
+		log.Info("uninitializing app", vlog.String("id", id))
+		err := uninitialize(a)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func uninitialize(a Uninitializable) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- a.Uninitialize()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(UninitializeTimeout):
+		return ErrUninitializeTimeout
 	}
 }
 
@@ -0,0 +1,169 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gorilla/websocket"
+	"github.com/vertex-center/vertex/core/types"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/router"
+	"github.com/vertex-center/vlog"
+)
+
+// EventFilter converts an event dispatched in Vertex to the event sent to
+// the client. It returns nil if the event must not be sent.
+type EventFilter func(e interface{}) *sse.Event
+
+var upgrader = websocket.Upgrader{
+	// The SSE endpoints are already open to all origins.
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// eventsBufferSize is the number of events kept for a slow client before
+// the oldest events are dropped.
+const eventsBufferSize = 64
+
+// subscribe listens to the events of Vertex. It returns the channel that
+// receives the filtered events, and the function to stop listening.
+//
+// The listener is buffered, so a slow client never blocks the dispatcher.
+func (ctx *Context) subscribe(filter EventFilter) (<-chan sse.Event, func()) {
+	eventsChan := make(chan sse.Event)
+	stopped := make(chan struct{})
+
+	listener := types.NewTempListener(func(e interface{}) {
+		event := filter(e)
+		if event == nil {
+			return
+		}
+
+		select {
+		case eventsChan <- *event:
+		case <-stopped:
+		}
+	})
+
+	ctx.AddListener(types.NewBufferedListener(listener, eventsBufferSize))
+
+	return eventsChan, func() {
+		ctx.RemoveListener(listener)
+		// eventsChan is not closed, because the listener may still be
+		// running on the buffered listener goroutine.
+		close(stopped)
+	}
+}
+
+// StreamSSE sends the filtered events to the client with Server-Sent Events,
+// until the client disconnects.
+func (ctx *Context) StreamSSE(c *router.Context, filter EventFilter) {
+	eventsChan, unsubscribe := ctx.subscribe(filter)
+	defer unsubscribe()
+
+	log.Debug("sse stream opened", vlog.String("path", c.Request.URL.Path))
+	defer log.Debug("sse stream closed", vlog.String("path", c.Request.URL.Path))
+
+	done := c.Request.Context().Done()
+
+	first := true
+
+	c.Stream(func(w io.Writer) bool {
+		if first {
+			err := sse.Encode(w, sse.Event{
+				Event: "open",
+			})
+
+			if err != nil {
+				log.Error(err)
+				return false
+			}
+			first = false
+			return true
+		}
+
+		select {
+		case e := <-eventsChan:
+			err := sse.Encode(w, e)
+			if err != nil {
+				log.Error(err)
+			}
+			return true
+		case <-done:
+			return false
+		}
+	})
+}
+
+type wsMessage struct {
+	Event string      `json:"event"`
+	Data  interface{} `json:"data,omitempty"`
+}
+
+// StreamWebSocket sends the filtered events to the client with a WebSocket,
+// until the client disconnects. The messages are JSON objects with the same
+// event name and data as the SSE events.
+func (ctx *Context) StreamWebSocket(c *router.Context, filter EventFilter) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader already replied to the client.
+		log.Error(err)
+		return
+	}
+	defer conn.Close()
+
+	eventsChan, unsubscribe := ctx.subscribe(filter)
+	defer unsubscribe()
+
+	// The client doesn't send messages, but the connection must be read
+	// to detect when it is closed.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			_, _, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	err = conn.WriteJSON(wsMessage{Event: "open"})
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	for {
+		select {
+		case e := <-eventsChan:
+			err := conn.WriteJSON(wsMessage{
+				Event: e.Event,
+				Data:  wsData(e.Data),
+			})
+			if err != nil {
+				log.Error(err)
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// wsData formats the data like sse.Encode does: structs, slices and maps
+// are sent as JSON, everything else as a string.
+func wsData(data interface{}) interface{} {
+	if data == nil {
+		return nil
+	}
+	switch reflect.TypeOf(data).Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Map:
+		return data
+	}
+	return fmt.Sprint(data)
+}
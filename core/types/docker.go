@@ -1,16 +1,52 @@
 package types
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
 	dockertypes "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/go-connections/nat"
 )
 
+var (
+	ErrSysctlNotAllowed = errors.New("sysctl not allowed in a container")
+	ErrInvalidExtraHost = errors.New("invalid extra host")
+	ErrInvalidShmSize   = errors.New("invalid shm size")
+)
+
+// namespacedSysctls are the sysctls that are namespaced by the kernel, and
+// can be set per container. The keys ending with a dot are prefixes.
+var namespacedSysctls = []string{
+	"net.",
+	"fs.mqueue.",
+	"kernel.msgmax",
+	"kernel.msgmnb",
+	"kernel.msgmni",
+	"kernel.sem",
+	"kernel.shmall",
+	"kernel.shmmax",
+	"kernel.shmmni",
+	"kernel.shm_rmid_forced",
+}
+
 type Container struct {
-	ID      string   `json:"id,omitempty"`
-	ImageID string   `json:"image_id,omitempty"`
-	Names   []string `json:"names,omitempty"`
-	Mounts  []Mount  `json:"mounts,omitempty"`
+	ID      string            `json:"id,omitempty"`
+	ImageID string            `json:"image_id,omitempty"`
+	Names   []string          `json:"names,omitempty"`
+	Mounts  []Mount           `json:"mounts,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+type ListContainersOptions struct {
+	// Labels keeps only the containers having all these labels.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Name keeps only the containers whose name contains this value.
+	Name string `json:"name,omitempty"`
 }
 
 type Mount struct {
@@ -24,10 +60,37 @@ type CreateContainerOptions struct {
 	ExposedPorts  nat.PortSet       `json:"exposed_ports,omitempty"`
 	PortBindings  nat.PortMap       `json:"port_bindings,omitempty"`
 	Binds         []string          `json:"binds,omitempty"`
+	Volumes       []VolumeMount     `json:"volumes,omitempty"`
 	Env           []string          `json:"env,omitempty"`
 	CapAdd        []string          `json:"cap_add,omitempty"`
 	Sysctls       map[string]string `json:"sysctls,omitempty"`
+	ExtraHosts    []string          `json:"extra_hosts,omitempty"`
+	ShmSize       int64             `json:"shm_size,omitempty"`
+	Init          bool              `json:"init,omitempty"`
 	Cmd           []string          `json:"cmd,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+
+	// Network is the bridge network joined by the container, created if it
+	// doesn't exist. The containers of a network reach each other by their
+	// NetworkAliases.
+	Network        string   `json:"network,omitempty"`
+	NetworkAliases []string `json:"network_aliases,omitempty"`
+}
+
+// VolumeMount mounts a named Docker volume in a container. The volume is
+// created by Docker if it doesn't exist.
+type VolumeMount struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+}
+
+type LogsContainerOptions struct {
+	// Tail is the number of lines printed before following the logs, like
+	// docker logs --tail. It can be "all". The default is "0".
+	Tail string `json:"tail,omitempty"`
+
+	// Timestamps prefixes each line with its RFC3339Nano timestamp.
+	Timestamps bool `json:"timestamps,omitempty"`
 }
 
 type BuildImageOptions struct {
@@ -38,6 +101,14 @@ type BuildImageOptions struct {
 
 type PullImageOptions struct {
 	Image string `json:"image,omitempty"`
+
+	// Auth are the credentials of the registry of the image, if any.
+	Auth *RegistryAuth `json:"auth,omitempty"`
+}
+
+type RegistryAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
 }
 
 type CreateContainerResponse struct {
@@ -50,6 +121,15 @@ type InfoContainerResponse struct {
 	Name     string `json:"name,omitempty"`
 	Platform string `json:"platform,omitempty"`
 	Image    string `json:"image,omitempty"`
+
+	// State is the Docker state of the container, like running or exited.
+	State    string   `json:"state,omitempty"`
+	Mounts   []Mount  `json:"mounts,omitempty"`
+	Networks []string `json:"networks,omitempty"`
+
+	// Ports are the host ports bound to each port of the container, like
+	// 80/tcp.
+	Ports map[string][]string `json:"ports,omitempty"`
 }
 
 type InfoImageResponse struct {
@@ -58,6 +138,9 @@ type InfoImageResponse struct {
 	OS           string   `json:"os,omitempty"`
 	Size         int64    `json:"size,omitempty"`
 	Tags         []string `json:"tags,omitempty"`
+	// Digests are the repository digests of the image, in the
+	// repository@sha256:... format.
+	Digests []string `json:"digests,omitempty"`
 }
 
 type WaitContainerCondition container.WaitCondition
@@ -68,6 +151,7 @@ func NewContainer(c dockertypes.Container) Container {
 		ImageID: c.ImageID,
 		Names:   c.Names,
 		Mounts:  NewMounts(c.Mounts),
+		Labels:  c.Labels,
 	}
 }
 
@@ -85,3 +169,46 @@ func NewMount(m dockertypes.MountPoint) Mount {
 		Destination: m.Destination,
 	}
 }
+
+// ValidateSysctls checks that the sysctls can be set in a container. The
+// other sysctls apply to the whole host, so Docker refuses them.
+func ValidateSysctls(sysctls map[string]string) error {
+	var denied []string
+	for key := range sysctls {
+		if !isNamespacedSysctl(key) {
+			denied = append(denied, key)
+		}
+	}
+	if len(denied) == 0 {
+		return nil
+	}
+	sort.Strings(denied)
+	return fmt.Errorf("%w: %s is not namespaced and can only be set on the host", ErrSysctlNotAllowed, strings.Join(denied, ", "))
+}
+
+func isNamespacedSysctl(key string) bool {
+	for _, s := range namespacedSysctls {
+		if key == s || (strings.HasSuffix(s, ".") && strings.HasPrefix(key, s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExtraHostGateway is the extra host ip replaced by Docker with the ip of
+// the host.
+const ExtraHostGateway = "host-gateway"
+
+// ValidateExtraHosts checks that the extra hosts are in the host:ip form.
+func ValidateExtraHosts(hosts []string) error {
+	for _, h := range hosts {
+		host, ip, ok := strings.Cut(h, ":")
+		if !ok || host == "" {
+			return fmt.Errorf("%w: %s is not in the host:ip form", ErrInvalidExtraHost, h)
+		}
+		if ip != ExtraHostGateway && net.ParseIP(ip) == nil {
+			return fmt.Errorf("%w: %s is not an ip address or %s", ErrInvalidExtraHost, ip, ExtraHostGateway)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DockerTestSuite struct {
+	suite.Suite
+}
+
+func TestDockerTestSuite(t *testing.T) {
+	suite.Run(t, new(DockerTestSuite))
+}
+
+func (suite *DockerTestSuite) TestValidateSysctls() {
+	err := ValidateSysctls(map[string]string{
+		"net.ipv4.ip_forward":    "1",
+		"net.core.somaxconn":     "1024",
+		"fs.mqueue.msg_max":      "10",
+		"kernel.shmmax":          "68719476736",
+		"kernel.shm_rmid_forced": "1",
+	})
+	suite.NoError(err)
+
+	err = ValidateSysctls(nil)
+	suite.NoError(err)
+}
+
+func (suite *DockerTestSuite) TestValidateSysctlsNotAllowed() {
+	err := ValidateSysctls(map[string]string{
+		"net.ipv4.ip_forward": "1",
+		"vm.swappiness":       "10",
+		"kernel.shmmaxx":      "1",
+		"network":             "1",
+	})
+	suite.ErrorIs(err, ErrSysctlNotAllowed)
+	suite.ErrorContains(err, "kernel.shmmaxx, network, vm.swappiness")
+}
+
+func (suite *DockerTestSuite) TestValidateExtraHosts() {
+	err := ValidateExtraHosts([]string{
+		"db.internal:10.0.0.2",
+		"v6.internal:::1",
+		"host.docker.internal:host-gateway",
+	})
+	suite.NoError(err)
+
+	err = ValidateExtraHosts([]string{"db.internal"})
+	suite.ErrorIs(err, ErrInvalidExtraHost)
+
+	err = ValidateExtraHosts([]string{"db.internal:db"})
+	suite.ErrorIs(err, ErrInvalidExtraHost)
+}
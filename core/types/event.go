@@ -43,4 +43,10 @@ type (
 	EventServerStop      struct{}
 	EventServerHardReset struct{}
 	EventVertexUpdated   struct{}
+
+	// EventSettingsChanged is dispatched when the settings are changed, so
+	// the services using them can reload them.
+	EventSettingsChanged struct {
+		Settings Settings
+	}
 )
@@ -0,0 +1,132 @@
+package types
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vlog"
+)
+
+// CoalescableEvent is an event that can be replaced by a newer event with
+// the same key while it is waiting in a BufferedListener. For example, only
+// the latest status of a container is useful to a slow listener.
+type CoalescableEvent interface {
+	CoalesceKey() string
+}
+
+// BufferedListener delivers the events to a listener from its own goroutine.
+// The dispatcher never waits for the listener: the events are queued, and
+// if the queue is full, the oldest event is dropped.
+type BufferedListener struct {
+	listener Listener
+	size     int
+
+	queue      []interface{}
+	queueMutex sync.Mutex
+
+	notify chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+func NewBufferedListener(l Listener, size int) *BufferedListener {
+	if size < 1 {
+		size = 1
+	}
+
+	b := &BufferedListener{
+		listener: l,
+		size:     size,
+		queue:    []interface{}{},
+		notify:   make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func (b *BufferedListener) OnEvent(e interface{}) {
+	b.queueMutex.Lock()
+	defer b.queueMutex.Unlock()
+
+	if b.coalesce(e) {
+		return
+	}
+
+	b.queue = append(b.queue, e)
+	if len(b.queue) > b.size {
+		b.queue = b.queue[1:]
+		log.Debug("listener too slow, dropping oldest event", vlog.String("uuid", b.GetUUID().String()))
+	}
+
+	select {
+	case b.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (b *BufferedListener) GetUUID() uuid.UUID {
+	return b.listener.GetUUID()
+}
+
+// Close stops the delivery of the events. The events still queued are dropped.
+func (b *BufferedListener) Close() {
+	b.once.Do(func() {
+		close(b.done)
+	})
+}
+
+// coalesce replaces a queued event that has the same key as e. It returns
+// false if e must be queued.
+func (b *BufferedListener) coalesce(e interface{}) bool {
+	ce, ok := e.(CoalescableEvent)
+	if !ok {
+		return false
+	}
+
+	key := ce.CoalesceKey()
+	for i, queued := range b.queue {
+		if qe, ok := queued.(CoalescableEvent); ok && qe.CoalesceKey() == key {
+			b.queue[i] = e
+			return true
+		}
+	}
+	return false
+}
+
+func (b *BufferedListener) pop() (interface{}, bool) {
+	b.queueMutex.Lock()
+	defer b.queueMutex.Unlock()
+
+	if len(b.queue) == 0 {
+		return nil, false
+	}
+	e := b.queue[0]
+	b.queue = b.queue[1:]
+	return e, true
+}
+
+func (b *BufferedListener) run() {
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-b.notify:
+		}
+
+		for {
+			select {
+			case <-b.done:
+				return
+			default:
+			}
+
+			e, ok := b.pop()
+			if !ok {
+				break
+			}
+			b.listener.OnEvent(e)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package types
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type BufferedListenerTestSuite struct {
+	suite.Suite
+
+	// release unblocks the listener.
+	release  chan struct{}
+	received chan interface{}
+	listener TempListener
+}
+
+func TestBufferedListenerTestSuite(t *testing.T) {
+	suite.Run(t, new(BufferedListenerTestSuite))
+}
+
+func (suite *BufferedListenerTestSuite) SetupTest() {
+	// The listener of a test can still run once the next test started, so
+	// it must not read the fields reassigned here.
+	release := make(chan struct{})
+	received := make(chan interface{}, 100)
+	suite.release = release
+	suite.received = received
+	suite.listener = NewTempListener(func(e interface{}) {
+		<-release
+		received <- e
+	})
+}
+
+type mockIntEvent int
+
+type mockStatusEvent struct {
+	id     string
+	status string
+}
+
+func (e mockStatusEvent) CoalesceKey() string {
+	return e.id
+}
+
+// block sends a first event, and waits until the listener is stuck on it.
+func (suite *BufferedListenerTestSuite) block(b *BufferedListener) {
+	b.OnEvent(mockIntEvent(-1))
+	suite.Eventually(func() bool {
+		b.queueMutex.Lock()
+		defer b.queueMutex.Unlock()
+		return len(b.queue) == 0
+	}, time.Second, time.Millisecond)
+}
+
+func (suite *BufferedListenerTestSuite) receive(count int) []interface{} {
+	var events []interface{}
+	for i := 0; i < count; i++ {
+		select {
+		case e := <-suite.received:
+			events = append(events, e)
+		case <-time.After(time.Second):
+			suite.FailNow("timeout waiting for events")
+		}
+	}
+	return events
+}
+
+func (suite *BufferedListenerTestSuite) TestOrder() {
+	b := NewBufferedListener(suite.listener, 10)
+	defer b.Close()
+	close(suite.release)
+
+	for i := 0; i < 5; i++ {
+		b.OnEvent(mockIntEvent(i))
+	}
+
+	events := suite.receive(5)
+	for i, e := range events {
+		suite.Equal(mockIntEvent(i), e)
+	}
+}
+
+func (suite *BufferedListenerTestSuite) TestDoesNotBlockDispatcher() {
+	b := NewBufferedListener(suite.listener, 2)
+	defer b.Close()
+	suite.block(b)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			b.OnEvent(mockIntEvent(i))
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		suite.FailNow("the dispatcher was blocked by the listener")
+	}
+	close(suite.release)
+}
+
+func (suite *BufferedListenerTestSuite) TestDropOldest() {
+	b := NewBufferedListener(suite.listener, 2)
+	defer b.Close()
+	suite.block(b)
+
+	for i := 0; i < 5; i++ {
+		b.OnEvent(mockIntEvent(i))
+	}
+	close(suite.release)
+
+	events := suite.receive(3)
+	suite.Equal([]interface{}{mockIntEvent(-1), mockIntEvent(3), mockIntEvent(4)}, events)
+}
+
+func (suite *BufferedListenerTestSuite) TestCoalesce() {
+	b := NewBufferedListener(suite.listener, 10)
+	defer b.Close()
+	suite.block(b)
+
+	b.OnEvent(mockStatusEvent{id: "a", status: "starting"})
+	b.OnEvent(mockStatusEvent{id: "b", status: "starting"})
+	b.OnEvent(mockIntEvent(0))
+	b.OnEvent(mockStatusEvent{id: "a", status: "running"})
+	close(suite.release)
+
+	events := suite.receive(4)
+	suite.Equal([]interface{}{
+		mockIntEvent(-1),
+		mockStatusEvent{id: "a", status: "running"},
+		mockStatusEvent{id: "b", status: "starting"},
+		mockIntEvent(0),
+	}, events)
+}
+
+func (suite *BufferedListenerTestSuite) TestClose() {
+	b := NewBufferedListener(suite.listener, 10)
+	close(suite.release)
+
+	b.Close()
+	b.Close()
+	b.OnEvent(mockIntEvent(0))
+
+	select {
+	case <-suite.received:
+		suite.Fail("the listener received an event after being closed")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func (suite *BufferedListenerTestSuite) TestRemoveFromBusCloses() {
+	bus := NewEventBus()
+	b := NewBufferedListener(suite.listener, 10)
+
+	bus.AddListener(b)
+	suite.Equal(suite.listener.GetUUID(), b.GetUUID())
+
+	// Removing the inner listener removes and closes the buffered listener.
+	bus.RemoveListener(suite.listener)
+	suite.Len(*bus.listeners, 0)
+
+	select {
+	case <-b.done:
+	default:
+		suite.Fail("the buffered listener was not closed")
+	}
+}
+
+func (suite *BufferedListenerTestSuite) TestConcurrentDispatch() {
+	var count int
+	var mutex sync.Mutex
+	listener := NewTempListener(func(e interface{}) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		count++
+	})
+
+	b := NewBufferedListener(listener, 1000)
+	defer b.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				b.OnEvent(mockIntEvent(j))
+			}
+		}()
+	}
+	wg.Wait()
+
+	suite.Eventually(func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return count == 500
+	}, time.Second, time.Millisecond)
+}
+
+var _ Listener = (*BufferedListener)(nil)
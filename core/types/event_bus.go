@@ -29,10 +29,15 @@ func (b *EventBus) AddListener(l Listener) {
 	(*b.listeners)[l.GetUUID()] = l
 }
 
+// RemoveListener removes the listener with the same UUID as l. If the
+// listener was wrapped in a BufferedListener, the buffered listener is closed.
 func (b *EventBus) RemoveListener(l Listener) {
 	b.listenersMutex.Lock()
 	defer b.listenersMutex.Unlock()
 
+	if bl, ok := (*b.listeners)[l.GetUUID()].(*BufferedListener); ok {
+		bl.Close()
+	}
 	delete(*b.listeners, l.GetUUID())
 }
 
@@ -61,6 +66,7 @@ func (b *EventBus) Send(e interface{}) {
 			}
 			toNotify = append(toNotify, l)
 		}
+		count := len(*b.listeners)
 		b.listenersMutex.RUnlock()
 
 		if len(toNotify) == 0 {
@@ -69,7 +75,7 @@ func (b *EventBus) Send(e interface{}) {
 		}
 
 		if tryCount > 0 {
-			log.Debug("some listeners were not notified; retrying...", vlog.Any("count", len(*b.listeners)-len(notified)))
+			log.Debug("some listeners were not notified; retrying...", vlog.Any("count", count-len(notified)))
 		}
 		if tryCount > 10 {
 			log.Error(errors.New("after 10 retries to send events, there seems to be an issue with the event bus; the issue is probably caused by some listeners that create new listeners that themselves create new listeners, and so on"))
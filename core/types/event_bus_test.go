@@ -1,6 +1,7 @@
 package types
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/google/uuid"
@@ -42,6 +43,32 @@ func (suite *EventInMemoryAdapterTestSuite) TestEvents() {
 	assert.Equal(suite.T(), 0, len(*suite.adapter.listeners))
 }
 
+func (suite *EventInMemoryAdapterTestSuite) TestConcurrentListeners() {
+	bus := NewEventBus()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l := NewTempListener(func(e interface{}) {})
+				bus.AddListener(l)
+				bus.RemoveListener(l)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				bus.Send(MockEvent{})
+			}
+		}()
+	}
+	wg.Wait()
+
+	suite.Len(*bus.listeners, 0)
+}
+
 type MockEvent struct{}
 
 type MockListener struct {
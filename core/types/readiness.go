@@ -0,0 +1,18 @@
+package types
+
+const (
+	// ReadinessCheckDocker passes once the Docker daemon is reachable
+	// through the kernel.
+	ReadinessCheckDocker = "docker"
+
+	// ReadinessCheckContainers passes once the containers are loaded.
+	ReadinessCheckContainers = "vx-containers"
+)
+
+type Readiness struct {
+	// Ready is true when all the checks passed.
+	Ready bool `json:"ready"`
+
+	// Checks are the state of each check, by name.
+	Checks map[string]bool `json:"checks"`
+}
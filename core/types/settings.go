@@ -1,5 +1,19 @@
 package types
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"golang.org/x/exp/slices"
+)
+
+var (
+	ErrCustomSettingNotFound = errors.New("custom setting not found")
+	ErrInvalidSettings       = errors.New("invalid settings")
+)
+
 type SettingsNotifications struct {
 	Webhook *string `json:"webhook,omitempty"`
 }
@@ -11,11 +25,47 @@ const (
 	SettingsUpdatesChannelBeta   SettingsUpdatesChannel = "beta"
 )
 
+// SettingsUpdatesChannels are all the update channels, from the most stable.
+var SettingsUpdatesChannels = []SettingsUpdatesChannel{
+	SettingsUpdatesChannelStable,
+	SettingsUpdatesChannelBeta,
+}
+
 type SettingsUpdates struct {
 	Channel *SettingsUpdatesChannel `json:"channel,omitempty"`
 }
 
+// SettingsCustom are the settings of the apps, by namespace then by key,
+// so they don't need to be added to the Settings type.
+type SettingsCustom map[string]map[string]json.RawMessage
+
 type Settings struct {
 	Notifications *SettingsNotifications `json:"notifications,omitempty"`
 	Updates       *SettingsUpdates       `json:"updates,omitempty"`
+	Custom        SettingsCustom         `json:"custom,omitempty"`
+}
+
+// Validate returns ErrInvalidSettings if the settings can't be applied, for
+// example when importing them.
+func (s Settings) Validate() error {
+	if s.Notifications != nil && s.Notifications.Webhook != nil && *s.Notifications.Webhook != "" {
+		u, err := url.Parse(*s.Notifications.Webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: the webhook must be an http or https URL", ErrInvalidSettings)
+		}
+	}
+	if s.Updates != nil && s.Updates.Channel != nil && !slices.Contains(SettingsUpdatesChannels, *s.Updates.Channel) {
+		return fmt.Errorf("%w: unknown update channel %s", ErrInvalidSettings, *s.Updates.Channel)
+	}
+	for namespace, values := range s.Custom {
+		if namespace == "" {
+			return fmt.Errorf("%w: empty custom namespace", ErrInvalidSettings)
+		}
+		for key := range values {
+			if key == "" {
+				return fmt.Errorf("%w: empty custom key in %s", ErrInvalidSettings, namespace)
+			}
+		}
+	}
+	return nil
 }
@@ -0,0 +1,37 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SettingsTestSuite struct {
+	suite.Suite
+}
+
+func TestSettingsTestSuite(t *testing.T) {
+	suite.Run(t, new(SettingsTestSuite))
+}
+
+func (suite *SettingsTestSuite) TestValidate() {
+	webhook := "https://discord.com/api/webhooks/1/abc"
+	channel := SettingsUpdatesChannelBeta
+	settings := Settings{
+		Notifications: &SettingsNotifications{Webhook: &webhook},
+		Updates:       &SettingsUpdates{Channel: &channel},
+		Custom: SettingsCustom{
+			"vx-containers": {"idle_timeout": json.RawMessage("10")},
+		},
+	}
+	suite.NoError(settings.Validate())
+	suite.NoError(Settings{}.Validate())
+
+	webhook = "not a url"
+	suite.ErrorIs(settings.Validate(), ErrInvalidSettings)
+
+	webhook = ""
+	channel = "nightly"
+	suite.ErrorIs(settings.Validate(), ErrInvalidSettings)
+}
@@ -3,7 +3,10 @@ package types
 import "errors"
 
 var (
-	ErrAlreadyUpdating = errors.New("an update is already in progress, cannot start another")
+	ErrAlreadyUpdating       = errors.New("an update is already in progress, cannot start another")
+	ErrUpdaterNotFound       = errors.New("updater not found")
+	ErrVersionsNotListable   = errors.New("the versions of this updater cannot be listed")
+	ErrUpdateVersionNotFound = errors.New("this version is not available")
 )
 
 type Update struct {
@@ -11,9 +14,28 @@ type Update struct {
 	Updating bool     `json:"updating"` // Updating is true if an update is currently in progress.
 }
 
+// UpdateChannels describes the versions available on each update channel.
+type UpdateChannels struct {
+	Channel  SettingsUpdatesChannel `json:"channel"`  // Channel is the channel currently selected.
+	Updaters []UpdaterVersions      `json:"updaters"` // Updaters are the versions of each dependency.
+}
+
+type UpdaterVersions struct {
+	ID             string                            `json:"id"`
+	CurrentVersion string                            `json:"current_version"`
+	Latest         map[SettingsUpdatesChannel]string `json:"latest"` // Latest is the latest version available on each channel.
+}
+
 type Updater interface {
 	CurrentVersion() (string, error)
 	Install(version string) error
 	IsInstalled() bool
 	ID() string
 }
+
+// VersionsLister is implemented by the updaters that can list the versions
+// available to install.
+type VersionsLister interface {
+	// Versions returns the available versions, from the newest.
+	Versions() ([]string, error)
+}
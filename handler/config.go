@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"github.com/vertex-center/vertex/config"
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type ConfigHandler struct{}
+
+func NewConfigHandler() port.ConfigHandler {
+	return &ConfigHandler{}
+}
+
+// Get returns the configuration in use, after the defaults, the file, the
+// environment variables and the flags are applied.
+func (h *ConfigHandler) Get(c *router.Context) {
+	c.JSON(config.Current.Redacted())
+}
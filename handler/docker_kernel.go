@@ -2,11 +2,14 @@ package handler
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"github.com/vertex-center/vertex/core/port"
 	"github.com/vertex-center/vertex/core/types"
 	"github.com/vertex-center/vertex/core/types/api"
 	"io"
+	"strconv"
+	"strings"
 
 	"github.com/docker/docker/client"
 	"github.com/vertex-center/vertex/pkg/log"
@@ -23,8 +26,33 @@ func NewDockerKernelHandler(dockerKernelService port.DockerService) port.DockerK
 	}
 }
 
+func (h *DockerKernelHandler) Ping(c *router.Context) {
+	err := h.dockerService.Ping()
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrDockerUnavailable,
+			PublicMessage:  "Docker is not available.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+// GetContainers lists the containers. They can be filtered with the
+// label=key=value and name query parameters.
 func (h *DockerKernelHandler) GetContainers(c *router.Context) {
-	containers, err := h.dockerService.ListContainers()
+	options := types.ListContainersOptions{
+		Labels: map[string]string{},
+		Name:   c.Query("name"),
+	}
+	for _, label := range c.QueryArray("label") {
+		key, value, _ := strings.Cut(label, "=")
+		options.Labels[key] = value
+	}
+
+	containers, err := h.dockerService.ListContainers(options)
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToListContainers,
@@ -45,7 +73,28 @@ func (h *DockerKernelHandler) CreateContainer(c *router.Context) {
 	}
 
 	res, err := h.dockerService.CreateContainer(options)
-	if err != nil {
+	if errors.Is(err, types.ErrSysctlNotAllowed) {
+		c.BadRequest(router.Error{
+			Code:           api.ErrSysctlNotAllowed,
+			PublicMessage:  "A sysctl is not allowed in a container.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if errors.Is(err, types.ErrInvalidExtraHost) {
+		c.BadRequest(router.Error{
+			Code:           api.ErrInvalidExtraHost,
+			PublicMessage:  "An extra host is invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if errors.Is(err, types.ErrInvalidShmSize) {
+		c.BadRequest(router.Error{
+			Code:           api.ErrInvalidShmSize,
+			PublicMessage:  "The shm size is invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToCreateContainer,
 			PublicMessage:  "Failed to create container.",
@@ -61,15 +110,8 @@ func (h *DockerKernelHandler) DeleteContainer(c *router.Context) {
 	id := c.Param("id")
 
 	err := h.dockerService.DeleteContainer(id)
-	if err != nil && client.IsErrNotFound(err) {
-		c.NotFound(router.Error{
-			Code:           api.ErrContainerNotFound,
-			PublicMessage:  fmt.Sprintf("Container %s not found.", id),
-			PrivateMessage: err.Error(),
-		})
-		return
-	} else if err != nil {
-		c.Abort(router.Error{
+	if err != nil {
+		abortContainer(c, id, err, router.Error{
 			Code:           api.ErrFailedToDeleteContainer,
 			PublicMessage:  fmt.Sprintf("Failed to delete container %s.", id),
 			PrivateMessage: err.Error(),
@@ -85,7 +127,7 @@ func (h *DockerKernelHandler) StartContainer(c *router.Context) {
 
 	err := h.dockerService.StartContainer(id)
 	if err != nil {
-		c.Abort(router.Error{
+		abortContainer(c, id, err, router.Error{
 			Code:           api.ErrFailedToStartContainer,
 			PublicMessage:  fmt.Sprintf("Failed to start container %s.", id),
 			PrivateMessage: err.Error(),
@@ -101,7 +143,7 @@ func (h *DockerKernelHandler) StopContainer(c *router.Context) {
 
 	err := h.dockerService.StopContainer(id)
 	if err != nil {
-		c.Abort(router.Error{
+		abortContainer(c, id, err, router.Error{
 			Code:           api.ErrFailedToStopContainer,
 			PublicMessage:  fmt.Sprintf("Failed to stop container %s.", id),
 			PrivateMessage: err.Error(),
@@ -112,12 +154,44 @@ func (h *DockerKernelHandler) StopContainer(c *router.Context) {
 	c.OK()
 }
 
+func (h *DockerKernelHandler) PauseContainer(c *router.Context) {
+	id := c.Param("id")
+
+	err := h.dockerService.PauseContainer(id)
+	if err != nil {
+		abortContainer(c, id, err, router.Error{
+			Code:           api.ErrFailedToPauseContainer,
+			PublicMessage:  fmt.Sprintf("Failed to pause container %s.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+func (h *DockerKernelHandler) UnpauseContainer(c *router.Context) {
+	id := c.Param("id")
+
+	err := h.dockerService.UnpauseContainer(id)
+	if err != nil {
+		abortContainer(c, id, err, router.Error{
+			Code:           api.ErrFailedToUnpauseContainer,
+			PublicMessage:  fmt.Sprintf("Failed to unpause container %s.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
 func (h *DockerKernelHandler) InfoContainer(c *router.Context) {
 	id := c.Param("id")
 
 	info, err := h.dockerService.InfoContainer(id)
 	if err != nil {
-		c.Abort(router.Error{
+		abortContainer(c, id, err, router.Error{
 			Code:           api.ErrFailedToGetContainerInfo,
 			PublicMessage:  fmt.Sprintf("Failed to get info for container %s.", id),
 			PrivateMessage: err.Error(),
@@ -131,7 +205,12 @@ func (h *DockerKernelHandler) InfoContainer(c *router.Context) {
 func (h *DockerKernelHandler) LogsStdoutContainer(c *router.Context) {
 	id := c.Param("id")
 
-	stdout, err := h.dockerService.LogsStdoutContainer(id)
+	options, ok := getLogsOptions(c)
+	if !ok {
+		return
+	}
+
+	stdout, err := h.dockerService.LogsStdoutContainer(id, options)
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToGetContainerLogs,
@@ -164,7 +243,12 @@ func (h *DockerKernelHandler) LogsStdoutContainer(c *router.Context) {
 func (h *DockerKernelHandler) LogsStderrContainer(c *router.Context) {
 	id := c.Param("id")
 
-	stderr, err := h.dockerService.LogsStderrContainer(id)
+	options, ok := getLogsOptions(c)
+	if !ok {
+		return
+	}
+
+	stderr, err := h.dockerService.LogsStderrContainer(id, options)
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToGetContainerLogs,
@@ -194,13 +278,34 @@ func (h *DockerKernelHandler) LogsStderrContainer(c *router.Context) {
 	})
 }
 
+// getLogsOptions returns the options of the logs from the query. The tail is
+// a number of lines or "all", and timestamps=true prefixes the lines with
+// their timestamp. It returns false if the query is invalid.
+func getLogsOptions(c *router.Context) (types.LogsContainerOptions, bool) {
+	tail := c.DefaultQuery("tail", "0")
+	if tail != "all" {
+		n, err := strconv.Atoi(tail)
+		if err != nil || n < 0 {
+			c.BadRequest(router.Error{
+				Code:          api.ErrInvalidLogsTail,
+				PublicMessage: fmt.Sprintf("Invalid tail: '%s'. Use a number of lines or 'all'.", tail),
+			})
+			return types.LogsContainerOptions{}, false
+		}
+	}
+	return types.LogsContainerOptions{
+		Tail:       tail,
+		Timestamps: c.Query("timestamps") == "true",
+	}, true
+}
+
 func (h *DockerKernelHandler) WaitContainer(c *router.Context) {
 	id := c.Param("id")
 	cond := c.Param("cond")
 
 	err := h.dockerService.WaitContainer(id, types.WaitContainerCondition(cond))
 	if err != nil {
-		c.Abort(router.Error{
+		abortContainer(c, id, err, router.Error{
 			Code:           api.ErrFailedToWaitContainer,
 			PublicMessage:  fmt.Sprintf("Failed to wait the event '%s' for container %s.", cond, id),
 			PrivateMessage: err.Error(),
@@ -211,6 +316,39 @@ func (h *DockerKernelHandler) WaitContainer(c *router.Context) {
 	c.OK()
 }
 
+// CopyToContainer extracts the tar archive of the body in the directory
+// given by the path query parameter.
+func (h *DockerKernelHandler) CopyToContainer(c *router.Context) {
+	id := c.Param("id")
+	dir := c.Query("path")
+
+	err := h.dockerService.CopyToContainer(id, dir, c.Request.Body)
+	if err != nil {
+		abortContainer(c, id, err, router.Error{
+			Code:           api.ErrFailedToCopyToContainer,
+			PublicMessage:  fmt.Sprintf("Failed to copy files to container %s.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+// abortContainer replies 404 Not Found if the container doesn't exist, so
+// the clients can tell it apart from other failures.
+func abortContainer(c *router.Context, id string, err error, e router.Error) {
+	if client.IsErrNotFound(err) {
+		c.NotFound(router.Error{
+			Code:           api.ErrContainerNotFound,
+			PublicMessage:  fmt.Sprintf("Container %s not found.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+	c.Abort(e)
+}
+
 func (h *DockerKernelHandler) InfoImage(c *router.Context) {
 	id := c.Param("id")
 
@@ -234,7 +372,8 @@ func (h *DockerKernelHandler) PullImage(c *router.Context) {
 		return
 	}
 
-	r, err := h.dockerService.PullImage(options)
+	// The pull is cancelled when the client goes away.
+	r, err := h.dockerService.PullImage(c.Request.Context(), options)
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToPullImage,
@@ -271,7 +410,7 @@ func (h *DockerKernelHandler) BuildImage(c *router.Context) {
 		return
 	}
 
-	res, err := h.dockerService.BuildImage(options)
+	res, err := h.dockerService.BuildImage(c.Request.Context(), options)
 	if err != nil {
 		c.Abort(router.Error{
 			Code:           api.ErrFailedToBuildImage,
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/vertex-center/vertex/core/port"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type HealthHandler struct {
+	readinessService port.ReadinessService
+}
+
+func NewHealthHandler(readinessService port.ReadinessService) port.HealthHandler {
+	return &HealthHandler{
+		readinessService: readinessService,
+	}
+}
+
+// Get replies 200 OK once Vertex is ready, and 503 Service Unavailable
+// before. The body details the state of each check in both cases.
+func (h *HealthHandler) Get(c *router.Context) {
+	readiness := h.readinessService.Get()
+	if !readiness.Ready {
+		c.Context.JSON(http.StatusServiceUnavailable, readiness)
+		return
+	}
+	c.JSON(readiness)
+}
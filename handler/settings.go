@@ -1,6 +1,10 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
 	"github.com/vertex-center/vertex/core/port"
 	"github.com/vertex-center/vertex/core/types"
 	"github.com/vertex-center/vertex/core/types/api"
@@ -40,3 +44,84 @@ func (h *SettingsHandler) Patch(c *router.Context) {
 
 	c.OK()
 }
+
+// Export sends all the settings as a file, to be imported later.
+func (h *SettingsHandler) Export(c *router.Context) {
+	c.Header("Content-Disposition", "attachment; filename=vertex-settings.json")
+	c.JSON(h.settingsService.Get())
+}
+
+func (h *SettingsHandler) Import(c *router.Context) {
+	var settings types.Settings
+	err := c.ParseBody(&settings)
+	if err != nil {
+		return
+	}
+
+	err = h.settingsService.Import(settings)
+	if errors.Is(err, types.ErrInvalidSettings) {
+		c.BadRequest(router.Error{
+			Code:           api.ErrInvalidSettings,
+			PublicMessage:  "The settings to import are invalid.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToImportSettings,
+			PublicMessage:  "Failed to import the settings.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
+func (h *SettingsHandler) GetCustom(c *router.Context) {
+	namespace := c.Param("namespace")
+	key := c.Param("key")
+
+	var value json.RawMessage
+	err := h.settingsService.GetCustom(namespace, key, &value)
+	if errors.Is(err, types.ErrCustomSettingNotFound) {
+		c.NotFound(router.Error{
+			Code:           api.ErrCustomSettingNotFound,
+			PublicMessage:  fmt.Sprintf("The setting %s/%s is not set.", namespace, key),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToGetCustomSetting,
+			PublicMessage:  fmt.Sprintf("Failed to get the setting %s/%s.", namespace, key),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(value)
+}
+
+func (h *SettingsHandler) SetCustom(c *router.Context) {
+	namespace := c.Param("namespace")
+	key := c.Param("key")
+
+	var value json.RawMessage
+	err := c.ParseBody(&value)
+	if err != nil {
+		return
+	}
+
+	err = h.settingsService.SetCustom(namespace, key, value)
+	if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToSetCustomSetting,
+			PublicMessage:  fmt.Sprintf("Failed to set the setting %s/%s.", namespace, key),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"github.com/vertex-center/vertex/core/port"
 	types2 "github.com/vertex-center/vertex/core/types"
 	"github.com/vertex-center/vertex/core/types/api"
@@ -43,6 +44,116 @@ func (h *UpdateHandler) Get(c *router.Context) {
 	c.JSON(update)
 }
 
+func (h *UpdateHandler) GetChannels(c *router.Context) {
+	updaters, err := h.updateService.GetChannels()
+	if errors.Is(err, types2.ErrFailedToFetchBaseline) {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToFetchLatestVersion,
+			PublicMessage:  "Failed to retrieve latest version information.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToGetUpdates,
+			PublicMessage:  "Failed to retrieve the update channels.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(types2.UpdateChannels{
+		Channel:  h.settingsService.GetChannel(),
+		Updaters: updaters,
+	})
+}
+
+func (h *UpdateHandler) GetVersions(c *router.Context) {
+	id := c.Param("updater_id")
+
+	versions, err := h.updateService.GetVersions(id)
+	if errors.Is(err, types2.ErrUpdaterNotFound) {
+		c.NotFound(router.Error{
+			Code:           api.ErrUpdaterNotFound,
+			PublicMessage:  fmt.Sprintf("Updater '%s' not found.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if errors.Is(err, types2.ErrVersionsNotListable) {
+		c.BadRequest(router.Error{
+			Code:           api.ErrVersionsNotListable,
+			PublicMessage:  fmt.Sprintf("The versions of '%s' cannot be listed.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToGetVersions,
+			PublicMessage:  fmt.Sprintf("Failed to retrieve the versions of '%s'.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(versions)
+}
+
+type InstallVersionBody struct {
+	Version string `json:"version"`
+}
+
+func (h *UpdateHandler) InstallVersion(c *router.Context) {
+	id := c.Param("updater_id")
+
+	var body InstallVersionBody
+	err := c.ParseBody(&body)
+	if err != nil {
+		return
+	}
+
+	if body.Version == "" {
+		c.BadRequest(router.Error{
+			Code:           api.ErrUpdateVersionMissing,
+			PublicMessage:  "The request was missing the version to install.",
+			PrivateMessage: "Field 'version' is required.",
+		})
+		return
+	}
+
+	err = h.updateService.InstallVersion(id, body.Version)
+	if errors.Is(err, types2.ErrUpdaterNotFound) {
+		c.NotFound(router.Error{
+			Code:           api.ErrUpdaterNotFound,
+			PublicMessage:  fmt.Sprintf("Updater '%s' not found.", id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if errors.Is(err, types2.ErrUpdateVersionNotFound) {
+		c.NotFound(router.Error{
+			Code:           api.ErrUpdateVersionNotFound,
+			PublicMessage:  fmt.Sprintf("Version '%s' of '%s' not found.", body.Version, id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if errors.Is(err, types2.ErrAlreadyUpdating) {
+		c.Abort(router.Error{
+			Code:           api.ErrAlreadyUpdating,
+			PublicMessage:  "Vertex is already Updating. Please wait for the update to finish.",
+			PrivateMessage: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.Abort(router.Error{
+			Code:           api.ErrFailedToInstallUpdates,
+			PublicMessage:  fmt.Sprintf("Failed to install version '%s' of '%s'.", body.Version, id),
+			PrivateMessage: err.Error(),
+		})
+		return
+	}
+
+	c.OK()
+}
+
 func (h *UpdateHandler) Install(c *router.Context) {
 	channel := h.settingsService.GetChannel()
 
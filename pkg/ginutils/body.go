@@ -0,0 +1,29 @@
+package ginutils
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+// MaxBodySize limits the size of the request bodies to limit bytes. The
+// requests announcing a larger body are rejected with a 413, and reading
+// past the limit fails, which router.Context.ParseBody also reports with a
+// 413. A limit of 0 or less disables the check.
+func MaxBodySize(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, router.ErrorRequestTooLarge(limit))
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
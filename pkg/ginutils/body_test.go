@@ -0,0 +1,68 @@
+package ginutils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type MaxBodySizeTestSuite struct {
+	suite.Suite
+
+	engine *gin.Engine
+}
+
+func TestMaxBodySizeTestSuite(t *testing.T) {
+	suite.Run(t, new(MaxBodySizeTestSuite))
+}
+
+func (suite *MaxBodySizeTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	suite.engine = gin.New()
+	suite.engine.Use(ErrorHandler())
+	suite.engine.Use(MaxBodySize(16))
+	suite.engine.POST("/echo", func(c *gin.Context) {
+		var body map[string]string
+		err := (&router.Context{Context: c}).ParseBody(&body)
+		if err != nil {
+			return
+		}
+		c.JSON(http.StatusOK, body)
+	})
+}
+
+func (suite *MaxBodySizeTestSuite) post(body string, contentLength int64) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(body))
+	req.ContentLength = contentLength
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	return w
+}
+
+func (suite *MaxBodySizeTestSuite) TestUnderLimit() {
+	w := suite.post(`{"a":"b"}`, 9)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal(`{"a":"b"}`, w.Body.String())
+}
+
+func (suite *MaxBodySizeTestSuite) TestContentLengthOverLimit() {
+	w := suite.post(`{"a":"bbbbbbbbbbbbbbbb"}`, 24)
+
+	suite.Equal(http.StatusRequestEntityTooLarge, w.Code)
+	suite.Contains(w.Body.String(), string(router.ErrRequestTooLarge))
+}
+
+func (suite *MaxBodySizeTestSuite) TestChunkedOverLimit() {
+	// Without a Content-Length, the limit is hit while reading the body.
+	w := suite.post(`{"a":"bbbbbbbbbbbbbbbb"}`, -1)
+
+	suite.Equal(http.StatusRequestEntityTooLarge, w.Code)
+	suite.Contains(w.Body.String(), string(router.ErrRequestTooLarge))
+}
@@ -0,0 +1,268 @@
+package ginutils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertex-center/vertex/pkg/log"
+)
+
+// CompressConfig configures the Compress middleware.
+type CompressConfig struct {
+	// MinSize is the size in bytes under which the responses are sent
+	// uncompressed, as the gzip overhead would outweigh the gain.
+	MinSize int
+
+	// ContentTypes are the media types of the compressed responses, like
+	// application/json. A type ending with /* matches all its subtypes.
+	ContentTypes []string
+
+	// Level is the gzip compression level.
+	Level int
+}
+
+// DefaultCompressConfig compresses the text responses of 1KB or more.
+func DefaultCompressConfig() CompressConfig {
+	return CompressConfig{
+		MinSize: 1024,
+		ContentTypes: []string{
+			"text/*",
+			"application/json",
+			"application/javascript",
+			"application/xml",
+			"image/svg+xml",
+		},
+		Level: gzip.DefaultCompression,
+	}
+}
+
+// Compress compresses the responses with gzip, for the clients accepting
+// it. The responses that are already encoded, partial, or streamed with
+// server-sent events are sent as is.
+func Compress(config CompressConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead ||
+			!acceptsGzip(c.GetHeader("Accept-Encoding")) ||
+			strings.Contains(c.GetHeader("Accept"), "text/event-stream") ||
+			c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		w := &compressWriter{
+			ResponseWriter: c.Writer,
+			config:         &config,
+		}
+		c.Writer = w
+		defer func() {
+			c.Writer = w.ResponseWriter
+		}()
+
+		c.Next()
+
+		err := w.close()
+		if err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+// acceptsGzip returns true if the Accept-Encoding header allows gzip.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		encoding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		encoding = strings.TrimSpace(encoding)
+		if encoding != "gzip" && encoding != "*" {
+			continue
+		}
+
+		q, ok := strings.CutPrefix(strings.TrimSpace(params), "q=")
+		if !ok {
+			return true
+		}
+		value, err := strconv.ParseFloat(q, 64)
+		return err == nil && value > 0
+	}
+	return false
+}
+
+// compressWriter buffers the start of the body, up to MinSize, to decide if
+// the response is worth compressing.
+type compressWriter struct {
+	gin.ResponseWriter
+	config *CompressConfig
+
+	status  int
+	buf     bytes.Buffer
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code > 0 {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow sends the buffered status, like when a request is aborted.
+// The body written afterward is sent uncompressed.
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		err := w.decide()
+		if err != nil {
+			log.Error(err)
+		}
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *compressWriter) Status() int {
+	if !w.decided && w.status != 0 {
+		return w.status
+	}
+	return w.ResponseWriter.Status()
+}
+
+func (w *compressWriter) Written() bool {
+	if !w.decided && w.buf.Len() > 0 {
+		return true
+	}
+	return w.ResponseWriter.Written()
+}
+
+func (w *compressWriter) Size() int {
+	if !w.decided && w.buf.Len() > 0 {
+		return w.buf.Len()
+	}
+	return w.ResponseWriter.Size()
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf.Write(b)
+	if w.buf.Len() >= w.config.MinSize {
+		err := w.decide()
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends the buffered body. A response flushed before MinSize, like a
+// stream, is sent uncompressed.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		err := w.decide()
+		if err != nil {
+			log.Error(err)
+		}
+	}
+	if w.gz != nil {
+		err := w.gz.Flush()
+		if err != nil {
+			log.Error(err)
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide writes the header and the buffered body, compressed or not.
+func (w *compressWriter) decide() error {
+	w.decided = true
+
+	compress := w.buf.Len() >= w.config.MinSize && w.compressible()
+	if compress {
+		w.Header().Del("Content-Length")
+		w.Header().Set("Content-Encoding", "gzip")
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+
+	if !compress {
+		if w.buf.Len() == 0 {
+			return nil
+		}
+		_, err := w.ResponseWriter.Write(w.buf.Bytes())
+		return err
+	}
+
+	gz, err := gzip.NewWriterLevel(w.ResponseWriter, w.config.Level)
+	if err != nil {
+		return err
+	}
+	w.gz = gz
+	_, err = w.gz.Write(w.buf.Bytes())
+	return err
+}
+
+func (w *compressWriter) compressible() bool {
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" {
+		return false
+	}
+
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent ||
+		status == http.StatusPartialContent || status == http.StatusNotModified {
+		return false
+	}
+
+	contentType := h.Get("Content-Type")
+	if contentType == "" {
+		// The type must be set before compressing, or it would be sniffed
+		// from the compressed bytes.
+		contentType = http.DetectContentType(w.buf.Bytes())
+		h.Set("Content-Type", contentType)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, t := range w.config.ContentTypes {
+		if t == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// close sends the rest of the response.
+func (w *compressWriter) close() error {
+	if !w.decided {
+		err := w.decide()
+		if err != nil {
+			return err
+		}
+	}
+	if w.gz != nil {
+		return w.gz.Close()
+	}
+	return nil
+}
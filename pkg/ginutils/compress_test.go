@@ -0,0 +1,137 @@
+package ginutils
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type CompressTestSuite struct {
+	suite.Suite
+
+	engine *gin.Engine
+	large  string
+}
+
+func TestCompressTestSuite(t *testing.T) {
+	suite.Run(t, new(CompressTestSuite))
+}
+
+func (suite *CompressTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	suite.large = strings.Repeat("vertex ", 500)
+	suite.engine = gin.New()
+	suite.engine.Use(ErrorHandler())
+	suite.engine.Use(Compress(DefaultCompressConfig()))
+	suite.engine.GET("/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": suite.large})
+	})
+	suite.engine.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "pong"})
+	})
+	suite.engine.GET("/encoded", func(c *gin.Context) {
+		c.Header("Content-Encoding", "br")
+		c.String(http.StatusOK, suite.large)
+	})
+	suite.engine.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(suite.large))
+	})
+	suite.engine.GET("/not-found", func(c *gin.Context) {
+		(&router.Context{Context: c}).NotFound(router.Error{
+			Code:          "not_found",
+			PublicMessage: "Not found.",
+		})
+	})
+	suite.engine.GET("/unavailable", func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusServiceUnavailable)
+	})
+	suite.engine.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		_, _ = c.Writer.WriteString("data: ping\n\n")
+		c.Writer.Flush()
+		_, _ = c.Writer.WriteString("data: " + suite.large + "\n\n")
+	})
+}
+
+func (suite *CompressTestSuite) request(path string, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	return w
+}
+
+func (suite *CompressTestSuite) TestCompress() {
+	w := suite.request("/large", "gzip, deflate")
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("gzip", w.Header().Get("Content-Encoding"))
+	suite.Equal("Accept-Encoding", w.Header().Get("Vary"))
+	suite.Equal("application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	r, err := gzip.NewReader(w.Body)
+	suite.Require().NoError(err)
+	body, err := io.ReadAll(r)
+	suite.Require().NoError(err)
+	suite.Equal(`{"message":"`+suite.large+`"}`, string(body))
+}
+
+func (suite *CompressTestSuite) TestSkip() {
+	tests := []struct {
+		path           string
+		acceptEncoding string
+	}{
+		{"/large", ""},
+		{"/large", "gzip;q=0"},
+		{"/small", "gzip"},
+		{"/encoded", "gzip"},
+		{"/image", "gzip"},
+	}
+
+	for _, test := range tests {
+		w := suite.request(test.path, test.acceptEncoding)
+		suite.Equal(http.StatusOK, w.Code, test.path)
+		suite.NotEqual("gzip", w.Header().Get("Content-Encoding"), test.path)
+	}
+
+	w := suite.request("/small", "gzip")
+	suite.Equal(`{"message":"pong"}`, w.Body.String())
+}
+
+func (suite *CompressTestSuite) TestAbort() {
+	for _, acceptEncoding := range []string{"", "gzip"} {
+		w := suite.request("/not-found", acceptEncoding)
+		suite.Equal(http.StatusNotFound, w.Code, acceptEncoding)
+		suite.Empty(w.Header().Get("Content-Encoding"), acceptEncoding)
+		suite.JSONEq(`{"code":"not_found","message":"Not found."}`, w.Body.String(), acceptEncoding)
+
+		w = suite.request("/unavailable", acceptEncoding)
+		suite.Equal(http.StatusServiceUnavailable, w.Code, acceptEncoding)
+	}
+}
+
+func (suite *CompressTestSuite) TestStream() {
+	w := suite.request("/stream", "gzip")
+
+	suite.Empty(w.Header().Get("Content-Encoding"))
+	suite.Equal("data: ping\n\ndata: "+suite.large+"\n\n", w.Body.String())
+}
+
+func (suite *CompressTestSuite) TestAcceptsGzip() {
+	suite.True(acceptsGzip("gzip"))
+	suite.True(acceptsGzip("deflate, gzip;q=0.5"))
+	suite.True(acceptsGzip("*"))
+	suite.False(acceptsGzip(""))
+	suite.False(acceptsGzip("br, deflate"))
+	suite.False(acceptsGzip("gzip;q=0"))
+}
@@ -0,0 +1,28 @@
+package ginutils
+
+import (
+	"net/http"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// CORS adds the CORS headers to the responses, and replies directly to the
+// preflight requests. It must be registered before any authentication
+// middleware: browsers send preflight requests without credentials, so they
+// must never reach the handlers.
+func CORS() gin.HandlerFunc {
+	config := cors.DefaultConfig()
+	config.AllowAllOrigins = true
+	config.AllowHeaders = append(config.AllowHeaders, "Authorization")
+
+	handler := cors.New(config)
+
+	return func(c *gin.Context) {
+		handler(c)
+
+		if c.Request.Method == http.MethodOptions && !c.IsAborted() {
+			c.AbortWithStatus(http.StatusNoContent)
+		}
+	}
+}
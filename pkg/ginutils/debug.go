@@ -0,0 +1,37 @@
+package ginutils
+
+import (
+	"expvar"
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Debug mounts the net/http/pprof profiles on /debug/pprof and the expvar
+// variables on /debug/vars. These endpoints expose the internals of the
+// process, so they must only be mounted in debug mode.
+func Debug(r gin.IRouter) {
+	debug := r.Group("/debug", func(c *gin.Context) {
+		// The CPU profiles and the traces take as long as requested.
+		DisableTimeout(c)
+	})
+	debug.GET("/vars", gin.WrapH(expvar.Handler()))
+	debug.GET("/pprof/*name", pprofHandler)
+	debug.POST("/pprof/*name", pprofHandler)
+}
+
+func pprofHandler(c *gin.Context) {
+	switch c.Param("name") {
+	case "/cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "/profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "/symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "/trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		// Index also serves the named profiles, like /debug/pprof/heap.
+		pprof.Index(c.Writer, c.Request)
+	}
+}
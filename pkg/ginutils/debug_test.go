@@ -0,0 +1,55 @@
+package ginutils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type DebugTestSuite struct {
+	suite.Suite
+
+	engine *gin.Engine
+}
+
+func TestDebugTestSuite(t *testing.T) {
+	suite.Run(t, new(DebugTestSuite))
+}
+
+func (suite *DebugTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	suite.engine = gin.New()
+	Debug(suite.engine)
+}
+
+func (suite *DebugTestSuite) get(path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	return w
+}
+
+func (suite *DebugTestSuite) TestIndex() {
+	w := suite.get("/debug/pprof/")
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), "goroutine")
+}
+
+func (suite *DebugTestSuite) TestProfile() {
+	w := suite.get("/debug/pprof/goroutine?debug=1")
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), "goroutine profile")
+}
+
+func (suite *DebugTestSuite) TestVars() {
+	w := suite.get("/debug/vars")
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), "memstats")
+}
@@ -1,41 +1,129 @@
 package ginutils
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vlog"
 )
 
-func Logger(router string) gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(params gin.LogFormatterParams) string {
-		if params.ErrorMessage != "" {
-			errorMessage := strings.TrimSuffix(params.ErrorMessage, "\n")
-			errorMessage = strings.ReplaceAll(errorMessage, "Error #01: ", "")
-
-			log.Request("request",
-				vlog.String("router", router),
-				vlog.String("method", params.Method),
-				vlog.Int("status", params.StatusCode),
-				vlog.String("path", params.Path),
-				vlog.String("latency", params.Latency.String()),
-				vlog.String("ip", params.ClientIP),
-				vlog.Int("size", params.BodySize),
-				vlog.String("error", errorMessage),
-			)
-		} else {
-			log.Request("request",
-				vlog.String("router", router),
-				vlog.String("method", params.Method),
-				vlog.Int("status", params.StatusCode),
-				vlog.String("path", params.Path),
-				vlog.String("latency", params.Latency.String()),
-				vlog.String("ip", params.ClientIP),
-				vlog.Int("size", params.BodySize),
-			)
+// LogFormat is the format of the access logs.
+type LogFormat string
+
+const (
+	// LogFormatText writes the access logs with the Vertex logger.
+	LogFormatText LogFormat = "text"
+	// LogFormatJSON writes one JSON object per request on stdout, to be
+	// ingested by log pipelines like Loki or ELK.
+	LogFormatJSON LogFormat = "json"
+)
+
+const (
+	// HeaderRequestID is the header carrying the ID of the request.
+	HeaderRequestID = "X-Request-ID"
+	// KeyRequestID is the key of the request ID in the gin context.
+	KeyRequestID = "request_id"
+)
+
+// accessLog is an access log entry in the JSON format.
+type accessLog struct {
+	Time      string `json:"time"`
+	Router    string `json:"router"`
+	RequestID string `json:"request_id"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Status    int    `json:"status"`
+	Latency   int64  `json:"latency_ms"`
+	IP        string `json:"ip"`
+	Bytes     int    `json:"bytes"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Logger logs each request handled by the router, in the given format.
+// An unknown format falls back to LogFormatText. The request ID is read
+// from the X-Request-ID header, or generated, and sent back in the response.
+func Logger(router string, format LogFormat) gin.HandlerFunc {
+	return logger(router, format, os.Stdout)
+}
+
+func logger(router string, format LogFormat, out io.Writer) gin.HandlerFunc {
+	var formatter gin.LogFormatter
+	switch format {
+	case LogFormatJSON:
+		formatter = jsonFormatter(router)
+	default:
+		formatter = textFormatter(router)
+	}
+
+	handler := gin.LoggerWithConfig(gin.LoggerConfig{
+		Formatter: formatter,
+		Output:    out,
+	})
+
+	return func(c *gin.Context) {
+		id := c.GetHeader(HeaderRequestID)
+		if id == "" {
+			id = uuid.NewString()
 		}
+		c.Set(KeyRequestID, id)
+		c.Header(HeaderRequestID, id)
 
+		handler(c)
+	}
+}
+
+func jsonFormatter(router string) gin.LogFormatter {
+	return func(params gin.LogFormatterParams) string {
+		entry := accessLog{
+			Time:      params.TimeStamp.UTC().Format(time.RFC3339Nano),
+			Router:    router,
+			RequestID: params.Keys[KeyRequestID].(string),
+			Method:    params.Method,
+			Path:      params.Path,
+			Status:    params.StatusCode,
+			Latency:   params.Latency.Milliseconds(),
+			IP:        params.ClientIP,
+			Bytes:     params.BodySize,
+			Error:     errorMessage(params),
+		}
+
+		b, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Sprintf("{\"error\":%q}\n", err.Error())
+		}
+		return string(b) + "\n"
+	}
+}
+
+func textFormatter(router string) gin.LogFormatter {
+	return func(params gin.LogFormatterParams) string {
+		fields := []vlog.KeyValue{
+			vlog.String("router", router),
+			vlog.String("request_id", params.Keys[KeyRequestID].(string)),
+			vlog.String("method", params.Method),
+			vlog.Int("status", params.StatusCode),
+			vlog.String("path", params.Path),
+			vlog.String("latency", params.Latency.String()),
+			vlog.String("ip", params.ClientIP),
+			vlog.Int("size", params.BodySize),
+		}
+		if msg := errorMessage(params); msg != "" {
+			fields = append(fields, vlog.String("error", msg))
+		}
+
+		log.Request("request", fields...)
 		return ""
-	})
+	}
+}
+
+func errorMessage(params gin.LogFormatterParams) string {
+	msg := strings.TrimSuffix(params.ErrorMessage, "\n")
+	return strings.ReplaceAll(msg, "Error #01: ", "")
 }
@@ -0,0 +1,67 @@
+package ginutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoggerTestSuite struct {
+	suite.Suite
+
+	out    *bytes.Buffer
+	engine *gin.Engine
+}
+
+func TestLoggerTestSuite(t *testing.T) {
+	suite.Run(t, new(LoggerTestSuite))
+}
+
+func (suite *LoggerTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	suite.out = &bytes.Buffer{}
+	suite.engine = gin.New()
+	suite.engine.Use(logger("TEST", LogFormatJSON, suite.out))
+	suite.engine.GET("/ping", func(c *gin.Context) {
+		c.String(http.StatusTeapot, "pong")
+	})
+}
+
+func (suite *LoggerTestSuite) TestJSONFormat() {
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(HeaderRequestID, "abc")
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+
+	suite.Equal("abc", w.Header().Get(HeaderRequestID))
+
+	var entry accessLog
+	err := json.Unmarshal(suite.out.Bytes(), &entry)
+	suite.Require().NoError(err)
+	suite.Equal("TEST", entry.Router)
+	suite.Equal("abc", entry.RequestID)
+	suite.Equal(http.MethodGet, entry.Method)
+	suite.Equal("/ping", entry.Path)
+	suite.Equal(http.StatusTeapot, entry.Status)
+	suite.Equal(4, entry.Bytes)
+}
+
+func (suite *LoggerTestSuite) TestRequestIDGenerated() {
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+
+	id := w.Header().Get(HeaderRequestID)
+	suite.NotEmpty(id)
+
+	var entry accessLog
+	err := json.Unmarshal(suite.out.Bytes(), &entry)
+	suite.Require().NoError(err)
+	suite.Equal(id, entry.RequestID)
+}
@@ -0,0 +1,50 @@
+package ginutils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/router"
+	"github.com/vertex-center/vlog"
+)
+
+// Recovery recovers from the panics of the handlers. It logs the panic with
+// its stack and the request ID, and replies with a 500 and the
+// internal_error code. The http.ErrAbortHandler panics, used to abort a
+// response on purpose, are passed on to net/http.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			if err, ok := r.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(r)
+			}
+
+			err := fmt.Errorf("panic: %v", r)
+			log.Error(err,
+				vlog.String("request_id", c.GetString(KeyRequestID)),
+				vlog.String("method", c.Request.Method),
+				vlog.String("path", c.Request.URL.Path),
+				vlog.String("stack", string(debug.Stack())),
+			)
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, router.Error{
+				Code:          router.ErrInternalError,
+				PublicMessage: "An internal error occurred.",
+			})
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,54 @@
+package ginutils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type RecoveryTestSuite struct {
+	suite.Suite
+
+	engine *gin.Engine
+}
+
+func TestRecoveryTestSuite(t *testing.T) {
+	suite.Run(t, new(RecoveryTestSuite))
+}
+
+func (suite *RecoveryTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	suite.engine = gin.New()
+	suite.engine.Use(Recovery())
+	suite.engine.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	suite.engine.GET("/abort", func(c *gin.Context) {
+		panic(http.ErrAbortHandler)
+	})
+}
+
+func (suite *RecoveryTestSuite) TestPanic() {
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusInternalServerError, w.Code)
+	suite.Contains(w.Header().Get("Content-Type"), "application/json")
+	suite.Contains(w.Body.String(), string(router.ErrInternalError))
+	suite.NotContains(w.Body.String(), "boom")
+}
+
+func (suite *RecoveryTestSuite) TestAbortHandler() {
+	req := httptest.NewRequest(http.MethodGet, "/abort", nil)
+	w := httptest.NewRecorder()
+
+	suite.PanicsWithValue(http.ErrAbortHandler, func() {
+		suite.engine.ServeHTTP(w, req)
+	})
+}
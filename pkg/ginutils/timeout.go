@@ -0,0 +1,56 @@
+package ginutils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+const timeoutParentKey = "timeout_parent"
+
+// Timeout cancels the context of the requests after the given duration, and
+// replies with a 504 if the handler did not reply in time. The server-sent
+// events and WebSocket requests are not limited, and a streaming handler can
+// lift the limit with DisableTimeout. A duration of 0 or less disables it.
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 ||
+			strings.Contains(c.GetHeader("Accept"), "text/event-stream") ||
+			c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		parent := c.Request.Context()
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+
+		c.Set(timeoutParentKey, parent)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if !errors.Is(c.Request.Context().Err(), context.DeadlineExceeded) ||
+			c.Writer.Written() || len(c.Errors) > 0 {
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, router.ErrorRequestTimeout())
+	}
+}
+
+// DisableTimeout removes the limit set by Timeout on the current request.
+// It must be called by the handlers that stream their response, like
+// app.HeadersSSE does, or that have a longer limit of their own.
+func DisableTimeout(c *gin.Context) {
+	parent, ok := c.Get(timeoutParentKey)
+	if !ok {
+		return
+	}
+	c.Request = c.Request.WithContext(parent.(context.Context))
+}
@@ -0,0 +1,90 @@
+package ginutils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vertex/pkg/router"
+)
+
+type TimeoutTestSuite struct {
+	suite.Suite
+
+	engine *gin.Engine
+}
+
+func TestTimeoutTestSuite(t *testing.T) {
+	suite.Run(t, new(TimeoutTestSuite))
+}
+
+func (suite *TimeoutTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	suite.engine = gin.New()
+	suite.engine.Use(ErrorHandler())
+	suite.engine.Use(Timeout(20 * time.Millisecond))
+	suite.engine.GET("/fast", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	suite.engine.GET("/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+	suite.engine.GET("/fail", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		(&router.Context{Context: c}).Fail(c.Request.Context().Err(), router.Error{})
+	})
+	suite.engine.GET("/stream", func(c *gin.Context) {
+		DisableTimeout(c)
+		time.Sleep(40 * time.Millisecond)
+		if c.Request.Context().Err() != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.String(http.StatusOK, "ok")
+	})
+}
+
+func (suite *TimeoutTestSuite) get(path string, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Accept", accept)
+	w := httptest.NewRecorder()
+	suite.engine.ServeHTTP(w, req)
+	return w
+}
+
+func (suite *TimeoutTestSuite) TestInTime() {
+	w := suite.get("/fast", "")
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("ok", w.Body.String())
+}
+
+func (suite *TimeoutTestSuite) TestTimeout() {
+	w := suite.get("/slow", "")
+
+	suite.Equal(http.StatusGatewayTimeout, w.Code)
+	suite.Contains(w.Body.String(), string(router.ErrRequestTimeout))
+}
+
+func (suite *TimeoutTestSuite) TestTimeoutFail() {
+	w := suite.get("/fail", "")
+
+	suite.Equal(http.StatusGatewayTimeout, w.Code)
+	suite.Contains(w.Body.String(), string(router.ErrRequestTimeout))
+}
+
+func (suite *TimeoutTestSuite) TestDisableTimeout() {
+	w := suite.get("/stream", "")
+
+	suite.Equal(http.StatusOK, w.Code)
+}
+
+func (suite *TimeoutTestSuite) TestEventStream() {
+	w := suite.get("/stream", "text/event-stream")
+
+	suite.Equal(http.StatusOK, w.Code)
+}
@@ -0,0 +1,191 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vertex-center/vlog"
+)
+
+// FileOptions configures the log file written with UseFile.
+type FileOptions struct {
+	// Path is the path of the log file, like "live/logs/vertex.log".
+	Path string
+
+	// MaxSize is the size in bytes after which the file is rotated. 0
+	// only rotates the file each day.
+	MaxSize int64
+
+	// MaxAge is how long the rotated files are kept. 0 keeps them forever.
+	MaxAge time.Duration
+}
+
+// rotatingFile is a log file that is rotated each day, and when it grows
+// larger than maxSize. The rotated files are renamed with their rotation
+// time, like vertex-2006-01-02T15-04-05.000.log, and removed after maxAge.
+type rotatingFile struct {
+	mutex sync.Mutex
+
+	path    string
+	maxSize int64
+	maxAge  time.Duration
+
+	file   *os.File
+	size   int64
+	opened time.Time
+	now    func() time.Time
+}
+
+func newRotatingFile(opts FileOptions) (*rotatingFile, error) {
+	f := &rotatingFile{
+		path:    opts.Path,
+		maxSize: opts.MaxSize,
+		maxAge:  opts.MaxAge,
+		now:     time.Now,
+	}
+	err := f.open()
+	if err != nil {
+		return nil, err
+	}
+	f.cleanup()
+	return f, nil
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.shouldRotate(int64(len(p))) {
+		err := f.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *rotatingFile) shouldRotate(n int64) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.maxSize > 0 && f.size+n > f.maxSize {
+		return true
+	}
+	y1, m1, d1 := f.opened.Date()
+	y2, m2, d2 := f.now().Date()
+	return y1 != y2 || m1 != m2 || d1 != d2
+}
+
+func (f *rotatingFile) open() error {
+	err := os.MkdirAll(filepath.Dir(f.path), 0755)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.opened = f.now()
+	if f.size > 0 {
+		// A file left by a previous run is rotated on the day after its
+		// last write.
+		f.opened = info.ModTime()
+	}
+	return nil
+}
+
+func (f *rotatingFile) rotate() error {
+	err := f.file.Close()
+	if err != nil {
+		return err
+	}
+	f.file = nil
+
+	err = os.Rename(f.path, f.backupPath(f.now()))
+	if err != nil {
+		return err
+	}
+
+	err = f.open()
+	if err != nil {
+		return err
+	}
+	f.cleanup()
+	return nil
+}
+
+func (f *rotatingFile) backupPath(t time.Time) string {
+	ext := filepath.Ext(f.path)
+	base := strings.TrimSuffix(f.path, ext)
+	return fmt.Sprintf("%s-%s%s", base, t.Format("2006-01-02T15-04-05.000"), ext)
+}
+
+// cleanup removes the rotated files older than maxAge.
+func (f *rotatingFile) cleanup() {
+	if f.maxAge <= 0 {
+		return
+	}
+
+	ext := filepath.Ext(f.path)
+	pattern := strings.TrimSuffix(f.path, ext) + "-*" + ext
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return
+	}
+
+	limit := f.now().Add(-f.maxAge)
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil || info.ModTime().After(limit) {
+			continue
+		}
+		err = os.Remove(match)
+		if err != nil {
+			Default.Error(err, vlog.String("file", match))
+		}
+	}
+}
+
+// formatLine formats a line of the log file like the text files of vlog.
+func formatLine(t time.Time, tag vlog.Tag, msg string, fields []vlog.KeyValue) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s msg=%s", t.Format(time.DateTime), tag, msg))
+	for _, field := range fields {
+		sb.WriteString(fmt.Sprintf(" %s=%s", field.Key, field.Value))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -0,0 +1,102 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vlog"
+)
+
+type RotatingFileTestSuite struct {
+	suite.Suite
+
+	dir string
+	now time.Time
+}
+
+func TestRotatingFileTestSuite(t *testing.T) {
+	suite.Run(t, new(RotatingFileTestSuite))
+}
+
+func (suite *RotatingFileTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+	suite.now = time.Date(2023, 10, 1, 12, 0, 0, 0, time.Local)
+}
+
+func (suite *RotatingFileTestSuite) newFile(opts FileOptions) *rotatingFile {
+	opts.Path = filepath.Join(suite.dir, "vertex.log")
+	f, err := newRotatingFile(opts)
+	suite.Require().NoError(err)
+	f.now = func() time.Time { return suite.now }
+	f.opened = suite.now
+	suite.T().Cleanup(func() { _ = f.Close() })
+	return f
+}
+
+func (suite *RotatingFileTestSuite) backups() []string {
+	matches, err := filepath.Glob(filepath.Join(suite.dir, "vertex-*.log"))
+	suite.Require().NoError(err)
+	return matches
+}
+
+func (suite *RotatingFileTestSuite) TestRotateOnSize() {
+	f := suite.newFile(FileOptions{MaxSize: 10})
+
+	_, err := f.Write([]byte("12345678\n"))
+	suite.Require().NoError(err)
+	suite.Empty(suite.backups())
+
+	_, err = f.Write([]byte("abcdefgh\n"))
+	suite.Require().NoError(err)
+	suite.Len(suite.backups(), 1)
+
+	content, err := os.ReadFile(filepath.Join(suite.dir, "vertex.log"))
+	suite.Require().NoError(err)
+	suite.Equal("abcdefgh\n", string(content))
+}
+
+func (suite *RotatingFileTestSuite) TestRotateOnDay() {
+	f := suite.newFile(FileOptions{})
+
+	_, err := f.Write([]byte("day 1\n"))
+	suite.Require().NoError(err)
+
+	suite.now = suite.now.Add(24 * time.Hour)
+	_, err = f.Write([]byte("day 2\n"))
+	suite.Require().NoError(err)
+
+	backups := suite.backups()
+	suite.Require().Len(backups, 1)
+	content, err := os.ReadFile(backups[0])
+	suite.Require().NoError(err)
+	suite.Equal("day 1\n", string(content))
+}
+
+func (suite *RotatingFileTestSuite) TestCleanup() {
+	// The files are also cleaned up when opened, with the real time.
+	suite.now = time.Now()
+
+	old := filepath.Join(suite.dir, "vertex-2023-09-01T00-00-00.000.log")
+	recent := filepath.Join(suite.dir, "vertex-2023-09-30T00-00-00.000.log")
+	suite.Require().NoError(os.WriteFile(old, nil, 0644))
+	suite.Require().NoError(os.WriteFile(recent, nil, 0644))
+	suite.Require().NoError(os.Chtimes(old, suite.now.Add(-30*24*time.Hour), suite.now.Add(-30*24*time.Hour)))
+	suite.Require().NoError(os.Chtimes(recent, suite.now.Add(-24*time.Hour), suite.now.Add(-24*time.Hour)))
+
+	f := suite.newFile(FileOptions{MaxAge: 7 * 24 * time.Hour})
+	f.cleanup()
+
+	suite.NoFileExists(old)
+	suite.FileExists(recent)
+}
+
+func (suite *RotatingFileTestSuite) TestFormatLine() {
+	line := formatLine(suite.now, vlog.LogTagInfo, "started", []vlog.KeyValue{
+		vlog.String("url", "http://localhost:6130"),
+	})
+
+	suite.Equal("2023-10-01 12:00:00 INF msg=started url=http://localhost:6130\n", line)
+}
@@ -1,14 +1,23 @@
 package log
 
 import (
+	"fmt"
+	stdlog "log"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/vertex-center/vlog"
 )
 
 var Default vlog.Logger
 
+// file is the rotating log file set with UseFile, if any.
+var file atomic.Pointer[rotatingFile]
+
 func init() {
 	var p string
 	if strings.Contains(os.Args[0], "kernel") || strings.Contains(os.Args[0], "Kernel") {
@@ -30,24 +39,124 @@ func init() {
 		)
 		Default.Info("full logger initialized")
 	}
+
+	// The standard library, like net/http, logs with the standard logger.
+	// Its lines are written as warnings, in the same format as the others.
+	stdlog.SetFlags(0)
+	stdlog.SetOutput(stdWriter{})
+}
+
+// UseFile writes the logs to a rotating file, instead of the daily log
+// files. It must be called at startup, before the logs are written
+// concurrently.
+func UseFile(opts FileOptions) error {
+	f, err := newRotatingFile(opts)
+	if err != nil {
+		return err
+	}
+
+	Default.Close()
+	Default = *vlog.New(vlog.WithOutputStd())
+	file.Store(f)
+	return nil
+}
+
+// Close closes the log outputs.
+func Close() {
+	Default.Close()
+	if f := file.Load(); f != nil {
+		err := f.Close()
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to close log file: %v\n", err)
+		}
+	}
 }
 
 func Debug(msg string, fields ...vlog.KeyValue) {
-	Default.Debug(msg, fields...)
+	if !enabled(LevelDebug) {
+		return
+	}
+	fields = withCaller(fields)
+	if os.Getenv("DEBUG") != "" {
+		Default.Debug(msg, fields...)
+	} else {
+		// vlog only prints the debug lines in the development mode.
+		_, _ = fmt.Fprint(os.Stdout, formatLine(time.Now(), vlog.LogTagDebug, msg, fields))
+	}
+	writeFile(vlog.LogTagDebug, msg, fields)
 }
 
 func Info(msg string, fields ...vlog.KeyValue) {
+	if !enabled(LevelInfo) {
+		return
+	}
+	fields = withCaller(fields)
 	Default.Info(msg, fields...)
+	writeFile(vlog.LogTagInfo, msg, fields)
 }
 
 func Warn(msg string, fields ...vlog.KeyValue) {
+	if !enabled(LevelWarn) {
+		return
+	}
+	fields = withCaller(fields)
 	Default.Warn(msg, fields...)
+	writeFile(vlog.LogTagWarn, msg, fields)
 }
 
 func Error(err error, fields ...vlog.KeyValue) {
+	fields = withCaller(fields)
 	Default.Error(err, fields...)
+	writeFile(vlog.LogTagError, err.Error(), fields)
 }
 
 func Request(msg string, fields ...vlog.KeyValue) {
+	if !enabled(LevelInfo) {
+		return
+	}
 	Default.Request(msg, fields...)
+	writeFile(vlog.LogTagRequest, msg, fields)
+}
+
+// writeFile writes a line to the log file set with UseFile, if any.
+func writeFile(tag vlog.Tag, msg string, fields []vlog.KeyValue) {
+	f := file.Load()
+	if f == nil {
+		return
+	}
+	_, err := f.Write([]byte(formatLine(time.Now(), tag, msg, fields)))
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to write to log file: %v\n", err)
+	}
+}
+
+// withCaller appends the caller of the logging function to the fields, like
+// "handler/apps.go:42".
+func withCaller(fields []vlog.KeyValue) []vlog.KeyValue {
+	return append(fields, vlog.String("caller", caller(3)))
+}
+
+// caller returns the file and the line of the caller skip frames above, with
+// the file relative to its parent directory.
+func caller(skip int) string {
+	_, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return "unknown"
+	}
+	dir, name := filepath.Split(file)
+	return fmt.Sprintf("%s/%s:%d", filepath.Base(dir), name, line)
+}
+
+// stdWriter writes the lines of the standard logger as warnings.
+type stdWriter struct{}
+
+func (stdWriter) Write(p []byte) (int, error) {
+	if !enabled(LevelWarn) {
+		return len(p), nil
+	}
+	msg := strings.TrimSuffix(string(p), "\n")
+	fields := []vlog.KeyValue{vlog.String("caller", "stdlib")}
+	Default.Warn(msg, fields...)
+	writeFile(vlog.LogTagWarn, msg, fields)
+	return len(p), nil
 }
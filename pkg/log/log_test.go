@@ -0,0 +1,58 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vertex-center/vlog"
+)
+
+type LogTestSuite struct {
+	suite.Suite
+}
+
+func TestLogTestSuite(t *testing.T) {
+	suite.Run(t, new(LogTestSuite))
+}
+
+func (suite *LogTestSuite) TestCaller() {
+	suite.Regexp(`^log/log_test\.go:\d+$`, caller(1))
+}
+
+func (suite *LogTestSuite) TestWithCaller() {
+	fields := func() []vlog.KeyValue {
+		return withCaller([]vlog.KeyValue{vlog.String("key", "value")})
+	}()
+
+	suite.Len(fields, 2)
+	suite.Equal("key", fields[0].Key)
+	suite.Equal("caller", fields[1].Key)
+	suite.Regexp(`^log/log_test\.go:\d+$`, fields[1].Value)
+}
+
+func (suite *LogTestSuite) TestParseLevel() {
+	for name, expected := range map[string]Level{
+		"debug":   LevelDebug,
+		"info":    LevelInfo,
+		"warn":    LevelWarn,
+		"warning": LevelWarn,
+		" ERROR ": LevelError,
+	} {
+		level, err := ParseLevel(name)
+		suite.NoError(err)
+		suite.Equal(expected, level)
+	}
+
+	_, err := ParseLevel("verbose")
+	suite.Error(err)
+}
+
+func (suite *LogTestSuite) TestEnabled() {
+	defer SetLevel(GetLevel())
+
+	SetLevel(LevelWarn)
+	suite.False(enabled(LevelDebug))
+	suite.False(enabled(LevelInfo))
+	suite.True(enabled(LevelWarn))
+	suite.True(enabled(LevelError))
+}
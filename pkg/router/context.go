@@ -1,7 +1,13 @@
 package router
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -21,6 +27,40 @@ func (c *Context) JSON(data interface{}) {
 	c.Context.JSON(http.StatusOK, data)
 }
 
+// JSONWithETag sends the data with an ETag computed from its content. If the
+// client already has this version of the data, it replies 304 Not Modified
+// without the body.
+func (c *Context) JSONWithETag(data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		_ = c.Context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	hash := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+
+	c.Header("ETag", etag)
+	if etagMatch(c.GetHeader("If-None-Match"), etag) {
+		c.Context.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Context.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// etagMatch returns true if the If-None-Match header contains the etag.
+func etagMatch(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		candidate = strings.TrimPrefix(candidate, "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Context) OK() {
 	c.Context.Status(http.StatusNoContent)
 }
@@ -49,13 +89,45 @@ func (c *Context) AbortWithCode(code int, err Error) {
 	c.AbortWithError(code, err)
 }
 
+// Fail aborts the request with the error registered for err with
+// RegisterError. If err is not registered, it aborts with a 500 and the
+// fallback error. The errors caused by the expiration of the request context
+// abort with a 504.
+func (c *Context) Fail(err error, fallback Error) {
+	if errors.Is(err, context.DeadlineExceeded) && c.Request.Context().Err() != nil {
+		e := ErrorRequestTimeout()
+		e.PrivateMessage = err.Error()
+		c.AbortWithError(http.StatusGatewayTimeout, e)
+		return
+	}
+
+	m, ok := lookupError(err)
+	if !ok {
+		fallback.PrivateMessage = err.Error()
+		c.Abort(fallback)
+		return
+	}
+
+	e := m.err
+	e.PrivateMessage = err.Error()
+	c.AbortWithError(m.status, e)
+}
+
 func (c *Context) ParseBody(obj interface{}) error {
-	err := c.BindJSON(obj)
+	err := c.ShouldBindJSON(obj)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		e := ErrorRequestTooLarge(maxBytesErr.Limit)
+		e.PrivateMessage = err.Error()
+		c.AbortWithError(http.StatusRequestEntityTooLarge, e)
+		return err
+	}
 	if err != nil {
 		c.BadRequest(Error{
 			Code:           ErrFailedToParseBody,
 			PublicMessage:  "Failed to parse the request.",
 			PrivateMessage: err.Error(),
+			Details:        fieldErrors(err),
 		})
 		return err
 	}
@@ -1,17 +1,81 @@
 package router
 
+import (
+	"errors"
+	"fmt"
+)
+
 type ErrCode string
 
 const (
+	ErrInternalError     ErrCode = "internal_error"
 	ErrFailedToParseBody ErrCode = "failed_to_parse_body"
+	ErrRequestTooLarge   ErrCode = "request_too_large"
+	ErrRequestTimeout    ErrCode = "request_timeout"
 )
 
 type Error struct {
 	Code           ErrCode `json:"code"`
 	PublicMessage  string  `json:"message,omitempty"`
 	PrivateMessage string  `json:"-"`
+
+	// Details explains which fields of the request are invalid.
+	Details []FieldError `json:"details,omitempty"`
+}
+
+// FieldError is a validation error on a single field of the request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ErrorRequestTooLarge is the error of the request bodies larger than limit
+// bytes.
+func ErrorRequestTooLarge(limit int64) Error {
+	return Error{
+		Code:          ErrRequestTooLarge,
+		PublicMessage: fmt.Sprintf("The request body is larger than the limit of %d bytes.", limit),
+	}
+}
+
+// ErrorRequestTimeout is the error of the requests that took too long to be
+// handled.
+func ErrorRequestTimeout() Error {
+	return Error{
+		Code:          ErrRequestTimeout,
+		PublicMessage: "The request took too long to be handled.",
+	}
 }
 
 func (e Error) Error() string {
 	return e.PublicMessage + "; " + e.PrivateMessage
 }
+
+type errorMapping struct {
+	target error
+	status int
+	err    Error
+}
+
+var errorMappings []errorMapping
+
+// RegisterError maps an error to the HTTP status and the error returned by
+// Context.Fail. The target is matched with errors.Is, so wrapped errors are
+// recognized too. It is meant to be called at initialization.
+func RegisterError(target error, status int, err Error) {
+	errorMappings = append(errorMappings, errorMapping{
+		target: target,
+		status: status,
+		err:    err,
+	})
+}
+
+// lookupError returns the mapping registered for err, if any.
+func lookupError(err error) (errorMapping, bool) {
+	for _, m := range errorMappings {
+		if errors.Is(err, m.target) {
+			return m, true
+		}
+	}
+	return errorMapping{}, false
+}
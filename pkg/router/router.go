@@ -3,13 +3,16 @@ package router
 import (
 	"context"
 	"net/http"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Router struct {
 	*gin.Engine
-	server *http.Server
+
+	server      *http.Server
+	serverMutex sync.Mutex
 }
 
 func New() *Router {
@@ -19,23 +22,29 @@ func New() *Router {
 }
 
 func (r *Router) Start(addr string) error {
-	r.server = &http.Server{
+	r.serverMutex.Lock()
+	server := &http.Server{
 		Addr:    addr,
 		Handler: r.Engine,
 	}
-	return r.server.ListenAndServe()
+	r.server = server
+	r.serverMutex.Unlock()
+
+	return server.ListenAndServe()
 }
 
+// Stop gracefully shuts down the server. It does nothing if the server is
+// not started, or is already stopped.
 func (r *Router) Stop(ctx context.Context) error {
-	if r.server == nil {
+	r.serverMutex.Lock()
+	server := r.server
+	r.server = nil
+	r.serverMutex.Unlock()
+
+	if server == nil {
 		return nil
 	}
-	err := r.server.Shutdown(ctx)
-	if err != nil {
-		return err
-	}
-	r.server = nil
-	return nil
+	return server.Shutdown(ctx)
 }
 
 func (r *Router) Group(path string, handlers ...HandlerFunc) *Group {
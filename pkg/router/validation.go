@@ -0,0 +1,80 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if ok {
+		v.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+// jsonFieldName returns the name of the field in the JSON body, so the
+// validation errors name the fields as the clients send them.
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// fieldErrors converts the errors returned by the binding of a request to
+// readable errors for each invalid field.
+func fieldErrors(err error) []FieldError {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		var details []FieldError
+		for _, e := range validationErrors {
+			details = append(details, FieldError{
+				Field:   e.Field(),
+				Message: validationMessage(e),
+			})
+		}
+		return details
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return []FieldError{{
+			Field:   typeError.Field,
+			Message: fmt.Sprintf("must be of type %s", typeError.Type.String()),
+		}}
+	}
+
+	return nil
+}
+
+func validationMessage(e validator.FieldError) string {
+	switch e.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("must be at least %s", e.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s", e.Param())
+	case "len":
+		return fmt.Sprintf("must have a length of %s", e.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", e.Param())
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "uuid":
+		return "must be a valid UUID"
+	}
+	return fmt.Sprintf("failed the '%s' validation", e.Tag())
+}
@@ -0,0 +1,53 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/suite"
+)
+
+type ValidationTestSuite struct {
+	suite.Suite
+}
+
+func TestValidationTestSuite(t *testing.T) {
+	suite.Run(t, new(ValidationTestSuite))
+}
+
+type validationBody struct {
+	DisplayName string `json:"display_name" binding:"required"`
+	Port        int    `json:"port,omitempty" binding:"min=1"`
+	Kind        string `binding:"oneof=a b"`
+}
+
+func (suite *ValidationTestSuite) bind(body string) []FieldError {
+	var obj validationBody
+	err := binding.JSON.BindBody([]byte(body), &obj)
+	suite.Require().Error(err)
+	return fieldErrors(err)
+}
+
+func (suite *ValidationTestSuite) TestJSONNames() {
+	details := suite.bind(`{"port":0,"Kind":"c"}`)
+
+	suite.Equal([]FieldError{
+		{Field: "display_name", Message: "is required"},
+		{Field: "port", Message: "must be at least 1"},
+		{Field: "Kind", Message: "must be one of: a b"},
+	}, details)
+}
+
+func (suite *ValidationTestSuite) TestTypeError() {
+	details := suite.bind(`{"display_name":"a","port":"80","Kind":"a"}`)
+
+	suite.Equal([]FieldError{
+		{Field: "port", Message: "must be of type int"},
+	}, details)
+}
+
+func (suite *ValidationTestSuite) TestOtherError() {
+	details := suite.bind(`{`)
+
+	suite.Nil(details)
+}
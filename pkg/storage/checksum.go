@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v50/github"
+)
+
+var (
+	ErrChecksumsNotFound = errors.New("this release has no published checksums")
+	ErrChecksumNotFound  = errors.New("no checksum is published for this file")
+	ErrChecksumMismatch  = errors.New("the checksum of the downloaded file doesn't match the published checksum")
+)
+
+// Checksums are the SHA256 checksums of the assets of a release, by asset
+// name.
+type Checksums map[string]string
+
+// ParseChecksums parses a checksums file in the sha256sum format, as
+// published by goreleaser: one "<sha256>  <name>" per line.
+func ParseChecksums(r io.Reader) (Checksums, error) {
+	checksums := Checksums{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid checksums line: '%s'", line)
+		}
+		checksums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return checksums, scanner.Err()
+}
+
+// Verify checks that the file at filePath has the checksum published for
+// the asset name.
+func (c Checksums) Verify(name string, filePath string) error {
+	expected, ok := c[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrChecksumNotFound, name)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return err
+	}
+
+	actual := hex.EncodeToString(hash.Sum(nil))
+	if actual != expected {
+		return fmt.Errorf("%w: %s: expected %s, got %s", ErrChecksumMismatch, name, expected, actual)
+	}
+	return nil
+}
+
+// FetchGithubChecksums downloads the checksums published as an asset of the
+// release. It returns ErrChecksumsNotFound if the release has none.
+func FetchGithubChecksums(release *github.RepositoryRelease) (Checksums, error) {
+	for _, asset := range release.Assets {
+		if !strings.HasSuffix(asset.GetName(), "checksums.txt") {
+			continue
+		}
+
+		res, err := http.Get(asset.GetBrowserDownloadURL())
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to download %s: %s", asset.GetName(), res.Status)
+		}
+
+		return ParseChecksums(res.Body)
+	}
+	return nil, ErrChecksumsNotFound
+}
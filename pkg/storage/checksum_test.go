@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// helloSHA256 is the SHA256 checksum of "hello".
+const helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+type ChecksumTestSuite struct {
+	suite.Suite
+}
+
+func TestChecksumTestSuite(t *testing.T) {
+	suite.Run(t, new(ChecksumTestSuite))
+}
+
+func (suite *ChecksumTestSuite) TestParseChecksums() {
+	checksums, err := ParseChecksums(strings.NewReader(
+		helloSHA256 + "  vertex_linux_amd64.tar.gz\n" +
+			"\n" +
+			"ABCDEF *vertex_darwin_arm64.tar.gz\n",
+	))
+	suite.NoError(err)
+	suite.Equal(Checksums{
+		"vertex_linux_amd64.tar.gz":  helloSHA256,
+		"vertex_darwin_arm64.tar.gz": "abcdef",
+	}, checksums)
+
+	_, err = ParseChecksums(strings.NewReader("invalid\n"))
+	suite.Error(err)
+}
+
+func (suite *ChecksumTestSuite) TestVerify() {
+	p := path.Join(suite.T().TempDir(), "archive.tar.gz")
+	err := os.WriteFile(p, []byte("hello"), 0644)
+	suite.Require().NoError(err)
+
+	checksums := Checksums{
+		"valid.tar.gz":   helloSHA256,
+		"invalid.tar.gz": strings.Repeat("0", 64),
+	}
+
+	suite.NoError(checksums.Verify("valid.tar.gz", p))
+	suite.ErrorIs(checksums.Verify("invalid.tar.gz", p), ErrChecksumMismatch)
+	suite.ErrorIs(checksums.Verify("missing.tar.gz", p), ErrChecksumNotFound)
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// BackupSuffix is appended to the name of the previous version of a file
+// written by WriteFileAtomic.
+const BackupSuffix = ".bak"
+
+// WriteFileAtomic writes data to the file at p like os.WriteFile, but a crash
+// can't leave the file truncated. The data is written to a temporary file in
+// the same directory, then renamed over p. The previous version of the file
+// is kept next to it, with the BackupSuffix.
+func WriteFileAtomic(p string, data []byte, perm os.FileMode) error {
+	previous, err := os.ReadFile(p)
+	if err == nil {
+		err = writeFileAtomic(p+BackupSuffix, previous, perm)
+		if err != nil {
+			return err
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return writeFileAtomic(p, data, perm)
+}
+
+func writeFileAtomic(p string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(p), filepath.Base(p)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// Does nothing once renamed.
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		// The data must be on the disk before the rename.
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tmp.Name(), perm)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
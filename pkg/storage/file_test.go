@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FileTestSuite struct {
+	suite.Suite
+}
+
+func TestFileTestSuite(t *testing.T) {
+	suite.Run(t, new(FileTestSuite))
+}
+
+func (suite *FileTestSuite) TestWriteFileAtomic() {
+	p := filepath.Join(suite.T().TempDir(), "settings.json")
+
+	err := WriteFileAtomic(p, []byte("first"), 0644)
+	suite.Require().NoError(err)
+	suite.NoFileExists(p + BackupSuffix)
+
+	err = WriteFileAtomic(p, []byte("second"), 0644)
+	suite.Require().NoError(err)
+
+	content, err := os.ReadFile(p)
+	suite.Require().NoError(err)
+	suite.Equal("second", string(content))
+
+	backup, err := os.ReadFile(p + BackupSuffix)
+	suite.Require().NoError(err)
+	suite.Equal("first", string(backup))
+
+	// The temporary files are removed.
+	entries, err := os.ReadDir(filepath.Dir(p))
+	suite.Require().NoError(err)
+	suite.Len(entries, 2)
+}
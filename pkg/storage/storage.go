@@ -22,6 +22,7 @@ const Path = "live"
 var (
 	ErrNoReleasesPublished = errors.New("this repository has no existing releases")
 	ErrNoReleasesForThisOS = errors.New("this repository has no releases appropriate for this OS")
+	ErrRemoteBranchMissing = errors.New("the current branch doesn't exist on the remote")
 )
 
 func CloneRepository(url string, dest string) error {
@@ -59,6 +60,37 @@ func CloneOrPullRepository(url string, dest string) error {
 	return nil
 }
 
+// RepositoryHeads returns the commit checked out in the repository at dir, and
+// the latest commit of the same branch on the origin remote.
+func RepositoryHeads(dir string) (local string, remote string, err error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", "", err
+	}
+
+	origin, err := repo.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return "", "", err
+	}
+
+	refs, err := origin.List(&git.ListOptions{})
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, ref := range refs {
+		if ref.Name() == head.Name() {
+			return head.Hash().String(), ref.Hash().String(), nil
+		}
+	}
+	return "", "", ErrRemoteBranchMissing
+}
+
 func DownloadLatestGithubRelease(owner string, repo string, dest string) error {
 	log.Info("downloading repository",
 		vlog.String("owner", owner),
@@ -75,11 +107,19 @@ func DownloadLatestGithubRelease(owner string, repo string, dest string) error {
 	return DownloadGithubRelease(release, dest)
 }
 
+// DownloadGithubRelease downloads and extracts the archive of the release for
+// this platform. The archive is verified with the checksums published in the
+// release before being extracted.
 func DownloadGithubRelease(release *github.RepositoryRelease, dest string) error {
 	log.Info("downloading release",
 		vlog.String("release", *release.Name),
 	)
 
+	checksums, err := FetchGithubChecksums(release)
+	if err != nil {
+		return err
+	}
+
 	platform := fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH)
 
 	for _, asset := range release.Assets {
@@ -92,6 +132,12 @@ func DownloadGithubRelease(release *github.RepositoryRelease, dest string) error
 				return err
 			}
 
+			err = checksums.Verify(*asset.Name, archivePath)
+			if err != nil {
+				_ = os.Remove(archivePath)
+				return err
+			}
+
 			err = varchiver.Untar(archivePath, dest)
 			if err != nil {
 				return err
@@ -51,3 +51,39 @@ func (suite *RepositoryTestSuite) TestCloneOrPullRepository() {
 	suite.NoError(err)
 	suite.DirExists(dir)
 }
+
+func (suite *RepositoryTestSuite) TestRepositoryHeads() {
+	fs := fixtures.Basic().One().DotGit()
+
+	dir, err := os.MkdirTemp("", "*_live_test")
+	suite.NoError(err)
+
+	defer os.RemoveAll(dir)
+
+	err = CloneRepository(fs.Root(), dir)
+	suite.NoError(err)
+
+	local, remote, err := RepositoryHeads(dir)
+	suite.NoError(err)
+	suite.Equal(remote, local)
+
+	// Go back one commit, as if the remote had a new one.
+	repo, err := git.PlainOpen(dir)
+	suite.NoError(err)
+	head, err := repo.Head()
+	suite.NoError(err)
+	commit, err := repo.CommitObject(head.Hash())
+	suite.NoError(err)
+	worktree, err := repo.Worktree()
+	suite.NoError(err)
+	err = worktree.Reset(&git.ResetOptions{
+		Commit: commit.ParentHashes[0],
+		Mode:   git.HardReset,
+	})
+	suite.NoError(err)
+
+	local, remote, err = RepositoryHeads(dir)
+	suite.NoError(err)
+	suite.Equal(commit.ParentHashes[0].String(), local)
+	suite.Equal(head.Hash().String(), remote)
+}
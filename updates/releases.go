@@ -0,0 +1,30 @@
+package updates
+
+import (
+	"context"
+
+	"github.com/google/go-github/v50/github"
+)
+
+// listReleaseTags returns the tags of the latest releases of a repository,
+// from the newest.
+func listReleaseTags(owner, repo string) ([]string, error) {
+	client := github.NewClient(nil)
+
+	releases, res, err := client.Repositories.ListReleases(context.Background(), owner, repo, &github.ListOptions{
+		PerPage: 50,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var tags []string
+	for _, release := range releases {
+		if release.GetDraft() {
+			continue
+		}
+		tags = append(tags, release.GetTagName())
+	}
+	return tags, nil
+}
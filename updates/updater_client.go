@@ -2,9 +2,11 @@ package updates
 
 import (
 	"context"
+	"errors"
 	"github.com/google/go-github/v50/github"
 	"github.com/vertex-center/vertex/config"
 	"github.com/vertex-center/vertex/pkg/log"
+	"github.com/vertex-center/vertex/pkg/storage"
 	"github.com/vertex-center/vertex/pkg/varchiver"
 	"github.com/vertex-center/vlog"
 	"io"
@@ -43,15 +45,32 @@ func (u VertexClientUpdater) Install(tag string) error {
 	}
 	defer res.Body.Close()
 
+	checksums, err := storage.FetchGithubChecksums(release)
+	if errors.Is(err, storage.ErrChecksumsNotFound) {
+		log.Warn("the vertex client release has no published checksums, skipping verification", vlog.String("tag", tag))
+	} else if err != nil {
+		return err
+	}
+
 	for _, asset := range release.Assets {
-		if strings.Contains(*asset.Name, "vertex-webui") {
-			return install(u.dir, *asset.BrowserDownloadURL)
+		if strings.Contains(*asset.Name, "vertex-webui") && !strings.HasSuffix(*asset.Name, "checksums.txt") {
+			name := *asset.Name
+			return install(u.dir, *asset.BrowserDownloadURL, func(archivePath string) error {
+				if checksums == nil {
+					return nil
+				}
+				return checksums.Verify(name, archivePath)
+			})
 		}
 	}
 
 	return nil
 }
 
+func (u VertexClientUpdater) Versions() ([]string, error) {
+	return listReleaseTags("vertex-center", "vertex-webui")
+}
+
 func (u VertexClientUpdater) IsInstalled() bool {
 	_, err := os.Stat(path.Join(u.dir, "dist"))
 	return err == nil
@@ -61,30 +80,39 @@ func (u VertexClientUpdater) ID() string {
 	return "vertex_client"
 }
 
-func install(dir string, releaseUrl string) error {
-	tempPath := path.Join(dir, "temp.zip")
+// install downloads the archive of the release, verifies it, and replaces
+// the content of dir with it. The previous content is kept if the download
+// or the verification fails.
+func install(dir string, releaseUrl string, verify func(archivePath string) error) error {
+	tempPath := dir + "_temp.zip"
+	defer os.Remove(tempPath)
 
-	err := os.RemoveAll(dir)
+	err := os.MkdirAll(path.Dir(tempPath), os.ModePerm)
 	if err != nil {
 		return err
 	}
 
-	err = os.MkdirAll(dir, os.ModePerm)
+	err = download(tempPath, releaseUrl)
 	if err != nil {
 		return err
 	}
 
-	err = download(tempPath, releaseUrl)
+	err = verify(tempPath)
 	if err != nil {
 		return err
 	}
 
-	err = varchiver.Unzip(tempPath, dir)
+	err = os.RemoveAll(dir)
 	if err != nil {
 		return err
 	}
 
-	err = os.Remove(tempPath)
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	err = varchiver.Unzip(tempPath, dir)
 	if err != nil {
 		return err
 	}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/vertex-center/vertex/config"
 	"github.com/vertex-center/vertex/pkg/log"
 	"github.com/vertex-center/vertex/pkg/storage"
 	"github.com/vertex-center/vlog"
 	"os"
 	"path"
+	"strings"
 )
 
 type RepositoryUpdater struct {
@@ -41,7 +43,7 @@ func (u RepositoryUpdater) CurrentVersion() (string, error) {
 }
 
 func (u RepositoryUpdater) Install(version string) error {
-	url := fmt.Sprintf("https://github.com/%s/%s", u.owner, u.repo)
+	url := fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(config.Current.GitURL, "/"), u.owner, u.repo)
 
 	log.Info("installing package", vlog.String("url", url), vlog.String("version", version))
 
@@ -71,6 +71,10 @@ func (u VertexUpdater) Install(tag string) error {
 	return nil
 }
 
+func (u VertexUpdater) Versions() ([]string, error) {
+	return listReleaseTags("vertex-center", "vertex")
+}
+
 func (u VertexUpdater) IsInstalled() bool {
 	return true
 }